@@ -1,6 +1,7 @@
 package client
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -26,6 +27,12 @@ type Config struct {
 	ProductName        string `json:"product_name"`
 	ProductVersion     string `json:"product_version"`
 	EnableDebugLogging bool   `json:"enable_debug_logging"`
+	// MaxPages limits the number of pages fetched for business transactions
+	// answered with continuation references. Defaults to DefaultMaxPages.
+	MaxPages int `json:"max_pages"`
+	// Recovery defines how to recover when the bank institute aborts a
+	// dialog. Defaults to dialog.DefaultRecoveryPolicy.
+	Recovery *dialog.RecoveryPolicy `json:"-"`
 }
 
 func (c Config) hbciVersion() (segment.HBCIVersion, error) {
@@ -79,6 +86,7 @@ func New(config Config) (*Client, error) {
 		ProductName:    config.ProductName,
 		ProductVersion: config.ProductVersion,
 		Transport:      config.Transport,
+		Recovery:       config.Recovery,
 	}
 
 	d := dialog.NewPinTanDialog(dcfg)
@@ -91,6 +99,12 @@ func New(config Config) (*Client, error) {
 	return client, nil
 }
 
+// ErrPinRejected is returned for all requests after the bank institute
+// rejected the PIN or locked the access. The client does not send the rejected
+// PIN again, to prevent the bank institute from locking the access. Requests
+// are sent again after a PIN is provided with SetPIN or ChangePIN.
+var ErrPinRejected = dialog.ErrPinRejected
+
 // Client is the main entrypoint to perform high level HBCI requests.
 //
 // Its methods reflect possible actions and abstract the lower level dialog
@@ -105,12 +119,73 @@ func (c *Client) init() error {
 	if c.pinTanDialog.BankParameterDataVersion() == 0 {
 		_, err := c.pinTanDialog.SyncClientSystemID()
 		if err != nil {
-			return fmt.Errorf("error while fetching accounts: %v", err)
+			return fmt.Errorf("error while fetching accounts: %w", err)
+		}
+	}
+	return nil
+}
+
+// SetPIN sets the PIN used for all following requests. If the bank institute
+// rejected the former PIN, it allows sending requests again.
+func (c *Client) SetPIN(pin string) {
+	c.config.PIN = pin
+	c.pinTanDialog.SetPin(pin)
+}
+
+// ChangePIN changes the PIN of the user from oldPIN to newPIN. The client
+// uses newPIN for all following requests if the bank institute accepted the
+// change, and oldPIN otherwise. If the bank institute requires a TAN for the
+// change, ChangePIN returns an error wrapping domain.ErrTanRequired.
+func (c *Client) ChangePIN(oldPIN, newPIN string) error {
+	if newPIN == "" {
+		return fmt.Errorf("new PIN must not be empty")
+	}
+	c.SetPIN(oldPIN)
+	if err := c.init(); err != nil {
+		return err
+	}
+	if err := c.pinTanDialog.Open(); err != nil {
+		return err
+	}
+	pinChangeRequest := segment.NewPinChangeRequestSegment(newPIN)
+	bankMessage, err := c.pinTanDialog.Send(
+		message.NewHBCIMessage(c.hbciVersion, c.hbciVersion.TanProcess4Request(segment.PinChangeRequestID), pinChangeRequest),
+	)
+	if err != nil {
+		if errors.Is(err, domain.ErrPinWrong) {
+			// oldPIN was accepted when opening the dialog, so the
+			// rejection refers to newPIN
+			c.SetPIN(oldPIN)
 		}
+		logErr(c.pinTanDialog.Close())
+		return fmt.Errorf("error changing PIN: %w", err)
 	}
+	if tanChallengeIssued(bankMessage) {
+		// the bank institute did not execute the change yet, so oldPIN
+		// stays valid
+		logErr(c.pinTanDialog.Close())
+		return fmt.Errorf("error changing PIN: %w", domain.ErrTanRequired)
+	}
+	logErr(c.pinTanDialog.Close())
+	c.SetPIN(newPIN)
 	return nil
 }
 
+// tanChallengeIssued reports whether the bank institute answered with a TAN
+// challenge instead of executing the order.
+func tanChallengeIssued(bankMessage message.BankMessage) bool {
+	if bankMessage.FindMarshaledSegment("HITAN") == nil {
+		return false
+	}
+	for _, ack := range bankMessage.Acknowledgements() {
+		switch ack.Code {
+		case domain.ReturnCodeOrderReceivedTanRequired, domain.ReturnCodeDecoupledSecurityClearance:
+			return true
+		}
+	}
+	return false
+}
+
 // Accounts return the basic account information for the provided client config.
 func (c *Client) Accounts() ([]domain.AccountInformation, error) {
 	if err := c.init(); err != nil {
@@ -118,7 +193,7 @@ func (c *Client) Accounts() ([]domain.AccountInformation, error) {
 	}
 	err := c.pinTanDialog.SyncUserParameterData()
 	if err != nil {
-		return nil, fmt.Errorf("error getting accounts")
+		return nil, fmt.Errorf("error getting accounts: %w", err)
 	}
 	return c.pinTanDialog.Accounts, nil
 }
@@ -126,92 +201,104 @@ func (c *Client) Accounts() ([]domain.AccountInformation, error) {
 // AccountTransactions return all transactions for the provided timeframe.
 // If allAccouts is true, it will fetch all transactions associated with the
 // proviced account. For the initial request no continuationReference is
-// needed, as this method will be called recursivly if the server sends one.
+// needed, as this method will fetch all pages if the server sends one.
 func (c *Client) AccountTransactions(account domain.AccountConnection, timeframe domain.Timeframe, allAccounts bool, continuationReference string) ([]domain.AccountTransaction, error) {
-	if err := c.init(); err != nil {
-		return nil, err
-	}
 	requestBuilder := func() (segment.AccountTransactionRequest, error) {
 		builder := segment.NewBuilder(c.pinTanDialog.SupportedSegments())
 		return builder.AccountTransactionRequest(account, allAccounts)
 	}
-	bookedSwiftTransactions, err := c.accountTransactions(requestBuilder, timeframe, continuationReference)
-	if err != nil {
-		return nil, fmt.Errorf("error executing HBCI request: %w", err)
-	}
-	unmarshaler := swift.NewMT940MessagesUnmarshaler()
-	tx, err := unmarshaler.UnmarshalMT940(bookedSwiftTransactions.Data)
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshaling SWIFT transactions: %w", err)
+	return c.transactionsIter(requestBuilder, timeframe, continuationReference).All()
+}
+
+// TransactionsIter returns an iterator over the transactions for the provided
+// timeframe. Each page is fetched lazily within one dialog when calling Next
+// on the iterator.
+func (c *Client) TransactionsIter(account domain.AccountConnection, timeframe domain.Timeframe, allAccounts bool) *PageIterator[domain.AccountTransaction] {
+	requestBuilder := func() (segment.AccountTransactionRequest, error) {
+		builder := segment.NewBuilder(c.pinTanDialog.SupportedSegments())
+		return builder.AccountTransactionRequest(account, allAccounts)
 	}
-	return tx, nil
+	return c.transactionsIter(requestBuilder, timeframe, "")
 }
 
 // SepaAccountTransactions return all transactions for the provided timeframe.
 // If allAccouts is true, it will fetch all transactions associated with the
 // provided account. For the initial request no continuationReference is
-// needed, as this method will be called recursivly if the server sends one.
+// needed, as this method will fetch all pages if the server sends one.
 func (c *Client) SepaAccountTransactions(account domain.InternationalAccountConnection, timeframe domain.Timeframe, allAccounts bool, continuationReference string) ([]domain.AccountTransaction, error) {
-	if err := c.init(); err != nil {
-		return nil, err
-	}
 	requestBuilder := func() (segment.AccountTransactionRequest, error) {
 		builder := segment.NewBuilder(c.pinTanDialog.SupportedSegments())
 		return builder.SepaAccountTransactionRequest(account, allAccounts)
 	}
-	bookedSwiftTransactions, err := c.accountTransactions(requestBuilder, timeframe, continuationReference)
-	if err != nil {
-		return nil, fmt.Errorf("error executing HBCI request: %w", err)
+	return c.transactionsIter(requestBuilder, timeframe, continuationReference).All()
+}
+
+// SepaTransactionsIter returns an iterator over the transactions for the
+// provided timeframe. Each page is fetched lazily within one dialog when
+// calling Next on the iterator.
+func (c *Client) SepaTransactionsIter(account domain.InternationalAccountConnection, timeframe domain.Timeframe, allAccounts bool) *PageIterator[domain.AccountTransaction] {
+	requestBuilder := func() (segment.AccountTransactionRequest, error) {
+		builder := segment.NewBuilder(c.pinTanDialog.SupportedSegments())
+		return builder.SepaAccountTransactionRequest(account, allAccounts)
 	}
-	unmarshaler := swift.NewMT940MessagesUnmarshaler()
-	tx, err := unmarshaler.UnmarshalMT940(bookedSwiftTransactions.Data)
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshaling SWIFT transactions: %w", err)
+	return c.transactionsIter(requestBuilder, timeframe, "")
+}
+
+func (c *Client) transactionsIter(requestBuilder func() (segment.AccountTransactionRequest, error), timeframe domain.Timeframe, continuationReference string) *PageIterator[domain.AccountTransaction] {
+	fetch := func(continuationReference string) ([]domain.AccountTransaction, string, error) {
+		bookedSwiftTransactions, next, err := c.accountTransactions(requestBuilder, timeframe, continuationReference)
+		if err != nil {
+			return nil, "", fmt.Errorf("error executing HBCI request: %w", err)
+		}
+		if len(bookedSwiftTransactions.Data) == 0 {
+			return nil, next, nil
+		}
+		unmarshaler := swift.NewMT940MessagesUnmarshaler()
+		tx, err := unmarshaler.UnmarshalMT940(bookedSwiftTransactions.Data)
+		if err != nil {
+			return nil, "", fmt.Errorf("error unmarshaling SWIFT transactions: %w", err)
+		}
+		return tx, next, nil
 	}
-	return tx, nil
+	return newPageIterator(c.pinTanDialog, c.config.MaxPages, continuationReference, c.init, fetch)
 }
 
-func (c *Client) accountTransactions(requestBuilder func() (segment.AccountTransactionRequest, error), timeframe domain.Timeframe, continuationReference string) (*swift.MT940Messages, error) {
+func (c *Client) accountTransactions(requestBuilder func() (segment.AccountTransactionRequest, error), timeframe domain.Timeframe, continuationReference string) (*swift.MT940Messages, string, error) {
 	accountTransactionRequest, err := requestBuilder()
 	if err != nil {
-		return nil, fmt.Errorf("error building request: %w", err)
+		return nil, "", fmt.Errorf("error building request: %w", err)
 	}
 	accountTransactionRequest.SetTransactionRange(timeframe)
 	if continuationReference != "" {
 		accountTransactionRequest.SetContinuationReference(continuationReference)
 	}
-	decryptedMessage, err := c.pinTanDialog.SendMessage(
+	decryptedMessage, err := c.pinTanDialog.Send(
 		message.NewHBCIMessage(c.hbciVersion, c.hbciVersion.TanProcess4Request(segment.IdentificationID), accountTransactionRequest),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("error sending hbci request: %w", err)
+		return nil, "", fmt.Errorf("error sending hbci request: %w", err)
 	}
 	var bookedSwiftTransactions []*swift.MT940Messages
 	accountTransactionResponses := decryptedMessage.FindSegments("HIKAZ")
 	for _, unmarshaledSegment := range accountTransactionResponses {
 		seg, ok := unmarshaledSegment.(segment.AccountTransactionResponse)
 		if !ok {
-			return nil, fmt.Errorf("malformed segment found with ID `HIKAZ`")
+			return nil, "", fmt.Errorf("malformed segment found with ID `HIKAZ`")
 		}
 		bookedSwiftTransactions = append(bookedSwiftTransactions, seg.BookedSwiftTransactions())
 	}
-	var newContinuationReference string
-	acknowledgements := decryptedMessage.Acknowledgements()
-	for _, ack := range acknowledgements {
-		if ack.Code == element.AcknowledgementAdditionalInformation {
-			newContinuationReference = ack.Params[0]
-			break
+	return swift.MergeMT940Messages(bookedSwiftTransactions...), continuationReferenceFrom(decryptedMessage), nil
+}
+
+// continuationReferenceFrom returns the continuation reference sent by the
+// bank institute, if any.
+func continuationReferenceFrom(bankMessage message.BankMessage) string {
+	for _, ack := range bankMessage.Acknowledgements() {
+		if ack.Code == element.AcknowledgementAdditionalInformation && len(ack.Params) > 0 {
+			return ack.Params[0]
 		}
 	}
-	tx := swift.MergeMT940Messages(bookedSwiftTransactions...)
-	if newContinuationReference == "" {
-		return tx, nil
-	}
-	msg, err := c.accountTransactions(requestBuilder, timeframe, newContinuationReference)
-	if err != nil {
-		return nil, err
-	}
-	return swift.MergeMT940Messages(msg, tx), err
+	return ""
 }
 
 // AccountInformation will print all information attached to the provided
@@ -274,91 +361,100 @@ func (c *Client) AccountBalances(account domain.AccountConnection, allAccounts b
 	return balances, nil
 }
 
-// AccountBalances retrieves the balance for the provided account.
+// SepaAccountBalances retrieves the balance for the provided account.
 // If allAccounts is true it will fetch also the balances for all accounts
 // associated with the account.
 func (c *Client) SepaAccountBalances(account domain.InternationalAccountConnection, allAccounts bool, continuationReference string) ([]domain.SepaAccountBalance, error) {
-	if err := c.init(); err != nil {
-		return nil, err
-	}
-	builder := segment.NewBuilder(c.pinTanDialog.SupportedSegments())
-	accountBalanceRequest, err := builder.SepaAccountBalanceRequest(account, allAccounts)
-	if err != nil {
-		return nil, err
-	}
-	if continuationReference != "" {
-		accountBalanceRequest.SetContinuationMark(continuationReference)
-	}
-	decryptedMessage, err := c.pinTanDialog.SendMessage(
-		message.NewHBCIMessage(
-			c.hbciVersion,
-			c.hbciVersion.TanProcess4Request(segment.IdentificationID),
-			accountBalanceRequest,
-		),
-	)
-	if err != nil {
-		return nil, err
-	}
-	var balances []domain.SepaAccountBalance
-	balanceResponses := decryptedMessage.FindSegments(segment.AccountBalanceResponseID)
-	for _, unmarshaledSegment := range balanceResponses {
-		seg, ok := unmarshaledSegment.(segment.AccountBalanceResponse)
-		if !ok {
-			return nil, fmt.Errorf("malformed segment found with ID %q", segment.AccountBalanceResponseID)
+	return c.sepaAccountBalancesIter(account, allAccounts, continuationReference).All()
+}
+
+// SepaAccountBalancesIter returns an iterator over the balances for the
+// provided account. Each page is fetched lazily within one dialog when
+// calling Next on the iterator.
+func (c *Client) SepaAccountBalancesIter(account domain.InternationalAccountConnection, allAccounts bool) *PageIterator[domain.SepaAccountBalance] {
+	return c.sepaAccountBalancesIter(account, allAccounts, "")
+}
+
+func (c *Client) sepaAccountBalancesIter(account domain.InternationalAccountConnection, allAccounts bool, continuationReference string) *PageIterator[domain.SepaAccountBalance] {
+	fetch := func(continuationReference string) ([]domain.SepaAccountBalance, string, error) {
+		builder := segment.NewBuilder(c.pinTanDialog.SupportedSegments())
+		accountBalanceRequest, err := builder.SepaAccountBalanceRequest(account, allAccounts)
+		if err != nil {
+			return nil, "", err
 		}
-		sepaBalances, err := seg.SepaAccountBalance()
+		if continuationReference != "" {
+			accountBalanceRequest.SetContinuationMark(continuationReference)
+		}
+		decryptedMessage, err := c.pinTanDialog.Send(
+			message.NewHBCIMessage(
+				c.hbciVersion,
+				c.hbciVersion.TanProcess4Request(segment.IdentificationID),
+				accountBalanceRequest,
+			),
+		)
 		if err != nil {
-			return nil, fmt.Errorf("could not get sepa balances: %w", err)
+			return nil, "", err
 		}
-		balances = append(balances, sepaBalances)
-	}
-	if len(balanceResponses) == 0 {
-		return nil, fmt.Errorf("malformed response: expected HISAL segment")
-	}
-	var newContinuationReference string
-	acknowledgements := decryptedMessage.Acknowledgements()
-	for _, ack := range acknowledgements {
-		if ack.Code == element.AcknowledgementAdditionalInformation {
-			newContinuationReference = ack.Params[0]
-			break
+		var balances []domain.SepaAccountBalance
+		balanceResponses := decryptedMessage.FindSegments(segment.AccountBalanceResponseID)
+		for _, unmarshaledSegment := range balanceResponses {
+			seg, ok := unmarshaledSegment.(segment.AccountBalanceResponse)
+			if !ok {
+				return nil, "", fmt.Errorf("malformed segment found with ID %q", segment.AccountBalanceResponseID)
+			}
+			sepaBalances, err := seg.SepaAccountBalance()
+			if err != nil {
+				return nil, "", fmt.Errorf("could not get sepa balances: %w", err)
+			}
+			balances = append(balances, sepaBalances)
 		}
+		if len(balanceResponses) == 0 {
+			return nil, "", fmt.Errorf("malformed response: expected HISAL segment")
+		}
+		return balances, continuationReferenceFrom(decryptedMessage), nil
 	}
-	if newContinuationReference == "" {
-		return balances, nil
-	}
-	nextBal, err := c.SepaAccountBalances(account, allAccounts, newContinuationReference)
-	if err != nil {
-		return nil, err
-	}
-	balances = append(balances, nextBal...)
-	return balances, nil
+	return newPageIterator(c.pinTanDialog, c.config.MaxPages, continuationReference, c.init, fetch)
 }
 
 // Status returns information about open jobs to fetch from the institute.
 // If a continuationReference is present, the status information attached to it
-// will be fetched.
+// will be fetched. All following pages are fetched as well.
 func (c *Client) Status(from, to time.Time, maxEntries int, continuationReference string) ([]domain.StatusAcknowledgement, error) {
-	if err := c.init(); err != nil {
-		return nil, err
-	}
-	builder := segment.NewBuilder(c.pinTanDialog.SupportedSegments())
-	statusRequest, err := builder.StatusProtocolRequest(from, to, maxEntries, continuationReference)
-	if err != nil {
-		return nil, err
-	}
-	bankMessage, err := c.pinTanDialog.SendMessage(
-		message.NewHBCIMessage(c.hbciVersion, c.hbciVersion.TanProcess4Request(segment.IdentificationID), statusRequest),
-	)
-	if err != nil {
-		return nil, err
-	}
-	var statusAcknowledgements []domain.StatusAcknowledgement
-	statusResponses := bankMessage.FindSegments("HIPRO")
-	for _, seg := range statusResponses {
-		statusResponse := seg.(segment.StatusProtocolResponse)
-		statusAcknowledgements = append(statusAcknowledgements, statusResponse.Status())
+	return c.statusIter(from, to, maxEntries, continuationReference).All()
+}
+
+// StatusIter returns an iterator over the status information about open jobs.
+// Each page is fetched lazily within one dialog when calling Next on the
+// iterator.
+func (c *Client) StatusIter(from, to time.Time, maxEntries int) *PageIterator[domain.StatusAcknowledgement] {
+	return c.statusIter(from, to, maxEntries, "")
+}
+
+func (c *Client) statusIter(from, to time.Time, maxEntries int, continuationReference string) *PageIterator[domain.StatusAcknowledgement] {
+	fetch := func(continuationReference string) ([]domain.StatusAcknowledgement, string, error) {
+		builder := segment.NewBuilder(c.pinTanDialog.SupportedSegments())
+		statusRequest, err := builder.StatusProtocolRequest(from, to, maxEntries, continuationReference)
+		if err != nil {
+			return nil, "", err
+		}
+		bankMessage, err := c.pinTanDialog.Send(
+			message.NewHBCIMessage(c.hbciVersion, c.hbciVersion.TanProcess4Request(segment.IdentificationID), statusRequest),
+		)
+		if err != nil {
+			return nil, "", err
+		}
+		var statusAcknowledgements []domain.StatusAcknowledgement
+		statusResponses := bankMessage.FindSegments("HIPRO")
+		for _, seg := range statusResponses {
+			statusResponse, ok := seg.(segment.StatusProtocolResponse)
+			if !ok {
+				return nil, "", fmt.Errorf("malformed segment found with ID %q", "HIPRO")
+			}
+			statusAcknowledgements = append(statusAcknowledgements, statusResponse.Status())
+		}
+		return statusAcknowledgements, continuationReferenceFrom(bankMessage), nil
 	}
-	return statusAcknowledgements, nil
+	return newPageIterator(c.pinTanDialog, c.config.MaxPages, continuationReference, c.init, fetch)
 }
 
 // AnonymousClient wraps a Client and allows anonymous requests to bank
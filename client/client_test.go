@@ -71,15 +71,15 @@ func TestClientBalances(t *testing.T) {
 		ProductName: "Sichteinlagen",
 		Currency:    "EUR",
 		BookedBalance: domain.Balance{
-			Amount:           domain.Amount{Amount: 1000.15, Currency: "EUR"},
+			Amount:           domain.NewAmount(100015, "EUR"),
 			TransmissionDate: date,
 		},
 		EarmarkedBalance: &domain.Balance{
-			Amount:           domain.Amount{Amount: 20, Currency: "EUR"},
+			Amount:           domain.NewAmount(2000, "EUR"),
 			TransmissionDate: date,
 		},
-		CreditLimit:     &domain.Amount{Amount: 500, Currency: "EUR"},
-		AvailableAmount: &domain.Amount{Amount: 1499.85, Currency: "EUR"},
+		CreditLimit:     &domain.Amount{MinorUnits: 50000, Currency: "EUR"},
+		AvailableAmount: &domain.Amount{MinorUnits: 149985, Currency: "EUR"},
 	}
 
 	if len(balances) != 1 {
@@ -172,15 +172,15 @@ func TestClientSepaBalances(t *testing.T) {
 		ProductName: "Sichteinlagen",
 		Currency:    "EUR",
 		BookedBalance: domain.Balance{
-			Amount:           domain.Amount{Amount: 1000.15, Currency: "EUR"},
+			Amount:           domain.NewAmount(100015, "EUR"),
 			TransmissionDate: date,
 		},
 		EarmarkedBalance: &domain.Balance{
-			Amount:           domain.Amount{Amount: 20, Currency: "EUR"},
+			Amount:           domain.NewAmount(2000, "EUR"),
 			TransmissionDate: date,
 		},
-		CreditLimit:     &domain.Amount{Amount: 500, Currency: "EUR"},
-		AvailableAmount: &domain.Amount{Amount: 1499.85, Currency: "EUR"},
+		CreditLimit:     &domain.Amount{MinorUnits: 50000, Currency: "EUR"},
+		AvailableAmount: &domain.Amount{MinorUnits: 149985, Currency: "EUR"},
 	}
 
 	if len(balances) != 1 {
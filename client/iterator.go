@@ -0,0 +1,185 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mitch000001/go-hbci/internal"
+)
+
+// DefaultMaxPages defines the maximum number of pages fetched by a
+// PageIterator if Config.MaxPages is not set.
+const DefaultMaxPages = 100
+
+var (
+	// ErrTooManyPages is returned by a PageIterator when the bank institute
+	// sends more pages than allowed by Config.MaxPages
+	ErrTooManyPages = errors.New("maximum number of pages exceeded")
+	// ErrContinuationLoop is returned by a PageIterator when the bank
+	// institute sends a continuation reference which was already used
+	ErrContinuationLoop = errors.New("continuation reference repeated")
+)
+
+// pageFetcher fetches the page for the given continuation reference and
+// returns the reference for the next page, if any.
+type pageFetcher[T any] func(continuationReference string) (page []T, next string, err error)
+
+// pagingDialog defines the dialog used to fetch all pages.
+type pagingDialog interface {
+	Open() error
+	Close() error
+}
+
+func newPageIterator[T any](d pagingDialog, maxPages int, continuationReference string, init func() error, fetch pageFetcher[T]) *PageIterator[T] {
+	if maxPages <= 0 {
+		maxPages = DefaultMaxPages
+	}
+	return &PageIterator[T]{
+		dialog:    d,
+		init:      init,
+		fetch:     fetch,
+		maxPages:  maxPages,
+		reference: continuationReference,
+		seen:      make(map[string]bool),
+	}
+}
+
+// PageIterator iterates lazily over the pages of a business transaction the
+// bank institute answers with continuation references. All pages are fetched
+// within one dialog, which is opened with the first call to Next and ended
+// when the last page was fetched, an error occurred or Close is called.
+//
+// A PageIterator is used like a bufio.Scanner:
+//
+//	it := c.TransactionsIter(account, timeframe, false)
+//	defer it.Close()
+//	for it.Next() {
+//		for _, tx := range it.Page() {
+//			...
+//		}
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// Errors ending the dialog do not affect the fetched pages. They are reported
+// by Close.
+type PageIterator[T any] struct {
+	dialog    pagingDialog
+	init      func() error
+	fetch     pageFetcher[T]
+	maxPages  int
+	reference string
+	seen      map[string]bool
+	pages     int
+	page      []T
+	err       error
+	closeErr  error
+	open      bool
+	done      bool
+}
+
+// Next fetches the next page. It returns false when there are no more pages
+// or an error occurred. After Next returns false, Err returns the error, if
+// any.
+func (p *PageIterator[T]) Next() bool {
+	if p.done {
+		return false
+	}
+	if !p.open {
+		if err := p.init(); err != nil {
+			return p.fail(err)
+		}
+		if err := p.dialog.Open(); err != nil {
+			p.done = true
+			p.err = err
+			return false
+		}
+		p.open = true
+	}
+	if p.pages >= p.maxPages {
+		return p.fail(fmt.Errorf("%w: fetched %d pages", ErrTooManyPages, p.pages))
+	}
+	if p.reference != "" {
+		if p.seen[p.reference] {
+			return p.fail(fmt.Errorf("%w: %q", ErrContinuationLoop, p.reference))
+		}
+		p.seen[p.reference] = true
+	}
+	page, next, err := p.fetch(p.reference)
+	if err != nil {
+		return p.fail(err)
+	}
+	p.pages++
+	p.page = page
+	p.reference = next
+	if next == "" {
+		p.done = true
+		p.closeErr = p.closeDialog()
+	}
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *PageIterator[T]) Page() []T {
+	return p.page
+}
+
+// ContinuationReference returns the reference of the next page. It can be
+// used to resume fetching later on. It is empty if there are no more pages.
+func (p *PageIterator[T]) ContinuationReference() string {
+	return p.reference
+}
+
+// Err returns the first error that occurred while fetching pages. Errors
+// ending the dialog are returned by Close.
+func (p *PageIterator[T]) Err() error {
+	return p.err
+}
+
+// Close ends the underlying dialog if it is still open. If the dialog was
+// already ended after the last page, it returns the error of ending it. It is
+// safe to call Close multiple times.
+func (p *PageIterator[T]) Close() error {
+	p.done = true
+	if err := p.closeDialog(); err != nil {
+		p.closeErr = err
+	}
+	return p.closeErr
+}
+
+// All fetches all remaining pages and returns their entries. Errors while
+// fetching take precedence over errors ending the dialog.
+func (p *PageIterator[T]) All() ([]T, error) {
+	var all []T
+	for p.Next() {
+		all = append(all, p.Page()...)
+	}
+	closeErr := p.Close()
+	if err := p.Err(); err != nil {
+		return all, err
+	}
+	return all, closeErr
+}
+
+func (p *PageIterator[T]) fail(err error) bool {
+	p.done = true
+	p.err = err
+	p.page = nil
+	logErr(p.closeDialog())
+	return false
+}
+
+func (p *PageIterator[T]) closeDialog() error {
+	if !p.open {
+		return nil
+	}
+	p.open = false
+	return p.dialog.Close()
+}
+
+func logErr(err error) {
+	if err != nil {
+		internal.Info.Println(err)
+	}
+}
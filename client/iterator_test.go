@@ -0,0 +1,229 @@
+package client
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
+	https "github.com/mitch000001/go-hbci/transport/https"
+)
+
+type mockPagingDialog struct {
+	opened   int
+	closed   int
+	closeErr error
+}
+
+func (m *mockPagingDialog) Open() error {
+	m.opened++
+	return nil
+}
+
+func (m *mockPagingDialog) Close() error {
+	m.closed++
+	return m.closeErr
+}
+
+func TestPageIteratorAll(t *testing.T) {
+	pages := map[string]struct {
+		page []int
+		next string
+	}{
+		"":   {[]int{1, 2}, "p2"},
+		"p2": {[]int{3}, "p3"},
+		"p3": {[]int{4, 5}, ""},
+	}
+	var requested []string
+	fetch := func(ref string) ([]int, string, error) {
+		requested = append(requested, ref)
+		p := pages[ref]
+		return p.page, p.next, nil
+	}
+	d := &mockPagingDialog{}
+
+	it := newPageIterator(d, 0, "", func() error { return nil }, fetch)
+	all, err := it.All()
+
+	if err != nil {
+		t.Logf("Expected no error, got %T:%v\n", err, err)
+		t.Fail()
+	}
+	if !reflect.DeepEqual(all, []int{1, 2, 3, 4, 5}) {
+		t.Logf("Expected all pages to be returned, got %v\n", all)
+		t.Fail()
+	}
+	if !reflect.DeepEqual(requested, []string{"", "p2", "p3"}) {
+		t.Logf("Expected pages to be requested in order, got %q\n", requested)
+		t.Fail()
+	}
+	if d.opened != 1 || d.closed != 1 {
+		t.Logf("Expected dialog to be opened and closed once, got %d opens and %d closes\n", d.opened, d.closed)
+		t.Fail()
+	}
+}
+
+func TestPageIteratorCloseError(t *testing.T) {
+	closeErr := errors.New("dialog end failed")
+	fetch := func(ref string) ([]int, string, error) {
+		return []int{1, 2}, "", nil
+	}
+	d := &mockPagingDialog{closeErr: closeErr}
+
+	it := newPageIterator(d, 0, "", func() error { return nil }, fetch)
+
+	if !it.Next() {
+		t.Fatalf("Expected last page to be returned, got error %v\n", it.Err())
+	}
+	if !reflect.DeepEqual(it.Page(), []int{1, 2}) {
+		t.Logf("Expected last page to be returned, got %v\n", it.Page())
+		t.Fail()
+	}
+	if it.Next() {
+		t.Logf("Expected no more pages\n")
+		t.Fail()
+	}
+	if it.Err() != nil {
+		t.Logf("Expected no fetch error, got %T:%v\n", it.Err(), it.Err())
+		t.Fail()
+	}
+	if err := it.Close(); !errors.Is(err, closeErr) {
+		t.Logf("Expected Close to return the close error, got %T:%v\n", err, err)
+		t.Fail()
+	}
+
+	all, err := newPageIterator(&mockPagingDialog{closeErr: closeErr}, 0, "", func() error { return nil }, fetch).All()
+
+	if !reflect.DeepEqual(all, []int{1, 2}) || !errors.Is(err, closeErr) {
+		t.Logf("Expected all pages and the close error, got %v and %T:%v\n", all, err, err)
+		t.Fail()
+	}
+	if d.closed != 1 {
+		t.Logf("Expected dialog to be closed once, got %d\n", d.closed)
+		t.Fail()
+	}
+}
+
+func TestPageIteratorContinuationLoop(t *testing.T) {
+	fetch := func(ref string) ([]int, string, error) {
+		return []int{1}, "loop", nil
+	}
+	d := &mockPagingDialog{}
+
+	_, err := newPageIterator(d, 0, "", func() error { return nil }, fetch).All()
+
+	if !errors.Is(err, ErrContinuationLoop) {
+		t.Logf("Expected error to be ErrContinuationLoop, got %T:%v\n", err, err)
+		t.Fail()
+	}
+	if d.closed != 1 {
+		t.Logf("Expected dialog to be closed once, got %d\n", d.closed)
+		t.Fail()
+	}
+}
+
+func TestPageIteratorMaxPages(t *testing.T) {
+	page := 0
+	fetch := func(ref string) ([]int, string, error) {
+		page++
+		return []int{page}, string(rune('a' + page)), nil
+	}
+	d := &mockPagingDialog{}
+
+	it := newPageIterator(d, 3, "", func() error { return nil }, fetch)
+	var fetched int
+	for it.Next() {
+		fetched++
+	}
+
+	if !errors.Is(it.Err(), ErrTooManyPages) {
+		t.Logf("Expected error to be ErrTooManyPages, got %T:%v\n", it.Err(), it.Err())
+		t.Fail()
+	}
+	if fetched != 3 {
+		t.Logf("Expected 3 pages to be fetched, got %d\n", fetched)
+		t.Fail()
+	}
+}
+
+func TestClientSepaBalancesIter(t *testing.T) {
+	transport := &https.MockHTTPTransport{}
+	defer setMockHTTPTransport(transport)()
+
+	c := newTestClient()
+
+	syncResponse := encryptedTestMessage(
+		"abcde",
+		"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+		"HISYN:193:4:5+LRZYhZNbV2IBAAAd0?+VNqlkXrAQA'",
+		"HIBPA:2:2:+12+280:10000000+Bank Name+3+1+201:210:220+0'",
+		"HISALS:3:7:4+3+1'",
+	)
+	initResponse := encryptedTestMessage(
+		"abcde",
+		"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+	)
+	firstPage := encryptedTestMessage(
+		"abcde",
+		"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+		"HIRMS:3:2:3+3040::Es liegen weitere Informationen vor:REF1'",
+		"HISAL:4:7:3+DE88100000000100000000:ABCDEFG1HIJ:100000000::280:10000000+Sichteinlagen+EUR+C:1000,15:EUR:20150812'",
+	)
+	secondPage := encryptedTestMessage(
+		"abcde",
+		"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+		"HISAL:3:7:3+DE88100000000200000000:ABCDEFG1HIJ:200000000::280:10000000+Sichteinlagen+EUR+C:20,:EUR:20150812'",
+	)
+	dialogEndResponseMessage := encryptedTestMessage("abcde", "HIRMG:2:2:1+0020::Der Auftrag wurde ausgeführt'")
+
+	transport.SetResponsePayloads([][]byte{
+		syncResponse,
+		dialogEndResponseMessage,
+		initResponse,
+		firstPage,
+		secondPage,
+		dialogEndResponseMessage,
+	})
+
+	accountConn := domain.InternationalAccountConnection{
+		IBAN:      "DE88100000000100000000",
+		BIC:       "ABCDEFG1HIJ",
+		AccountID: "100000000",
+		BankID:    domain.BankID{CountryCode: 280, ID: "10000000"},
+	}
+
+	it := c.SepaAccountBalancesIter(accountConn, true)
+	defer it.Close()
+
+	var pages [][]domain.SepaAccountBalance
+	var references []string
+	for it.Next() {
+		pages = append(pages, it.Page())
+		references = append(references, it.ContinuationReference())
+	}
+	if err := it.Err(); err != nil {
+		t.Logf("Expected no error, got %T:%v\n", err, err)
+		t.Fail()
+	}
+
+	if len(pages) != 2 {
+		t.Logf("Expected 2 pages, got %d\n", len(pages))
+		t.FailNow()
+	}
+	if !reflect.DeepEqual(references, []string{"REF1", ""}) {
+		t.Logf("Expected continuation references %q, got %q\n", []string{"REF1", ""}, references)
+		t.Fail()
+	}
+	expectedAmounts := []domain.Amount{domain.NewAmount(100015, "EUR"), domain.NewAmount(2000, "EUR")}
+	for i, page := range pages {
+		if len(page) != 1 {
+			t.Logf("Expected page %d to contain 1 balance, got %d\n", i, len(page))
+			t.Fail()
+			continue
+		}
+		if page[0].BookedBalance.Amount != expectedAmounts[i] {
+			t.Logf("Expected balance of page %d to equal %s, got %s\n", i, expectedAmounts[i], page[0].BookedBalance.Amount)
+			t.Fail()
+		}
+	}
+}
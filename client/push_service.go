@@ -0,0 +1,123 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// PushServiceParameters returns the parameters of the push services offered
+// by the bank institute. It returns false if the bank institute does not
+// offer push services.
+//
+// The parameters contain the WebSocket URL to receive notifications from. See
+// package notification for parsing the received notifications.
+func (c *Client) PushServiceParameters() (domain.PushServiceParameters, bool, error) {
+	if err := c.init(); err != nil {
+		return domain.PushServiceParameters{}, false, err
+	}
+	params := c.pinTanDialog.BankParameterData.PushServiceParameters
+	if params == nil {
+		return domain.PushServiceParameters{}, false, nil
+	}
+	return *params, true, nil
+}
+
+// RegisterPushServices registers the client product for notifications about
+// the business transactions identified by segmentIDs, e.g. HKCAZ for new
+// account transactions. The returned registration contains the token to
+// establish WebSocket connections.
+func (c *Client) RegisterPushServices(product domain.PushServiceClientProduct, segmentIDs []string) (domain.PushServiceRegistration, error) {
+	if err := c.init(); err != nil {
+		return domain.PushServiceRegistration{}, err
+	}
+	bankMessage, err := c.pinTanDialog.SendMessage(
+		message.NewHBCIMessage(
+			c.hbciVersion,
+			c.hbciVersion.TanProcess4Request(segment.PushServiceRegistrationRequestID),
+			segment.NewPushServiceRegistrationRequestSegment(product, segmentIDs),
+		),
+	)
+	if err != nil {
+		return domain.PushServiceRegistration{}, fmt.Errorf("error registering push services: %w", err)
+	}
+	registration, ok := bankMessage.FindSegment(segment.PushServiceRegistrationResponseID).(*segment.PushServiceRegistrationResponseSegment)
+	if !ok {
+		return domain.PushServiceRegistration{}, fmt.Errorf("malformed response: expected %s segment", segment.PushServiceRegistrationResponseID)
+	}
+	return registration.Registration(), nil
+}
+
+// UpdatePushServices replaces the business transactions registered for the
+// client product identified by token with segmentIDs and refreshes the token.
+// Empty segmentIDs cancel the registration.
+func (c *Client) UpdatePushServices(token string, product domain.PushServiceClientProduct, segmentIDs []string) (domain.PushServiceRegistration, error) {
+	if err := c.init(); err != nil {
+		return domain.PushServiceRegistration{}, err
+	}
+	bankMessage, err := c.pinTanDialog.SendMessage(
+		message.NewHBCIMessage(
+			c.hbciVersion,
+			c.hbciVersion.TanProcess4Request(segment.PushServiceChangeRequestID),
+			segment.NewPushServiceChangeRequestSegment(token, product, segmentIDs),
+		),
+	)
+	if err != nil {
+		return domain.PushServiceRegistration{}, fmt.Errorf("error updating push services: %w", err)
+	}
+	registration, ok := bankMessage.FindSegment(segment.PushServiceChangeResponseID).(*segment.PushServiceChangeResponseSegment)
+	if !ok {
+		// the bank institute may omit the response if the registration
+		// was cancelled
+		return domain.PushServiceRegistration{}, nil
+	}
+	return registration.Registration(), nil
+}
+
+// PushServiceClientProducts returns all client products of the user
+// registered for push services.
+func (c *Client) PushServiceClientProducts() ([]domain.PushServiceClientProduct, error) {
+	fetch := func(continuationReference string) ([]domain.PushServiceClientProduct, string, error) {
+		bankMessage, err := c.pinTanDialog.Send(
+			message.NewHBCIMessage(
+				c.hbciVersion,
+				c.hbciVersion.TanProcess4Request(segment.PushServiceClientProductsRequestID),
+				segment.NewPushServiceClientProductsRequestSegment(0, continuationReference),
+			),
+		)
+		if err != nil {
+			return nil, "", err
+		}
+		var products []domain.PushServiceClientProduct
+		for _, seg := range bankMessage.FindSegments(segment.PushServiceClientProductsResponseID) {
+			product, ok := seg.(*segment.PushServiceClientProductResponseSegment)
+			if !ok {
+				return nil, "", fmt.Errorf("malformed segment found with ID %q", segment.PushServiceClientProductsResponseID)
+			}
+			products = append(products, product.ClientProduct())
+		}
+		return products, continuationReferenceFrom(bankMessage), nil
+	}
+	return newPageIterator(c.pinTanDialog, c.config.MaxPages, "", c.init, fetch).All()
+}
+
+// DeregisterPushServices deregisters the client system with the given name
+// from all push services.
+func (c *Client) DeregisterPushServices(clientSystemName string) error {
+	if err := c.init(); err != nil {
+		return err
+	}
+	_, err := c.pinTanDialog.SendMessage(
+		message.NewHBCIMessage(
+			c.hbciVersion,
+			c.hbciVersion.TanProcess4Request(segment.PushServiceDeregistrationRequestID),
+			segment.NewPushServiceDeregistrationRequestSegment(clientSystemName),
+		),
+	)
+	if err != nil {
+		return fmt.Errorf("error deregistering push services: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,357 @@
+// Command returncode_extractor extracts the code tables of chapter B from the
+// FinTS document "Rückmeldungscodes" and writes them as CSV. The output is the
+// catalog read by cmd/returncode_generator.
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/csv"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+
+	"github.com/pkg/errors"
+)
+
+var outputFile string
+
+func init() {
+	flag.StringVar(&outputFile, "o", "doc/FinTS_Rueckmeldungscodes_2021-07-07.csv", "the file to write the extracted codes to")
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() != 1 {
+		log.Fatal("Exactly one PDF file must be provided. Exiting...")
+		os.Exit(1)
+	}
+	data, err := os.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("Cannot read file: %q", flag.Arg(0))
+		os.Exit(1)
+	}
+	pages, err := extractPages(data)
+	if err != nil {
+		log.Fatalf("Error while extracting text: %v", err)
+		os.Exit(1)
+	}
+	entries := parseCodeTables(pages)
+	if len(entries) == 0 {
+		log.Fatal("No return codes found. Exiting...")
+		os.Exit(1)
+	}
+	file, err := os.Create(outputFile)
+	if err != nil {
+		log.Fatalf("Cannot create file: %q", err)
+		os.Exit(1)
+	}
+	defer file.Close()
+	if err := writeEntries(file, entries); err != nil {
+		log.Fatalf("Error while writing file: %q", err)
+		os.Exit(1)
+	}
+}
+
+// textItem is a piece of text positioned on a page
+type textItem struct {
+	x, y float64
+	text string
+}
+
+var (
+	streamPattern    = regexp.MustCompile(`(?s)stream\r?\n(.*?)\r?\nendstream`)
+	textBlockPattern = regexp.MustCompile(`(?s)BT(.*?)ET`)
+	matrixPattern    = regexp.MustCompile(`1 0 0 1 ([\d.\-]+) ([\d.\-]+) Tm`)
+	showTextPattern  = regexp.MustCompile(`(?s)\[(.*?)\]\s*TJ|(\((?:\\.|[^\\)])*\))\s*Tj`)
+	stringPattern    = regexp.MustCompile(`(?s)\((?:\\.|[^\\)])*\)`)
+)
+
+// extractPages returns the text items of all page content streams. It only
+// supports what the published documents use: flate compressed content
+// streams, absolute text positioning and single byte encoded fonts.
+func extractPages(data []byte) ([][]textItem, error) {
+	var pages [][]textItem
+	for _, match := range streamPattern.FindAllSubmatch(data, -1) {
+		reader, err := zlib.NewReader(bytes.NewReader(match[1]))
+		if err != nil {
+			continue
+		}
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			continue
+		}
+		if !bytes.Contains(content, []byte(" TJ")) && !bytes.Contains(content, []byte(" Tj")) {
+			continue
+		}
+		var items []textItem
+		for _, block := range textBlockPattern.FindAllSubmatch(content, -1) {
+			position := matrixPattern.FindSubmatch(block[1])
+			if position == nil {
+				continue
+			}
+			x, err := strconv.ParseFloat(string(position[1]), 64)
+			if err != nil {
+				return nil, errors.WithMessage(err, "parse x position")
+			}
+			y, err := strconv.ParseFloat(string(position[2]), 64)
+			if err != nil {
+				return nil, errors.WithMessage(err, "parse y position")
+			}
+			var text strings.Builder
+			for _, show := range showTextPattern.FindAllSubmatch(block[1], -1) {
+				operands := show[1]
+				if operands == nil {
+					operands = show[2]
+				}
+				for _, str := range stringPattern.FindAll(operands, -1) {
+					decoded, err := charmap.Windows1252.NewDecoder().Bytes(unescapeString(str[1 : len(str)-1]))
+					if err != nil {
+						return nil, errors.WithMessage(err, "decode string")
+					}
+					text.Write(decoded)
+				}
+			}
+			items = append(items, textItem{x: x, y: y, text: text.String()})
+		}
+		pages = append(pages, items)
+	}
+	return pages, nil
+}
+
+func unescapeString(str []byte) []byte {
+	var out []byte
+	for i := 0; i < len(str); i++ {
+		if str[i] != '\\' || i+1 == len(str) {
+			out = append(out, str[i])
+			continue
+		}
+		i++
+		switch c := str[i]; {
+		case c == 'n':
+			out = append(out, '\n')
+		case c == 'r':
+			out = append(out, '\r')
+		case c == 't':
+			out = append(out, '\t')
+		case c == 'b':
+			out = append(out, '\b')
+		case c == 'f':
+			out = append(out, '\f')
+		case c >= '0' && c <= '7':
+			j := i
+			for j < len(str) && j < i+3 && str[j] >= '0' && str[j] <= '7' {
+				j++
+			}
+			value, _ := strconv.ParseUint(string(str[i:j]), 8, 8)
+			out = append(out, byte(value))
+			i = j - 1
+		case c == '\n' || c == '\r':
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// The columns of the code tables, identified by their left boundary
+const (
+	columnCode = iota
+	columnText
+	columnReference
+	columnParameter
+	columnRemarks
+	columnExample
+	columnCount
+)
+
+var columnBoundaries = [columnCount]float64{0, 140, 248, 290, 342, 432}
+
+func column(x float64) int {
+	for i := columnCount - 1; i > 0; i-- {
+		if x >= columnBoundaries[i] {
+			return i
+		}
+	}
+	return columnCode
+}
+
+// line is a row of text on a page, split into the table columns
+type line struct {
+	y         float64
+	fragments []textItem
+	cells     [columnCount]string
+	heading   string
+}
+
+const (
+	pageHeaderTop    = 745
+	pageFooterBottom = 60
+	headingMargin    = 100
+)
+
+func pageLines(items []textItem) []*line {
+	sort.SliceStable(items, func(i, j int) bool { return items[i].y > items[j].y })
+	var lines []*line
+	for _, item := range items {
+		if item.y > pageHeaderTop || item.y < pageFooterBottom {
+			continue
+		}
+		var current *line
+		for _, l := range lines {
+			if l.y-item.y < 1 && item.y-l.y < 1 {
+				current = l
+				break
+			}
+		}
+		if current == nil {
+			current = &line{y: item.y}
+			lines = append(lines, current)
+		}
+		current.fragments = append(current.fragments, item)
+		if item.x < headingMargin && strings.TrimSpace(item.text) != "" {
+			current.heading = strings.TrimSpace(item.text)
+		}
+	}
+	for _, l := range lines {
+		sort.SliceStable(l.fragments, func(i, j int) bool { return l.fragments[i].x < l.fragments[j].x })
+		for _, fragment := range l.fragments {
+			l.cells[column(fragment.x)] += fragment.text
+		}
+	}
+	return lines
+}
+
+var (
+	codePattern       = regexp.MustCompile(`^\d{4}$`)
+	codeRangePattern  = regexp.MustCompile(`^\d{4}-$`)
+	tableHeaderPrefix = "Code"
+)
+
+// entry is a row of a code table
+type entry struct {
+	code    string
+	columns [columnCount][]string
+	isRange bool
+}
+
+// parseCodeTables collects the table rows of chapter B. A row starts with the
+// line containing its code; lines directly above the code belong to the row
+// as well, as the code is vertically centered in multi line cells.
+// Rows spanning a range of codes are institute specific and skipped.
+func parseCodeTables(pages [][]textItem) []*entry {
+	var entries []*entry
+	var current *entry
+	var leading []*line
+	inChapter := false
+	for _, items := range pages {
+		lines := pageLines(items)
+		for i := 0; i < len(lines); i++ {
+			l := lines[i]
+			switch {
+			case strings.HasPrefix(l.heading, "B."):
+				inChapter = true
+				current = nil
+				continue
+			case strings.HasPrefix(l.heading, "C."):
+				inChapter = false
+				current = nil
+				continue
+			}
+			if !inChapter {
+				continue
+			}
+			code := strings.ReplaceAll(strings.TrimSpace(l.cells[columnCode]), " ", "")
+			if code == tableHeaderPrefix && strings.Contains(l.cells[columnText], "Bedeutung") {
+				current = nil
+				continue
+			}
+			if code == "" && i+1 < len(lines) && lines[i+1].y >= l.y-3 && startsRow(lines[i+1]) {
+				leading = append(leading, l)
+				continue
+			}
+			rowLines := append(leading, l)
+			leading = nil
+			switch {
+			case codeRangePattern.MatchString(code):
+				current = &entry{code: code, isRange: true}
+			case codePattern.MatchString(code):
+				if current != nil && current.isRange && strings.TrimSpace(l.cells[columnText]) == "" {
+					break
+				}
+				current = &entry{code: code}
+				entries = append(entries, current)
+			}
+			if current == nil {
+				continue
+			}
+			for _, rowLine := range rowLines {
+				for c := columnText; c < columnCount; c++ {
+					current.columns[c] = append(current.columns[c], rowLine.cells[c])
+				}
+			}
+		}
+	}
+	return entries
+}
+
+func startsRow(l *line) bool {
+	code := strings.ReplaceAll(strings.TrimSpace(l.cells[columnCode]), " ", "")
+	return codePattern.MatchString(code) || codeRangePattern.MatchString(code)
+}
+
+// joinLines joins the lines of a cell, removing hyphenation at line breaks
+func joinLines(lines []string) string {
+	var out string
+	for _, l := range lines {
+		l = strings.Join(strings.Fields(l), " ")
+		if l == "" {
+			continue
+		}
+		switch {
+		case out == "":
+			out = l
+		case strings.HasSuffix(out, "-") && !strings.HasSuffix(out, " -"):
+			before, _ := utf8.DecodeLastRuneInString(strings.TrimSuffix(out, "-"))
+			next, _ := utf8.DecodeRuneInString(l)
+			if unicode.IsLower(before) && unicode.IsLower(next) {
+				out = strings.TrimSuffix(out, "-") + l
+			} else {
+				out += l
+			}
+		default:
+			out += " " + l
+		}
+	}
+	return strings.TrimSuffix(out, "-")
+}
+
+func writeEntries(writer io.Writer, entries []*entry) error {
+	csvWriter := csv.NewWriter(writer)
+	csvWriter.Comma = ';'
+	err := csvWriter.Write([]string{"Code", "Bedeutung", "Bezug", "Parameter", "Anmerkungen", "Beispiel"})
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].code < entries[j].code })
+	for _, e := range entries {
+		record := []string{e.code}
+		for c := columnText; c < columnCount; c++ {
+			record = append(record, joinLines(e.columns[c]))
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	outputFile  string
+	catalogFile string
+)
+
+func init() {
+	flag.StringVar(&outputFile, "o", "domain/return_code_data.go", "the file to write the generated source to")
+	flag.StringVar(&catalogFile, "catalog", "doc/FinTS_Rueckmeldungscodes_2021-07-07.csv", "the code catalog extracted by cmd/returncode_extractor")
+}
+
+var categories = map[string]string{
+	"success": "ReturnCodeCategorySuccess",
+	"note":    "ReturnCodeCategoryNote",
+	"warning": "ReturnCodeCategoryWarning",
+	"error":   "ReturnCodeCategoryError",
+}
+
+var actions = map[string]string{
+	"none":           "ActionNone",
+	"continue":       "ActionContinue",
+	"provide_tan":    "ActionProvideTAN",
+	"reenter_pin":    "ActionReenterPIN",
+	"reenter_tan":    "ActionReenterTAN",
+	"do_not_retry":   "ActionDoNotRetry",
+	"retry_later":    "ActionRetryLater",
+	"restart_dialog": "ActionRestartDialog",
+	"fix_request":    "ActionFixRequest",
+	"contact_bank":   "ActionContactBank",
+	"status_unknown": "ActionStatusUnknown",
+}
+
+var errs = map[string]string{
+	"":                  "nil",
+	"pin_wrong":         "ErrPinWrong",
+	"account_locked":    "ErrAccountLocked",
+	"tan_required":      "ErrTanRequired",
+	"tan_wrong":         "ErrTanWrong",
+	"dialog_aborted":    "ErrDialogAborted",
+	"bank_unavailable":  "ErrBankUnavailable",
+	"message_malformed": "ErrMessageMalformed",
+	"status_unknown":    "ErrStatusUnknown",
+}
+
+type returnCode struct {
+	Code     int
+	Name     string
+	Category string
+	Action   string
+	Err      string
+	Text     string
+	Meaning  string
+}
+
+func main() {
+	flag.Parse()
+	annotationFiles := flag.Args()
+	if len(annotationFiles) == 0 {
+		log.Fatal("No file provided. Exiting...")
+		os.Exit(1)
+	}
+
+	file, err := os.Open(catalogFile)
+	if err != nil {
+		log.Fatalf("Cannot open file: %q", catalogFile)
+		os.Exit(1)
+	}
+	returnCodes, err := parseCatalog(file)
+	if err != nil {
+		log.Fatalf("Parse error: %q", err)
+		os.Exit(1)
+	}
+	for _, annotations := range annotationFiles {
+		file, err := os.Open(annotations)
+		if err != nil {
+			log.Fatalf("Cannot open file: %q", annotations)
+			os.Exit(1)
+		}
+		codes, err := parseReturnCodes(file)
+		if err != nil {
+			log.Fatalf("Parse error: %q", err)
+			os.Exit(1)
+		}
+		returnCodes, err = annotate(returnCodes, codes)
+		if err != nil {
+			log.Fatalf("Annotation error: %q", err)
+			os.Exit(1)
+		}
+	}
+	sort.Slice(returnCodes, func(i, j int) bool { return returnCodes[i].Code < returnCodes[j].Code })
+	data, err := writeDataToGoFile(returnCodes)
+	if err != nil {
+		log.Fatalf("Error while writing generated source: %v", err)
+		os.Exit(1)
+	}
+	goFile, err := os.Create(outputFile)
+	if err != nil {
+		log.Fatalf("Cannot create file: %q", err)
+		os.Exit(1)
+	}
+	_, err = io.Copy(goFile, data)
+	if err != nil {
+		log.Fatalf("Error while writing file: %q", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func parseReturnCodes(reader io.Reader) ([]returnCode, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma = ';'
+	csvReader.TrimLeadingSpace = true
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, errors.WithMessage(err, "read CSV file")
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	var returnCodes []returnCode
+	for _, record := range records[1:] {
+		if len(record) != 7 {
+			return nil, fmt.Errorf("malformed record: %q", record)
+		}
+		code, err := strconv.Atoi(record[0])
+		if err != nil {
+			return nil, errors.WithMessage(err, "parse code")
+		}
+		category, ok := categories[record[2]]
+		if !ok {
+			return nil, fmt.Errorf("unknown category %q for code %04d", record[2], code)
+		}
+		action, ok := actions[record[3]]
+		if !ok {
+			return nil, fmt.Errorf("unknown action %q for code %04d", record[3], code)
+		}
+		errName, ok := errs[record[4]]
+		if !ok {
+			return nil, fmt.Errorf("unknown error %q for code %04d", record[4], code)
+		}
+		returnCodes = append(returnCodes, returnCode{
+			Code:     code,
+			Name:     strings.TrimSpace(record[1]),
+			Category: category,
+			Action:   action,
+			Err:      errName,
+			Text:     strings.TrimSpace(record[5]),
+			Meaning:  strings.TrimSpace(record[6]),
+		})
+	}
+	return returnCodes, nil
+}
+
+// parseCatalog reads the codes of the specification. Codes listed with
+// several texts are represented by their first, generic, entry.
+func parseCatalog(reader io.Reader) ([]returnCode, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma = ';'
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, errors.WithMessage(err, "read CSV file")
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	var returnCodes []returnCode
+	seen := make(map[int]bool)
+	for _, record := range records[1:] {
+		code, err := strconv.Atoi(record[0])
+		if err != nil {
+			return nil, errors.WithMessage(err, "parse code")
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		category, ok := categoryForCode(code)
+		if !ok {
+			return nil, fmt.Errorf("code %04d outside of the defined ranges", code)
+		}
+		returnCodes = append(returnCodes, returnCode{
+			Code:     code,
+			Category: category,
+			Action:   actions["none"],
+			Err:      errs[""],
+			Text:     record[1],
+		})
+	}
+	return returnCodes, nil
+}
+
+// annotate adds names, actions and errors to the catalog codes. Annotated
+// codes missing from the catalog are added, their text must then be provided
+// by the annotation.
+func annotate(catalog []returnCode, annotations []returnCode) ([]returnCode, error) {
+	indices := make(map[int]int)
+	for i, code := range catalog {
+		indices[code.Code] = i
+	}
+	for _, annotation := range annotations {
+		if category, _ := categoryForCode(annotation.Code); category != annotation.Category {
+			return nil, fmt.Errorf("category %s of code %04d does not match its range", annotation.Category, annotation.Code)
+		}
+		i, ok := indices[annotation.Code]
+		if !ok {
+			if annotation.Text == "" {
+				return nil, fmt.Errorf("code %04d is not in the catalog and has no text", annotation.Code)
+			}
+			indices[annotation.Code] = len(catalog)
+			catalog = append(catalog, annotation)
+			continue
+		}
+		if annotation.Text != "" {
+			return nil, fmt.Errorf("code %04d is in the catalog and must not override its text", annotation.Code)
+		}
+		annotation.Text = catalog[i].Text
+		catalog[i] = annotation
+	}
+	return catalog, nil
+}
+
+func categoryForCode(code int) (string, bool) {
+	switch {
+	case code >= 0 && code < 1000:
+		return categories["success"], true
+	case code >= 1000 && code < 2000:
+		return categories["note"], true
+	case code >= 3000 && code < 4000:
+		return categories["warning"], true
+	case code >= 9000 && code < 10000:
+		return categories["error"], true
+	default:
+		return "", false
+	}
+}
+
+func writeDataToGoFile(data []returnCode) (io.Reader, error) {
+	t, err := template.New("return_code_data").Parse(dataTemplate)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error while parsing template")
+	}
+	var buf bytes.Buffer
+	err = t.Execute(&buf, data)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error while executing template")
+	}
+	formattedBytes, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, errors.WithMessage(err, "error while formatting source file")
+	}
+	return bytes.NewReader(formattedBytes), nil
+}
+
+const dataTemplate = `// Code generated by cmd/returncode_generator DO NOT EDIT.
+
+package domain
+
+// These represent the return codes of the FinTS return code catalog
+const (
+	{{range $element := .}}{{if .Name}}ReturnCode{{.Name}} = {{.Code}}
+	{{end}}{{end}}
+)
+
+var returnCodes = map[int]ReturnCode{
+	{{range $element := .}}{{template "code" .}}: {
+		Code: {{template "code" .}},
+		Name: "{{.Name}}",
+		Category: {{.Category}},
+		Action: {{.Action}},
+		Text: {{.Text | printf "%q"}},
+		Meaning: {{.Meaning | printf "%q"}},
+		Err: {{.Err}},
+	},
+	{{end}}
+}
+
+var returnCodeOrder = []int{
+	{{range $element := .}}{{template "code" .}},
+	{{end}}
+}
+
+{{define "code"}}{{if .Name}}ReturnCode{{.Name}}{{else}}{{.Code}}{{end}}{{end}}
+`
@@ -18,17 +18,21 @@ import (
 var segmentName string
 var segmentInterface string
 var segmentVersions segmentVersionsFlag
+var groupName string
+var groupType string
 
 func init() {
 	flag.StringVar(&segmentName, "segment", "", "'MyAwesomeSegment'")
 	flag.StringVar(&segmentInterface, "segment_interface", "Segment", "'MyAwesomeInterface'")
 	flag.Var(&segmentVersions, "segment_versions", "'MyAwesomeSegmentVersion1:1,MyAwesomeSegmentVersion2:2'")
+	flag.StringVar(&groupName, "group", "", "'MyAwesomeGroupDataElement'")
+	flag.StringVar(&groupType, "group_type", "", "'myAwesomeGroupDataElementGDEG'")
 }
 
 func main() {
 	flag.Parse()
-	if segmentName == "" {
-		fmt.Printf("You must provide a segment to generate the unmarshaler\n")
+	if segmentName == "" && groupName == "" {
+		fmt.Printf("You must provide a segment or a group to generate the unmarshaler\n")
 		os.Exit(1)
 	}
 	filename := os.Getenv("GOFILE")
@@ -44,7 +48,11 @@ func main() {
 		Versions:      segmentVersions,
 	}
 	var generated io.Reader
-	if len(segmentVersions) != 0 {
+	if groupName != "" {
+		group := generator.GroupDataElementIdentifier{Name: groupName, TypeName: groupType}
+		groupGenerator := generator.NewGroupDataElementGroupGenerator(group, packageName, fileSet, f)
+		generated, err = groupGenerator.Generate()
+	} else if len(segmentVersions) != 0 {
 		segmentGenerator := generator.NewVersionedSegmentUnmarshaler(segment, packageName, fileSet, f)
 		generated, err = segmentGenerator.Generate()
 	} else {
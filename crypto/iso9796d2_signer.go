@@ -0,0 +1,472 @@
+package crypto
+
+import (
+	"bytes"
+	stdcrypto "crypto"
+	"fmt"
+	"hash"
+
+	// register RIPEMD-160 for use with the signer
+	_ "golang.org/x/crypto/ripemd160"
+)
+
+// Trailer values as defined in ISO 9796-2
+const (
+	TrailerImplicit   = 0xBC
+	TrailerRIPEMD160  = 0x31CC
+	TrailerRIPEMD128  = 0x32CC
+	TrailerSHA1       = 0x33CC
+	TrailerSHA256     = 0x34CC
+	TrailerSHA512     = 0x35CC
+	TrailerSHA384     = 0x36CC
+	TrailerWhirlpool  = 0x37CC
+	TrailerSHA224     = 0x38CC
+	TrailerSHA512_224 = 0x39CC
+	TrailerSHA512_256 = 0x3aCC
+)
+
+var isoTrailers = map[stdcrypto.Hash]int{
+	stdcrypto.RIPEMD160:  TrailerRIPEMD160,
+	stdcrypto.SHA1:       TrailerSHA1,
+	stdcrypto.SHA224:     TrailerSHA224,
+	stdcrypto.SHA256:     TrailerSHA256,
+	stdcrypto.SHA384:     TrailerSHA384,
+	stdcrypto.SHA512:     TrailerSHA512,
+	stdcrypto.SHA512_224: TrailerSHA512_224,
+	stdcrypto.SHA512_256: TrailerSHA512_256,
+}
+
+/**
+* ISO9796-2 - mechanism using a hash function with recovery (scheme 1)
+ */
+// implements SignerWithRecovery
+type ISO9796d2Signer struct {
+	digestType stdcrypto.Hash
+	digest     hash.Hash
+	cipher     cipherEngine
+
+	trailer          int
+	keyBits          int
+	block            []byte
+	mBuf             []byte
+	messageLength    int
+	fullMessage      bool
+	recoveredMessage []byte
+
+	preSig   []byte
+	preBlock []byte
+}
+
+// NewISO9796d2Signer creates a new ISO 9796-2 signer for cipher and the
+// digest given by digestType. If implicit is true the implicit trailer is
+// used, otherwise the trailer identifying the digest is added.
+//
+// The digest must be available, i.e. registered by importing its package.
+func NewISO9796d2Signer(cipher cipherEngine, digestType stdcrypto.Hash, implicit bool) (*ISO9796d2Signer, error) {
+	if !digestType.Available() {
+		return nil, fmt.Errorf("digest not available: %v", digestType)
+	}
+	signer := &ISO9796d2Signer{
+		digestType: digestType,
+		digest:     digestType.New(),
+		cipher:     cipher,
+	}
+	if implicit {
+		signer.trailer = TrailerImplicit
+	} else {
+		trailer, ok := isoTrailers[digestType]
+		if !ok {
+			return nil, fmt.Errorf("no valid trailer for digest: %v", digestType)
+		}
+		signer.trailer = trailer
+	}
+	return signer, nil
+}
+
+// Init initializes the signer for signing or verification with key
+func (i *ISO9796d2Signer) Init(forSigning bool, key RSAKeyParameters) {
+	i.cipher.Init(forSigning, key)
+
+	i.keyBits = key.Modulus().BitLen()
+
+	i.block = make([]byte, (i.keyBits+7)/8)
+
+	if i.trailer == TrailerImplicit {
+		i.mBuf = make([]byte, len(i.block)-i.digest.Size()-2)
+	} else {
+		i.mBuf = make([]byte, len(i.block)-i.digest.Size()-3)
+	}
+
+	i.Reset()
+}
+
+/**
+* compare two byte arrays - constant time
+ */
+func (i *ISO9796d2Signer) isSameAs(a, b []byte) bool {
+	isOkay := true
+
+	if i.messageLength > len(i.mBuf) {
+		if len(i.mBuf) > len(b) {
+			isOkay = false
+		}
+
+		for j := 0; j != len(i.mBuf) && j < len(b); j++ {
+			if a[j] != b[j] {
+				isOkay = false
+			}
+		}
+	} else {
+		if i.messageLength != len(b) {
+			isOkay = false
+		}
+
+		for j := 0; j != len(b) && j < len(a); j++ {
+			if a[j] != b[j] {
+				isOkay = false
+			}
+		}
+	}
+
+	return isOkay
+}
+
+/**
+* clear possible sensitive data
+ */
+func clearBlock(block []byte) {
+	for j := range block {
+		block[j] = 0
+	}
+}
+
+// UpdateWithRecoveredMessage recovers the message part contained within
+// signature and updates the digest with it
+func (i *ISO9796d2Signer) UpdateWithRecoveredMessage(signature []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("malformed signature: %v", r)
+		}
+	}()
+	block := i.cipher.ProcessBlock(signature, 0, len(signature))
+
+	if ((block[0] & 0xC0) ^ 0x40) != 0 {
+		return fmt.Errorf("malformed signature")
+	}
+
+	if ((block[len(block)-1] & 0xF) ^ 0xC) != 0 {
+		return fmt.Errorf("malformed signature")
+	}
+
+	delta, err := i.trailerLength(block)
+	if err != nil {
+		return err
+	}
+
+	//
+	// find out how much padding we've got
+	//
+	mStart := i.messageStart(block)
+
+	off := len(block) - delta - i.digest.Size()
+
+	//
+	// there must be at least one byte of message string
+	//
+	if (off - mStart) <= 0 {
+		return fmt.Errorf("malformed block")
+	}
+
+	//
+	// if we contain the whole message as well, check the hash of that.
+	//
+	i.fullMessage = (block[0] & 0x20) == 0
+	i.recoveredMessage = make([]byte, off-mStart)
+	copy(i.recoveredMessage, block[mStart:off])
+
+	i.preSig = signature
+	i.preBlock = block
+
+	i.digest.Write(i.recoveredMessage)
+	i.messageLength = len(i.recoveredMessage)
+	copy(i.mBuf, i.recoveredMessage)
+	return nil
+}
+
+// Update updates the internal digest with in
+func (i *ISO9796d2Signer) Update(in []byte) {
+	for len(in) > 0 && i.messageLength < len(i.mBuf) {
+		i.digest.Write(in[:1])
+		i.mBuf[i.messageLength] = in[0]
+		i.messageLength++
+		in = in[1:]
+	}
+
+	i.digest.Write(in)
+	i.messageLength += len(in)
+}
+
+// Reset resets the signer for a new signature
+func (i *ISO9796d2Signer) Reset() {
+	i.digest.Reset()
+	i.messageLength = 0
+	clearBlock(i.mBuf)
+
+	if i.recoveredMessage != nil {
+		clearBlock(i.recoveredMessage)
+	}
+
+	i.recoveredMessage = nil
+	i.fullMessage = false
+
+	if i.preSig != nil {
+		i.preSig = nil
+		clearBlock(i.preBlock)
+		i.preBlock = nil
+	}
+}
+
+// GenerateSignature generates a signature for the message passed via
+// Update
+func (i *ISO9796d2Signer) GenerateSignature() (signature []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("error generating signature: %v", r)
+		}
+	}()
+	digSize := i.digest.Size()
+
+	t := 0
+	delta := 0
+
+	if i.trailer == TrailerImplicit {
+		t = 8
+		delta = len(i.block) - digSize - 1
+		copy(i.block[delta:], i.digest.Sum(nil))
+		i.block[len(i.block)-1] = byte(TrailerImplicit)
+	} else {
+		t = 16
+		delta = len(i.block) - digSize - 2
+		copy(i.block[delta:], i.digest.Sum(nil))
+		i.block[len(i.block)-2] = byte(i.trailer >> 8)
+		i.block[len(i.block)-1] = byte(i.trailer)
+	}
+
+	var header byte
+	x := (digSize+i.messageLength)*8 + t + 4 - i.keyBits
+
+	if x > 0 {
+		mR := i.messageLength - ((x + 7) / 8)
+		header = 0x60
+
+		delta -= mR
+
+		copy(i.block[delta:], i.mBuf[:mR])
+
+		i.recoveredMessage = make([]byte, mR)
+	} else {
+		header = 0x40
+		delta -= i.messageLength
+
+		copy(i.block[delta:], i.mBuf[:i.messageLength])
+
+		i.recoveredMessage = make([]byte, i.messageLength)
+	}
+
+	if (delta - 1) > 0 {
+		for j := delta - 1; j != 0; j-- {
+			i.block[j] = 0xbb
+		}
+		i.block[delta-1] ^= 0x01
+		i.block[0] = 0x0b
+		i.block[0] |= header
+	} else {
+		i.block[0] = 0x0a
+		i.block[0] |= header
+	}
+
+	signature = i.cipher.ProcessBlock(i.block, 0, len(i.block))
+
+	i.fullMessage = (header & 0x20) == 0
+	copy(i.recoveredMessage, i.mBuf)
+
+	i.messageLength = 0
+
+	clearBlock(i.mBuf)
+	clearBlock(i.block)
+	i.digest.Reset()
+
+	return signature, nil
+}
+
+// VerifySignature returns true if signature is valid for the message passed
+// via Update or UpdateWithRecoveredMessage. It returns an error if
+// UpdateWithRecoveredMessage was called with a different signature.
+func (i *ISO9796d2Signer) VerifySignature(signature []byte) (bool, error) {
+	var block []byte
+
+	if i.preSig == nil {
+		var ok bool
+		block, ok = i.processBlock(signature)
+		if !ok {
+			return false, nil
+		}
+	} else {
+		if !bytes.Equal(i.preSig, signature) {
+			i.Reset()
+			return false, fmt.Errorf("UpdateWithRecoveredMessage called on different signature")
+		}
+
+		block = i.preBlock
+
+		i.preSig = nil
+		i.preBlock = nil
+	}
+
+	if ((block[0] & 0xC0) ^ 0x40) != 0 {
+		return i.returnFalse(block), nil
+	}
+
+	if ((block[len(block)-1] & 0xF) ^ 0xC) != 0 {
+		return i.returnFalse(block), nil
+	}
+
+	delta, err := i.trailerLength(block)
+	if err != nil {
+		return i.returnFalse(block), nil
+	}
+
+	//
+	// find out how much padding we've got
+	//
+	mStart := i.messageStart(block)
+
+	//
+	// check the hashes
+	//
+	hash := make([]byte, i.digest.Size())
+
+	off := len(block) - delta - len(hash)
+
+	//
+	// there must be at least one byte of message string
+	//
+	if (off - mStart) <= 0 {
+		return i.returnFalse(block), nil
+	}
+
+	//
+	// if we contain the whole message as well, check the hash of that.
+	//
+	if (block[0] & 0x20) == 0 {
+		i.fullMessage = true
+
+		// check right number of bytes passed in.
+		if i.messageLength > off-mStart {
+			return i.returnFalse(block), nil
+		}
+
+		i.digest.Reset()
+		i.digest.Write(block[mStart:off])
+		hash = i.digest.Sum(hash[:0])
+	} else {
+		i.fullMessage = false
+
+		hash = i.digest.Sum(hash[:0])
+	}
+
+	isOkay := true
+
+	for j := 0; j != len(hash); j++ {
+		block[off+j] ^= hash[j]
+		if block[off+j] != 0 {
+			isOkay = false
+		}
+	}
+
+	if !isOkay {
+		return i.returnFalse(block), nil
+	}
+
+	i.recoveredMessage = make([]byte, off-mStart)
+	copy(i.recoveredMessage, block[mStart:off])
+
+	//
+	// if they've input a message check what we've recovered against
+	// what was input.
+	//
+	if i.messageLength != 0 {
+		if !i.isSameAs(i.mBuf, i.recoveredMessage) {
+			return i.returnFalse(block), nil
+		}
+	}
+
+	clearBlock(i.mBuf)
+	clearBlock(block)
+
+	i.messageLength = 0
+	i.digest.Reset()
+
+	return true, nil
+}
+
+// processBlock applies the cipher to signature and reports whether this
+// succeeded
+func (i *ISO9796d2Signer) processBlock(signature []byte) (block []byte, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			block, ok = nil, false
+		}
+	}()
+	return i.cipher.ProcessBlock(signature, 0, len(signature)), true
+}
+
+// trailerLength returns the length of the trailer of block and checks an
+// explicit trailer against the digest of the signer
+func (i *ISO9796d2Signer) trailerLength(block []byte) (int, error) {
+	if ((block[len(block)-1] & 0xFF) ^ 0xBC) == 0 {
+		return 1, nil
+	}
+	sigTrail := int(block[len(block)-2])<<8 | int(block[len(block)-1])
+	trailer, ok := isoTrailers[i.digestType]
+	if !ok {
+		return 0, fmt.Errorf("unrecognised hash in signature")
+	}
+	if sigTrail != trailer {
+		return 0, fmt.Errorf("signer initialised with wrong digest for trailer %X", sigTrail)
+	}
+	return 2, nil
+}
+
+// messageStart returns the index of the first message byte after the
+// padding of block
+func (i *ISO9796d2Signer) messageStart(block []byte) int {
+	mStart := 0
+	for mStart = 0; mStart != len(block); mStart++ {
+		if ((block[mStart] & 0x0f) ^ 0x0a) == 0 {
+			break
+		}
+	}
+	return mStart + 1
+}
+
+func (i *ISO9796d2Signer) returnFalse(block []byte) bool {
+	i.messageLength = 0
+	i.digest.Reset()
+	clearBlock(i.mBuf)
+	clearBlock(block)
+
+	return false
+}
+
+// HasFullMessage returns true if the full message was recoverable from the
+// signature, false otherwise
+func (i *ISO9796d2Signer) HasFullMessage() bool {
+	return i.fullMessage
+}
+
+// RecoveredMessage returns a reference to what message was recovered (if
+// any)
+func (i *ISO9796d2Signer) RecoveredMessage() []byte {
+	return i.recoveredMessage
+}
@@ -0,0 +1,273 @@
+package crypto
+
+import (
+	stdcrypto "crypto"
+	"testing"
+
+	_ "crypto/sha256"
+)
+
+func TestISO9796d2Signer(t *testing.T) {
+	doSignerTest5(t)
+	doSignerTestSHA256(t)
+	doSignerTestSHA256Partial(t)
+	doSignerTestDifferentRecoveredSignature(t)
+}
+
+func newTestSigner(t *testing.T, digestType stdcrypto.Hash, implicit bool) *ISO9796d2Signer {
+	eng, err := NewISO9796d2Signer(new(RSAEngine), digestType, implicit)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	return eng
+}
+
+func verifySignature(t *testing.T, eng *ISO9796d2Signer, signature []byte) bool {
+	ok, err := eng.VerifySignature(signature)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	return ok
+}
+
+func doSignerTest5(t *testing.T) {
+	pubParameters := NewRSAKeyParameters(false, mod3, pub3)
+	privParameters := NewRSAKeyParameters(true, mod3, pri3)
+
+	//
+	// ISO 9796-2 - Signing
+	//
+	eng := newTestSigner(t, stdcrypto.RIPEMD160, true)
+
+	eng.Init(true, privParameters)
+
+	eng.Update(msg5[:1])
+	eng.Update(msg5[1:])
+
+	data, err := eng.GenerateSignature()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	recovered := make([]byte, len(eng.RecoveredMessage()))
+	copy(recovered, eng.RecoveredMessage())
+
+	eng.Init(false, pubParameters)
+
+	if !isSameAs(sig5, 0, data) {
+		t.Logf("failed ISO9796-2 generation Test 5")
+		t.Fail()
+	}
+
+	eng.Update(msg5[:1])
+	eng.Update(msg5[1:])
+
+	if !verifySignature(t, eng, sig5) {
+		t.Logf("failed ISO9796-2 verify Test 5")
+		t.Fail()
+	}
+
+	if eng.HasFullMessage() {
+		t.Logf("fullMessage true - Test 5")
+		t.Fail()
+	}
+
+	if !startsWith(msg5, eng.RecoveredMessage()) {
+		t.Logf("failed ISO9796-2 partial recovered message Test 5")
+		t.Fail()
+	}
+
+	length := len(eng.RecoveredMessage())
+
+	if length >= len(msg5) {
+		t.Logf("Test 5 recovered message too long")
+		t.Fail()
+	}
+
+	eng = newTestSigner(t, stdcrypto.RIPEMD160, true)
+
+	eng.Init(false, pubParameters)
+
+	err = eng.UpdateWithRecoveredMessage(sig5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	if !startsWith(msg5, eng.RecoveredMessage()) {
+		t.Logf("failed ISO9796-2 updateWithRecovered partial recovered message Test 5")
+		t.Fail()
+	}
+
+	if !isSameAs(recovered, 0, eng.RecoveredMessage()) {
+		t.Logf("failed ISO9796-2 updateWithRecovered partial recovered message Test 5 recovery check")
+		t.Fail()
+	}
+
+	if eng.HasFullMessage() {
+		t.Logf("fullMessage updateWithRecovered true - Test 5")
+		t.Fail()
+	}
+
+	eng.Update(msg5[length:])
+
+	if !verifySignature(t, eng, sig5) {
+		t.Logf("failed ISO9796-2 verify Test 5")
+		t.Fail()
+	}
+
+	if eng.HasFullMessage() {
+		t.Logf("fullMessage updateWithRecovered true - Test 5")
+		t.Fail()
+	}
+
+	// should fail
+	err = eng.UpdateWithRecoveredMessage(sig5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	eng.Update(msg5)
+
+	if verifySignature(t, eng, sig5) {
+		t.Logf("failed ISO9796-2 updateWithRecovered verify fail Test 5")
+		t.Fail()
+	}
+}
+
+func doSignerTestSHA256(t *testing.T) {
+	pubParameters := NewRSAKeyParameters(false, mod3, pub3)
+	privParameters := NewRSAKeyParameters(true, mod3, pri3)
+	msg := []byte("abc")
+
+	//
+	// ISO 9796-2 - Signing with explicit trailer and full message recovery
+	//
+	eng := newTestSigner(t, stdcrypto.SHA256, false)
+
+	eng.Init(true, privParameters)
+
+	eng.Update(msg)
+
+	data, err := eng.GenerateSignature()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	if !eng.HasFullMessage() {
+		t.Logf("full message flag false - SHA256 Test")
+		t.Fail()
+	}
+
+	eng.Init(false, pubParameters)
+
+	if !verifySignature(t, eng, data) {
+		t.Logf("failed ISO9796-2 verify and recover SHA256 Test")
+		t.Fail()
+	}
+
+	if !isSameAs(eng.RecoveredMessage(), 0, msg) {
+		t.Logf("failed ISO9796-2 recovered message SHA256 Test")
+		t.Fail()
+	}
+
+	eng.Update([]byte("abd"))
+
+	if verifySignature(t, eng, data) {
+		t.Logf("failed ISO9796-2 verify with different message SHA256 Test")
+		t.Fail()
+	}
+
+	eng = newTestSigner(t, stdcrypto.RIPEMD160, false)
+
+	eng.Init(false, pubParameters)
+
+	err = eng.UpdateWithRecoveredMessage(data)
+	if err == nil {
+		t.Logf("Expected error for wrong digest trailer")
+		t.Fail()
+	}
+}
+
+func doSignerTestSHA256Partial(t *testing.T) {
+	pubParameters := NewRSAKeyParameters(false, mod3, pub3)
+	privParameters := NewRSAKeyParameters(true, mod3, pri3)
+
+	//
+	// ISO 9796-2 - Signing with explicit trailer and partial message recovery
+	//
+	eng := newTestSigner(t, stdcrypto.SHA256, false)
+
+	eng.Init(true, privParameters)
+
+	eng.Update(msg5)
+
+	data, err := eng.GenerateSignature()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	if eng.HasFullMessage() {
+		t.Logf("fullMessage true - SHA256 partial Test")
+		t.Fail()
+	}
+
+	eng.Init(false, pubParameters)
+
+	err = eng.UpdateWithRecoveredMessage(data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	length := len(eng.RecoveredMessage())
+
+	if !startsWith(msg5, eng.RecoveredMessage()) || length >= len(msg5) {
+		t.Logf("failed ISO9796-2 partial recovered message SHA256 partial Test")
+		t.Fail()
+	}
+
+	eng.Update(msg5[length:])
+
+	if !verifySignature(t, eng, data) {
+		t.Logf("failed ISO9796-2 verify SHA256 partial Test")
+		t.Fail()
+	}
+
+	data[len(data)-1] ^= 0x01
+
+	eng.Update(msg5)
+
+	if verifySignature(t, eng, data) {
+		t.Logf("failed ISO9796-2 verify with modified signature SHA256 partial Test")
+		t.Fail()
+	}
+}
+
+func doSignerTestDifferentRecoveredSignature(t *testing.T) {
+	privParameters := NewRSAKeyParameters(true, mod3, pri3)
+	pubParameters := NewRSAKeyParameters(false, mod3, pub3)
+
+	eng := newTestSigner(t, stdcrypto.RIPEMD160, true)
+	eng.Init(true, privParameters)
+	eng.Update(msg5)
+	sig, err := eng.GenerateSignature()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	eng = newTestSigner(t, stdcrypto.RIPEMD160, true)
+	eng.Init(false, pubParameters)
+	err = eng.UpdateWithRecoveredMessage(sig5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	other := append([]byte{}, sig...)
+	other[len(other)-1] ^= 0x01
+	ok, err := eng.VerifySignature(other)
+	if err == nil {
+		t.Errorf("Expected error verifying a different signature, got nil")
+	}
+	if ok {
+		t.Errorf("Expected verification of a different signature to fail")
+	}
+}
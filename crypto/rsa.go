@@ -193,13 +193,13 @@ func (r *RSACoreEngine) ProcessBlock(input *big.Int) *big.Int {
 		var mP, mQ, h, m *big.Int
 
 		// mP = ((input mod p) ^ dP)) mod p
-		mP = mP.Exp(new(big.Int).Rem(input, p), dP, p)
+		mP = new(big.Int).Exp(new(big.Int).Rem(input, p), dP, p)
 
 		// mQ = ((input mod q) ^ dQ)) mod q
-		mQ = mQ.Exp(new(big.Int).Rem(input, q), dQ, q)
+		mQ = new(big.Int).Exp(new(big.Int).Rem(input, q), dQ, q)
 
 		// h = qInv * (mP - mQ) mod p
-		h = h.Sub(mP, mQ)
+		h = new(big.Int).Sub(mP, mQ)
 		h = h.Mul(h, qInv)
 		h = h.Mod(h, p) // mod (in Java) returns the positive residual
 
@@ -3,6 +3,8 @@ package dialog
 import (
 	"bufio"
 	"bytes"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -17,6 +19,7 @@ import (
 	"github.com/mitch000001/go-hbci/message"
 	"github.com/mitch000001/go-hbci/segment"
 	"github.com/mitch000001/go-hbci/transport"
+	middleware "github.com/mitch000001/go-hbci/transport/middleware"
 )
 
 // Dialog represents the common interface to use when talking to bank institutes
@@ -57,6 +60,7 @@ func newDialog(
 		hbciVersion:       hbciVersion,
 		productName:       productName,
 		productVersion:    productVersion,
+		recovery:          DefaultRecoveryPolicy,
 	}
 }
 
@@ -75,11 +79,17 @@ type dialog struct {
 	securityFn        string
 	signatureProvider message.SignatureProvider
 	cryptoProvider    message.CryptoProvider
+	signatureVerifier message.SignatureVerifier
+	// blockRejectedPin stops sending requests once the bank institute
+	// rejected the PIN, see ErrPinRejected
+	blockRejectedPin  bool
+	pinErr            error
 	BankParameterData BankParameterData
 	hbciVersion       segment.HBCIVersion
 	productName       string
 	productVersion    string
 	supportedSegments []segment.VersionedSegment
+	recovery          RecoveryPolicy
 }
 
 func (d *dialog) UserParameterDataVersion() int {
@@ -106,12 +116,36 @@ func (d *dialog) SetSecurityFunction(securityFn string) {
 	d.cryptoProvider.SetSecurityFunction(d.securityFn)
 }
 
+// SendMessage sends clientMessage within a new dialog. The dialog is
+// initialized before and ended after the message is sent.
 func (d *dialog) SendMessage(clientMessage message.HBCIMessage) (message.BankMessage, error) {
 	err := d.init()
 	if err != nil {
 		return nil, err
 	}
 	defer func() { logErr(d.end()) }()
+	return d.Send(clientMessage)
+}
+
+// Open initializes a new dialog with the bank institute. Messages can be sent
+// within the dialog with Send until the dialog is ended with Close.
+func (d *dialog) Open() error {
+	return d.init()
+}
+
+// Close ends the dialog initialized with Open.
+func (d *dialog) Close() error {
+	return d.end()
+}
+
+// Send sends clientMessage within the dialog initialized with Open. If the
+// bank institute aborts the dialog, Send recovers as defined by the
+// RecoveryPolicy of the dialog.
+func (d *dialog) Send(clientMessage message.HBCIMessage) (message.BankMessage, error) {
+	return d.sendWithRecovery(clientMessage)
+}
+
+func (d *dialog) send(clientMessage message.HBCIMessage) (message.BankMessage, error) {
 	requestMessage := d.newBasicMessage(clientMessage)
 	signedMessage, err := requestMessage.Sign(d.signatureProvider)
 	if err != nil {
@@ -125,18 +159,14 @@ func (d *dialog) SendMessage(clientMessage message.HBCIMessage) (message.BankMes
 	if err != nil {
 		return nil, err
 	}
-	var errors []string
 	acknowledgements := decryptedMessage.Acknowledgements()
 	for _, ack := range acknowledgements {
 		if ack.IsWarning() {
 			internal.Info.Printf("%v\n", ack)
 		}
-		if ack.IsError() {
-			errors = append(errors, ack.String())
-		}
 	}
-	if len(errors) > 0 {
-		return nil, fmt.Errorf("institute returned errors:\n%s", strings.Join(errors, "\n"))
+	if err := acknowledgementError(acknowledgements); err != nil {
+		return nil, err
 	}
 	return decryptedMessage, nil
 }
@@ -175,7 +205,7 @@ func (d *dialog) SyncClientSystemID() (string, error) {
 
 	decryptedMessage, err := d.request(encryptedSyncMessage)
 	if err != nil {
-		return "", fmt.Errorf("error while extracting encrypted message: %v", err)
+		return "", fmt.Errorf("error while extracting encrypted message: %w", err)
 	}
 
 	messageHeader := decryptedMessage.MessageHeader()
@@ -183,7 +213,6 @@ func (d *dialog) SyncClientSystemID() (string, error) {
 		return "", fmt.Errorf("malformed response message: %q", decryptedMessage)
 	}
 	d.dialogID = messageHeader.DialogID.Val()
-	var errors []string
 	acknowledgements := decryptedMessage.Acknowledgements()
 	for _, ack := range acknowledgements {
 		if ack.IsSuccess() {
@@ -192,12 +221,9 @@ func (d *dialog) SyncClientSystemID() (string, error) {
 		if ack.IsWarning() {
 			internal.Info.Printf("%v\n", ack)
 		}
-		if ack.IsError() {
-			errors = append(errors, ack.String())
-		}
 	}
-	if len(errors) > 0 {
-		return "", fmt.Errorf("institute returned errors:\n%s", strings.Join(errors, "\n"))
+	if err := acknowledgementError(acknowledgements); err != nil {
+		return "", err
 	}
 	if err := d.updateSecurityFunctionIfNeeded(decryptedMessage); err != nil {
 		return "", fmt.Errorf("error updating security function: %w", err)
@@ -242,18 +268,14 @@ func (d *dialog) SendAnonymousMessage(clientMessage message.HBCIMessage) (messag
 	if err != nil {
 		return nil, err
 	}
-	var errors []string
 	acknowledgements := bankMessage.Acknowledgements()
 	for _, ack := range acknowledgements {
 		if ack.IsWarning() {
 			fmt.Printf("%v\n", ack)
 		}
-		if ack.IsError() {
-			errors = append(errors, ack.String())
-		}
 	}
-	if len(errors) > 0 {
-		return nil, fmt.Errorf("Institute returned errors:\n%s", strings.Join(errors, "\n"))
+	if err := acknowledgementError(acknowledgements); err != nil {
+		return nil, err
 	}
 	return bankMessage, nil
 }
@@ -294,18 +316,14 @@ func (d *dialog) anonymousInit() error {
 		internal.Info.Printf("INFO:\n%s\n%s\n", bankInfoSegment.Subject.Val(), bankInfoSegment.Body.Val())
 	}
 
-	errors := make([]string, 0)
 	acknowledgements := bankMessage.Acknowledgements()
 	for _, ack := range acknowledgements {
 		if ack.IsWarning() {
 			fmt.Printf("%v\n", ack)
 		}
-		if ack.IsError() {
-			errors = append(errors, ack.String())
-		}
 	}
-	if len(errors) > 0 {
-		return fmt.Errorf("DialogEnd: Institute returned errors:\n%s", strings.Join(errors, "\n"))
+	if err := acknowledgementError(acknowledgements); err != nil {
+		return fmt.Errorf("DialogInit: %w", err)
 	}
 	return nil
 }
@@ -317,18 +335,11 @@ func (d *dialog) anonymousEnd() error {
 
 	decryptedMessage, err := d.request(dialogEnd)
 	if err != nil {
-		return fmt.Errorf("Error while ending dialog: %v", err)
+		return fmt.Errorf("Error while ending dialog: %w", err)
 	}
 
-	errors := make([]string, 0)
-	acknowledgements := decryptedMessage.Acknowledgements()
-	for _, ack := range acknowledgements {
-		if ack.IsError() {
-			errors = append(errors, ack.String())
-		}
-	}
-	if len(errors) > 0 {
-		return fmt.Errorf("DialogEnd: Institute returned errors:\n%s", strings.Join(errors, "\n"))
+	if err := acknowledgementError(decryptedMessage.Acknowledgements()); err != nil {
+		return fmt.Errorf("DialogEnd: %w", err)
 	}
 
 	return nil
@@ -362,7 +373,7 @@ func (d *dialog) init() error {
 
 	decryptedMessage, err := d.request(encryptedInitMessage)
 	if err != nil {
-		return fmt.Errorf("error while initializing dialog: %v", err)
+		return fmt.Errorf("error while initializing dialog: %w", err)
 	}
 	messageHeader := decryptedMessage.MessageHeader()
 	if messageHeader == nil {
@@ -386,7 +397,6 @@ func (d *dialog) init() error {
 		internal.Info.Printf("INFO:\n%s\n%s\n", bankInfoSegment.Subject.Val(), bankInfoSegment.Body.Val())
 	}
 
-	var errors []string
 	acknowledgements := decryptedMessage.Acknowledgements()
 	for _, ack := range acknowledgements {
 		if ack.IsSuccess() {
@@ -395,12 +405,9 @@ func (d *dialog) init() error {
 		if ack.IsWarning() {
 			internal.Info.Printf("%v\n", ack)
 		}
-		if ack.IsError() {
-			errors = append(errors, ack.String())
-		}
 	}
-	if len(errors) > 0 {
-		return fmt.Errorf("DialogEnd: Institute returned errors:\n%s", strings.Join(errors, "\n"))
+	if err := acknowledgementError(acknowledgements); err != nil {
+		return fmt.Errorf("DialogInit: %w", err)
 	}
 
 	if err := d.updateSecurityFunctionIfNeeded(decryptedMessage); err != nil {
@@ -411,6 +418,10 @@ func (d *dialog) init() error {
 }
 
 func (d *dialog) end() error {
+	if d.dialogID == initialDialogID {
+		// the dialog was never initialized or aborted by the bank institute
+		return nil
+	}
 	dialogEnd := message.NewDialogFinishingMessage(d.hbciVersion, d.dialogID)
 	dialogEnd.BasicMessage = d.newBasicMessage(dialogEnd)
 	signedDialogEnd, err := dialogEnd.Sign(d.signatureProvider)
@@ -424,18 +435,11 @@ func (d *dialog) end() error {
 
 	decryptedMessage, err := d.request(encryptedDialogEnd)
 	if err != nil {
-		return fmt.Errorf("Error while ending dialog: %v", err)
+		return fmt.Errorf("Error while ending dialog: %w", err)
 	}
 
-	errors := make([]string, 0)
-	acknowledgements := decryptedMessage.Acknowledgements()
-	for _, ack := range acknowledgements {
-		if ack.IsError() {
-			errors = append(errors, ack.String())
-		}
-	}
-	if len(errors) > 0 {
-		return fmt.Errorf("DialogEnd: Institute returned errors:\n%s", strings.Join(errors, "\n"))
+	if err := acknowledgementError(decryptedMessage.Acknowledgements()); err != nil {
+		return fmt.Errorf("DialogEnd: %w", err)
 	}
 
 	return nil
@@ -550,6 +554,10 @@ func (d *dialog) parseBankParameterData(bankMessage message.BankMessage) error {
 		}
 		d.BankParameterData.PinTanBusinessTransactions = pinTransactions
 	}
+	if pushServiceParams, ok := bankMessage.FindSegment(segment.PushServiceParameterID).(segment.PushServiceParameter); ok {
+		params := pushServiceParams.PushServiceParameters()
+		d.BankParameterData.PushServiceParameters = &params
+	}
 	for i, s := range d.supportedSegments {
 		param := SegmentParameter{
 			VersionedSegment: s,
@@ -580,6 +588,9 @@ func (d *dialog) parseUserParameterData(bankMessage message.BankMessage) error {
 }
 
 func (d *dialog) request(clientMessage message.ClientMessage) (message.BankMessage, error) {
+	if d.pinErr != nil {
+		return nil, &pinRejectedError{err: d.pinErr}
+	}
 	marshaledMessage, err := clientMessage.MarshalHBCI()
 	if err != nil {
 		return nil, err
@@ -626,6 +637,14 @@ func (d *dialog) request(clientMessage message.ClientMessage) (message.BankMessa
 		internal.Debug.Printf("Response:\n %s\n", decryptedMessage.MessageHeader())
 		bankMessage = decryptedMessage
 	}
+	if d.signatureVerifier != nil {
+		if err := message.VerifySignature(bankMessage, d.signatureVerifier); err != nil {
+			return nil, fmt.Errorf("error while verifying bank signature: %w", err)
+		}
+	}
+	if err := pinError(bankMessage.Acknowledgements()); err != nil && d.blockRejectedPin {
+		d.pinErr = err
+	}
 
 	return bankMessage, err
 }
@@ -641,10 +660,18 @@ func (d *dialog) extractEncryptedMessage(response *transport.Response) (*message
 		return nil, fmt.Errorf("error while unmarshaling message header: %v", err)
 	}
 	// TODO: parse messageEnd
-	// TODO: parse encryptionHeader
 
 	encMessage := message.NewEncryptedMessage(header, nil, d.hbciVersion)
 
+	if rawEncryptionHeader := response.FindSegment(segment.EncryptionHeaderSegmentID); rawEncryptionHeader != nil {
+		encryptionHeader := &segment.EncryptionHeaderSegment{}
+		if err := encryptionHeader.UnmarshalHBCI(rawEncryptionHeader); err != nil {
+			internal.Debug.Printf("error while unmarshaling encryption header: %v", err)
+		} else {
+			encMessage.EncryptionHeader = encryptionHeader
+		}
+	}
+
 	encryptedData := response.FindSegment("HNVSD")
 	if encryptedData != nil {
 		encSegment := &segment.EncryptedDataSegment{}
@@ -719,6 +746,37 @@ func (d *dialog) dial(message []byte) ([]byte, error) {
 	return retBuf.Bytes(), err
 }
 
+// acknowledgementError returns a *domain.AcknowledgementError if any of the
+// acknowledgements represents an error, nil otherwise.
+func acknowledgementError(acknowledgements []domain.Acknowledgement) error {
+	if err := domain.NewAcknowledgementError(acknowledgements); err != nil {
+		return err
+	}
+	return nil
+}
+
+// pinError returns the acknowledgement error if the bank institute rejected
+// the PIN or locked the access, nil otherwise.
+func pinError(acknowledgements []domain.Acknowledgement) error {
+	ackErr := domain.NewAcknowledgementError(acknowledgements)
+	if ackErr == nil {
+		return nil
+	}
+	if errors.Is(ackErr, domain.ErrPinWrong) || errors.Is(ackErr, domain.ErrAccountLocked) {
+		return ackErr
+	}
+	return nil
+}
+
+// encodedTransport wraps t with the Base64 encoding used over HTTPS.
+// Transports with raw framing, like plain TCP, are returned as is.
+func encodedTransport(t transport.Transport) transport.Transport {
+	if framer, ok := t.(transport.RawFramer); ok && framer.RawFraming() {
+		return t
+	}
+	return middleware.Base64Encoding(base64.StdEncoding)(t)
+}
+
 func logErr(err error) {
 	if err != nil {
 		log.Println(err)
@@ -2,6 +2,7 @@ package dialog
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -208,6 +209,10 @@ func TestPinTanDialogSyncClientSystemID(t *testing.T) {
 			t.Logf("Expected error to equal\n%q\n\tgot\n%q\n", expectedMessage, errMessage)
 			t.Fail()
 		}
+		if !errors.Is(err, domain.ErrStatusUnknown) {
+			t.Logf("Expected error to be %v, got %v\n", domain.ErrStatusUnknown, err)
+			t.Fail()
+		}
 	}
 }
 
@@ -0,0 +1,188 @@
+package dialog
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mitch000001/go-hbci/charset"
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/message"
+)
+
+// NewIniLetter creates the INI letter for the public keys of the user. The
+// hashes of the keys are computed as defined by profile.
+func NewIniLetter(bankID domain.BankID, userID string, profile *message.RDHProfile, keys ...*domain.RSAKey) *IniLetter {
+	letter := &IniLetter{
+		BankID:  bankID,
+		UserID:  userID,
+		Profile: profile.String(),
+		Date:    time.Now(),
+	}
+	for _, key := range keys {
+		letter.Keys = append(letter.Keys, IniLetterKey{
+			KeyName:  key.KeyName(),
+			Exponent: key.Exponent,
+			Modulus:  key.Modulus,
+			Hash:     profile.IniHash(key),
+		})
+	}
+	return letter
+}
+
+// IniLetter represents the letter a user has to sign and mail to the bank
+// after submitting new public keys. The bank compares the printed hashes with
+// the hashes of the received keys before unlocking them.
+type IniLetter struct {
+	BankID  domain.BankID
+	UserID  string
+	Profile string
+	Date    time.Time
+	Keys    []IniLetterKey
+}
+
+// IniLetterKey represents a public key printed on an INI letter
+type IniLetterKey struct {
+	KeyName  domain.KeyName
+	Exponent []byte
+	Modulus  []byte
+	Hash     []byte
+}
+
+// Lines returns the text lines of the INI letter
+func (i *IniLetter) Lines() []string {
+	lines := []string{
+		"INI-Brief",
+		"",
+		fmt.Sprintf("Datum:          %s", i.Date.Format("02.01.2006")),
+		fmt.Sprintf("Uhrzeit:        %s", i.Date.Format("15:04:05")),
+		fmt.Sprintf("Bankleitzahl:   %s", i.BankID.ID),
+		fmt.Sprintf("Benutzerkennung: %s", i.UserID),
+		fmt.Sprintf("Sicherheitsprofil: %s", i.Profile),
+	}
+	for _, key := range i.Keys {
+		keyType := "Chiffrierschlüssel"
+		if key.KeyName.KeyType == domain.KeyTypeSigning {
+			keyType = "Signierschlüssel"
+		}
+		lines = append(lines,
+			"",
+			fmt.Sprintf("%s (Schlüsselnummer %d, Schlüsselversion %d)", keyType, key.KeyName.KeyNumber, key.KeyName.KeyVersion),
+			"",
+			"Exponent:",
+		)
+		lines = append(lines, hexLines(key.Exponent)...)
+		lines = append(lines, "", "Modulus:")
+		lines = append(lines, hexLines(key.Modulus)...)
+		lines = append(lines, "", "Hashwert:")
+		lines = append(lines, hexLines(key.Hash)...)
+	}
+	lines = append(lines,
+		"",
+		"Ich bestätige hiermit, dass die obigen Schlüssel für meine",
+		"elektronische Signatur erzeugt wurden.",
+		"",
+		"",
+		"_________________________        _________________________",
+		"Ort, Datum                       Unterschrift",
+	)
+	return lines
+}
+
+// WriteText writes the INI letter as plain text to w
+func (i *IniLetter) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, line := range i.Lines() {
+		if _, err := fmt.Fprintln(bw, line); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// iniLetterLinesPerPage defines the number of lines fitting on one PDF page
+const iniLetterLinesPerPage = 60
+
+// WritePDF writes the INI letter as PDF document with A4 pages to w
+func (i *IniLetter) WritePDF(w io.Writer) error {
+	lines := i.Lines()
+	var pages [][]string
+	for len(lines) > iniLetterLinesPerPage {
+		pages = append(pages, lines[:iniLetterLinesPerPage])
+		lines = lines[iniLetterLinesPerPage:]
+	}
+	pages = append(pages, lines)
+
+	// Object layout: 1 catalog, 2 pages, 3 font, then content and page
+	// object for every page.
+	var objects []string
+	objects = append(objects, "<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(pages))
+	for n := range pages {
+		kids[n] = fmt.Sprintf("%d 0 R", 5+2*n)
+	}
+	objects = append(objects, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	for n, page := range pages {
+		var content bytes.Buffer
+		content.WriteString("BT /F1 10 Tf 12 TL 56 790 Td\n")
+		for _, line := range page {
+			content.WriteString("(")
+			content.Write(pdfEscape(charset.ToISO8859_1(line)))
+			content.WriteString(") Tj T*\n")
+		}
+		content.WriteString("ET")
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+		objects = append(objects, fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", 4+2*n,
+		))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for n, object := range objects {
+		offsets[n] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", n+1, object)
+	}
+	xrefOffset := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xrefOffset)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// hexLines formats data as hexadecimal bytes with 16 bytes per line
+func hexLines(data []byte) []string {
+	var lines []string
+	for len(data) > 0 {
+		n := 16
+		if len(data) < n {
+			n = len(data)
+		}
+		parts := make([]string, n)
+		for j, b := range data[:n] {
+			parts[j] = fmt.Sprintf("%02X", b)
+		}
+		lines = append(lines, "  "+strings.Join(parts, " "))
+		data = data[n:]
+	}
+	return lines
+}
+
+func pdfEscape(text []byte) []byte {
+	var buf bytes.Buffer
+	for _, b := range text {
+		if b == '(' || b == ')' || b == '\\' {
+			buf.WriteByte('\\')
+		}
+		buf.WriteByte(b)
+	}
+	return buf.Bytes()
+}
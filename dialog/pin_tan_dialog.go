@@ -1,7 +1,8 @@
 package dialog
 
 import (
-	"encoding/base64"
+	"errors"
+	"fmt"
 
 	"github.com/mitch000001/go-hbci/domain"
 	"github.com/mitch000001/go-hbci/internal"
@@ -21,6 +22,9 @@ type Config struct {
 	ProductName    string
 	ProductVersion string
 	Transport      transport.Transport
+	// Recovery defines how to recover aborted dialogs. Defaults to
+	// DefaultRecoveryPolicy.
+	Recovery *RecoveryPolicy
 }
 
 // NewPinTanDialog creates a new dialog to use for pin/tan transport
@@ -42,25 +46,52 @@ func NewPinTanDialog(config Config) *PinTanDialog {
 		),
 	}
 
+	d.blockRejectedPin = true
+	if config.Recovery != nil {
+		d.recovery = *config.Recovery
+	}
+
 	var dialogTransport transport.Transport
 	if config.Transport == nil {
 		dialogTransport = https.New()
 	} else {
 		dialogTransport = config.Transport
 	}
-	dialogTransport = middleware.Base64Encoding(base64.StdEncoding)(dialogTransport)
+	dialogTransport = encodedTransport(dialogTransport)
 	dialogTransport = middleware.Logging(internal.Debug, cryptoProvider)(dialogTransport)
 	d.transport = dialogTransport
 	return d
 }
 
+// ErrPinRejected is returned for all requests after the bank institute
+// rejected the PIN or locked the access. The dialog does not send the
+// rejected PIN again, as every further attempt counts as failed and leads to
+// a locked access. Requests are sent again after a PIN is provided with
+// SetPin.
+var ErrPinRejected = errors.New("PIN rejected by bank institute")
+
+// pinRejectedError wraps the acknowledgement error of the response rejecting
+// the PIN. It matches ErrPinRejected as well as the wrapped error.
+type pinRejectedError struct {
+	err error
+}
+
+func (p *pinRejectedError) Error() string {
+	return fmt.Sprintf("%v, set a new PIN before sending further requests: %v", ErrPinRejected, p.err)
+}
+
+func (p *pinRejectedError) Is(target error) bool { return target == ErrPinRejected }
+func (p *pinRejectedError) Unwrap() error        { return p.err }
+
 // PinTanDialog represents a dialog to use in pin/tan flow with HTTPS transport
 type PinTanDialog struct {
 	*dialog
 }
 
-// SetPin lets the user reset the pin after creation
+// SetPin lets the user reset the pin after creation. If the bank institute
+// rejected the former PIN, it allows sending requests again.
 func (d *PinTanDialog) SetPin(pin string) {
+	d.pinErr = nil
 	pinKey := domain.NewPinKey(pin, domain.NewPinTanKeyName(d.BankID, d.UserID, domain.KeyTypeSigning))
 	d.signatureProvider = message.NewPinTanSignatureProvider(pinKey, d.ClientSystemID)
 	pinKey = domain.NewPinKey(pin, domain.NewPinTanKeyName(d.BankID, d.UserID, domain.KeyTypeEncryption))
@@ -1,25 +1,342 @@
 package dialog
 
 import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/internal"
 	"github.com/mitch000001/go-hbci/message"
 	"github.com/mitch000001/go-hbci/segment"
+	"github.com/mitch000001/go-hbci/transport"
+	https "github.com/mitch000001/go-hbci/transport/https"
 )
 
-// NewRDHDialog creates a dialog to use with cardreader flow
-func NewRDHDialog(bankID domain.BankID, hbciURL string, clientID string, hbciVersion segment.HBCIVersion, productName string, productVersion string) Dialog {
-	key, err := domain.GenerateSigningKey()
-	if err != nil {
-		panic(err)
+// ErrBankKeyHashMismatch is returned if the hash of a received bank key does
+// not match the hash printed on the INI letter of the bank.
+var ErrBankKeyHashMismatch = errors.New("bank key hash does not match INI letter")
+
+// RDHConfig contains the configuration of a RDHDialog
+type RDHConfig struct {
+	Config
+	// SigningKey defines the signing key of the user. A new key is
+	// generated if it is nil.
+	SigningKey *domain.RSAKey
+	// EncryptionKey defines the encryption key of the user. A new key is
+	// generated if it is nil.
+	EncryptionKey *domain.RSAKey
+	// BankEncryptionKey defines the already known encryption key of the bank.
+	// If it is nil, it has to be fetched with FetchBankKeys.
+	BankEncryptionKey *domain.RSAKey
+	// BankSigningKey defines the already known signing key of the bank. If
+	// it is set, the signatures of all bank messages are verified.
+	BankSigningKey *domain.RSAKey
+	// ClientSystemID defines an already synchronized client system ID. If it
+	// is empty, a new one is requested on dialog initialization.
+	ClientSystemID string
+	// SignatureID defines the signature ID of the first signed message, as
+	// returned by RDHDialog.SignatureID within a previous session. It
+	// defaults to 1.
+	SignatureID int
+	// Profile defines the security profile to use. It defaults to
+	// message.RDH2Profile.
+	Profile *message.RDHProfile
+}
+
+// NewRDHDialog creates a dialog to use with the RDH flow
+func NewRDHDialog(config RDHConfig) (*RDHDialog, error) {
+	profile := config.Profile
+	if profile == nil {
+		profile = message.RDH2Profile
+	}
+	signingKey := config.SigningKey
+	if signingKey == nil {
+		key, err := domain.GenerateKey(domain.KeyTypeSigning, profile.KeySize)
+		if err != nil {
+			return nil, fmt.Errorf("error generating signing key: %w", err)
+		}
+		signingKey = domain.NewRSAKey(key, newUserKeyName(config.BankID, config.UserID, domain.KeyTypeSigning))
+	}
+	encryptionKey := config.EncryptionKey
+	if encryptionKey == nil {
+		key, err := domain.GenerateKey(domain.KeyTypeEncryption, profile.KeySize)
+		if err != nil {
+			return nil, fmt.Errorf("error generating encryption key: %w", err)
+		}
+		encryptionKey = domain.NewRSAKey(key, newUserKeyName(config.BankID, config.UserID, domain.KeyTypeEncryption))
+	}
+	signatureID := config.SignatureID
+	if signatureID == 0 {
+		signatureID = 1
+	}
+	signatureProvider := profile.NewSignatureProvider(signingKey, signatureID)
+	cryptoProvider := profile.NewCryptoProvider(config.BankEncryptionKey, encryptionKey, initialClientSystemID)
+	d := &RDHDialog{
+		dialog: newDialog(
+			config.BankID,
+			config.HBCIURL,
+			config.UserID,
+			config.HBCIVersion,
+			config.ProductName,
+			config.ProductVersion,
+			signatureProvider,
+			cryptoProvider,
+		),
+		profile:           profile,
+		rdhSignature:      signatureProvider,
+		signingKey:        signingKey,
+		encryptionKey:     encryptionKey,
+		bankEncryptionKey: config.BankEncryptionKey,
+		bankSigningKey:    config.BankSigningKey,
 	}
-	signingKey := domain.NewRSAKey(key, domain.NewInitialKeyName(bankID.CountryCode, bankID.ID, clientID, "S"))
-	provider := message.NewRDHSignatureProvider(signingKey, 12345)
-	d := &rdhDialog{
-		dialog: newDialog(bankID, hbciURL, clientID, hbciVersion, productName, productVersion, provider, nil),
+	d.securityFn = "1"
+	if config.BankSigningKey != nil {
+		d.signatureVerifier = profile.NewSignatureVerifier(config.BankSigningKey)
+	}
+	if config.ClientSystemID != "" {
+		d.SetClientSystemID(config.ClientSystemID)
+	}
+
+	var dialogTransport transport.Transport
+	if config.Transport == nil {
+		dialogTransport = https.New()
+	} else {
+		dialogTransport = config.Transport
+	}
+	d.transport = encodedTransport(dialogTransport)
+	return d, nil
+}
+
+func newUserKeyName(bankID domain.BankID, userID string, keyType domain.KeyType) *domain.KeyName {
+	return &domain.KeyName{
+		BankID:     bankID,
+		UserID:     userID,
+		KeyType:    keyType,
+		KeyNumber:  1,
+		KeyVersion: 1,
 	}
-	return d
 }
 
-type rdhDialog struct {
+// RDHDialog represents a dialog to use in the RDH flow, where messages are
+// signed and encrypted with RSA keys.
+type RDHDialog struct {
 	*dialog
+	profile           *message.RDHProfile
+	rdhSignature      message.RDHSignatureProvider
+	signingKey        *domain.RSAKey
+	encryptionKey     *domain.RSAKey
+	bankEncryptionKey *domain.RSAKey
+	bankSigningKey    *domain.RSAKey
+}
+
+// Profile returns the security profile used by the dialog
+func (d *RDHDialog) Profile() *message.RDHProfile {
+	return d.profile
+}
+
+// SignatureID returns the signature ID of the next signed message. It has to
+// be persisted together with the keys, as the bank rejects signature IDs it has
+// already seen.
+func (d *RDHDialog) SignatureID() int {
+	return d.rdhSignature.SignatureID()
+}
+
+// SigningKey returns the signing key of the user
+func (d *RDHDialog) SigningKey() *domain.RSAKey {
+	return d.signingKey
+}
+
+// EncryptionKey returns the encryption key of the user
+func (d *RDHDialog) EncryptionKey() *domain.RSAKey {
+	return d.encryptionKey
+}
+
+// BankEncryptionKey returns the encryption key of the bank, or nil if not
+// known yet
+func (d *RDHDialog) BankEncryptionKey() *domain.RSAKey {
+	return d.bankEncryptionKey
+}
+
+// BankSigningKey returns the signing key of the bank, or nil if not known
+// yet. Not all banks use signing keys. If it is known, the signatures of all
+// bank messages are verified and messages with missing or invalid signatures
+// are rejected, whether they are encrypted or not.
+func (d *RDHDialog) BankSigningKey() *domain.RSAKey {
+	return d.bankSigningKey
+}
+
+// FetchBankKeys requests the public keys of the bank within an unsigned and
+// unencrypted dialog. encryptionKeyHash and signingKeyHash are the hashes of
+// the bank keys as printed on the INI letter of the bank, in hexadecimal
+// notation. Whitespace and colons within the hashes are ignored. signingKeyHash
+// may only be empty if the bank does not use a signing key. If the hash of a
+// received key does not match, an error wrapping ErrBankKeyHashMismatch is
+// returned and the keys are discarded.
+func (d *RDHDialog) FetchBankKeys(encryptionKeyHash, signingKeyHash string) error {
+	expectedEncryptionKeyHash, err := parseIniHash(encryptionKeyHash)
+	if err != nil {
+		return err
+	}
+	var expectedSigningKeyHash []byte
+	if signingKeyHash != "" {
+		expectedSigningKeyHash, err = parseIniHash(signingKeyHash)
+		if err != nil {
+			return err
+		}
+	}
+	encryptionKey, signingKey, err := d.fetchBankKeys()
+	if err != nil {
+		return err
+	}
+	if encryptionKey == nil {
+		return fmt.Errorf("bank did not transmit an encryption key")
+	}
+	if actualHash := d.profile.IniHash(encryptionKey); !bytes.Equal(actualHash, expectedEncryptionKeyHash) {
+		return fmt.Errorf("%w: encryption key: expected %X, got %X", ErrBankKeyHashMismatch, expectedEncryptionKeyHash, actualHash)
+	}
+	switch {
+	case signingKey == nil && expectedSigningKeyHash != nil:
+		return fmt.Errorf("bank did not transmit a signing key")
+	case signingKey != nil && expectedSigningKeyHash == nil:
+		return fmt.Errorf("%w: signing key: no INI hash provided", ErrBankKeyHashMismatch)
+	case signingKey != nil:
+		if actualHash := d.profile.IniHash(signingKey); !bytes.Equal(actualHash, expectedSigningKeyHash) {
+			return fmt.Errorf("%w: signing key: expected %X, got %X", ErrBankKeyHashMismatch, expectedSigningKeyHash, actualHash)
+		}
+	}
+	d.bankEncryptionKey = encryptionKey
+	d.bankSigningKey = signingKey
+	d.cryptoProvider = d.profile.NewCryptoProvider(encryptionKey, d.encryptionKey, d.ClientSystemID)
+	d.cryptoProvider.SetSecurityFunction(d.securityFn)
+	if signingKey != nil {
+		d.signatureVerifier = d.profile.NewSignatureVerifier(signingKey)
+	}
+	internal.Info.Printf("Received bank keys %v", encryptionKey.KeyName())
+	return nil
+}
+
+// fetchBankKeys requests the public keys of the bank and returns them
+// unchecked. The anonymous dialog is ended before returning.
+func (d *RDHDialog) fetchBankKeys() (encryptionKey, signingKey *domain.RSAKey, err error) {
+	d.dialogID = initialDialogID
+	d.messageCount = 0
+	keyRequest := message.NewDialogInitializationClientMessage(d.hbciVersion)
+	keyRequest.Identification = segment.NewIdentificationSegment(d.BankID, d.clientID, initialClientSystemID, false)
+	keyRequest.ProcessingPreparation = segment.NewProcessingPreparationSegmentV3(
+		d.BankParameterDataVersion(), d.UserParameterDataVersion(), d.Language, d.productName, d.productVersion,
+	)
+	keyRequest.PublicEncryptionKeyRequest = segment.NewPublicKeyRequestSegment(
+		5, *domain.NewInitialKeyName(d.BankID.CountryCode, d.BankID.ID, d.clientID, domain.KeyTypeEncryption),
+	)
+	keyRequest.PublicSigningKeyRequest = segment.NewPublicKeyRequestSegment(
+		6, *domain.NewInitialKeyName(d.BankID.CountryCode, d.BankID.ID, d.clientID, domain.KeyTypeSigning),
+	)
+	keyRequest.BasicMessage = d.newBasicMessage(keyRequest)
+	keyRequest.SetSegmentPositions()
+	bankMessage, err := d.request(keyRequest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error while requesting bank keys: %w", err)
+	}
+	messageHeader := bankMessage.MessageHeader()
+	if messageHeader == nil {
+		return nil, nil, fmt.Errorf("malformed response message: %q", bankMessage)
+	}
+	d.dialogID = messageHeader.DialogID.Val()
+	defer func() { logErr(d.anonymousEnd()) }()
+
+	if err := acknowledgementError(bankMessage.Acknowledgements()); err != nil {
+		return nil, nil, fmt.Errorf("FetchBankKeys: %w", err)
+	}
+
+	for _, seg := range bankMessage.FindSegments("HIISA") {
+		keySegment, ok := seg.(*segment.PublicKeyTransmissionSegment)
+		if !ok || keySegment.KeyName == nil || keySegment.PublicKey == nil {
+			return nil, nil, fmt.Errorf("malformed public key transmission segment")
+		}
+		keyName := keySegment.KeyName.Val()
+		publicKey := keySegment.PublicKey.Val()
+		key := domain.NewEncryptionKey(publicKey.Modulus, publicKey.Exponent)
+		key.Type = string(keyName.KeyType)
+		switch keyName.KeyType {
+		case domain.KeyTypeEncryption:
+			encryptionKey = domain.NewRSAKey(key, &keyName)
+		case domain.KeyTypeSigning:
+			signingKey = domain.NewRSAKey(key, &keyName)
+		}
+	}
+	return encryptionKey, signingKey, nil
+}
+
+// SubmitUserKeys submits the public signing and encryption keys of the user to
+// the bank. The bank keys have to be known, i.e. be configured or fetched with
+// FetchBankKeys. After submission, the INI letter has to be signed and sent to
+// the bank, which will unlock the keys afterwards.
+func (d *RDHDialog) SubmitUserKeys() error {
+	if d.bankEncryptionKey == nil {
+		return fmt.Errorf("bank encryption key unknown: bank keys must be fetched before submitting user keys")
+	}
+	d.dialogID = initialDialogID
+	d.messageCount = 0
+	keySubmission := message.NewDialogInitializationClientMessage(d.hbciVersion)
+	keySubmission.Identification = segment.NewIdentificationSegment(d.BankID, d.clientID, d.ClientSystemID, true)
+	keySubmission.ProcessingPreparation = segment.NewProcessingPreparationSegmentV3(
+		d.BankParameterDataVersion(), d.UserParameterDataVersion(), d.Language, d.productName, d.productVersion,
+	)
+	keySubmission.PublicSigningKeyRenewal = segment.NewPublicKeyRenewalSegment(5, d.signingKey.KeyName(), d.signingKey.PublicKey)
+	keySubmission.PublicEncryptionKeyRenewal = segment.NewPublicKeyRenewalSegment(6, d.encryptionKey.KeyName(), d.encryptionKey.PublicKey)
+	keySubmission.BasicMessage = d.newBasicMessage(keySubmission)
+	signedMessage, err := keySubmission.Sign(d.signatureProvider)
+	if err != nil {
+		return err
+	}
+	encryptedMessage, err := signedMessage.Encrypt(d.cryptoProvider)
+	if err != nil {
+		return err
+	}
+	bankMessage, err := d.request(encryptedMessage)
+	if err != nil {
+		return fmt.Errorf("error while submitting user keys: %w", err)
+	}
+	messageHeader := bankMessage.MessageHeader()
+	if messageHeader == nil {
+		return fmt.Errorf("malformed response message: %q", bankMessage)
+	}
+	d.dialogID = messageHeader.DialogID.Val()
+	defer func() { logErr(d.end()) }()
+
+	acknowledgements := bankMessage.Acknowledgements()
+	for _, ack := range acknowledgements {
+		if ack.IsWarning() {
+			internal.Info.Printf("%v\n", ack)
+		}
+	}
+	if err := acknowledgementError(acknowledgements); err != nil {
+		return fmt.Errorf("SubmitUserKeys: %w", err)
+	}
+	return nil
+}
+
+// IniLetter returns the INI letter for the public keys of the user
+func (d *RDHDialog) IniLetter() *IniLetter {
+	return NewIniLetter(d.BankID, d.UserID, d.profile, d.signingKey, d.encryptionKey)
+}
+
+func parseIniHash(iniHash string) ([]byte, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		if r == ' ' || r == ':' || r == '\t' || r == '\n' || r == '\r' {
+			return -1
+		}
+		return r
+	}, iniHash)
+	hash, err := hex.DecodeString(cleaned)
+	if err != nil {
+		return nil, fmt.Errorf("malformed INI hash: %w", err)
+	}
+	if len(hash) == 0 {
+		return nil, fmt.Errorf("malformed INI hash: empty")
+	}
+	return hash, nil
 }
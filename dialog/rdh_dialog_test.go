@@ -0,0 +1,472 @@
+package dialog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/charset"
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+func TestRDHDialogFetchBankKeys(t *testing.T) {
+	bankKey := newTestBankKey(t)
+	bankSigningKey := newTestBankSigningKey(t)
+	encryptionKeyHash := fmt.Sprintf("% X", message.RDH2Profile.IniHash(bankKey))
+	signingKeyHash := fmt.Sprintf("% X", message.RDH2Profile.IniHash(bankSigningKey))
+
+	t.Run("matching hashes", func(t *testing.T) {
+		transport := &mockHTTPSTransport{}
+		d := newTestRDHDialog(t, transport, nil)
+		transport.SetResponseMessages([][]byte{
+			unencryptedTestMessage("abcde", "HIRMG:2:2+0010::Nachricht entgegengenommen.'", publicKeyTransmission(bankKey), publicKeyTransmission(bankSigningKey)),
+			unencryptedTestMessage("abcde", "HIRMG:2:2+0100::Dialog beendet.'"),
+		})
+
+		err := d.FetchBankKeys(encryptionKeyHash, signingKeyHash)
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+
+		received := d.BankEncryptionKey()
+		if received == nil {
+			t.Fatalf("Expected bank encryption key to be set\n")
+		}
+		if !bytes.Equal(received.Modulus, bankKey.Modulus) {
+			t.Logf("Expected modulus to equal\n%X\n\tgot\n%X\n", bankKey.Modulus, received.Modulus)
+			t.Fail()
+		}
+		if received.KeyName() != bankKey.KeyName() {
+			t.Logf("Expected key name to equal %v, got %v\n", bankKey.KeyName(), received.KeyName())
+			t.Fail()
+		}
+		receivedSigningKey := d.BankSigningKey()
+		if receivedSigningKey == nil {
+			t.Fatalf("Expected bank signing key to be set\n")
+		}
+		if !bytes.Equal(receivedSigningKey.Modulus, bankSigningKey.Modulus) {
+			t.Logf("Expected modulus to equal\n%X\n\tgot\n%X\n", bankSigningKey.Modulus, receivedSigningKey.Modulus)
+			t.Fail()
+		}
+		if d.signatureVerifier == nil {
+			t.Logf("Expected signature verifier to be set\n")
+			t.Fail()
+		}
+		request := readRequest(t, transport, 0)
+		for _, expected := range []string{"HKISA:4:2+2+124+280:10000000:12345:S:999:999'", "HKISA:5:2+2+124+280:10000000:12345:V:999:999'"} {
+			if !strings.Contains(request, expected) {
+				t.Logf("Expected request to contain %q, got %q\n", expected, request)
+				t.Fail()
+			}
+		}
+		if strings.Contains(request, "HNSHK") || strings.Contains(request, "HNVSK") {
+			t.Logf("Expected key request to be unsigned and unencrypted, got %q\n", request)
+			t.Fail()
+		}
+	})
+	t.Run("without signing key", func(t *testing.T) {
+		transport := &mockHTTPSTransport{}
+		d := newTestRDHDialog(t, transport, nil)
+		transport.SetResponseMessages([][]byte{
+			unencryptedTestMessage("abcde", "HIRMG:2:2+0010::Nachricht entgegengenommen.'", publicKeyTransmission(bankKey)),
+			unencryptedTestMessage("abcde", "HIRMG:2:2+0100::Dialog beendet.'"),
+		})
+
+		err := d.FetchBankKeys(encryptionKeyHash, "")
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+		if d.BankSigningKey() != nil || d.signatureVerifier != nil {
+			t.Logf("Expected no bank signing key\n")
+			t.Fail()
+		}
+	})
+	tests := []struct {
+		description       string
+		encryptionKeyHash string
+		signingKeyHash    string
+	}{
+		{"encryption key hash mismatch", strings.Repeat("00", 20), signingKeyHash},
+		{"signing key hash mismatch", encryptionKeyHash, strings.Repeat("00", 20)},
+		{"missing signing key hash", encryptionKeyHash, ""},
+		{"hash of another profile", fmt.Sprintf("% X", message.RDH10Profile.IniHash(bankKey)), signingKeyHash},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			transport := &mockHTTPSTransport{}
+			d := newTestRDHDialog(t, transport, nil)
+			transport.SetResponseMessages([][]byte{
+				unencryptedTestMessage("abcde", "HIRMG:2:2+0010::Nachricht entgegengenommen.'", publicKeyTransmission(bankKey), publicKeyTransmission(bankSigningKey)),
+				unencryptedTestMessage("abcde", "HIRMG:2:2+0100::Dialog beendet.'"),
+			})
+
+			err := d.FetchBankKeys(test.encryptionKeyHash, test.signingKeyHash)
+			if !errors.Is(err, ErrBankKeyHashMismatch) {
+				t.Logf("Expected error to be ErrBankKeyHashMismatch, got %T:%v\n", err, err)
+				t.Fail()
+			}
+			if d.BankEncryptionKey() != nil || d.BankSigningKey() != nil || d.signatureVerifier != nil {
+				t.Logf("Expected bank keys to be discarded\n")
+				t.Fail()
+			}
+			if transport.callCount != 2 {
+				t.Logf("Expected dialog to be ended, got %d requests\n", transport.callCount)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestRDHDialogSubmitUserKeys(t *testing.T) {
+	bankKey := newTestBankKey(t)
+	transport := &mockHTTPSTransport{}
+	d := newTestRDHDialog(t, transport, bankKey)
+
+	bankCryptoProvider := message.NewRDHCryptoProvider(d.EncryptionKey(), nil, "0")
+	transport.SetResponseMessages([][]byte{
+		rdhEncryptedTestMessage(t, bankCryptoProvider, "abcde", "HIRMG:2:2+0010::Nachricht entgegengenommen.'HIRMS:3:2:5+0020::Schlüssel entgegengenommen.'"),
+		rdhEncryptedTestMessage(t, bankCryptoProvider, "abcde", "HIRMG:2:2+0100::Dialog beendet.'"),
+	})
+
+	err := d.SubmitUserKeys()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	request := readRequest(t, transport, 0)
+	if strings.Contains(request, "HKSAK") {
+		t.Logf("Expected key submission to be encrypted, got %q\n", request)
+		t.Fail()
+	}
+	decrypted := decryptRDHRequest(t, bankKey, request)
+	for _, key := range []*domain.RSAKey{d.SigningKey(), d.EncryptionKey()} {
+		keyName := key.KeyName()
+		expected := fmt.Sprintf("+2+112+280:10000000:12345:%s:1:1+", keyName.KeyType)
+		if !strings.Contains(decrypted, expected) {
+			t.Logf("Expected decrypted request to contain %q, got %q\n", expected, decrypted)
+			t.Fail()
+		}
+	}
+	if !strings.Contains(decrypted, "HNSHK:2:4+RDH:2+1+") {
+		t.Logf("Expected decrypted request to be signed with RDH, got %q\n", decrypted)
+		t.Fail()
+	}
+}
+
+func TestRDHDialogSignatureID(t *testing.T) {
+	bankKey := newTestBankKey(t)
+	transport := &mockHTTPSTransport{}
+	d, err := NewRDHDialog(RDHConfig{
+		Config: Config{
+			BankID:      domain.BankID{CountryCode: 280, ID: "10000000"},
+			HBCIURL:     "http://localhost",
+			UserID:      "12345",
+			HBCIVersion: segment.FINTS300,
+		},
+		BankEncryptionKey: bankKey,
+		SignatureID:       42,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	d.transport = transport
+
+	bankCryptoProvider := message.NewRDHCryptoProvider(d.EncryptionKey(), nil, "0")
+	transport.SetResponseMessages([][]byte{
+		rdhEncryptedTestMessage(t, bankCryptoProvider, "abcde", "HIRMG:2:2+0010::Nachricht entgegengenommen.'"),
+		rdhEncryptedTestMessage(t, bankCryptoProvider, "abcde", "HIRMG:2:2+0100::Dialog beendet.'"),
+	})
+
+	err = d.SubmitUserKeys()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	for i, expected := range []string{"+42+1:", "+43+1:"} {
+		decrypted := decryptRDHRequest(t, bankKey, readRequest(t, transport, i))
+		header := decrypted[:strings.Index(decrypted, "'")]
+		if !strings.Contains(header, expected) {
+			t.Logf("Expected signature header to contain signature ID %q, got %q\n", expected, header)
+			t.Fail()
+		}
+	}
+	if d.SignatureID() != 44 {
+		t.Logf("Expected next signature ID to equal 44, got %d\n", d.SignatureID())
+		t.Fail()
+	}
+}
+
+func TestRDHDialogVerifiesBankSignature(t *testing.T) {
+	bankKey := newTestBankKey(t)
+	bankSigningKey, err := domain.GenerateSigningKey()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	signingKey := domain.NewRSAKey(bankSigningKey, &domain.KeyName{
+		BankID:     domain.BankID{CountryCode: 280, ID: "10000000"},
+		UserID:     "12345",
+		KeyType:    domain.KeyTypeSigning,
+		KeyNumber:  3,
+		KeyVersion: 7,
+	})
+
+	response := "HIRMG:3:2+0010::Nachricht entgegengenommen.'"
+	tests := []struct {
+		description string
+		segments    string
+		expectedErr error
+	}{
+		{"signed response", signedTestSegments(t, signingKey, response), nil},
+		{"unsigned response", response, message.ErrMissingSignature},
+		{"forged response", strings.Replace(signedTestSegments(t, signingKey, response), "0010", "0020", 1), message.ErrInvalidSignature},
+	}
+	for _, test := range tests {
+		for _, encrypted := range []bool{true, false} {
+			testRDHDialogVerifiesBankSignature(t, test.description, encrypted, test.segments, test.expectedErr, bankKey, signingKey)
+		}
+	}
+}
+
+func testRDHDialogVerifiesBankSignature(t *testing.T, description string, encrypted bool, segments string, expectedErr error, bankKey, bankSigningKey *domain.RSAKey) {
+	if !encrypted {
+		description += " unencrypted"
+	}
+	publicSigningKey := domain.NewEncryptionKey(bankSigningKey.Modulus, bankSigningKey.Exponent)
+	publicSigningKey.Type = domain.KeyTypeSigning.String()
+	bankSigningKeyName := bankSigningKey.KeyName()
+	t.Run(description, func(t *testing.T) {
+		transport := &mockHTTPSTransport{}
+		d, err := NewRDHDialog(RDHConfig{
+			Config: Config{
+				BankID:      domain.BankID{CountryCode: 280, ID: "10000000"},
+				HBCIURL:     "http://localhost",
+				UserID:      "12345",
+				HBCIVersion: segment.FINTS300,
+			},
+			BankEncryptionKey: bankKey,
+			BankSigningKey:    domain.NewRSAKey(publicSigningKey, &bankSigningKeyName),
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+		d.transport = transport
+
+		bankCryptoProvider := message.NewRDHCryptoProvider(d.EncryptionKey(), nil, "0")
+		response := func(segments string) []byte {
+			if encrypted {
+				return rdhEncryptedRawTestMessage(t, bankCryptoProvider, "abcde", []byte(segments))
+			}
+			return unencryptedTestMessage("abcde", segments)
+		}
+		transport.SetResponseMessages([][]byte{
+			response(segments),
+			response(signedTestSegments(t, bankSigningKey, "HIRMG:3:2+0100::Dialog beendet.'")),
+		})
+
+		err = d.SubmitUserKeys()
+
+		if expectedErr == nil && err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+		if expectedErr != nil && !errors.Is(err, expectedErr) {
+			t.Logf("Expected error to be %v, got %T:%v\n", expectedErr, err, err)
+			t.Fail()
+		}
+	})
+}
+
+func TestRDHDialogSubmitUserKeysWithoutBankKeys(t *testing.T) {
+	d := newTestRDHDialog(t, &mockHTTPSTransport{}, nil)
+
+	err := d.SubmitUserKeys()
+	if err == nil {
+		t.Logf("Expected error when bank keys are unknown\n")
+		t.Fail()
+	}
+}
+
+func TestIniLetter(t *testing.T) {
+	d := newTestRDHDialog(t, &mockHTTPSTransport{}, nil)
+	letter := d.IniLetter()
+
+	var text bytes.Buffer
+	if err := letter.WriteText(&text); err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	for _, key := range []*domain.RSAKey{d.SigningKey(), d.EncryptionKey()} {
+		expectedHash := strings.Join(hexLines(d.profile.IniHash(key)), "\n")
+		if !strings.Contains(text.String(), expectedHash) {
+			t.Logf("Expected INI letter to contain hash\n%s\n\tgot\n%s\n", expectedHash, text.String())
+			t.Fail()
+		}
+	}
+	if !strings.Contains(text.String(), "Benutzerkennung: 12345") {
+		t.Logf("Expected INI letter to contain user ID, got\n%s\n", text.String())
+		t.Fail()
+	}
+	if !strings.Contains(text.String(), "Sicherheitsprofil: RDH-2") {
+		t.Logf("Expected INI letter to contain security profile, got\n%s\n", text.String())
+		t.Fail()
+	}
+
+	var pdf bytes.Buffer
+	if err := letter.WritePDF(&pdf); err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if !bytes.HasPrefix(pdf.Bytes(), []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf.Bytes(), []byte("%%EOF\n")) {
+		t.Logf("Expected valid PDF document, got\n%s\n", pdf.String())
+		t.Fail()
+	}
+	if !bytes.Contains(pdf.Bytes(), charset.ToISO8859_1("(Signierschlüssel")) {
+		t.Logf("Expected PDF to contain ISO-8859-1 encoded text\n")
+		t.Fail()
+	}
+}
+
+func newTestRDHDialog(t *testing.T, transport *mockHTTPSTransport, bankKey *domain.RSAKey) *RDHDialog {
+	d, err := NewRDHDialog(RDHConfig{
+		Config: Config{
+			BankID:      domain.BankID{CountryCode: 280, ID: "10000000"},
+			HBCIURL:     "http://localhost",
+			UserID:      "12345",
+			HBCIVersion: segment.FINTS300,
+		},
+		BankEncryptionKey: bankKey,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	d.transport = transport
+	return d
+}
+
+func newTestBankKey(t *testing.T) *domain.RSAKey {
+	key, err := domain.GenerateEncryptionKey()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	return domain.NewRSAKey(key, &domain.KeyName{
+		BankID:     domain.BankID{CountryCode: 280, ID: "10000000"},
+		UserID:     "12345",
+		KeyType:    domain.KeyTypeEncryption,
+		KeyNumber:  3,
+		KeyVersion: 7,
+	})
+}
+
+func newTestBankSigningKey(t *testing.T) *domain.RSAKey {
+	key, err := domain.GenerateSigningKey()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	publicKey := domain.NewEncryptionKey(key.Modulus, key.Exponent)
+	publicKey.Type = domain.KeyTypeSigning.String()
+	return domain.NewRSAKey(publicKey, &domain.KeyName{
+		BankID:     domain.BankID{CountryCode: 280, ID: "10000000"},
+		UserID:     "12345",
+		KeyType:    domain.KeyTypeSigning,
+		KeyNumber:  3,
+		KeyVersion: 7,
+	})
+}
+
+func publicKeyTransmission(key *domain.RSAKey) string {
+	keyName := key.KeyName()
+	return fmt.Sprintf(
+		"HIISA:3:2:5+1+abcde+1+224+280:10000000:%s:%s:%d:%d+5:16:10:@%d@%s:12:@%d@%s:13'",
+		keyName.UserID, keyName.KeyType, keyName.KeyNumber, keyName.KeyVersion,
+		len(key.Modulus), key.Modulus, len(key.Exponent), key.Exponent,
+	)
+}
+
+// unencryptedTestMessage wraps the segments into a message. Segments are
+// expected to be ISO-8859-1 encoded already, as they may contain binary data.
+func unencryptedTestMessage(dialogID string, segments ...string) []byte {
+	body := strings.Join(segments, "")
+	messageEnd := fmt.Sprintf("HNHBS:%d:1+1'", len(segments)+2)
+	headerSuffix := fmt.Sprintf("+300+%s+1+%s:1'", dialogID, dialogID)
+	length := len("HNHBK:1:3+") + 12 + len(headerSuffix) + len(body) + len(messageEnd)
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "HNHBK:1:3+%012d%s", length, headerSuffix)
+	buf.WriteString(body)
+	buf.WriteString(messageEnd)
+	return buf.Bytes()
+}
+
+func rdhEncryptedTestMessage(t *testing.T, provider message.CryptoProvider, dialogID string, segments string) []byte {
+	return rdhEncryptedRawTestMessage(t, provider, dialogID, charset.ToISO8859_1(segments))
+}
+
+func rdhEncryptedRawTestMessage(t *testing.T, provider message.CryptoProvider, dialogID string, segments []byte) []byte {
+	encrypted, err := provider.Encrypt(segments)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	keyName := domain.NewInitialKeyName(280, "10000000", "12345", domain.KeyTypeEncryption)
+	header := segment.NewPinTanEncryptionHeaderSegmentV3("0", *keyName)
+	provider.WriteEncryptionHeader(header)
+	marshaledHeader, err := header.MarshalHBCI()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	encryptedData := fmt.Sprintf("HNVSD:999:1+@%d@%s'", len(encrypted), encrypted)
+	return unencryptedTestMessage(dialogID, string(marshaledHeader), encryptedData)
+}
+
+func signedTestSegments(t *testing.T, key *domain.RSAKey, segments string) string {
+	provider := message.NewRDHSignatureProvider(key, 1)
+	header := segment.FINTS300.SignatureHeader()
+	provider.WriteSignatureHeader(header)
+	header.SetPosition(func() int { return 2 })
+	signedData := header.String() + segments
+	signature, err := provider.Sign([]byte(signedData))
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	end := segment.FINTS300.SignatureEnd()
+	provider.WriteSignature(end, signature)
+	end.SetPosition(func() int { return 4 })
+	return signedData + end.String()
+}
+
+func readRequest(t *testing.T, transport *mockHTTPSTransport, index int) string {
+	if len(transport.requests) <= index {
+		t.Fatalf("Expected at least %d requests, got %d\n", index+1, len(transport.requests))
+	}
+	body, err := io.ReadAll(transport.requests[index].Body)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	return string(body)
+}
+
+func decryptRDHRequest(t *testing.T, bankKey *domain.RSAKey, request string) string {
+	extractor := message.NewSegmentExtractor([]byte(request))
+	if _, err := extractor.Extract(); err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	encryptionHeader := &segment.EncryptionHeaderSegment{}
+	if err := encryptionHeader.UnmarshalHBCI(extractor.FindSegment("HNVSK")); err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	encryptedData := &segment.EncryptedDataSegment{}
+	if err := encryptedData.UnmarshalHBCI(extractor.FindSegment("HNVSD")); err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	provider := message.NewRDHCryptoProvider(nil, bankKey, "0")
+	if err := provider.(interface {
+		ReadEncryptionHeader(segment.EncryptionHeader) error
+	}).ReadEncryptionHeader(encryptionHeader); err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	decrypted, err := provider.Decrypt(encryptedData.Data.Val())
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	return charset.ToUTF8(decrypted)
+}
@@ -0,0 +1,183 @@
+package dialog
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/internal"
+	"github.com/mitch000001/go-hbci/message"
+)
+
+// DefaultRetryableSegments lists the IDs of business transactions which only
+// read data from the bank institute and can be sent again safely.
+var DefaultRetryableSegments = []string{
+	"HKSAL", // balances
+	"HKKAZ", // account transactions
+	"HKCAZ", // account transactions in camt format
+	"HKSPA", // SEPA account information
+	"HKKIF", // account information
+	"HKPRO", // status protocol
+	"HKKOM", // communication access
+	"HKPRB", // push service client products
+	"HKTAB", // TAN media
+}
+
+// DefaultRecoveryPolicy is used by dialogs without explicit RecoveryPolicy.
+// It recovers once from an aborted dialog.
+var DefaultRecoveryPolicy = RecoveryPolicy{
+	MaxAttempts: 1,
+}
+
+// RecoveryPolicy defines how a dialog recovers when the bank institute aborts
+// it, e.g. after a timeout or because of a message number mismatch.
+//
+// To recover, the dialog is initialized again and the client system ID is
+// synchronized again if the bank institute rejects the new dialog as well.
+// Afterwards the message is sent again, but only if all of its business
+// transactions are retryable. Orders like transfers are never sent again, as
+// the bank institute may have executed them already.
+type RecoveryPolicy struct {
+	// MaxAttempts limits the number of recoveries per message. Zero disables
+	// recovery.
+	MaxAttempts int
+	// Backoff is waited before each attempt.
+	Backoff time.Duration
+	// RetryableSegments lists the IDs of business transactions to send
+	// again. Defaults to DefaultRetryableSegments.
+	RetryableSegments []string
+	// BeforeRecovery is called with the attempt, starting at 1, and the
+	// error which aborted the dialog. It may be nil.
+	BeforeRecovery func(attempt int, cause error)
+	// AfterRecovery is called with the attempt and the error of the
+	// recovery, which is nil on success. It may be nil.
+	AfterRecovery func(attempt int, err error)
+}
+
+func (r RecoveryPolicy) retryable(clientMessage message.HBCIMessage) bool {
+	retryableSegments := r.RetryableSegments
+	if retryableSegments == nil {
+		retryableSegments = DefaultRetryableSegments
+	}
+	jobs := 0
+	for _, seg := range clientMessage.HBCISegments() {
+		if seg == nil {
+			continue
+		}
+		id := seg.Header().ID.Val()
+		if id == "HKTAN" {
+			// TAN requests are only retryable together with the job
+			// they are referencing
+			continue
+		}
+		if !contains(retryableSegments, id) {
+			return false
+		}
+		jobs++
+	}
+	return jobs > 0
+}
+
+func (r RecoveryPolicy) beforeRecovery(attempt int, cause error) {
+	internal.Info.Printf("Dialog aborted, recovering (attempt %d/%d): %v\n", attempt, r.MaxAttempts, cause)
+	if r.BeforeRecovery != nil {
+		r.BeforeRecovery(attempt, cause)
+	}
+}
+
+func (r RecoveryPolicy) afterRecovery(attempt int, err error) {
+	if err != nil {
+		internal.Info.Printf("Dialog recovery failed (attempt %d/%d): %v\n", attempt, r.MaxAttempts, err)
+	}
+	if r.AfterRecovery != nil {
+		r.AfterRecovery(attempt, err)
+	}
+}
+
+// SetRecoveryPolicy sets the policy used to recover aborted dialogs
+func (d *dialog) SetRecoveryPolicy(policy RecoveryPolicy) {
+	d.recovery = policy
+}
+
+// sendWithRecovery sends clientMessage and recovers from an aborted dialog
+// as defined by the RecoveryPolicy of the dialog.
+func (d *dialog) sendWithRecovery(clientMessage message.HBCIMessage) (message.BankMessage, error) {
+	bankMessage, err := d.send(clientMessage)
+	for attempt := 1; err != nil && dialogAborted(err); attempt++ {
+		// the dialog is not usable anymore
+		d.reset()
+		if attempt > d.recovery.MaxAttempts || !d.recovery.retryable(clientMessage) {
+			return nil, err
+		}
+		d.recovery.beforeRecovery(attempt, err)
+		if d.recovery.Backoff > 0 {
+			time.Sleep(d.recovery.Backoff)
+		}
+		recoveryErr := d.recover()
+		d.recovery.afterRecovery(attempt, recoveryErr)
+		if recoveryErr != nil {
+			d.reset()
+			return nil, fmt.Errorf("error recovering dialog: %w (aborted with: %v)", recoveryErr, err)
+		}
+		bankMessage, err = d.send(clientMessage)
+	}
+	return bankMessage, err
+}
+
+// recover initializes a new dialog. If the bank institute aborts the new
+// dialog as well, the client system ID is synchronized again.
+func (d *dialog) recover() error {
+	err := d.init()
+	if err == nil || !dialogAborted(err) {
+		return err
+	}
+	internal.Info.Printf("Dialog initialization aborted, synchronizing client system ID\n")
+	d.reset()
+	d.SetClientSystemID(initialClientSystemID)
+	return d.init()
+}
+
+// reset drops the current dialog, so that Close does not try to end it.
+func (d *dialog) reset() {
+	d.dialogID = initialDialogID
+	d.messageCount = 0
+}
+
+// dialogAborted reports whether err means that the bank institute aborted
+// the dialog, e.g. because it timed out or the message number did not match.
+// These are all message acknowledgements recommending to restart the dialog.
+// Rejected PINs never count as aborted dialog, as the PIN must not be sent
+// again.
+func dialogAborted(err error) bool {
+	if errors.Is(err, domain.ErrPinWrong) || errors.Is(err, domain.ErrAccountLocked) {
+		return false
+	}
+	var ackErr *domain.AcknowledgementError
+	if !errors.As(err, &ackErr) {
+		return false
+	}
+	for _, ack := range ackErr.Acknowledgements {
+		if !ack.IsMessageAcknowledgement() {
+			continue
+		}
+		if ack.ReturnCode().Action == domain.ActionRestartDialog {
+			return true
+		}
+		if ack.Code == domain.ReturnCodeProcessingNotPossible {
+			// rejected on message level without a single segment being
+			// processed, as for unknown dialogs or message numbers
+			return len(ackErr.Errors()) == 1
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
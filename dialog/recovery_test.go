@@ -0,0 +1,68 @@
+package dialog
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+func TestDialogAborted(t *testing.T) {
+	messageAck := func(code int) domain.Acknowledgement {
+		return domain.NewMessageAcknowledgement(code, "", "", nil)
+	}
+	segmentAck := func(code int) domain.Acknowledgement {
+		return domain.NewSegmentAcknowledgement(code, "", "", nil)
+	}
+	tests := []struct {
+		description string
+		err         error
+		expected    bool
+	}{
+		{"dialog aborted", &domain.AcknowledgementError{Acknowledgements: []domain.Acknowledgement{messageAck(9800)}}, true},
+		{"wrapped", fmt.Errorf("wrapped: %w", &domain.AcknowledgementError{Acknowledgements: []domain.Acknowledgement{messageAck(9800)}}), true},
+		{"unknown structure", &domain.AcknowledgementError{Acknowledgements: []domain.Acknowledgement{messageAck(9110)}}, true},
+		{"message not expected", &domain.AcknowledgementError{Acknowledgements: []domain.Acknowledgement{messageAck(9120)}}, true},
+		{"status unknown", &domain.AcknowledgementError{Acknowledgements: []domain.Acknowledgement{messageAck(9000)}}, false},
+		{"message rejected", &domain.AcknowledgementError{Acknowledgements: []domain.Acknowledgement{messageAck(9010)}}, true},
+		{"job rejected", &domain.AcknowledgementError{Acknowledgements: []domain.Acknowledgement{messageAck(9050), segmentAck(9010)}}, false},
+		{"PIN wrong", &domain.AcknowledgementError{Acknowledgements: []domain.Acknowledgement{messageAck(9800), segmentAck(9942)}}, false},
+		{"other error", fmt.Errorf("connection refused"), false},
+	}
+	for _, test := range tests {
+		actual := dialogAborted(test.err)
+
+		if actual != test.expected {
+			t.Logf("%s: Expected dialogAborted to return %t, got %t\n", test.description, test.expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+func TestRecoveryPolicyRetryable(t *testing.T) {
+	version := segment.FINTS300
+	account := domain.InternationalAccountConnection{IBAN: "DE89370400440532013000", BankID: domain.BankID{CountryCode: 280, ID: "10000000"}}
+	balanceRequest := segment.NewAccountBalanceRequestV7(account, false)
+	pinChangeRequest := segment.NewPinChangeRequestSegment("12345")
+
+	tests := []struct {
+		description string
+		message     message.HBCIMessage
+		expected    bool
+	}{
+		{"read only job", message.NewHBCIMessage(version, version.TanProcess4Request(segment.IdentificationID), balanceRequest), true},
+		{"order", message.NewHBCIMessage(version, version.TanProcess4Request(segment.PinChangeRequestID), pinChangeRequest), false},
+		{"mixed", message.NewHBCIMessage(version, balanceRequest, pinChangeRequest), false},
+		{"TAN only", message.NewHBCIMessage(version, version.TanProcess4Request(segment.IdentificationID)), false},
+	}
+	for _, test := range tests {
+		actual := DefaultRecoveryPolicy.retryable(test.message)
+
+		if actual != test.expected {
+			t.Logf("%s: Expected retryable to return %t, got %t\n", test.description, test.expected, actual)
+			t.Fail()
+		}
+	}
+}
@@ -21,10 +21,9 @@ func (at AccountTransactions) String() string {
 			first.Account.BankID, first.Account.AccountID,
 		)
 		fmt.Fprintf(
-			&buf, "Balance at %s: %.2f %s\n",
+			&buf, "Balance at %s: %s\n",
 			first.AccountBalanceBefore.TransmissionDate.Format("2006-01-02"),
-			first.AccountBalanceBefore.Amount.Amount,
-			first.AccountBalanceBefore.Amount.Currency,
+			first.AccountBalanceBefore.Amount,
 		)
 	}
 	buf.WriteString("BookingDate\tBooking Text\tAmount\tBankID\tAccountID\tName\tPurpose")
@@ -34,7 +33,7 @@ func (at AccountTransactions) String() string {
 		buf.WriteString("\t")
 		buf.WriteString(a.BookingText)
 		buf.WriteString("\t")
-		buf.WriteString(fmt.Sprintf("%.2f %s", a.Amount.Amount, a.Amount.Currency))
+		buf.WriteString(a.Amount.String())
 		buf.WriteString("\t")
 		buf.WriteString(a.BankID)
 		buf.WriteString("\t")
@@ -48,10 +47,9 @@ func (at AccountTransactions) String() string {
 	if len(at) != 0 {
 		last := at[len(at)-1]
 		fmt.Fprintf(
-			&buf, "Balance at %s: %.2f %s\n",
+			&buf, "Balance at %s: %s\n",
 			last.AccountBalanceAfter.TransmissionDate.Format("2006-01-02"),
-			last.AccountBalanceAfter.Amount.Amount,
-			last.AccountBalanceAfter.Amount.Currency,
+			last.AccountBalanceAfter.Amount,
 		)
 	}
 	var out bytes.Buffer
@@ -61,21 +59,74 @@ func (at AccountTransactions) String() string {
 	return out.String()
 }
 
+// Statements splits at into the account statements the transactions belong
+// to. Consecutive transactions of the same account with the same statement
+// number and balances form one statement.
+func (at AccountTransactions) Statements() []AccountTransactions {
+	var statements []AccountTransactions
+	for i, transaction := range at {
+		if i == 0 || !sameStatement(at[i-1], transaction) {
+			statements = append(statements, nil)
+		}
+		statements[len(statements)-1] = append(statements[len(statements)-1], transaction)
+	}
+	return statements
+}
+
+func sameStatement(a, b AccountTransaction) bool {
+	return a.Account == b.Account &&
+		a.StatementNumber == b.StatementNumber &&
+		a.AccountBalanceBefore.Amount == b.AccountBalanceBefore.Amount &&
+		a.AccountBalanceBefore.TransmissionDate.Equal(b.AccountBalanceBefore.TransmissionDate) &&
+		a.AccountBalanceAfter.Amount == b.AccountBalanceAfter.Amount &&
+		a.AccountBalanceAfter.TransmissionDate.Equal(b.AccountBalanceAfter.TransmissionDate)
+}
+
 // AccountTransaction represents one transaction entry for a given account
 type AccountTransaction struct {
-	Account              AccountConnection
-	Amount               Amount
-	ValutaDate           time.Time
-	BookingDate          time.Time
-	BookingText          string
-	BankID               string
-	AccountID            string
-	Name                 string
-	Purpose              string
-	Purpose2             string
-	TransactionID        int
+	Account       AccountConnection
+	Amount        Amount
+	ValutaDate    time.Time
+	BookingDate   time.Time
+	BookingText   string
+	BankID        string
+	AccountID     string
+	Name          string
+	Purpose       string
+	Purpose2      string
+	TransactionID int
+	// MessageKeyAddition holds the Textschlüsselergänzung of field 86. For
+	// returns it contains the reason of the return.
+	MessageKeyAddition   int
+	Category             TransactionCategory
+	Reference            string
+	BankReference        string
+	StatementNumber      int
 	AccountBalanceBefore Balance
 	AccountBalanceAfter  Balance
+	// The following fields are only set for SEPA transactions. They are
+	// extracted from the qualifiers within the purpose, like EREF+ or SVWZ+.
+	EndToEndReference     string
+	CustomerReference     string
+	MandateReference      string
+	CreditorID            string
+	RemittanceInformation string
+	UltimateDebtor        string
+	UltimateCreditor      string
+	CounterpartyIBAN      string
+	CounterpartyBIC       string
+}
+
+// IsReturn reports whether a is a returned direct debit or credit transfer
+func (a AccountTransaction) IsReturn() bool {
+	return IsReturn(a.TransactionID, a.MessageKeyAddition)
+}
+
+// TransactionCode returns the catalog entry for the business transaction code
+// of a
+func (a AccountTransaction) TransactionCode() TransactionCode {
+	transactionCode, _ := LookupTransactionCode(a.TransactionID)
+	return transactionCode
 }
 
 func (a AccountTransaction) String() string {
@@ -85,7 +136,7 @@ func (a AccountTransaction) String() string {
 	buf.WriteString("\n")
 	buf.WriteString(a.BookingDate.Format("2006-01-02"))
 	buf.WriteString("\t")
-	buf.WriteString(fmt.Sprintf("%.2f %s", a.Amount.Amount, a.Amount.Currency))
+	buf.WriteString(a.Amount.String())
 	buf.WriteString("\t")
 	buf.WriteString(a.BankID)
 	buf.WriteString("\t")
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAccountTransactionsStatements(t *testing.T) {
+	account := AccountConnection{BankID: "12345678", AccountID: "1234123456", CountryCode: 280}
+	otherAccount := AccountConnection{BankID: "12345678", AccountID: "9999999999", CountryCode: 280}
+	balance := func(amount int64, day int) Balance {
+		return Balance{Amount: NewAmount(amount, "EUR"), TransmissionDate: time.Date(2021, 3, day, 0, 0, 0, 0, time.UTC)}
+	}
+	transactions := AccountTransactions{
+		{Account: account, StatementNumber: 1, AccountBalanceBefore: balance(100, 1), AccountBalanceAfter: balance(300, 2)},
+		{Account: account, StatementNumber: 1, AccountBalanceBefore: balance(100, 1), AccountBalanceAfter: balance(300, 2)},
+		{Account: account, StatementNumber: 2, AccountBalanceBefore: balance(300, 2), AccountBalanceAfter: balance(200, 3)},
+		{Account: otherAccount, StatementNumber: 2, AccountBalanceBefore: balance(300, 2), AccountBalanceAfter: balance(200, 3)},
+		{Account: account, StatementNumber: 2, AccountBalanceBefore: balance(300, 2), AccountBalanceAfter: balance(200, 3)},
+	}
+
+	statements := transactions.Statements()
+
+	expectedLengths := []int{2, 1, 1, 1}
+	if len(statements) != len(expectedLengths) {
+		t.Fatalf("Expected %d statements, got %d\n", len(expectedLengths), len(statements))
+	}
+	for i, statement := range statements {
+		if len(statement) != expectedLengths[i] {
+			t.Errorf("Expected statement %d to contain %d transactions, got %d", i, expectedLengths[i], len(statement))
+		}
+	}
+
+	if statements := (AccountTransactions{}).Statements(); len(statements) != 0 {
+		t.Errorf("Expected no statements for no transactions, got %d", len(statements))
+	}
+}
@@ -93,6 +93,18 @@ func (a Acknowledgement) IsSuccess() bool {
 	return a.Code > 0 && a.Code < 1000
 }
 
+// ReturnCode returns the catalog entry of the acknowledgement code
+func (a Acknowledgement) ReturnCode() ReturnCode {
+	returnCode, _ := LookupReturnCode(a.Code)
+	return returnCode
+}
+
+// Err returns the error the acknowledgement code maps to. It returns nil if
+// the code does not map to any error.
+func (a Acknowledgement) Err() error {
+	return a.ReturnCode().Err
+}
+
 // StatusAcknowledgement represents an Acknowledgement with a transmission date
 type StatusAcknowledgement struct {
 	Acknowledgement
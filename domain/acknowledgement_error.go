@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// These errors represent failure classes of bank institute responses. They
+// can be used with errors.Is on errors returned from dialogs and clients.
+var (
+	// ErrPinWrong is returned when the bank institute rejected the PIN
+	ErrPinWrong = errors.New("PIN wrong")
+	// ErrAccountLocked is returned when the access or the account is locked
+	ErrAccountLocked = errors.New("account locked")
+	// ErrTanRequired is returned when the order needs a TAN to be executed
+	ErrTanRequired = errors.New("TAN required")
+	// ErrTanWrong is returned when the bank institute rejected the TAN
+	ErrTanWrong = errors.New("TAN wrong")
+	// ErrDialogAborted is returned when the bank institute aborted the dialog
+	ErrDialogAborted = errors.New("dialog aborted")
+	// ErrBankUnavailable is returned when the bank institute is not able to
+	// process orders at the moment
+	ErrBankUnavailable = errors.New("bank institute unavailable")
+	// ErrMessageMalformed is returned when the bank institute was not able to
+	// parse the sent message
+	ErrMessageMalformed = errors.New("message malformed")
+	// ErrStatusUnknown is returned when it is unclear whether the bank
+	// institute processed the message or order
+	ErrStatusUnknown = errors.New("status unknown")
+)
+
+// NewAcknowledgementError returns an AcknowledgementError for the given
+// acknowledgements. It returns nil if none of the acknowledgements represents
+// an error.
+func NewAcknowledgementError(acknowledgements []Acknowledgement) *AcknowledgementError {
+	for _, ack := range acknowledgements {
+		if ack.IsError() {
+			return &AcknowledgementError{Acknowledgements: acknowledgements}
+		}
+	}
+	return nil
+}
+
+// AcknowledgementError represents a response of the bank institute which
+// contains at least one error acknowledgement. It holds all acknowledgements
+// of the response, as warnings can carry additional information about the
+// failure.
+type AcknowledgementError struct {
+	Acknowledgements []Acknowledgement
+}
+
+func (a *AcknowledgementError) Error() string {
+	var errs []string
+	for _, ack := range a.Errors() {
+		errs = append(errs, ack.String())
+	}
+	return "institute returned errors:\n" + strings.Join(errs, "\n")
+}
+
+// Errors returns all acknowledgements representing an error
+func (a *AcknowledgementError) Errors() []Acknowledgement {
+	var errs []Acknowledgement
+	for _, ack := range a.Acknowledgements {
+		if ack.IsError() {
+			errs = append(errs, ack)
+		}
+	}
+	return errs
+}
+
+// HasCode returns true if any of the acknowledgements has the given code
+func (a *AcknowledgementError) HasCode(code int) bool {
+	for _, ack := range a.Acknowledgements {
+		if ack.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Is implements the interface used by errors.Is. It reports whether any of
+// the acknowledgements maps to target.
+func (a *AcknowledgementError) Is(target error) bool {
+	for _, ack := range a.Acknowledgements {
+		if err := ack.Err(); err != nil && err == target {
+			return true
+		}
+	}
+	return false
+}
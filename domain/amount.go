@@ -0,0 +1,238 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// defaultCurrencyExponent is used for all currencies not listed in
+// currencyExponents and for amounts without currency.
+const defaultCurrencyExponent = 2
+
+// currencyExponents lists all ISO 4217 currencies whose minor unit differs
+// from defaultCurrencyExponent.
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// CurrencyExponent returns the number of decimal places of the minor unit of
+// currency as defined by ISO 4217.
+func CurrencyExponent(currency string) int {
+	if exponent, ok := currencyExponents[currency]; ok {
+		return exponent
+	}
+	return defaultCurrencyExponent
+}
+
+// NewAmount returns a new Amount with the provided value in minor units of the
+// currency, i.e. cents for EUR.
+func NewAmount(minorUnits int64, currency string) Amount {
+	return Amount{MinorUnits: minorUnits, Currency: currency}
+}
+
+// AmountFromFloat returns an Amount for value, rounded to the minor unit of
+// currency. It is meant for compatibility only, as float64 values can not
+// represent most decimal amounts exactly.
+func AmountFromFloat(value float64, currency string) Amount {
+	scale := math.Pow10(CurrencyExponent(currency))
+	return Amount{MinorUnits: int64(math.Round(value * scale)), Currency: currency}
+}
+
+// ParseAmount parses value in the HBCI notation with a decimal comma, i.e.
+// "1234,56", into an Amount of the given currency. It returns an error if value
+// is malformed or has more significant decimal places than the currency.
+func ParseAmount(value, currency string) (Amount, error) {
+	minorUnits, err := parseMinorUnits(value, CurrencyExponent(currency))
+	if err != nil {
+		return Amount{}, fmt.Errorf("malformed amount %q: %w", value, err)
+	}
+	return Amount{MinorUnits: minorUnits, Currency: currency}, nil
+}
+
+func parseMinorUnits(value string, exponent int) (int64, error) {
+	value = strings.TrimSpace(value)
+	negative := false
+	if strings.HasPrefix(value, "-") {
+		negative = true
+		value = value[1:]
+	}
+	integerPart, fractionPart := value, ""
+	if idx := strings.IndexByte(value, ','); idx != -1 {
+		integerPart, fractionPart = value[:idx], value[idx+1:]
+	}
+	if integerPart == "" && fractionPart == "" {
+		return 0, fmt.Errorf("no digits")
+	}
+	if integerPart == "" {
+		integerPart = "0"
+	}
+	if !isDigits(integerPart) || !isDigits(fractionPart) {
+		return 0, fmt.Errorf("invalid characters")
+	}
+	if len(fractionPart) > exponent {
+		if strings.Trim(fractionPart[exponent:], "0") != "" {
+			return 0, fmt.Errorf("more than %d decimal places", exponent)
+		}
+		fractionPart = fractionPart[:exponent]
+	}
+	fractionPart += strings.Repeat("0", exponent-len(fractionPart))
+	minorUnits, err := strconv.ParseInt(integerPart+fractionPart, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if negative {
+		minorUnits = -minorUnits
+	}
+	return minorUnits, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Amount represents an exact monetary value associated with a currency. The
+// value is stored in minor units of the currency, the number of decimal places
+// is defined by the ISO 4217 exponent of the currency.
+type Amount struct {
+	MinorUnits int64
+	Currency   string
+}
+
+// Exponent returns the number of decimal places of a.
+func (a Amount) Exponent() int {
+	return CurrencyExponent(a.Currency)
+}
+
+// Float returns the value of a as float64. It is meant for compatibility only
+// and must not be used for calculations.
+func (a Amount) Float() float64 {
+	return float64(a.MinorUnits) / math.Pow10(a.Exponent())
+}
+
+// IsZero returns true if the value of a is zero
+func (a Amount) IsZero() bool {
+	return a.MinorUnits == 0
+}
+
+// Sign returns -1 if a is negative, 0 if a is zero and 1 if a is positive.
+func (a Amount) Sign() int {
+	switch {
+	case a.MinorUnits < 0:
+		return -1
+	case a.MinorUnits > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Neg returns a with the sign inverted
+func (a Amount) Neg() Amount {
+	return Amount{MinorUnits: -a.MinorUnits, Currency: a.Currency}
+}
+
+// Abs returns the absolute value of a
+func (a Amount) Abs() Amount {
+	if a.MinorUnits < 0 {
+		return a.Neg()
+	}
+	return a
+}
+
+// Add returns the sum of a and b. It returns an error if the currencies differ.
+func (a Amount) Add(b Amount) (Amount, error) {
+	if a.Currency != b.Currency {
+		return Amount{}, fmt.Errorf("currency mismatch: %q and %q", a.Currency, b.Currency)
+	}
+	return Amount{MinorUnits: a.MinorUnits + b.MinorUnits, Currency: a.Currency}, nil
+}
+
+// Sub returns the difference of a and b. It returns an error if the currencies
+// differ.
+func (a Amount) Sub(b Amount) (Amount, error) {
+	return a.Add(b.Neg())
+}
+
+// Cmp compares a and b and returns -1 if a is less than b, 0 if they are equal
+// and 1 if a is greater than b. It returns an error if the currencies differ.
+func (a Amount) Cmp(b Amount) (int, error) {
+	diff, err := a.Sub(b)
+	if err != nil {
+		return 0, err
+	}
+	return diff.Sign(), nil
+}
+
+// WithCurrency returns a converted to currency. The minor units are rescaled
+// if the exponents of the currencies differ, rounding half away from zero if
+// decimal places have to be dropped.
+func (a Amount) WithCurrency(currency string) Amount {
+	minorUnits := a.MinorUnits
+	diff := CurrencyExponent(currency) - a.Exponent()
+	for ; diff > 0; diff-- {
+		minorUnits *= 10
+	}
+	if diff < 0 {
+		divisor := int64(math.Pow10(-diff))
+		quotient, remainder := minorUnits/divisor, minorUnits%divisor
+		if remainder*2 >= divisor {
+			quotient++
+		} else if remainder*2 <= -divisor {
+			quotient--
+		}
+		minorUnits = quotient
+	}
+	return Amount{MinorUnits: minorUnits, Currency: currency}
+}
+
+// Decimal returns the value of a in decimal notation with a decimal point and
+// all decimal places of the currency, i.e. "1234.50".
+func (a Amount) Decimal() string {
+	return a.format(".", true)
+}
+
+// FormatHBCI returns the value of a in the HBCI notation with a decimal comma
+// and without trailing zeros, i.e. "1234,5" or "20,".
+func (a Amount) FormatHBCI() string {
+	return a.format(",", false)
+}
+
+func (a Amount) format(separator string, keepTrailingZeros bool) string {
+	exponent := a.Exponent()
+	units := a.MinorUnits
+	sign := ""
+	if units < 0 {
+		sign = "-"
+	}
+	digits := strings.TrimPrefix(strconv.FormatInt(units, 10), "-")
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	integerPart := digits[:len(digits)-exponent]
+	fractionPart := digits[len(digits)-exponent:]
+	if !keepTrailingZeros {
+		fractionPart = strings.TrimRight(fractionPart, "0")
+	}
+	if keepTrailingZeros && fractionPart == "" {
+		return sign + integerPart
+	}
+	return sign + integerPart + separator + fractionPart
+}
+
+func (a Amount) String() string {
+	if a.Currency == "" {
+		return a.Decimal()
+	}
+	return a.Decimal() + " " + a.Currency
+}
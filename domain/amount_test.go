@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value    string
+		currency string
+		expected Amount
+		err      bool
+	}{
+		{"1234,56", "EUR", NewAmount(123456, "EUR"), false},
+		{"20,", "EUR", NewAmount(2000, "EUR"), false},
+		{"0,5", "EUR", NewAmount(50, "EUR"), false},
+		{",5", "EUR", NewAmount(50, "EUR"), false},
+		{"-4,52", "EUR", NewAmount(-452, "EUR"), false},
+		{"1,500", "EUR", NewAmount(150, "EUR"), false},
+		{"1000", "JPY", NewAmount(1000, "JPY"), false},
+		{"1,234", "KWD", NewAmount(1234, "KWD"), false},
+		{"1,234", "EUR", Amount{}, true},
+		{"1.234,00", "EUR", Amount{}, true},
+		{"", "EUR", Amount{}, true},
+		{"abc", "EUR", Amount{}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.value+" "+test.currency, func(t *testing.T) {
+			amount, err := ParseAmount(test.value, test.currency)
+
+			if test.err && err == nil {
+				t.Errorf("Expected error, got nil")
+			}
+			if !test.err && err != nil {
+				t.Errorf("Expected no error, got %T:%v", err, err)
+			}
+			if amount != test.expected {
+				t.Errorf("Expected amount to equal %#v, got %#v", test.expected, amount)
+			}
+		})
+	}
+}
+
+func TestAmountFormat(t *testing.T) {
+	tests := []struct {
+		amount          Amount
+		expectedHBCI    string
+		expectedDecimal string
+	}{
+		{NewAmount(123456, "EUR"), "1234,56", "1234.56"},
+		{NewAmount(2000, "EUR"), "20,", "20.00"},
+		{NewAmount(150, "EUR"), "1,5", "1.50"},
+		{NewAmount(5, "EUR"), "0,05", "0.05"},
+		{NewAmount(0, "EUR"), "0,", "0.00"},
+		{NewAmount(-452, "EUR"), "-4,52", "-4.52"},
+		{NewAmount(1000, "JPY"), "1000,", "1000"},
+		{NewAmount(1234, "KWD"), "1,234", "1.234"},
+	}
+	for _, test := range tests {
+		t.Run(test.expectedDecimal, func(t *testing.T) {
+			if hbci := test.amount.FormatHBCI(); hbci != test.expectedHBCI {
+				t.Errorf("Expected HBCI notation to equal %q, got %q", test.expectedHBCI, hbci)
+			}
+			if decimal := test.amount.Decimal(); decimal != test.expectedDecimal {
+				t.Errorf("Expected decimal notation to equal %q, got %q", test.expectedDecimal, decimal)
+			}
+		})
+	}
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	sum := NewAmount(0, "EUR")
+	for i := 0; i < 10; i++ {
+		var err error
+		sum, err = sum.Add(NewAmount(10, "EUR"))
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v", err, err)
+		}
+	}
+	if sum != NewAmount(100, "EUR") {
+		t.Errorf("Expected sum to equal 1.00 EUR, got %s", sum)
+	}
+
+	if _, err := sum.Add(NewAmount(100, "USD")); err == nil {
+		t.Errorf("Expected error for currency mismatch, got nil")
+	}
+
+	cmp, err := NewAmount(-5, "EUR").Cmp(NewAmount(3, "EUR"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v", err, err)
+	}
+	if cmp != -1 {
+		t.Errorf("Expected -1, got %d", cmp)
+	}
+
+	if abs := NewAmount(-5, "EUR").Abs(); abs != NewAmount(5, "EUR") {
+		t.Errorf("Expected absolute value to equal 0.05 EUR, got %s", abs)
+	}
+}
+
+func TestAmountWithCurrency(t *testing.T) {
+	tests := []struct {
+		amount   Amount
+		currency string
+		expected Amount
+	}{
+		{NewAmount(452, ""), "EUR", NewAmount(452, "EUR")},
+		{NewAmount(452, ""), "KWD", NewAmount(4520, "KWD")},
+		{NewAmount(100000, ""), "JPY", NewAmount(1000, "JPY")},
+		{NewAmount(150, ""), "JPY", NewAmount(2, "JPY")},
+		{NewAmount(-150, ""), "JPY", NewAmount(-2, "JPY")},
+		{NewAmount(149, ""), "JPY", NewAmount(1, "JPY")},
+	}
+	for _, test := range tests {
+		t.Run(test.expected.String(), func(t *testing.T) {
+			if actual := test.amount.WithCurrency(test.currency); actual != test.expected {
+				t.Errorf("Expected amount to equal %#v, got %#v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAmountFromFloat(t *testing.T) {
+	amount := AmountFromFloat(1499.85, "EUR")
+
+	if amount != NewAmount(149985, "EUR") {
+		t.Errorf("Expected amount to equal 1499.85 EUR, got %s", amount)
+	}
+	if amount.Float() != 1499.85 {
+		t.Errorf("Expected float to equal 1499.85, got %f", amount.Float())
+	}
+}
@@ -11,6 +11,9 @@ type BankParameterData struct {
 	MinTimeout                 int             `yaml:"minTimeout"`
 	MaxTimeout                 int             `yaml:"maxTimeout"`
 	PinTanBusinessTransactions map[string]bool `yaml:"pinTanBusinessTransactions"`
+	// PushServiceParameters is nil if the bank institute does not offer push
+	// services
+	PushServiceParameters *PushServiceParameters `yaml:"pushServiceParameters,omitempty"`
 }
 
 // PinTanBusinessTransaction provides information about whether a given Segment
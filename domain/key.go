@@ -1,8 +1,12 @@
 package domain
 
 import (
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+	"hash"
 	"math/big"
 )
 
@@ -154,6 +158,45 @@ func GenerateSigningKey() (*PublicKey, error) {
 	return &p, nil
 }
 
+// GenerateEncryptionKey generates a new encryption key
+func GenerateEncryptionKey() (*PublicKey, error) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 768)
+	if err != nil {
+		return nil, err
+	}
+	p := PublicKey{
+		Type:          "V",
+		Modulus:       rsaKey.N.Bytes(),
+		Exponent:      big.NewInt(int64(rsaKey.E)).Bytes(),
+		rsaPrivateKey: rsaKey,
+		rsaPublicKey:  &rsaKey.PublicKey,
+	}
+	return &p, nil
+}
+
+// GenerateKey generates a new key of keyType with the given size in bits
+func GenerateKey(keyType KeyType, bits int) (*PublicKey, error) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrivateKey(keyType, rsaKey), nil
+}
+
+// NewPrivateKey creates a new key of keyType from an existing RSA private key
+func NewPrivateKey(keyType KeyType, privateKey *rsa.PrivateKey) *PublicKey {
+	p := PublicKey{
+		Type:          keyType.String(),
+		Modulus:       privateKey.N.Bytes(),
+		Exponent:      big.NewInt(int64(privateKey.E)).Bytes(),
+		rsaPrivateKey: privateKey,
+	}
+	if keyType == KeyTypeEncryption {
+		p.rsaPublicKey = &privateKey.PublicKey
+	}
+	return &p
+}
+
 // NewRSAKey returns a new RSA key
 func NewRSAKey(pubKey *PublicKey, keyName *KeyName) *RSAKey {
 	return &RSAKey{PublicKey: pubKey, keyName: keyName}
@@ -193,10 +236,10 @@ func (r *RSAKey) CanEncrypt() bool {
 // NewEncryptionKey creates a new RSA encryption key
 func NewEncryptionKey(modulus, exponent []byte) *PublicKey {
 	p := &PublicKey{
-		Type: "V",
+		Type:     "V",
+		Modulus:  append([]byte(nil), modulus...),
+		Exponent: append([]byte(nil), exponent...),
 	}
-	copy(p.Modulus, modulus)
-	copy(p.Exponent, exponent)
 	mod := new(big.Int).SetBytes(modulus)
 	exp := new(big.Int).SetBytes(exponent)
 	pubKey := rsa.PublicKey{
@@ -221,6 +264,11 @@ func (p *PublicKey) SigningKey() *rsa.PrivateKey {
 	return p.rsaPrivateKey
 }
 
+// PrivateKey returns the RSA private key, or nil when not set
+func (p *PublicKey) PrivateKey() *rsa.PrivateKey {
+	return p.rsaPrivateKey
+}
+
 // Sign signs message with the private key
 func (p *PublicKey) Sign(message []byte) ([]byte, error) {
 	return rsa.SignPKCS1v15(rand.Reader, p.rsaPrivateKey, 0, message)
@@ -230,3 +278,116 @@ func (p *PublicKey) Sign(message []byte) ([]byte, error) {
 func (p *PublicKey) Encrypt(message []byte) ([]byte, error) {
 	return rsa.EncryptPKCS1v15(rand.Reader, p.rsaPublicKey, message)
 }
+
+// SignPSS signs the SHA-256 hash of a message with RSASSA-PSS, using a salt
+// of the length of the hash
+func (p *PublicKey) SignPSS(hashed []byte) ([]byte, error) {
+	if p.rsaPrivateKey == nil {
+		return nil, fmt.Errorf("no private key available")
+	}
+	return rsa.SignPSS(rand.Reader, p.rsaPrivateKey, crypto.SHA256, hashed, &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+	})
+}
+
+// Verify verifies the PKCS #1 v1.5 signature of the already hashed message
+// with the public key
+func (p *PublicKey) Verify(hashed, signature []byte) error {
+	pubKey := p.publicKey()
+	if pubKey == nil {
+		return fmt.Errorf("no public key available")
+	}
+	return rsa.VerifyPKCS1v15(pubKey, 0, hashed, signature)
+}
+
+// VerifyPSS verifies the RSASSA-PSS signature of the SHA-256 hash of a message
+// with the public key
+func (p *PublicKey) VerifyPSS(hashed, signature []byte) error {
+	pubKey := p.publicKey()
+	if pubKey == nil {
+		return fmt.Errorf("no public key available")
+	}
+	return rsa.VerifyPSS(pubKey, crypto.SHA256, hashed, signature, &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+	})
+}
+
+// EncryptOAEP encrypts message with RSAES-OAEP, using SHA-256
+func (p *PublicKey) EncryptOAEP(message []byte) ([]byte, error) {
+	pubKey := p.publicKey()
+	if pubKey == nil {
+		return nil, fmt.Errorf("no public key available")
+	}
+	return rsa.EncryptOAEP(sha256.New(), rand.Reader, pubKey, message, nil)
+}
+
+// DecryptOAEP decrypts ciphertext with RSAES-OAEP, using SHA-256
+func (p *PublicKey) DecryptOAEP(ciphertext []byte) ([]byte, error) {
+	if p.rsaPrivateKey == nil {
+		return nil, fmt.Errorf("no private key available")
+	}
+	return rsa.DecryptOAEP(sha256.New(), nil, p.rsaPrivateKey, ciphertext, nil)
+}
+
+// EncryptRaw encrypts message with the plain RSA function, without any
+// padding applied. The result has the length of the modulus.
+func (p *PublicKey) EncryptRaw(message []byte) ([]byte, error) {
+	pubKey := p.publicKey()
+	if pubKey == nil {
+		return nil, fmt.Errorf("no public key available")
+	}
+	m := new(big.Int).SetBytes(message)
+	if m.Cmp(pubKey.N) >= 0 {
+		return nil, fmt.Errorf("message too long for RSA key size")
+	}
+	c := new(big.Int).Exp(m, big.NewInt(int64(pubKey.E)), pubKey.N)
+	return leftPad(c.Bytes(), (pubKey.N.BitLen()+7)/8), nil
+}
+
+// DecryptRaw decrypts ciphertext with the plain RSA function, without any
+// padding removed. The result has the length of the modulus.
+func (p *PublicKey) DecryptRaw(ciphertext []byte) ([]byte, error) {
+	if p.rsaPrivateKey == nil {
+		return nil, fmt.Errorf("no private key available")
+	}
+	c := new(big.Int).SetBytes(ciphertext)
+	if c.Cmp(p.rsaPrivateKey.N) >= 0 {
+		return nil, fmt.Errorf("ciphertext too long for RSA key size")
+	}
+	m := new(big.Int).Exp(c, p.rsaPrivateKey.D, p.rsaPrivateKey.N)
+	return leftPad(m.Bytes(), (p.rsaPrivateKey.N.BitLen()+7)/8), nil
+}
+
+// IniHash returns the hash of the key as printed on INI letters, computed with
+// h over exponent and modulus. Both are left padded with zeros to the length
+// of the modulus, but at least to minLength bytes. The hash algorithm and
+// minimal length depend on the security profile.
+func (p *PublicKey) IniHash(h hash.Hash, minLength int) []byte {
+	length := minLength
+	if len(p.Modulus) > length {
+		length = len(p.Modulus)
+	}
+	h.Reset()
+	h.Write(leftPad(p.Exponent, length))
+	h.Write(leftPad(p.Modulus, length))
+	return h.Sum(nil)
+}
+
+func (p *PublicKey) publicKey() *rsa.PublicKey {
+	if p.rsaPublicKey != nil {
+		return p.rsaPublicKey
+	}
+	if p.rsaPrivateKey != nil {
+		return &p.rsaPrivateKey.PublicKey
+	}
+	return nil
+}
+
+func leftPad(b []byte, length int) []byte {
+	if len(b) >= length {
+		return b
+	}
+	padded := make([]byte, length)
+	copy(padded[length-len(b):], b)
+	return padded
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"testing"
+
+	"golang.org/x/crypto/ripemd160"
+)
+
+func TestPublicKeyEncryptRawDecryptRaw(t *testing.T) {
+	key, err := GenerateEncryptionKey()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	message := []byte("0123456789abcdef")
+
+	encrypted, err := key.EncryptRaw(message)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if len(encrypted) != len(key.Modulus) {
+		t.Logf("Expected encrypted message to have length %d, got %d\n", len(key.Modulus), len(encrypted))
+		t.Fail()
+	}
+
+	decrypted, err := key.DecryptRaw(encrypted)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if !bytes.HasSuffix(decrypted, message) {
+		t.Logf("Expected decrypted message to end with %q, got %q\n", message, decrypted)
+		t.Fail()
+	}
+
+	publicOnly := NewEncryptionKey(key.Modulus, key.Exponent)
+	_, err = publicOnly.DecryptRaw(encrypted)
+	if err == nil {
+		t.Logf("Expected error when decrypting without private key\n")
+		t.Fail()
+	}
+}
+
+func TestPublicKeyIniHash(t *testing.T) {
+	// Example key of the INI letter in the HBCI security specification
+	modulus, err := hex.DecodeString("" +
+		"97a9d8fd6846ba8c5edbe0f51c3541413c4ab37c956acc1129e3a763a67ba86d" +
+		"e81d99d274246e6cc6fda99ca2191544bf2a5db6935bfafb8819feb21c96fb4f" +
+		"5e666a2df8805e849b428ce69a76ab525a36c16d81d1650abc863586e78e5bb5" +
+		"efe084a1447669de34d531a2d0250278b5beabc0d930b10491df0d3be1333de7" +
+		"2457cde5a213ef954b23815891608571393e00927cf9063a973d3f875280af4e" +
+		"3739f19e14a57130336ce4dd3866e8b2296bbff225363e0fa055394a317a6988" +
+		"9bd626b6b653cac7404a607929c497dc433b54a6b64433e0e80517435013eed4" +
+		"f2eff2bdb22ab9875295fc56932475a7e7d1cd909ad3d878991dc3214d2f5aa3",
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	key := NewEncryptionKey(modulus, []byte{0x01, 0x00, 0x01})
+
+	tests := []struct {
+		description string
+		hash        hash.Hash
+		minLength   int
+		expected    string
+	}{
+		// RDH-10 and RAH-10
+		{"SHA-256", sha256.New(), 256, "BF C2 A1 01 7C 65 31 9C 8B 1B D8 26 09 85 E6 1F A9 99 1A 65 17 BF 67 86 17 D8 7C EE DC C3 61 11"},
+		// RDH-2, the specification misprints the 14th byte as 3D
+		{"RIPEMD-160", ripemd160.New(), 128, "0B AD D5 D5 FD 57 0D 34 E7 84 7C ED AE 4B 4D EE 6B EB 98 AC"},
+	}
+	for _, test := range tests {
+		actual := fmt.Sprintf("% X", key.IniHash(test.hash, test.minLength))
+
+		if test.expected != actual {
+			t.Logf("%s: Expected hash to equal\n%q\n\tgot\n%q\n", test.description, test.expected, actual)
+			t.Fail()
+		}
+	}
+}
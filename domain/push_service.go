@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// PushServiceParameters represents the parameters of the push services
+// (Echtzeitbenachrichtigungen) offered by the bank institute
+type PushServiceParameters struct {
+	// WebSocketURL is the URL to receive notifications from
+	WebSocketURL string `yaml:"webSocketURL"`
+	// PollingInterval is the time after which the client may fall back to
+	// polling if a WebSocket connection can not be established
+	PollingInterval time.Duration `yaml:"pollingInterval"`
+	// UseUserID defines whether the user ID is part of the credentials to
+	// establish the WebSocket connection
+	UseUserID bool `yaml:"useUserID"`
+	// SegmentIDs lists the business transactions which can be registered for
+	// push services
+	SegmentIDs []string `yaml:"segmentIDs"`
+}
+
+// PushServiceRegistration represents the registration of a client product for
+// push services
+type PushServiceRegistration struct {
+	// Token is the password to establish WebSocket connections
+	Token string
+	// ValidUntil defines the end of validity of Token. A zero value means the
+	// token is valid indefinitely.
+	ValidUntil time.Time
+	// SegmentIDs lists the business transactions registered for push services
+	SegmentIDs []string
+}
+
+// PushServiceClientProduct identifies a client product registered for push
+// services
+type PushServiceClientProduct struct {
+	ProductName    string
+	ProductVersion string
+	Manufacturer   string
+	// ClientSystemName is the unique name the user gave the client system,
+	// e.g. 'Laptop'
+	ClientSystemName string
+}
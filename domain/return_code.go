@@ -0,0 +1,139 @@
+package domain
+
+import "fmt"
+
+//go:generate go run ../cmd/returncode_extractor/returncode_extractor.go -o ../doc/FinTS_Rueckmeldungscodes_2021-07-07.csv ../doc/FinTS_Rueckmeldungscodes_2021-07-07_final_version.pdf
+//go:generate go run ../cmd/returncode_generator/returncode_generator.go -o return_code_data.go -catalog ../doc/FinTS_Rueckmeldungscodes_2021-07-07.csv ../doc/FinTS_Rueckmeldungscodes.csv
+
+// ReturnCodeCategory defines the class of a return code as defined by the
+// FinTS specification
+type ReturnCodeCategory int
+
+const (
+	// ReturnCodeCategoryUnknown represents codes outside of the defined ranges
+	ReturnCodeCategoryUnknown ReturnCodeCategory = iota
+	// ReturnCodeCategorySuccess represents codes from 0000 to 0999
+	ReturnCodeCategorySuccess
+	// ReturnCodeCategoryWarning represents codes from 3000 to 3999
+	ReturnCodeCategoryWarning
+	// ReturnCodeCategoryError represents codes from 9000 to 9999
+	ReturnCodeCategoryError
+	// ReturnCodeCategoryNote represents codes from 1000 to 1999, which are
+	// only used by FinTS 4
+	ReturnCodeCategoryNote
+)
+
+func (r ReturnCodeCategory) String() string {
+	switch r {
+	case ReturnCodeCategorySuccess:
+		return "success"
+	case ReturnCodeCategoryWarning:
+		return "warning"
+	case ReturnCodeCategoryError:
+		return "error"
+	case ReturnCodeCategoryNote:
+		return "note"
+	default:
+		return "unknown"
+	}
+}
+
+// ClientAction describes what a client is recommended to do after receiving a
+// return code
+type ClientAction string
+
+// These represent the recommended client actions for return codes
+const (
+	// ActionNone means the client has nothing to do
+	ActionNone ClientAction = "none"
+	// ActionContinue means the client should request further data with the
+	// provided continuation reference
+	ActionContinue ClientAction = "continue"
+	// ActionProvideTAN means the order needs a TAN or another security
+	// clearance to be executed
+	ActionProvideTAN ClientAction = "provide_tan"
+	// ActionReenterPIN means the PIN was not accepted and the user must enter it
+	// again. The client must not retry with the same PIN.
+	ActionReenterPIN ClientAction = "reenter_pin"
+	// ActionReenterTAN means the TAN was not accepted and the user must enter
+	// it again
+	ActionReenterTAN ClientAction = "reenter_tan"
+	// ActionDoNotRetry means the client must not send the order again
+	// automatically
+	ActionDoNotRetry ClientAction = "do_not_retry"
+	// ActionRetryLater means the bank institute is currently not able to
+	// process the order and the client can try again later
+	ActionRetryLater ClientAction = "retry_later"
+	// ActionRestartDialog means the dialog is not usable anymore and the
+	// client must initialize a new one
+	ActionRestartDialog ClientAction = "restart_dialog"
+	// ActionFixRequest means the request was rejected because of its content
+	ActionFixRequest ClientAction = "fix_request"
+	// ActionContactBank means the user has to contact the bank institute to
+	// resolve the problem
+	ActionContactBank ClientAction = "contact_bank"
+	// ActionStatusUnknown means it is unclear whether the bank institute
+	// processed the message or order. The client must not send it again, but
+	// check its status, e.g. with the status protocol.
+	ActionStatusUnknown ClientAction = "status_unknown"
+)
+
+// ReturnCode represents an entry of the FinTS return code catalog
+type ReturnCode struct {
+	Code     int
+	Name     string
+	Category ReturnCodeCategory
+	Action   ClientAction
+	// Text contains the german text as defined by the specification
+	Text string
+	// Meaning contains an english description of the code
+	Meaning string
+	// Err is the error the code maps to, if any
+	Err error
+}
+
+func (r ReturnCode) String() string {
+	if r.Meaning == "" {
+		return fmt.Sprintf("%04d (%s)", r.Code, r.Category)
+	}
+	return fmt.Sprintf("%04d (%s): %s", r.Code, r.Category, r.Meaning)
+}
+
+// LookupReturnCode returns the catalog entry for the given code. If the code
+// is not in the catalog the returned ReturnCode only carries the code and the
+// category derived from the code range, and ok is false.
+func LookupReturnCode(code int) (returnCode ReturnCode, ok bool) {
+	returnCode, ok = returnCodes[code]
+	if ok {
+		return returnCode, true
+	}
+	return ReturnCode{
+		Code:     code,
+		Category: returnCodeCategory(code),
+		Action:   ActionNone,
+	}, false
+}
+
+// ReturnCodes returns all known return codes, ordered by code.
+func ReturnCodes() []ReturnCode {
+	codes := make([]ReturnCode, len(returnCodeOrder))
+	for i, code := range returnCodeOrder {
+		codes[i] = returnCodes[code]
+	}
+	return codes
+}
+
+func returnCodeCategory(code int) ReturnCodeCategory {
+	switch {
+	case code >= 0 && code < 1000:
+		return ReturnCodeCategorySuccess
+	case code >= 1000 && code < 2000:
+		return ReturnCodeCategoryNote
+	case code >= 3000 && code < 4000:
+		return ReturnCodeCategoryWarning
+	case code >= 9000 && code < 10000:
+		return ReturnCodeCategoryError
+	default:
+		return ReturnCodeCategoryUnknown
+	}
+}
@@ -0,0 +1,1689 @@
+// Code generated by cmd/returncode_generator DO NOT EDIT.
+
+package domain
+
+// These represent the return codes of the FinTS return code catalog
+const (
+	ReturnCodeMessageReceived                 = 10
+	ReturnCodeOrderExecuted                   = 20
+	ReturnCodeOrderReceivedTanRequired        = 30
+	ReturnCodeDialogEnded                     = 100
+	ReturnCodeTanValid                        = 900
+	ReturnCodePinValid                        = 901
+	ReturnCodeNotAvailable                    = 3010
+	ReturnCodeAdditionalInformation           = 3040
+	ReturnCodeNotCurrent                      = 3050
+	ReturnCodeWarningsPresent                 = 3060
+	ReturnCodeStrongAuthenticationNotRequired = 3076
+	ReturnCodeNoChallengeCreated              = 3905
+	ReturnCodeSupportedSecurityFunction       = 3920
+	ReturnCodePinOrTanGeneratorLocked         = 3931
+	ReturnCodeAccessTemporarilyLocked         = 3938
+	ReturnCodeDecoupledSecurityClearance      = 3955
+	ReturnCodeDecoupledAuthenticationPending  = 3956
+	ReturnCodeStatusUnknown                   = 9000
+	ReturnCodeProcessingNotPossible           = 9010
+	ReturnCodePartiallyErroneous              = 9050
+	ReturnCodeStrongAuthenticationRequired    = 9075
+	ReturnCodeUnknownStructure                = 9110
+	ReturnCodeMessageNotExpected              = 9120
+	ReturnCodeContentTooLong                  = 9140
+	ReturnCodeMandatoryFieldMissing           = 9160
+	ReturnCodeContentInvalid                  = 9210
+	ReturnCodeSignatureWrong                  = 9340
+	ReturnCodeNotPermitted                    = 9380
+	ReturnCodeDialogAborted                   = 9800
+	ReturnCodePinInvalid                      = 9910
+	ReturnCodePinLocked                       = 9930
+	ReturnCodeLockedAfterFailedAttempts       = 9931
+	ReturnCodeTanWrong                        = 9941
+	ReturnCodePinWrong                        = 9942
+	ReturnCodeTanProcedureNotAllowed          = 9955
+	ReturnCodeTechnicalError                  = 9999
+)
+
+var returnCodes = map[int]ReturnCode{
+	ReturnCodeMessageReceived: {
+		Code:     ReturnCodeMessageReceived,
+		Name:     "MessageReceived",
+		Category: ReturnCodeCategorySuccess,
+		Action:   ActionNone,
+		Text:     "Entgegengenommen",
+		Meaning:  "Message received",
+		Err:      nil,
+	},
+	ReturnCodeOrderExecuted: {
+		Code:     ReturnCodeOrderExecuted,
+		Name:     "OrderExecuted",
+		Category: ReturnCodeCategorySuccess,
+		Action:   ActionNone,
+		Text:     "Ausgeführt",
+		Meaning:  "Order executed",
+		Err:      nil,
+	},
+	ReturnCodeOrderReceivedTanRequired: {
+		Code:     ReturnCodeOrderReceivedTanRequired,
+		Name:     "OrderReceivedTanRequired",
+		Category: ReturnCodeCategorySuccess,
+		Action:   ActionProvideTAN,
+		Text:     "Auftrag empfangen-Sicherheitsfreigabe erforderlich",
+		Meaning:  "Order received, security clearance (TAN) required",
+		Err:      nil,
+	},
+	31: {
+		Code:     31,
+		Name:     "",
+		Category: ReturnCodeCategorySuccess,
+		Action:   ActionNone,
+		Text:     "Auftragsstorno durchgeführt",
+		Meaning:  "",
+		Err:      nil,
+	},
+	40: {
+		Code:     40,
+		Name:     "",
+		Category: ReturnCodeCategorySuccess,
+		Action:   ActionNone,
+		Text:     "Letzter Dialog endete am %1 um %2",
+		Meaning:  "",
+		Err:      nil,
+	},
+	41: {
+		Code:     41,
+		Name:     "",
+		Category: ReturnCodeCategorySuccess,
+		Action:   ActionNone,
+		Text:     "Falls Datum/Uhrzeit nicht korrekt, wenden Sie sich an Ihren Berater unter %1 bzw. %2",
+		Meaning:  "",
+		Err:      nil,
+	},
+	90: {
+		Code:     90,
+		Name:     "",
+		Category: ReturnCodeCategorySuccess,
+		Action:   ActionNone,
+		Text:     "TAN OK (%1)",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeDialogEnded: {
+		Code:     ReturnCodeDialogEnded,
+		Name:     "DialogEnded",
+		Category: ReturnCodeCategorySuccess,
+		Action:   ActionNone,
+		Text:     "Beendet",
+		Meaning:  "Dialog ended",
+		Err:      nil,
+	},
+	ReturnCodeTanValid: {
+		Code:     ReturnCodeTanValid,
+		Name:     "TanValid",
+		Category: ReturnCodeCategorySuccess,
+		Action:   ActionNone,
+		Text:     "TAN gültig",
+		Meaning:  "TAN or security clearance valid",
+		Err:      nil,
+	},
+	ReturnCodePinValid: {
+		Code:     ReturnCodePinValid,
+		Name:     "PinValid",
+		Category: ReturnCodeCategorySuccess,
+		Action:   ActionNone,
+		Text:     "PIN gültig",
+		Meaning:  "PIN valid",
+		Err:      nil,
+	},
+	1010: {
+		Code:     1010,
+		Name:     "",
+		Category: ReturnCodeCategoryNote,
+		Action:   ActionNone,
+		Text:     "Es liegen neue Kontoinformationen vor",
+		Meaning:  "",
+		Err:      nil,
+	},
+	1040: {
+		Code:     1040,
+		Name:     "",
+		Category: ReturnCodeCategoryNote,
+		Action:   ActionNone,
+		Text:     "BPD nicht mehr aktuell, aktuelle Version enthalten.",
+		Meaning:  "",
+		Err:      nil,
+	},
+	1050: {
+		Code:     1050,
+		Name:     "",
+		Category: ReturnCodeCategoryNote,
+		Action:   ActionNone,
+		Text:     "UPD nicht mehr aktuell, aktuelle Version enthalten.",
+		Meaning:  "",
+		Err:      nil,
+	},
+	1060: {
+		Code:     1060,
+		Name:     "",
+		Category: ReturnCodeCategoryNote,
+		Action:   ActionNone,
+		Text:     "Teilweise liegen Hinweise vor",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3000: {
+		Code:     3000,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Auftrag nur teilweise ausgeführt",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeNotAvailable: {
+		Code:     ReturnCodeNotAvailable,
+		Name:     "NotAvailable",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Nicht verfügbar",
+		Meaning:  "Not available, e.g. no new entries",
+		Err:      nil,
+	},
+	3020: {
+		Code:     3020,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Korrigiert, da nicht mehr aktuell",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3021: {
+		Code:     3021,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "IBAN %1 / BIC %2 für Auftrag",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3030: {
+		Code:     3030,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Korrigiert, da ungültig",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeAdditionalInformation: {
+		Code:     ReturnCodeAdditionalInformation,
+		Name:     "AdditionalInformation",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionContinue,
+		Text:     "Es liegen weitere Informationen vor",
+		Meaning:  "Further information available, continue with the continuation reference",
+		Err:      nil,
+	},
+	3045: {
+		Code:     3045,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "SEPA Instant Payment Statusabfrage HKIPS veranlassen",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3046: {
+		Code:     3046,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Überprüfen Sie Ihre Umsätze",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeNotCurrent: {
+		Code:     ReturnCodeNotCurrent,
+		Name:     "NotCurrent",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Nicht mehr aktuell. Wird noch bis zum %1 Tage akzeptiert",
+		Meaning:  "No longer current, still accepted until the given date",
+		Err:      nil,
+	},
+	3051: {
+		Code:     3051,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Zeitüberschreitung bei außerbörslichem Direkthandel",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeWarningsPresent: {
+		Code:     ReturnCodeWarningsPresent,
+		Name:     "WarningsPresent",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Teilweise liegen Warnungen vor",
+		Meaning:  "At least one order contains warnings",
+		Err:      nil,
+	},
+	3070: {
+		Code:     3070,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Neuanlage einer PIN, TAN-Liste oder - Generator für Benutzer %1 schlug fehl",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3071: {
+		Code:     3071,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Die Benachrichtigung des Autorisierungssystems für Benutzer %1 schlug fehl",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3072: {
+		Code:     3072,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Neue Anmeldedaten - bitte berücksichtigen",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3075: {
+		Code:     3075,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Starke Authentifizierung ab dem %1 erforderlich",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeStrongAuthenticationNotRequired: {
+		Code:     ReturnCodeStrongAuthenticationNotRequired,
+		Name:     "StrongAuthenticationNotRequired",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Keine starke Authentifizierung erforderlich",
+		Meaning:  "Strong customer authentication not required",
+		Err:      nil,
+	},
+	3077: {
+		Code:     3077,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Verwendung gehärteter Browser erforderlich",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3078: {
+		Code:     3078,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Unregistriertes FinTS-Produkt nur zugelassen bis %1",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3079: {
+		Code:     3079,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Bitte an SW-Hersteller wenden",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3080: {
+		Code:     3080,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Es liegen weitere Informationen vor",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3081: {
+		Code:     3081,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Aktualisierte Parameterdaten beachten",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3210: {
+		Code:     3210,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Auftrag angenommen, fehlerhafte Einzelpositionen",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3220: {
+		Code:     3220,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Auftrag ausgeführt, fehlerhafte Einzelpositionen",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3230: {
+		Code:     3230,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Die Zahlung erfolgt an neue Empfänger-Konto-/Bankverbindung",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3260: {
+		Code:     3260,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Sammler unvollständig verarbeitet. 1 Satz fehlerhaft.",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3290: {
+		Code:     3290,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Die eingegebene Bankleitzahl ist ungültig",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3300: {
+		Code:     3300,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Kein Schlüssel verfügbar. Keine Signatur von Kreditinstitutsnachrichten",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3310: {
+		Code:     3310,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Mehrfache Unterzeichnung durch identischen Benutzer nicht zulässig",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3320: {
+		Code:     3320,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Ini-Brief nicht erforderlich",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3330: {
+		Code:     3330,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Schlüssel liegen bereits vor",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3340: {
+		Code:     3340,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Karte erneuern. Benutzerschlüssel noch gültig bis zum %1.",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3345: {
+		Code:     3345,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Profilwechsel Chipkarte (RDHx) durchführen bis %1",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3361: {
+		Code:     3361,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Sicherheitsverfahren nur noch zulässig bis %1",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3390: {
+		Code:     3390,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Doppeleinreichung Signatur-ID %1",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3710: {
+		Code:     3710,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Bei Beträgen > 50.000 EUR ist eine AWV-Meldung erforderlich",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3810: {
+		Code:     3810,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Zusätzlich Datei %1 abholen",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3820: {
+		Code:     3820,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Prüfen Sie zu gegebener Zeit den Orderstatus",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3900: {
+		Code:     3900,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Mitteilung ohne Text erhalten.",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeNoChallengeCreated: {
+		Code:     ReturnCodeNoChallengeCreated,
+		Name:     "NoChallengeCreated",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Es wurde keine Challenge erzeugt",
+		Meaning:  "No challenge has been created",
+		Err:      nil,
+	},
+	3910: {
+		Code:     3910,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "TAN wurde nicht verbraucht",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3911: {
+		Code:     3911,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Bitte neue TAN-Liste aktivieren",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3912: {
+		Code:     3912,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "neue TAN-Liste wird automatisch verschickt",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3913: {
+		Code:     3913,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "TAN wurde verbraucht",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3914: {
+		Code:     3914,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "neue TAN-Liste aktivieren",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3915: {
+		Code:     3915,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "neue TAN-Liste aktiviert",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3916: {
+		Code:     3916,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "PIN muss wegen erstmaliger Anmeldung zwangsweise geändert werden",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3917: {
+		Code:     3917,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Alte TAN-Liste ist infolge der Aktivierung einer neuen TAN-Liste ungültig",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3918: {
+		Code:     3918,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Kompetenz nicht ausreichend-weitere TAN erforderlich",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeSupportedSecurityFunction: {
+		Code:     ReturnCodeSupportedSecurityFunction,
+		Name:     "SupportedSecurityFunction",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Zugelassene Ein- und Zwei-Schritt-Verfahren für den Benutzer (+Rückmeldungsparamet er)",
+		Meaning:  "One- and two-step procedures allowed for the user",
+		Err:      nil,
+	},
+	3921: {
+		Code:     3921,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Zugelassene AZS-Verfahren für den Benutzer (+Rückmeldungsparamet er)",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodePinOrTanGeneratorLocked: {
+		Code:     ReturnCodePinOrTanGeneratorLocked,
+		Name:     "PinOrTanGeneratorLocked",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionDoNotRetry,
+		Text:     "PIN gesperrt. Entsperren -Sperre auf",
+		Meaning:  "PIN locked, or TAN generator locked and to be synchronized",
+		Err:      ErrAccountLocked,
+	},
+	3932: {
+		Code:     3932,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Bitte führen Sie zunächst eine PIN-Änderung durch",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3933: {
+		Code:     3933,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "TAN-Generator gesperrt, Synchronisierung erfordert Kartennummer %1",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3934: {
+		Code:     3934,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Bitte eine Karte zur Verwendung mit chipTAN zulassen",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3935: {
+		Code:     3935,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Bitte eine Karte zur Verwendung mit chipTAN zulassen",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3936: {
+		Code:     3936,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Die neuen Funktionen stehen erst nach einer erneuten Anmeldung zur Verfügung",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeAccessTemporarilyLocked: {
+		Code:     ReturnCodeAccessTemporarilyLocked,
+		Name:     "AccessTemporarilyLocked",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionDoNotRetry,
+		Text:     "Ihr Zugang ist vorläufig gesperrt-bitte PIN-Sperre aufheben",
+		Meaning:  "Access temporarily locked, the PIN lock must be lifted",
+		Err:      ErrAccountLocked,
+	},
+	3939: {
+		Code:     3939,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "mobileTAN-Freischaltung erforderlich. SMS-Freischaltcode wurde versendet",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3940: {
+		Code:     3940,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Reservierte TAN wurde entwertet",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3941: {
+		Code:     3941,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Zur PIN-Änderung stehen folgende Rufnummern zur Verfügung:",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3942: {
+		Code:     3942,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Freischaltung einer Mobilfunkverbindung zwingend erforderlich",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3944: {
+		Code:     3944,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Bitte benutzen Sie die erhaltene Folgekarte %1 zur TAN-Erzeugung",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3950: {
+		Code:     3950,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Die Selbstumstellung auf ein anderes Sicherheitsverfahren ist möglich",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3951: {
+		Code:     3951,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Die Selbstumstellung auf ein anderes Sicherheitsverfahren ist erforderlich",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3952: {
+		Code:     3952,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Erfolgreicher Prozessschritt bei der Selbstumstellung",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeDecoupledSecurityClearance: {
+		Code:     ReturnCodeDecoupledSecurityClearance,
+		Name:     "DecoupledSecurityClearance",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionProvideTAN,
+		Text:     "Sicherheitsfreigabe erfolgt über anderen Kanal",
+		Meaning:  "Security clearance is given via another channel (decoupled)",
+		Err:      nil,
+	},
+	ReturnCodeDecoupledAuthenticationPending: {
+		Code:     ReturnCodeDecoupledAuthenticationPending,
+		Name:     "DecoupledAuthenticationPending",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionRetryLater,
+		Text:     "Starke Kundenauthentifizierung noch ausstehend",
+		Meaning:  "Strong customer authentication still pending",
+		Err:      nil,
+	},
+	3957: {
+		Code:     3957,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Auf Push Nachricht warten",
+		Meaning:  "",
+		Err:      nil,
+	},
+	3958: {
+		Code:     3958,
+		Name:     "",
+		Category: ReturnCodeCategoryWarning,
+		Action:   ActionNone,
+		Text:     "Freigabe-Anwendung unterstützt das gewählte Verfahren nicht",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeStatusUnknown: {
+		Code:     ReturnCodeStatusUnknown,
+		Name:     "StatusUnknown",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionStatusUnknown,
+		Text:     "Status indifferent",
+		Meaning:  "Status indifferent, it is unclear whether the message or order was processed",
+		Err:      ErrStatusUnknown,
+	},
+	ReturnCodeProcessingNotPossible: {
+		Code:     ReturnCodeProcessingNotPossible,
+		Name:     "ProcessingNotPossible",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionDoNotRetry,
+		Text:     "Verarbeitung nicht möglich",
+		Meaning:  "Processing not possible",
+		Err:      nil,
+	},
+	9020: {
+		Code:     9020,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Antwort zu groß",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9021: {
+		Code:     9021,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "IBAN %1 / BIC %2 konnte nicht ermittelt werden",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9030: {
+		Code:     9030,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Fehler bei Entschlüsselung",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9040: {
+		Code:     9040,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Fehler bei Dekomprimierung",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodePartiallyErroneous: {
+		Code:     ReturnCodePartiallyErroneous,
+		Name:     "PartiallyErroneous",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionFixRequest,
+		Text:     "Teilweise fehlerhaft",
+		Meaning:  "At least one order contains errors",
+		Err:      nil,
+	},
+	ReturnCodeStrongAuthenticationRequired: {
+		Code:     ReturnCodeStrongAuthenticationRequired,
+		Name:     "StrongAuthenticationRequired",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionProvideTAN,
+		Text:     "Dialog abgebrochen - starke Authentifizierung erforderlich",
+		Meaning:  "Dialog aborted, strong customer authentication required",
+		Err:      ErrTanRequired,
+	},
+	9077: {
+		Code:     9077,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Dialog abgebrochen gehärteter Browser erforderlich",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9078: {
+		Code:     9078,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Dialog abgebrochen FinTS-Produkt ist nicht registriert",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeUnknownStructure: {
+		Code:     ReturnCodeUnknownStructure,
+		Name:     "UnknownStructure",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionRestartDialog,
+		Text:     "Unbekannter Aufbau",
+		Meaning:  "Unknown structure of the message, order list or order",
+		Err:      ErrMessageMalformed,
+	},
+	ReturnCodeMessageNotExpected: {
+		Code:     ReturnCodeMessageNotExpected,
+		Name:     "MessageNotExpected",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionRestartDialog,
+		Text:     "Nicht erwartet",
+		Meaning:  "Not expected, e.g. two initializations in a row or an order without initialization",
+		Err:      nil,
+	},
+	9130: {
+		Code:     9130,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Inhalt syntaktisch ungültig",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeContentTooLong: {
+		Code:     ReturnCodeContentTooLong,
+		Name:     "ContentTooLong",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionFixRequest,
+		Text:     "Inhalt zu lang",
+		Meaning:  "Content too long for the format of the element",
+		Err:      ErrMessageMalformed,
+	},
+	9145: {
+		Code:     9145,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Inhalt zu kurz",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9150: {
+		Code:     9150,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Belegung nicht erlaubt",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeMandatoryFieldMissing: {
+		Code:     ReturnCodeMandatoryFieldMissing,
+		Name:     "MandatoryFieldMissing",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionFixRequest,
+		Text:     "Fehlt",
+		Meaning:  "Missing",
+		Err:      ErrMessageMalformed,
+	},
+	9170: {
+		Code:     9170,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Tritt zu oft auf",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9180: {
+		Code:     9180,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Wird nicht mehr akzeptiert",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9185: {
+		Code:     9185,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "HBCI-/FinTS-Version %1 wird nicht unterstützt",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9190: {
+		Code:     9190,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Nachricht enthält kein erkennbares Sicherheitsmerkmal (Signatur)",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeContentInvalid: {
+		Code:     ReturnCodeContentInvalid,
+		Name:     "ContentInvalid",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionFixRequest,
+		Text:     "Inhaltlich ungültig",
+		Meaning:  "Content invalid",
+		Err:      nil,
+	},
+	9211: {
+		Code:     9211,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Verwendung eines Secoders verpflichtend",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9212: {
+		Code:     9212,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Inhalt zu groß",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9215: {
+		Code:     9215,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Inhalt zu klein",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9219: {
+		Code:     9219,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Schlüsselart falsch.",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9220: {
+		Code:     9220,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Einzelposition %1 inhaltlich ungültig",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9230: {
+		Code:     9230,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Unzureichendes Guthaben des Kontos",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9310: {
+		Code:     9310,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Elektronische Signatur noch nicht hinterlegt",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9311: {
+		Code:     9311,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "PIN/TAN-System nicht verfügbar",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9315: {
+		Code:     9315,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "<Code intern genutzt>",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9320: {
+		Code:     9320,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Elektronische Signatur noch nicht freigeschaltet",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9330: {
+		Code:     9330,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Elektronische Signatur gesperrt",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9331: {
+		Code:     9331,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Synchronisieren des neuen TAN-Mediums nicht möglich",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9333: {
+		Code:     9333,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "PIN-Zugang (noch) nicht freigeschaltet",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeSignatureWrong: {
+		Code:     ReturnCodeSignatureWrong,
+		Name:     "SignatureWrong",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionReenterPIN,
+		Text:     "Elektronische Signatur falsch",
+		Meaning:  "Electronic signature wrong, i.e. the PIN for PIN/TAN",
+		Err:      ErrPinWrong,
+	},
+	9350: {
+		Code:     9350,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Zertifikat abgelaufen",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9351: {
+		Code:     9351,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Zertifikat gesperrt",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9352: {
+		Code:     9352,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Zertifikatseigner unbekannt",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9353: {
+		Code:     9353,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Zertifikatssignatur falsch",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9354: {
+		Code:     9354,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Bitte Einreichung der Zweitkennung wiederholen mit RDHx",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9355: {
+		Code:     9355,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Fehler im Zertifikatsaufbau",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9356: {
+		Code:     9356,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Zertifikatstyp nicht akzeptiert",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9357: {
+		Code:     9357,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Zertifikat erwartet",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9359: {
+		Code:     9359,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "OCSP-Anfrage nicht beendet",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9360: {
+		Code:     9360,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Sperrung der Signatur nach weiteren %1 Falschsignaturen",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9361: {
+		Code:     9361,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Dialog abgebrochen - Sicherheitsverfahren nicht mehr zulässig",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9370: {
+		Code:     9370,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Signaturberechtigung reicht nicht aus",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeNotPermitted: {
+		Code:     ReturnCodeNotPermitted,
+		Name:     "NotPermitted",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionDoNotRetry,
+		Text:     "Benutzer hat keine Auftragsberechtigung",
+		Meaning:  "Not permitted, e.g. the user is not authorized for the order",
+		Err:      nil,
+	},
+	9390: {
+		Code:     9390,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Doppeleinreichung",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9400: {
+		Code:     9400,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Allgemeiner Fehler des Sicherheitsmediums",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9420: {
+		Code:     9420,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Challenge-Betrag passt nicht zum Auftrag",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeDialogAborted: {
+		Code:     ReturnCodeDialogAborted,
+		Name:     "DialogAborted",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionRestartDialog,
+		Text:     "Abgebrochen",
+		Meaning:  "Dialog aborted by the bank",
+		Err:      ErrDialogAborted,
+	},
+	9901: {
+		Code:     9901,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Fehler Kryptomodul Signaturverifizierung",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodePinInvalid: {
+		Code:     ReturnCodePinInvalid,
+		Name:     "PinInvalid",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionReenterPIN,
+		Text:     "PIN ungültig, bitte richtige PIN eingeben",
+		Meaning:  "PIN invalid, please enter the correct PIN",
+		Err:      ErrPinWrong,
+	},
+	9920: {
+		Code:     9920,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Reservierung nicht möglich, keine freien TANs mehr vorhanden",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodePinLocked: {
+		Code:     ReturnCodePinLocked,
+		Name:     "PinLocked",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionContactBank,
+		Text:     "Ihre PIN ist gesperrt.",
+		Meaning:  "PIN locked",
+		Err:      ErrAccountLocked,
+	},
+	ReturnCodeLockedAfterFailedAttempts: {
+		Code:     ReturnCodeLockedAfterFailedAttempts,
+		Name:     "LockedAfterFailedAttempts",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionContactBank,
+		Text:     "Sperrung des Kontos nach %1 Fehlversuchen",
+		Meaning:  "Locked after too many failed attempts",
+		Err:      ErrAccountLocked,
+	},
+	9939: {
+		Code:     9939,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Freischalten der Mobilfunknummer für mobileTAN nicht möglich",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeTanWrong: {
+		Code:     ReturnCodeTanWrong,
+		Name:     "TanWrong",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionReenterTAN,
+		Text:     "TAN ungültig",
+		Meaning:  "TAN invalid",
+		Err:      ErrTanWrong,
+	},
+	ReturnCodePinWrong: {
+		Code:     ReturnCodePinWrong,
+		Name:     "PinWrong",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionReenterPIN,
+		Text:     "PIN ungültig",
+		Meaning:  "PIN invalid",
+		Err:      ErrPinWrong,
+	},
+	9943: {
+		Code:     9943,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "TAN bereits verbraucht",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9951: {
+		Code:     9951,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Zeitüberschreitung im Zwei-Schritt-Verfahren-TAN ungültig",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9953: {
+		Code:     9953,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Nur ein TAN-pflichtiger Auftrag pro Nachricht erlaubt",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9954: {
+		Code:     9954,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Mehrfach-TANs nicht erlaubt",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeTanProcedureNotAllowed: {
+		Code:     ReturnCodeTanProcedureNotAllowed,
+		Name:     "TanProcedureNotAllowed",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionFixRequest,
+		Text:     "Ein-Schritt-TAN-Verfahren nicht zugelassen",
+		Meaning:  "One-step TAN procedure not allowed",
+		Err:      nil,
+	},
+	9956: {
+		Code:     9956,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Zeitversetzte Eingabe von Mehrfach-TANs nicht erlaubt",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9957: {
+		Code:     9957,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Wechsel des Signatur-Prozesses bei Mehrfach-Signaturen nicht erlaubt",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9958: {
+		Code:     9958,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Das genutzte Legitimationsverfahren wird nicht mehr unterstützt",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9959: {
+		Code:     9959,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "SMS konnte nicht gesendet werden bitte Vorgang wiederholen",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9960: {
+		Code:     9960,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Es kann kein TAN-pflichtiger Auftrag durchgeführt werden.",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9961: {
+		Code:     9961,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Bitte schalten Sie die Mobilfunkverbindung für mobileTAN frei",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9962: {
+		Code:     9962,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Auftrag nicht ausgeführt die Telefonbezeichnung ist unbekannt",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9963: {
+		Code:     9963,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Auftrag nicht ausgeführt Rufnummer für SMS fehlerhaft",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9964: {
+		Code:     9964,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Auftrag nicht ausgeführt keine gültige Karte für chipTAN",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9980: {
+		Code:     9980,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Abgebrochen-Zweischrittdialog",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9991: {
+		Code:     9991,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "chipTAN nicht zulässig bei Benutzerkennung für TANalt",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9992: {
+		Code:     9992,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Eine neue TAN-Liste wurde bereits erstellt",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9997: {
+		Code:     9997,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Zurzeit Wartungsarbeiten",
+		Meaning:  "",
+		Err:      nil,
+	},
+	9998: {
+		Code:     9998,
+		Name:     "",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionNone,
+		Text:     "Daten sind nicht zu entschlüsseln.",
+		Meaning:  "",
+		Err:      nil,
+	},
+	ReturnCodeTechnicalError: {
+		Code:     ReturnCodeTechnicalError,
+		Name:     "TechnicalError",
+		Category: ReturnCodeCategoryError,
+		Action:   ActionRetryLater,
+		Text:     "Auftrag konnte aus technischen Gründen nicht verarbeitet werden.",
+		Meaning:  "Order could not be processed for technical reasons",
+		Err:      ErrBankUnavailable,
+	},
+}
+
+var returnCodeOrder = []int{
+	ReturnCodeMessageReceived,
+	ReturnCodeOrderExecuted,
+	ReturnCodeOrderReceivedTanRequired,
+	31,
+	40,
+	41,
+	90,
+	ReturnCodeDialogEnded,
+	ReturnCodeTanValid,
+	ReturnCodePinValid,
+	1010,
+	1040,
+	1050,
+	1060,
+	3000,
+	ReturnCodeNotAvailable,
+	3020,
+	3021,
+	3030,
+	ReturnCodeAdditionalInformation,
+	3045,
+	3046,
+	ReturnCodeNotCurrent,
+	3051,
+	ReturnCodeWarningsPresent,
+	3070,
+	3071,
+	3072,
+	3075,
+	ReturnCodeStrongAuthenticationNotRequired,
+	3077,
+	3078,
+	3079,
+	3080,
+	3081,
+	3210,
+	3220,
+	3230,
+	3260,
+	3290,
+	3300,
+	3310,
+	3320,
+	3330,
+	3340,
+	3345,
+	3361,
+	3390,
+	3710,
+	3810,
+	3820,
+	3900,
+	ReturnCodeNoChallengeCreated,
+	3910,
+	3911,
+	3912,
+	3913,
+	3914,
+	3915,
+	3916,
+	3917,
+	3918,
+	ReturnCodeSupportedSecurityFunction,
+	3921,
+	ReturnCodePinOrTanGeneratorLocked,
+	3932,
+	3933,
+	3934,
+	3935,
+	3936,
+	ReturnCodeAccessTemporarilyLocked,
+	3939,
+	3940,
+	3941,
+	3942,
+	3944,
+	3950,
+	3951,
+	3952,
+	ReturnCodeDecoupledSecurityClearance,
+	ReturnCodeDecoupledAuthenticationPending,
+	3957,
+	3958,
+	ReturnCodeStatusUnknown,
+	ReturnCodeProcessingNotPossible,
+	9020,
+	9021,
+	9030,
+	9040,
+	ReturnCodePartiallyErroneous,
+	ReturnCodeStrongAuthenticationRequired,
+	9077,
+	9078,
+	ReturnCodeUnknownStructure,
+	ReturnCodeMessageNotExpected,
+	9130,
+	ReturnCodeContentTooLong,
+	9145,
+	9150,
+	ReturnCodeMandatoryFieldMissing,
+	9170,
+	9180,
+	9185,
+	9190,
+	ReturnCodeContentInvalid,
+	9211,
+	9212,
+	9215,
+	9219,
+	9220,
+	9230,
+	9310,
+	9311,
+	9315,
+	9320,
+	9330,
+	9331,
+	9333,
+	ReturnCodeSignatureWrong,
+	9350,
+	9351,
+	9352,
+	9353,
+	9354,
+	9355,
+	9356,
+	9357,
+	9359,
+	9360,
+	9361,
+	9370,
+	ReturnCodeNotPermitted,
+	9390,
+	9400,
+	9420,
+	ReturnCodeDialogAborted,
+	9901,
+	ReturnCodePinInvalid,
+	9920,
+	ReturnCodePinLocked,
+	ReturnCodeLockedAfterFailedAttempts,
+	9939,
+	ReturnCodeTanWrong,
+	ReturnCodePinWrong,
+	9943,
+	9951,
+	9953,
+	9954,
+	ReturnCodeTanProcedureNotAllowed,
+	9956,
+	9957,
+	9958,
+	9959,
+	9960,
+	9961,
+	9962,
+	9963,
+	9964,
+	9980,
+	9991,
+	9992,
+	9997,
+	9998,
+	ReturnCodeTechnicalError,
+}
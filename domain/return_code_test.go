@@ -0,0 +1,262 @@
+package domain
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLookupReturnCode(t *testing.T) {
+	tests := []struct {
+		code             int
+		expectedOk       bool
+		expectedCategory ReturnCodeCategory
+		expectedAction   ClientAction
+		expectedErr      error
+	}{
+		{20, true, ReturnCodeCategorySuccess, ActionNone, nil},
+		{3040, true, ReturnCodeCategoryWarning, ActionContinue, nil},
+		{9942, true, ReturnCodeCategoryError, ActionReenterPIN, ErrPinWrong},
+		{9931, true, ReturnCodeCategoryError, ActionContactBank, ErrAccountLocked},
+		{9800, true, ReturnCodeCategoryError, ActionRestartDialog, ErrDialogAborted},
+		{9075, true, ReturnCodeCategoryError, ActionProvideTAN, ErrTanRequired},
+		{9000, true, ReturnCodeCategoryError, ActionStatusUnknown, ErrStatusUnknown},
+		{9140, true, ReturnCodeCategoryError, ActionFixRequest, ErrMessageMalformed},
+		{9910, true, ReturnCodeCategoryError, ActionReenterPIN, ErrPinWrong},
+		{9120, true, ReturnCodeCategoryError, ActionRestartDialog, nil},
+		{1040, true, ReturnCodeCategoryNote, ActionNone, nil},
+		{9330, true, ReturnCodeCategoryError, ActionNone, nil},
+		{9001, false, ReturnCodeCategoryError, ActionNone, nil},
+		{3999, false, ReturnCodeCategoryWarning, ActionNone, nil},
+		{5000, false, ReturnCodeCategoryUnknown, ActionNone, nil},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%04d", test.code), func(t *testing.T) {
+			returnCode, ok := LookupReturnCode(test.code)
+
+			if ok != test.expectedOk {
+				t.Errorf("Expected ok to be %t, got %t", test.expectedOk, ok)
+			}
+			if returnCode.Code != test.code {
+				t.Errorf("Expected code to equal %d, got %d", test.code, returnCode.Code)
+			}
+			if returnCode.Category != test.expectedCategory {
+				t.Errorf("Expected category to equal %q, got %q", test.expectedCategory, returnCode.Category)
+			}
+			if returnCode.Action != test.expectedAction {
+				t.Errorf("Expected action to equal %q, got %q", test.expectedAction, returnCode.Action)
+			}
+			if returnCode.Err != test.expectedErr {
+				t.Errorf("Expected err to equal %v, got %v", test.expectedErr, returnCode.Err)
+			}
+		})
+	}
+}
+
+func TestReturnCodes(t *testing.T) {
+	codes := ReturnCodes()
+
+	if len(codes) == 0 {
+		t.Fatalf("Expected return codes, got none")
+	}
+	for i, code := range codes {
+		if i > 0 && codes[i-1].Code >= code.Code {
+			t.Errorf("Expected codes to be ordered, got %d before %d", codes[i-1].Code, code.Code)
+		}
+		if code.Category != returnCodeCategory(code.Code) {
+			t.Errorf("Expected category of %d to match its range, got %q", code.Code, code.Category)
+		}
+	}
+}
+
+func TestReturnCodesMatchCatalog(t *testing.T) {
+	catalog := make(map[int]string)
+	for code, texts := range readCatalogTexts(t, "../doc/FinTS_Rueckmeldungscodes_2021-07-07.csv") {
+		catalog[code] = texts[0]
+	}
+
+	if len(catalog) != 163 {
+		t.Errorf("Expected catalog to contain 163 codes, got %d", len(catalog))
+	}
+	for code, text := range catalog {
+		returnCode, ok := LookupReturnCode(code)
+		if !ok {
+			t.Errorf("Expected code %04d to be known", code)
+			continue
+		}
+		if returnCode.Text != text {
+			t.Errorf("Expected text of %04d to equal %q, got %q", code, text, returnCode.Text)
+		}
+	}
+	samples := map[int]string{
+		ReturnCodeOrderExecuted:         "Ausgeführt",
+		ReturnCodeAdditionalInformation: "Es liegen weitere Informationen vor",
+		ReturnCodeContentInvalid:        "Inhaltlich ungültig",
+		9998:                            "Daten sind nicht zu entschlüsseln.",
+	}
+	for code, text := range samples {
+		if returnCode, _ := LookupReturnCode(code); returnCode.Text != text {
+			t.Errorf("Expected text of %04d to equal %q, got %q", code, text, returnCode.Text)
+		}
+	}
+}
+
+// readCatalogTexts returns all texts of the return code catalog by code
+func readCatalogTexts(t *testing.T, path string) map[int][]string {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	defer file.Close()
+	reader := csv.NewReader(file)
+	reader.Comma = ';'
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	texts := make(map[int][]string)
+	for _, record := range records[1:] {
+		code, err := strconv.Atoi(record[0])
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+		texts[code] = append(texts[code], record[1])
+	}
+	return texts
+}
+
+func TestReturnCodeAnnotationsMatchCatalog(t *testing.T) {
+	catalog := readCatalogTexts(t, "../doc/FinTS_Rueckmeldungscodes_2021-07-07.csv")
+	file, err := os.Open("../doc/FinTS_Rueckmeldungscodes.csv")
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	defer file.Close()
+	reader := csv.NewReader(file)
+	reader.Comma = ';'
+	annotations, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	// Annotated errors and actions must be backed by one of these terms
+	// within the first catalog text of the code, which defines its primary
+	// meaning
+	errTerms := map[string][]string{
+		"pin_wrong":         {"PIN ungültig", "PIN falsch", "Signatur falsch"},
+		"account_locked":    {"gesperrt", "Sperrung", "Teilnehmersperre"},
+		"tan_required":      {"starke Authentifizierung", "Sicherheitsfreigabe"},
+		"tan_wrong":         {"TAN ungültig", "TAN falsch"},
+		"dialog_aborted":    {"Abgebrochen"},
+		"bank_unavailable":  {"technischen Gründen", "nicht verfügbar"},
+		"message_malformed": {"Aufbau", "Fehlt", "zu lang"},
+		"status_unknown":    {"Status indifferent"},
+	}
+	actionTerms := map[string][]string{
+		"provide_tan":    {"Sicherheitsfreigabe", "Authentifizierung"},
+		"reenter_pin":    {"PIN ungültig", "PIN falsch", "Signatur falsch"},
+		"reenter_tan":    {"TAN ungültig", "TAN falsch"},
+		"restart_dialog": {"Abgebrochen", "Aufbau", "Nicht erwartet"},
+		"retry_later":    {"technischen Gründen", "ausstehend"},
+		"status_unknown": {"Status indifferent"},
+	}
+	// Words of annotated names must be backed by one of these terms within
+	// the first catalog text of the code. Names joining alternatives with Or
+	// may be backed by any catalog text.
+	nameTerms := map[string][]string{
+		"Pin":          {"PIN"},
+		"Tan":          {"TAN", "Sicherheitsfreigabe"},
+		"TanGenerator": {"TAN-Generator"},
+		"Locked":       {"gesperrt", "Sperrung", "Teilnehmersperre"},
+		"Signature":    {"Signatur"},
+		"Aborted":      {"bgebrochen"},
+		"Unknown":      {"Unbekannt", "indifferent"},
+		"Long":         {"zu lang"},
+	}
+	containsAny := func(texts []string, terms []string) bool {
+		for _, text := range texts {
+			for _, term := range terms {
+				if strings.Contains(strings.ToLower(text), strings.ToLower(term)) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	for _, annotation := range annotations[1:] {
+		code, err := strconv.Atoi(annotation[0])
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+		name, action, errName := annotation[1], annotation[3], annotation[4]
+		texts, ok := catalog[code]
+		if !ok {
+			if annotation[5] == "" {
+				t.Errorf("Expected code %04d to be in the catalog or to define its text", code)
+			}
+			texts = []string{annotation[5]}
+		}
+
+		if terms, ok := errTerms[errName]; ok && !containsAny(texts[:1], terms) {
+			t.Errorf("Expected error %q of %04d to be backed by the catalog text %q", errName, code, texts[0])
+		}
+		if terms, ok := actionTerms[action]; ok && !containsAny(texts[:1], terms) {
+			t.Errorf("Expected action %q of %04d to be backed by the catalog text %q", action, code, texts[0])
+		}
+		nameTexts := texts[:1]
+		if strings.Contains(name, "Or") {
+			nameTexts = texts
+		}
+		for word, terms := range nameTerms {
+			if strings.Contains(name, word) && !containsAny(nameTexts, terms) {
+				t.Errorf("Expected name %q of %04d to be backed by the catalog texts %q", name, code, nameTexts)
+			}
+		}
+
+		returnCode, _ := LookupReturnCode(code)
+		if returnCode.Name != name || string(returnCode.Action) != action {
+			t.Errorf("Expected generated data of %04d to match its annotation, got %q and %q", code, returnCode.Name, returnCode.Action)
+		}
+		if returnCode.Category != returnCodeCategory(code) || returnCode.Category.String() != annotation[2] {
+			t.Errorf("Expected category of %04d to equal %q, got %q", code, annotation[2], returnCode.Category)
+		}
+	}
+}
+
+func TestAcknowledgementError(t *testing.T) {
+	acknowledgements := []Acknowledgement{
+		NewMessageAcknowledgement(3938, "", "Ihr Zugang ist vorläufig gesperrt", nil),
+		NewSegmentAcknowledgement(9942, "", "PIN falsch", nil),
+	}
+
+	var err error = NewAcknowledgementError(acknowledgements)
+
+	if !errors.Is(err, ErrPinWrong) {
+		t.Errorf("Expected error to be %v", ErrPinWrong)
+	}
+	if !errors.Is(err, ErrAccountLocked) {
+		t.Errorf("Expected error to be %v", ErrAccountLocked)
+	}
+	if errors.Is(err, ErrBankUnavailable) {
+		t.Errorf("Expected error not to be %v", ErrBankUnavailable)
+	}
+	var ackErr *AcknowledgementError
+	if !errors.As(fmt.Errorf("wrapped: %w", err), &ackErr) {
+		t.Fatalf("Expected error to be an *AcknowledgementError")
+	}
+	if len(ackErr.Errors()) != 1 {
+		t.Errorf("Expected one error acknowledgement, got %d", len(ackErr.Errors()))
+	}
+	if !ackErr.HasCode(3938) {
+		t.Errorf("Expected error to contain code 3938")
+	}
+
+	if NewAcknowledgementError(acknowledgements[:1]) != nil {
+		t.Errorf("Expected no error for warnings only")
+	}
+}
@@ -0,0 +1,232 @@
+package domain
+
+import "fmt"
+
+// TransactionCategory classifies account transactions by their business
+// transaction code (Geschäftsvorfallcode, GVC)
+type TransactionCategory int
+
+const (
+	// TransactionCategoryUnknown represents codes without a known category
+	TransactionCategoryUnknown TransactionCategory = iota
+	// TransactionCategoryTransfer represents credit transfers, incoming as
+	// well as outgoing
+	TransactionCategoryTransfer
+	// TransactionCategoryStandingOrder represents payments from standing
+	// orders
+	TransactionCategoryStandingOrder
+	// TransactionCategoryDirectDebit represents direct debits, incoming as
+	// well as outgoing
+	TransactionCategoryDirectDebit
+	// TransactionCategoryCardPayment represents payments by card
+	TransactionCategoryCardPayment
+	// TransactionCategoryCashWithdrawal represents cash withdrawals
+	TransactionCategoryCashWithdrawal
+	// TransactionCategoryCashDeposit represents cash deposits
+	TransactionCategoryCashDeposit
+	// TransactionCategorySalary represents salary, wage and pension payments
+	TransactionCategorySalary
+	// TransactionCategoryReturn represents returned direct debits and credit
+	// transfers
+	TransactionCategoryReturn
+	// TransactionCategoryFee represents fees and charges of the bank
+	// institute
+	TransactionCategoryFee
+	// TransactionCategoryInterest represents interest payments
+	TransactionCategoryInterest
+	// TransactionCategoryCheque represents cheque payments
+	TransactionCategoryCheque
+	// TransactionCategorySecurities represents transactions of the
+	// securities business
+	TransactionCategorySecurities
+	// TransactionCategoryLoan represents transactions of the loan business
+	TransactionCategoryLoan
+	// TransactionCategoryInternalTransfer represents transfers between
+	// accounts of the same bank institute
+	TransactionCategoryInternalTransfer
+	// TransactionCategoryReversal represents cancellations of bookings
+	TransactionCategoryReversal
+)
+
+func (t TransactionCategory) String() string {
+	switch t {
+	case TransactionCategoryTransfer:
+		return "transfer"
+	case TransactionCategoryStandingOrder:
+		return "standing_order"
+	case TransactionCategoryDirectDebit:
+		return "direct_debit"
+	case TransactionCategoryCardPayment:
+		return "card_payment"
+	case TransactionCategoryCashWithdrawal:
+		return "cash_withdrawal"
+	case TransactionCategoryCashDeposit:
+		return "cash_deposit"
+	case TransactionCategorySalary:
+		return "salary"
+	case TransactionCategoryReturn:
+		return "return"
+	case TransactionCategoryFee:
+		return "fee"
+	case TransactionCategoryInterest:
+		return "interest"
+	case TransactionCategoryCheque:
+		return "cheque"
+	case TransactionCategorySecurities:
+		return "securities"
+	case TransactionCategoryLoan:
+		return "loan"
+	case TransactionCategoryInternalTransfer:
+		return "internal_transfer"
+	case TransactionCategoryReversal:
+		return "reversal"
+	default:
+		return "unknown"
+	}
+}
+
+// TransactionCode represents an entry of the business transaction code
+// catalog, as used in field 86 of MT940 account statements
+type TransactionCode struct {
+	Code     int
+	Category TransactionCategory
+	// German contains the german description as defined by the DK
+	German string
+	// English contains an english description of the code
+	English string
+}
+
+func (t TransactionCode) String() string {
+	if t.English == "" {
+		return fmt.Sprintf("%03d (%s)", t.Code, t.Category)
+	}
+	return fmt.Sprintf("%03d (%s): %s", t.Code, t.Category, t.English)
+}
+
+// LookupTransactionCode returns the catalog entry for the given business
+// transaction code. If the code is not in the catalog the returned
+// TransactionCode only carries the code and the category derived from the
+// code range, and ok is false.
+func LookupTransactionCode(code int) (transactionCode TransactionCode, ok bool) {
+	transactionCode, ok = transactionCodes[code]
+	if ok {
+		return transactionCode, true
+	}
+	return TransactionCode{
+		Code:     code,
+		Category: transactionCodeCategory(code),
+	}, false
+}
+
+func transactionCodeCategory(code int) TransactionCategory {
+	switch {
+	case code >= 300 && code < 400:
+		return TransactionCategorySecurities
+	case code >= 600 && code < 700:
+		return TransactionCategoryLoan
+	default:
+		return TransactionCategoryUnknown
+	}
+}
+
+// IsReturn reports whether a transaction with the given business transaction
+// code and message key addition (Textschlüsselergänzung) is a returned direct
+// debit or credit transfer.
+//
+// Besides the dedicated return codes, SEPA transactions carry the reason of a
+// return as message key addition from 901 to 996. The additions from 997 on
+// are used for regular transactions and are not considered.
+func IsReturn(code, messageKeyAddition int) bool {
+	transactionCode, _ := LookupTransactionCode(code)
+	if transactionCode.Category == TransactionCategoryReturn {
+		return true
+	}
+	if code < 100 || code >= 200 {
+		return false
+	}
+	switch transactionCode.Category {
+	case TransactionCategoryDirectDebit, TransactionCategoryTransfer:
+		return messageKeyAddition >= 901 && messageKeyAddition <= 996
+	default:
+		return false
+	}
+}
+
+var transactionCodes = map[int]TransactionCode{
+	1:   {1, TransactionCategoryCheque, "Inhaberscheck", "Bearer cheque"},
+	2:   {2, TransactionCategoryCheque, "Orderscheck", "Order cheque"},
+	4:   {4, TransactionCategoryDirectDebit, "Lastschrift (Abbuchungsverfahren)", "Direct debit (debit authorisation)"},
+	5:   {5, TransactionCategoryDirectDebit, "Lastschrift (Einzugsermächtigungsverfahren)", "Direct debit (direct debit authorisation)"},
+	8:   {8, TransactionCategoryStandingOrder, "Dauerauftrag Belastung", "Standing order debit"},
+	9:   {9, TransactionCategoryReturn, "Rücklastschrift", "Returned direct debit"},
+	11:  {11, TransactionCategoryCheque, "eurocheque", "Eurocheque"},
+	13:  {13, TransactionCategoryTransfer, "EU-Standardüberweisung", "EU standard credit transfer"},
+	15:  {15, TransactionCategoryTransfer, "Auslandsüberweisung ohne Meldeteil", "Foreign credit transfer"},
+	20:  {20, TransactionCategoryTransfer, "Überweisungsauftrag", "Credit transfer"},
+	51:  {51, TransactionCategoryTransfer, "Überweisungsgutschrift", "Credit transfer credit"},
+	52:  {52, TransactionCategoryStandingOrder, "Dauerauftragsgutschrift", "Standing order credit"},
+	53:  {53, TransactionCategorySalary, "Lohn-, Gehalts-, Rentengutschrift", "Salary, wage or pension credit"},
+	54:  {54, TransactionCategorySalary, "Vermögenswirksame Leistungen", "Capital-forming payment"},
+	56:  {56, TransactionCategoryTransfer, "Überweisung öffentlicher Kassen", "Credit transfer of public authorities"},
+	59:  {59, TransactionCategoryReturn, "Gutschrift (Rücküberweisung)", "Returned credit transfer"},
+	63:  {63, TransactionCategoryTransfer, "Überweisungsgutschrift - EU-Standardüberweisung", "EU standard credit transfer credit"},
+	65:  {65, TransactionCategoryTransfer, "Überweisungsgutschrift (Auslandsüberweisung ohne Meldeteil)", "Foreign credit transfer credit"},
+	70:  {70, TransactionCategoryCheque, "Scheckeinreichung", "Cheque deposit"},
+	71:  {71, TransactionCategoryDirectDebit, "Lastschrifteinreichung", "Direct debit collection"},
+	80:  {80, TransactionCategorySalary, "Gehalt", "Salary"},
+	82:  {82, TransactionCategoryCashDeposit, "Einzahlungen", "Cash deposit"},
+	83:  {83, TransactionCategoryCashWithdrawal, "Auszahlungen", "Cash withdrawal"},
+	96:  {96, TransactionCategoryInternalTransfer, "Kontoübertrag (Soll)", "Account transfer debit"},
+	97:  {97, TransactionCategoryInternalTransfer, "Kontoübertrag (Haben)", "Account transfer credit"},
+	104: {104, TransactionCategoryDirectDebit, "SEPA-Firmenlastschrift", "SEPA B2B direct debit"},
+	105: {105, TransactionCategoryDirectDebit, "SEPA-Basislastschrift", "SEPA core direct debit"},
+	106: {106, TransactionCategoryCardPayment, "SEPA-Kartenzahlung", "SEPA card payment"},
+	107: {107, TransactionCategoryCardPayment, "SEPA-Lastschrift aus Kartenzahlung", "SEPA direct debit from card payment"},
+	108: {108, TransactionCategoryReturn, "SEPA-Rücklastschrift Firmenlastschrift", "Returned SEPA B2B direct debit"},
+	109: {109, TransactionCategoryReturn, "SEPA-Rücklastschrift Basislastschrift", "Returned SEPA core direct debit"},
+	116: {116, TransactionCategoryTransfer, "SEPA-Überweisung", "SEPA credit transfer"},
+	117: {117, TransactionCategoryStandingOrder, "SEPA-Dauerauftrag", "SEPA standing order"},
+	118: {118, TransactionCategoryTransfer, "SEPA-Echtzeitüberweisung", "SEPA instant credit transfer"},
+	119: {119, TransactionCategoryTransfer, "SEPA-Spendenüberweisung", "SEPA donation credit transfer"},
+	152: {152, TransactionCategoryStandingOrder, "SEPA-Dauerauftragsgutschrift", "SEPA standing order credit"},
+	153: {153, TransactionCategorySalary, "SEPA-Lohn-, Gehalts-, Rentengutschrift", "SEPA salary, wage or pension credit"},
+	154: {154, TransactionCategorySalary, "SEPA-Vermögenswirksame Leistungen", "SEPA capital-forming payment"},
+	156: {156, TransactionCategoryTransfer, "SEPA-Überweisung öffentlicher Kassen", "SEPA credit transfer of public authorities"},
+	159: {159, TransactionCategoryReturn, "SEPA-Rücküberweisung", "Returned SEPA credit transfer"},
+	166: {166, TransactionCategoryTransfer, "SEPA-Überweisungsgutschrift", "SEPA credit transfer credit"},
+	167: {167, TransactionCategoryTransfer, "SEPA-Überweisungsgutschrift mit Prüfziffer", "SEPA credit transfer credit with check digit"},
+	169: {169, TransactionCategoryTransfer, "SEPA-Spendengutschrift", "SEPA donation credit"},
+	171: {171, TransactionCategoryDirectDebit, "SEPA-Basislastschrift Einreichung", "SEPA core direct debit collection"},
+	174: {174, TransactionCategoryDirectDebit, "SEPA-Firmenlastschrift Einreichung", "SEPA B2B direct debit collection"},
+	181: {181, TransactionCategoryReturn, "SEPA-Wiedergutschrift Basislastschrift", "Refund of SEPA core direct debit"},
+	184: {184, TransactionCategoryReturn, "SEPA-Wiedergutschrift Firmenlastschrift", "Refund of SEPA B2B direct debit"},
+	191: {191, TransactionCategoryTransfer, "SEPA-Sammelüberweisung", "SEPA batch credit transfer"},
+	192: {192, TransactionCategoryDirectDebit, "SEPA-Sammeleinreichung Basislastschrift", "SEPA core direct debit batch collection"},
+	195: {195, TransactionCategoryDirectDebit, "SEPA-Sammeleinreichung Firmenlastschrift", "SEPA B2B direct debit batch collection"},
+	201: {201, TransactionCategoryTransfer, "Zahlungsauftrag", "Foreign payment order"},
+	206: {206, TransactionCategoryTransfer, "Auslandsüberweisung", "Foreign credit transfer"},
+	212: {212, TransactionCategoryStandingOrder, "Dauerauftrag", "Foreign standing order"},
+	213: {213, TransactionCategoryDirectDebit, "Lastschrift - Einzug aus dem Ausland", "Foreign direct debit"},
+	320: {320, TransactionCategoryFee, "Gebühren für Wertpapiergeschäfte", "Securities fees"},
+	321: {321, TransactionCategoryFee, "Depotgebühren", "Custody fees"},
+	399: {399, TransactionCategoryReversal, "Storno", "Reversal"},
+	604: {604, TransactionCategoryInterest, "Darlehenszinsen", "Loan interest"},
+	801: {801, TransactionCategoryFee, "Scheckkarte", "Cheque card"},
+	804: {804, TransactionCategoryFee, "Dauerauftragsgebühren", "Standing order fees"},
+	805: {805, TransactionCategoryFee, "Abschluss", "Account closing"},
+	806: {806, TransactionCategoryFee, "Porto/Zustellgebühren", "Postage"},
+	807: {807, TransactionCategoryFee, "Preise/Spesen", "Charges"},
+	808: {808, TransactionCategoryFee, "Gebühren", "Fees"},
+	809: {809, TransactionCategoryFee, "Provisionen", "Commissions"},
+	810: {810, TransactionCategoryFee, "Mahngebühren", "Dunning fees"},
+	811: {811, TransactionCategoryFee, "Kreditkosten", "Credit costs"},
+	812: {812, TransactionCategoryInterest, "Stundungszinsen", "Deferral interest"},
+	814: {814, TransactionCategoryInterest, "Zinsen", "Interest"},
+	815: {815, TransactionCategoryInterest, "kapitalisierte Zinsen", "Capitalised interest"},
+	817: {817, TransactionCategoryInterest, "Zinsberichtigung", "Interest correction"},
+	820: {820, TransactionCategoryInternalTransfer, "Übertrag", "Transfer between accounts"},
+	835: {835, TransactionCategoryReturn, "Retoure", "Return"},
+	836: {836, TransactionCategoryUnknown, "Reklamationsbuchung", "Complaint booking"},
+	899: {899, TransactionCategoryReversal, "Storno", "Reversal"},
+	999: {999, TransactionCategoryUnknown, "Unstrukturierte Belegung des Mehrzweckfeldes", "Unstructured field 86"},
+}
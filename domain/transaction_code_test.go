@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLookupTransactionCode(t *testing.T) {
+	tests := []struct {
+		code             int
+		expectedOk       bool
+		expectedCategory TransactionCategory
+	}{
+		{105, true, TransactionCategoryDirectDebit},
+		{166, true, TransactionCategoryTransfer},
+		{106, true, TransactionCategoryCardPayment},
+		{153, true, TransactionCategorySalary},
+		{109, true, TransactionCategoryReturn},
+		{808, true, TransactionCategoryFee},
+		{814, true, TransactionCategoryInterest},
+		{835, true, TransactionCategoryReturn},
+		{302, false, TransactionCategorySecurities},
+		{603, false, TransactionCategoryLoan},
+		{555, false, TransactionCategoryUnknown},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%03d", test.code), func(t *testing.T) {
+			transactionCode, ok := LookupTransactionCode(test.code)
+
+			if ok != test.expectedOk {
+				t.Errorf("Expected ok to be %t, got %t", test.expectedOk, ok)
+			}
+			if transactionCode.Code != test.code {
+				t.Errorf("Expected code to equal %d, got %d", test.code, transactionCode.Code)
+			}
+			if transactionCode.Category != test.expectedCategory {
+				t.Errorf("Expected category to equal %q, got %q", test.expectedCategory, transactionCode.Category)
+			}
+			if ok && (transactionCode.German == "" || transactionCode.English == "") {
+				t.Errorf("Expected descriptions to be set, got %+v", transactionCode)
+			}
+		})
+	}
+}
+
+func TestTransactionCodesConsistent(t *testing.T) {
+	for code, transactionCode := range transactionCodes {
+		if transactionCode.Code != code {
+			t.Errorf("Expected code of entry %03d to equal %03d, got %03d", code, code, transactionCode.Code)
+		}
+	}
+}
+
+func TestIsReturn(t *testing.T) {
+	tests := []struct {
+		description        string
+		code               int
+		messageKeyAddition int
+		expected           bool
+	}{
+		{"returned core direct debit", 109, 0, true},
+		{"refund of direct debit", 181, 906, true},
+		{"return booking", 835, 0, true},
+		{"direct debit with return reason", 105, 901, true},
+		{"transfer with return reason", 166, 902, true},
+		{"regular SEPA direct debit", 105, 0, false},
+		{"regular SEPA transfer", 166, 997, false},
+		{"card payment", 106, 901, false},
+		{"legacy transfer", 51, 901, false},
+	}
+	for _, test := range tests {
+		actual := IsReturn(test.code, test.messageKeyAddition)
+
+		if actual != test.expected {
+			t.Logf("%s: Expected IsReturn to return %t, got %t\n", test.description, test.expected, actual)
+			t.Fail()
+		}
+	}
+}
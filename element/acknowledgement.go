@@ -10,9 +10,11 @@ import (
 
 // These represent HBCI acknowledgement codes. Codes starting with 3 are meant
 // to be warnings.
+//
+// See domain.LookupReturnCode for the complete catalog of return codes.
 const (
-	AcknowledgementAdditionalInformation     = 3040
-	AcknowledgementSupportedSecurityFunction = 3920
+	AcknowledgementAdditionalInformation     = domain.ReturnCodeAdditionalInformation
+	AcknowledgementSupportedSecurityFunction = domain.ReturnCodeSupportedSecurityFunction
 )
 
 // NewAcknowledgement returns a new acknowledgement DataElement
@@ -253,20 +253,14 @@ func (p *PinTanBusinessTransactionParameters) UnmarshalHBCI(value []byte) error
 	return nil
 }
 
+//go:generate go run ../cmd/unmarshaler/unmarshaler_generator.go -group PinTanBusinessTransactionParameter -group_type pinTanBusinessTransactionParameterGDEG
+
 // PinTanBusinessTransactionParameter defines a specific
 // PinTanBusinessTransactionParameter DataElement
 type PinTanBusinessTransactionParameter struct {
 	DataElement `yaml:"-"`
-	SegmentID   *AlphaNumericDataElement `yaml:"segmentID"`
-	NeedsTAN    *BooleanDataElement      `yaml:"needsTan"`
-}
-
-// Elements returns the elements of this DataElement.
-func (p *PinTanBusinessTransactionParameter) Elements() []DataElement {
-	return []DataElement{
-		p.SegmentID,
-		p.NeedsTAN,
-	}
+	SegmentID   *AlphaNumericDataElement `hbci:"an,6" yaml:"segmentID"`
+	NeedsTAN    *BooleanDataElement      `hbci:"jn" yaml:"needsTan"`
 }
 
 // Val returns the underlying PinTanBusinessTransaction
@@ -276,20 +270,3 @@ func (p *PinTanBusinessTransactionParameter) Val() domain.PinTanBusinessTransact
 		NeedsTan:  p.NeedsTAN.Val(),
 	}
 }
-
-// UnmarshalHBCI unmarshals value into p
-func (p *PinTanBusinessTransactionParameter) UnmarshalHBCI(value []byte) error {
-	elements, err := ExtractElements(value)
-	if err != nil {
-		return err
-	}
-	p.SegmentID = NewAlphaNumeric(charset.ToUTF8(elements[0]), 6)
-	needsTan := &BooleanDataElement{}
-	err = needsTan.UnmarshalHBCI(elements[1])
-	if err != nil {
-		return err
-	}
-	p.NeedsTAN = needsTan
-	p.DataElement = NewGroupDataElementGroup(pinTanBusinessTransactionParameterGDEG, 2, p)
-	return nil
-}
@@ -64,3 +64,54 @@ func TestPinTanBusinessTransactionParameterUnmarshalHBCI(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestPinTanBusinessTransactionParameterUnmarshalHBCIMissingElement(t *testing.T) {
+	element := &PinTanBusinessTransactionParameter{}
+
+	err := element.UnmarshalHBCI([]byte("HKSAL"))
+
+	if err == nil {
+		t.Logf("Expected error for missing required element\n")
+		t.Fail()
+	}
+}
+
+func TestPinTanBusinessTransactionParameterValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		element     *PinTanBusinessTransactionParameter
+		err         bool
+	}{
+		{
+			"valid parameter",
+			&PinTanBusinessTransactionParameter{
+				SegmentID: NewAlphaNumeric("HKSAL", 6),
+				NeedsTAN:  NewBoolean(false),
+			},
+			false,
+		},
+		{
+			"missing NeedsTAN",
+			&PinTanBusinessTransactionParameter{
+				SegmentID: NewAlphaNumeric("HKSAL", 6),
+			},
+			true,
+		},
+		{
+			"SegmentID too long",
+			&PinTanBusinessTransactionParameter{
+				SegmentID: NewAlphaNumeric("HKSALDO", 7),
+				NeedsTAN:  NewBoolean(false),
+			},
+			true,
+		},
+	}
+	for _, test := range tests {
+		err := test.element.Validate()
+
+		if (err != nil) != test.err {
+			t.Logf("%s: Expected error %t, got %v\n", test.description, test.err, err)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Code generated by *generator.GroupDataElementGroupGenerator; DO NOT EDIT.
+
+package element
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Elements returns the elements of this DataElement.
+func (p *PinTanBusinessTransactionParameter) Elements() []DataElement {
+	return []DataElement{
+		p.SegmentID,
+		p.NeedsTAN,
+	}
+}
+
+// UnmarshalHBCI unmarshals value into p
+func (p *PinTanBusinessTransactionParameter) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) > 0 && len(elements[0]) > 0 {
+		p.SegmentID = &AlphaNumericDataElement{}
+		err = p.SegmentID.UnmarshalHBCI(elements[0])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling SegmentID: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element SegmentID")
+	}
+	if len(elements) > 1 && len(elements[1]) > 0 {
+		p.NeedsTAN = &BooleanDataElement{}
+		err = p.NeedsTAN.UnmarshalHBCI(bytes.Join(elements[1:], []byte(":")))
+		if err != nil {
+			return fmt.Errorf("error unmarshaling NeedsTAN: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element NeedsTAN")
+	}
+	p.DataElement = NewGroupDataElementGroup(pinTanBusinessTransactionParameterGDEG, 2, p)
+	return nil
+}
+
+// Validate validates the elements of p against their definition
+func (p *PinTanBusinessTransactionParameter) Validate() error {
+	if p.SegmentID == nil {
+		return fmt.Errorf("PinTanBusinessTransactionParameter: missing required element SegmentID")
+	}
+	if p.SegmentID.Length() > 6 {
+		return fmt.Errorf("PinTanBusinessTransactionParameter: SegmentID exceeds the maximum length of 6")
+	}
+	if !p.SegmentID.IsValid() {
+		return fmt.Errorf("PinTanBusinessTransactionParameter: SegmentID is invalid")
+	}
+	if p.NeedsTAN == nil {
+		return fmt.Errorf("PinTanBusinessTransactionParameter: missing required element NeedsTAN")
+	}
+	if !p.NeedsTAN.IsValid() {
+		return fmt.Errorf("PinTanBusinessTransactionParameter: NeedsTAN is invalid")
+	}
+	return nil
+}
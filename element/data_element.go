@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/mitch000001/go-hbci/charset"
+	"github.com/mitch000001/go-hbci/domain"
 )
 
 // DataElement represent the general interface of a DataElement used by HBCI
@@ -245,7 +246,7 @@ func (a *AlphaNumericDataElement) Val() string { return a.val.(string) }
 
 // IsValid returns false if a contains '\n' and '\r', true otherwise
 func (a *AlphaNumericDataElement) IsValid() bool {
-	if strings.ContainsAny(a.Val(), "\n & \r") {
+	if strings.ContainsAny(a.Val(), "\n\r") {
 		return false
 	}
 	return a.basicDataElement.IsValid()
@@ -499,6 +500,13 @@ func (b *BooleanDataElement) String() string {
 	return "N"
 }
 
+// Length returns the length of the marshaled value of b
+func (b *BooleanDataElement) Length() int { return len(b.String()) }
+
+// IsValid returns true if the marshaled value of b does not exceed its
+// maximum length, false otherwise
+func (b *BooleanDataElement) IsValid() bool { return b.Length() <= b.maxLength }
+
 // MarshalHBCI marshals b into the HBCI wire format
 func (b *BooleanDataElement) MarshalHBCI() ([]byte, error) {
 	return charset.ToISO8859_1(b.String()), nil
@@ -519,6 +527,7 @@ func (b *BooleanDataElement) UnmarshalHBCI(value []byte) error {
 
 // NewCode returns a new CodeDataElement
 func NewCode(val string, maxLength int, validSet []string) *CodeDataElement {
+	validSet = append([]string(nil), validSet...)
 	sort.Strings(validSet)
 	return &CodeDataElement{
 		AlphaNumericDataElement: NewAlphaNumeric(val, maxLength),
@@ -540,14 +549,15 @@ func (c *CodeDataElement) Type() DataElementType {
 // IsValid returns true if the value is in the valid set and the underlying
 // AlphaNumericDataElement is valid, false otherwise.
 func (c *CodeDataElement) IsValid() bool {
-	if sort.SearchStrings(c.validSet, c.Val()) >= len(c.validSet) {
+	i := sort.SearchStrings(c.validSet, c.Val())
+	if i >= len(c.validSet) || c.validSet[i] != c.Val() {
 		return false
 	}
 	return c.AlphaNumericDataElement.IsValid()
 }
 
-// UnmarshalHBCI unmarshals value into a. a will always be invalid, because the
-// valid set is not known when unmarshaling
+// UnmarshalHBCI unmarshals value into c. The valid set of c is kept, so c is
+// only valid if it was created with NewCode before unmarshaling.
 func (c *CodeDataElement) UnmarshalHBCI(value []byte) error {
 	c.AlphaNumericDataElement = &AlphaNumericDataElement{}
 	return c.AlphaNumericDataElement.UnmarshalHBCI(value)
@@ -744,14 +754,20 @@ func (c *CurrencyDataElement) UnmarshalHBCI(value []byte) error {
 
 // NewValue returns a new ValueDataElement
 func NewValue(val float64) *ValueDataElement {
-	f := NewFloat(val, 15)
-	f.typ = valueDE
-	return &ValueDataElement{f}
+	return &ValueDataElement{&basicDataElement{NewFloat(val, 15).String(), valueDE, 15, false}}
+}
+
+// NewAmountValue returns a new ValueDataElement holding the absolute value
+// of amount.
+func NewAmountValue(amount domain.Amount) *ValueDataElement {
+	return &ValueDataElement{&basicDataElement{amount.Abs().FormatHBCI(), valueDE, 15, false}}
 }
 
-// A ValueDataElement represents a float which can have upto 15 characters
+// A ValueDataElement represents a decimal number which can have upto 15
+// characters. The value is kept in its HBCI notation to retain its exact
+// decimal representation.
 type ValueDataElement struct {
-	*FloatDataElement
+	*basicDataElement
 }
 
 // Type returns the DataElementType of v
@@ -759,13 +775,36 @@ func (v *ValueDataElement) Type() DataElementType {
 	return valueDE
 }
 
+// Val returns the value of v as float64. Use Amount to get the exact value.
+func (v *ValueDataElement) Val() float64 {
+	val, err := strconv.ParseFloat(strings.Replace(v.String(), ",", ".", 1), 64)
+	if err != nil {
+		return 0
+	}
+	return val
+}
+
+// Amount returns the exact value of v in the provided currency. It returns an
+// error if v has more decimal places than the currency.
+func (v *ValueDataElement) Amount(currency string) (domain.Amount, error) {
+	return domain.ParseAmount(v.String(), currency)
+}
+
+func (v *ValueDataElement) String() string {
+	return v.val.(string)
+}
+
+// MarshalHBCI marshals v into HBCI wire format
+func (v *ValueDataElement) MarshalHBCI() ([]byte, error) {
+	return charset.ToISO8859_1(v.String()), nil
+}
+
 // UnmarshalHBCI unmarshals value into v
 func (v *ValueDataElement) UnmarshalHBCI(value []byte) error {
-	var f FloatDataElement
-	err := f.UnmarshalHBCI(value)
-	if err != nil {
+	str := charset.ToUTF8(value)
+	if _, err := strconv.ParseFloat(strings.Replace(str, ",", ".", 1), 64); err != nil {
 		return err
 	}
-	*v = ValueDataElement{&f}
+	*v = ValueDataElement{&basicDataElement{str, valueDE, len(value), false}}
 	return nil
 }
@@ -481,3 +481,45 @@ func TestGroupDataElementGroupUnmarshalHBCI(t *testing.T) {
 		}
 	}
 }
+
+func TestAlphaNumericDataElementIsValid(t *testing.T) {
+	tests := []struct {
+		value string
+		valid bool
+	}{
+		{"chipTAN manuell", true},
+		{"A&B", true},
+		{"line\nbreak", false},
+		{"carriage\rreturn", false},
+	}
+	for _, test := range tests {
+		valid := NewAlphaNumeric(test.value, 20).IsValid()
+
+		if valid != test.valid {
+			t.Logf("%q: Expected valid to be %t, got %t\n", test.value, test.valid, valid)
+			t.Fail()
+		}
+	}
+}
+
+func TestCodeDataElementIsValid(t *testing.T) {
+	validSet := []string{"1", "3"}
+	tests := []struct {
+		value string
+		valid bool
+	}{
+		{"1", true},
+		{"3", true},
+		{"0", false},
+		{"2", false},
+		{"4", false},
+	}
+	for _, test := range tests {
+		valid := NewCode(test.value, 1, validSet).IsValid()
+
+		if valid != test.valid {
+			t.Logf("%q: Expected valid to be %t, got %t\n", test.value, test.valid, valid)
+			t.Fail()
+		}
+	}
+}
@@ -65,6 +65,8 @@ const (
 	tan2StepSubmissionParameterDEG
 	tan2StepSubmissionProcessParameterDEG
 	pinTanSpecificParamDataElementDEG
+	timestampDEG
+	pushServiceParameterDEG
 )
 
 var typeName = map[DataElementType]string{
@@ -118,6 +120,8 @@ var typeName = map[DataElementType]string{
 	tan2StepSubmissionParameterDEG:        "Parameter Zwei-Schritt-TAN-Einreichung",
 	tan2StepSubmissionProcessParameterDEG: "Verfahrensparameter Zwei-Schritt-Verfahren",
 	pinTanSpecificParamDataElementDEG:     "Parameter PIN/TAN-spezifische Informationen",
+	timestampDEG:                          "Zeitstempel",
+	pushServiceParameterDEG:               "Parameter Push-Services Registrierung",
 }
 
 func (d DataElementType) String() string {
@@ -16,9 +16,8 @@ func ExtractElements(dataElementGroup []byte) ([][]byte, error) {
 // newElementExtractor creates a new GroupExtractor ready to use
 func newElementExtractor(dataElementGroup []byte) *groupExtractor {
 	// TODO: workaround to get the lexer work properly for us. Maybe we should adopt the lexer?
-	if bytes.LastIndexFunc(dataElementGroup, func(r rune) bool {
-		return r == '+' || r == '\''
-	}) == -1 {
+	// Only the last byte is relevant, as binary data may contain separators.
+	if !bytes.HasSuffix(dataElementGroup, []byte("+")) && !bytes.HasSuffix(dataElementGroup, []byte("'")) {
 		dataElementGroup = append(dataElementGroup, '+')
 	}
 	return &groupExtractor{
@@ -61,6 +61,15 @@ func Test_ExtractElements(t *testing.T) {
 			},
 			nil,
 		},
+		{
+			"5:@3@a'b:13",
+			[]string{
+				"5",
+				"@3@a'b",
+				"13",
+			},
+			nil,
+		},
 	}
 
 	for _, test := range tests {
@@ -32,6 +32,21 @@ func NewRDHEncryptionAlgorithm(pubKey []byte) *EncryptionAlgorithmDataElement {
 	return e
 }
 
+// NewAESEncryptionAlgorithm returns an EncryptionAlgorithmDataElement for
+// AES-256 message keys, encrypted with the public key of the receiver
+func NewAESEncryptionAlgorithm(encryptedKey []byte) *EncryptionAlgorithmDataElement {
+	e := &EncryptionAlgorithmDataElement{
+		Usage:                      NewAlphaNumeric("2", 3),
+		OperationMode:              NewAlphaNumeric("2", 3),
+		Algorithm:                  NewAlphaNumeric("14", 3),
+		Key:                        NewBinary(encryptedKey, 512),
+		KeyParamID:                 NewAlphaNumeric("6", 3),
+		InitializationValueParamID: NewAlphaNumeric("1", 3),
+	}
+	e.DataElement = NewDataElementGroup(encryptionAlgorithmDEG, 7, e)
+	return e
+}
+
 // EncryptionAlgorithmDataElement represents an encryption algorithm
 type EncryptionAlgorithmDataElement struct {
 	DataElement
@@ -40,6 +55,7 @@ type EncryptionAlgorithmDataElement struct {
 	// "2" for CBC, Cipher Block Chaining.
 	OperationMode *AlphaNumericDataElement
 	// "13" for 2-Key-Triple-DES
+	// "14" for AES-256
 	Algorithm *AlphaNumericDataElement
 	Key       *BinaryDataElement
 	// "5" for KYE, Symmetric key, en-/decryption with a symmetric key (DDV)
@@ -64,12 +80,12 @@ func (e *EncryptionAlgorithmDataElement) GroupDataElements() []DataElement {
 
 // UnmarshalHBCI unmarshals value into the DataElement
 func (e *EncryptionAlgorithmDataElement) UnmarshalHBCI(value []byte) error {
-	e = &EncryptionAlgorithmDataElement{}
 	elements, err := ExtractElements(value)
 	if err != nil {
 		return err
 	}
-	if len(elements) < 7 {
+	// The initialization value is optional
+	if len(elements) < 6 {
 		return fmt.Errorf("malformed marshaled value")
 	}
 	e.DataElement = NewDataElementGroup(encryptionAlgorithmDEG, 5, e)
@@ -3,7 +3,6 @@ package element
 import (
 	"bytes"
 	"fmt"
-	"math"
 	"strconv"
 	"time"
 
@@ -12,10 +11,10 @@ import (
 )
 
 // NewAmount returns a new AmountDataElement
-func NewAmount(value float64, currency string) *AmountDataElement {
+func NewAmount(amount domain.Amount) *AmountDataElement {
 	a := &AmountDataElement{
-		Amount:   NewValue(value),
-		Currency: NewCurrency(currency),
+		Amount:   NewAmountValue(amount),
+		Currency: NewCurrency(amount.Currency),
 	}
 	a.DataElement = NewGroupDataElementGroup(amountGDEG, 2, a)
 	return a
@@ -38,10 +37,11 @@ func (a *AmountDataElement) Elements() []DataElement {
 
 // Val returns the value of a as a domain.Amount
 func (a *AmountDataElement) Val() domain.Amount {
-	return domain.Amount{
-		Amount:   a.Amount.Val(),
-		Currency: a.Currency.Val(),
+	amount, err := a.Amount.Amount(a.Currency.Val())
+	if err != nil {
+		return domain.AmountFromFloat(a.Amount.Val(), a.Currency.Val())
 	}
+	return amount
 }
 
 // UnmarshalHBCI unmarshals value into a
@@ -63,6 +63,9 @@ func (a *AmountDataElement) UnmarshalHBCI(value []byte) error {
 	if err != nil {
 		return err
 	}
+	if _, err := a.Amount.Amount(a.Currency.Val()); err != nil {
+		return err
+	}
 	a.DataElement = NewGroupDataElementGroup(amountGDEG, 2, a)
 	return nil
 }
@@ -289,14 +292,14 @@ func (i *InternationalAccountConnectionDataElement) Val() domain.InternationalAc
 // NewBalance returns a new BalanceDataElement
 func NewBalance(amount domain.Amount, date time.Time, withTime bool) *BalanceDataElement {
 	var debitCredit string
-	if amount.Amount < 0 {
+	if amount.Sign() < 0 {
 		debitCredit = "D"
 	} else {
 		debitCredit = "C"
 	}
 	b := &BalanceDataElement{
 		DebitCreditIndicator: NewAlphaNumeric(debitCredit, 1),
-		Amount:               NewValue(math.Abs(amount.Amount)),
+		Amount:               NewAmountValue(amount),
 		Currency:             NewCurrency(amount.Currency),
 		TransmissionDate:     NewDate(date),
 	}
@@ -330,15 +333,13 @@ func (b *BalanceDataElement) Elements() []DataElement {
 
 // Balance returns the balance as domain.Balance
 func (b *BalanceDataElement) Balance() domain.Balance {
-	sign := b.DebitCreditIndicator.Val()
-	val := b.Amount.Val()
-	if sign == "D" {
-		val = -val
-	}
 	currency := b.Currency.Val()
-	amount := domain.Amount{
-		Amount:   val,
-		Currency: currency,
+	amount, err := b.Amount.Amount(currency)
+	if err != nil {
+		amount = domain.AmountFromFloat(b.Amount.Val(), currency)
+	}
+	if b.DebitCreditIndicator.Val() == "D" {
+		amount = amount.Neg()
 	}
 	balance := domain.Balance{
 		Amount:           amount,
@@ -375,6 +376,9 @@ func (b *BalanceDataElement) UnmarshalHBCI(value []byte) error {
 	if err != nil {
 		return err
 	}
+	if _, err := b.Amount.Amount(b.Currency.Val()); err != nil {
+		return err
+	}
 	b.TransmissionDate = &DateDataElement{}
 	err = b.TransmissionDate.UnmarshalHBCI(elements[3])
 	if err != nil {
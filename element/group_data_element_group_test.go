@@ -33,3 +33,36 @@ func TestAccountConnectionUnmarshalHBCI(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestBalanceUnmarshalHBCI(t *testing.T) {
+	test := "D:1234,56:EUR:20150812"
+
+	balance := &BalanceDataElement{}
+
+	err := balance.UnmarshalHBCI([]byte(test))
+
+	if err != nil {
+		t.Logf("Expected no error, got %T:%v\n", err, err)
+		t.Fail()
+	}
+
+	expected := domain.NewAmount(-123456, "EUR")
+	actual := balance.Balance().Amount
+
+	if expected != actual {
+		t.Logf("Expected amount to equal\n%#v\n\tgot\n%#v\n", expected, actual)
+		t.Fail()
+	}
+
+	marshaled, err := balance.MarshalHBCI()
+
+	if err != nil {
+		t.Logf("Expected no error, got %T:%v\n", err, err)
+		t.Fail()
+	}
+
+	if string(marshaled) != test {
+		t.Logf("Expected marshaled value to equal\n%q\n\tgot\n%q\n", test, marshaled)
+		t.Fail()
+	}
+}
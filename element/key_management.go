@@ -2,18 +2,27 @@ package element
 
 import (
 	"fmt"
-	"reflect"
+	"math/big"
 
 	"github.com/mitch000001/go-hbci/domain"
 )
 
+// publicKeyExponent defines the only allowed exponent for public keys
+const publicKeyExponent = 65537
+
+// publicKeyUsages maps key types to their usage codes
+var publicKeyUsages = map[string]string{
+	"V": "5",
+	"S": "6",
+}
+
 // NewPublicKey creates a new PublicKeyElement from pubKey
 func NewPublicKey(pubKey *domain.PublicKey) *PublicKeyDataElement {
-	if !reflect.DeepEqual(pubKey.Exponent, []byte("65537")) {
-		panic(fmt.Errorf("Exponent must equal 65537 (% X)", "65537"))
+	if exp := new(big.Int).SetBytes(pubKey.Exponent); !exp.IsInt64() || exp.Int64() != publicKeyExponent {
+		panic(fmt.Errorf("Exponent must equal %d", publicKeyExponent))
 	}
 	p := &PublicKeyDataElement{
-		Usage:         NewAlphaNumeric(pubKey.Type, 3),
+		Usage:         NewAlphaNumeric(publicKeyUsages[pubKey.Type], 3),
 		OperationMode: NewAlphaNumeric("16", 3),
 		Cipher:        NewAlphaNumeric("10", 3),
 		Modulus:       NewBinary(pubKey.Modulus, 512),
@@ -59,9 +68,56 @@ func (p *PublicKeyDataElement) GroupDataElements() []DataElement {
 
 // Val returns the public key
 func (p *PublicKeyDataElement) Val() *domain.PublicKey {
+	keyType := p.Usage.Val()
+	for typ, usage := range publicKeyUsages {
+		if usage == keyType {
+			keyType = typ
+		}
+	}
 	return &domain.PublicKey{
-		Type:     p.Usage.Val(),
+		Type:     keyType,
 		Modulus:  p.Modulus.Val(),
 		Exponent: p.Exponent.Val(),
 	}
 }
+
+// UnmarshalHBCI unmarshals value into the DataElement
+func (p *PublicKeyDataElement) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) < 7 {
+		return fmt.Errorf("malformed marshaled value")
+	}
+	p.DataElement = NewDataElementGroup(publicKeyDEG, 7, p)
+	p.Usage = &AlphaNumericDataElement{}
+	if err := p.Usage.UnmarshalHBCI(elements[0]); err != nil {
+		return err
+	}
+	p.OperationMode = &AlphaNumericDataElement{}
+	if err := p.OperationMode.UnmarshalHBCI(elements[1]); err != nil {
+		return err
+	}
+	p.Cipher = &AlphaNumericDataElement{}
+	if err := p.Cipher.UnmarshalHBCI(elements[2]); err != nil {
+		return err
+	}
+	p.Modulus = &BinaryDataElement{}
+	if err := p.Modulus.UnmarshalHBCI(elements[3]); err != nil {
+		return err
+	}
+	p.ModulusID = &AlphaNumericDataElement{}
+	if err := p.ModulusID.UnmarshalHBCI(elements[4]); err != nil {
+		return err
+	}
+	p.Exponent = &BinaryDataElement{}
+	if err := p.Exponent.UnmarshalHBCI(elements[5]); err != nil {
+		return err
+	}
+	p.ExponentID = &AlphaNumericDataElement{}
+	if err := p.ExponentID.UnmarshalHBCI(elements[6]); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package element
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/internal"
+)
+
+// NewTimestamp returns a new TimestampDataElement for t
+func NewTimestamp(t time.Time) *TimestampDataElement {
+	ts := &TimestampDataElement{
+		Date: NewDate(t),
+		Time: NewTime(t),
+	}
+	ts.DataElement = NewDataElementGroup(timestampDEG, 2, ts)
+	return ts
+}
+
+// TimestampDataElement represents a date with an optional time
+type TimestampDataElement struct {
+	DataElement
+	Date *DateDataElement
+	Time *TimeDataElement
+}
+
+// GroupDataElements returns the grouped DataElements
+func (t *TimestampDataElement) GroupDataElements() []DataElement {
+	return []DataElement{
+		t.Date,
+		t.Time,
+	}
+}
+
+// Val returns the timestamp as time.Time. The time component is zero if the
+// time is omitted.
+func (t *TimestampDataElement) Val() time.Time {
+	date := t.Date.Val()
+	if t.Time == nil {
+		return date
+	}
+	clock := t.Time.Val()
+	return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, date.Location())
+}
+
+// UnmarshalHBCI unmarshals value into the DataElement
+func (t *TimestampDataElement) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) == 0 || len(elements[0]) == 0 {
+		return fmt.Errorf("malformed marshaled value: missing date")
+	}
+	t.Date = &DateDataElement{}
+	if err := t.Date.UnmarshalHBCI(elements[0]); err != nil {
+		return fmt.Errorf("error unmarshaling Date: %w", err)
+	}
+	t.Time = nil
+	if len(elements) > 1 && len(elements[1]) > 0 {
+		t.Time = &TimeDataElement{}
+		if err := t.Time.UnmarshalHBCI(elements[1]); err != nil {
+			return fmt.Errorf("error unmarshaling Time: %w", err)
+		}
+	}
+	t.DataElement = NewDataElementGroup(timestampDEG, 2, t)
+	return nil
+}
+
+// PushServiceParameterDataElement represents the bank parameters for push
+// services
+type PushServiceParameterDataElement struct {
+	DataElement
+	WebSocketURL    *AlphaNumericDataElement
+	PollingInterval *NumberDataElement
+	UseUserID       *BooleanDataElement
+	SegmentIDs      []*AlphaNumericDataElement
+}
+
+// GroupDataElements returns the grouped DataElements
+func (p *PushServiceParameterDataElement) GroupDataElements() []DataElement {
+	elements := []DataElement{
+		p.WebSocketURL,
+		p.PollingInterval,
+		p.UseUserID,
+	}
+	for _, id := range p.SegmentIDs {
+		elements = append(elements, id)
+	}
+	return elements
+}
+
+// Val returns the parameters as domain.PushServiceParameters
+func (p *PushServiceParameterDataElement) Val() domain.PushServiceParameters {
+	params := domain.PushServiceParameters{
+		WebSocketURL:    p.WebSocketURL.Val(),
+		PollingInterval: time.Duration(p.PollingInterval.Val()) * time.Second,
+		UseUserID:       p.UseUserID.Val(),
+	}
+	for _, id := range p.SegmentIDs {
+		params.SegmentIDs = append(params.SegmentIDs, id.Val())
+	}
+	return params
+}
+
+// UnmarshalHBCI unmarshals value into the DataElement
+func (p *PushServiceParameterDataElement) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) < 3 {
+		return fmt.Errorf("malformed marshaled value: too few elements")
+	}
+	iter := internal.NewIterator(elements)
+	var webSocketURL AlphaNumericDataElement
+	if err := webSocketURL.UnmarshalHBCI(iter.Next()); err != nil {
+		return fmt.Errorf("error unmarshaling WebSocketURL: %w", err)
+	}
+	p.WebSocketURL = &webSocketURL
+	var pollingInterval NumberDataElement
+	if err := pollingInterval.UnmarshalHBCI(iter.Next()); err != nil {
+		return fmt.Errorf("error unmarshaling PollingInterval: %w", err)
+	}
+	p.PollingInterval = &pollingInterval
+	var useUserID BooleanDataElement
+	if err := useUserID.UnmarshalHBCI(iter.Next()); err != nil {
+		return fmt.Errorf("error unmarshaling UseUserID: %w", err)
+	}
+	p.UseUserID = &useUserID
+	p.SegmentIDs = nil
+	for _, id := range iter.Remainder() {
+		if len(id) == 0 {
+			continue
+		}
+		var segmentID AlphaNumericDataElement
+		if err := segmentID.UnmarshalHBCI(id); err != nil {
+			return fmt.Errorf("error unmarshaling SegmentIDs: %w", err)
+		}
+		p.SegmentIDs = append(p.SegmentIDs, &segmentID)
+	}
+	p.DataElement = NewDataElementGroup(pushServiceParameterDEG, 3+len(p.SegmentIDs), p)
+	return nil
+}
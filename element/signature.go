@@ -174,11 +174,36 @@ func NewDefaultHashAlgorithm() *HashAlgorithmDataElement {
 	return h
 }
 
+// NewSHA256HashAlgorithm creates a HashAlgorithmDataElement for SHA-256, as
+// used by the RDH-10 profile
+func NewSHA256HashAlgorithm() *HashAlgorithmDataElement {
+	h := &HashAlgorithmDataElement{
+		Usage:            NewAlphaNumeric("1", 3),
+		Algorithm:        NewAlphaNumeric("3", 3),
+		AlgorithmParamID: NewAlphaNumeric("1", 3),
+	}
+	h.DataElement = NewDataElementGroup(hashAlgorithmDEG, 4, h)
+	return h
+}
+
+// NewSHA256SHA256HashAlgorithm creates a HashAlgorithmDataElement for double
+// hashing with SHA-256, as used by the RAH-10 profile
+func NewSHA256SHA256HashAlgorithm() *HashAlgorithmDataElement {
+	h := &HashAlgorithmDataElement{
+		Usage:            NewAlphaNumeric("1", 3),
+		Algorithm:        NewAlphaNumeric("6", 3),
+		AlgorithmParamID: NewAlphaNumeric("1", 3),
+	}
+	h.DataElement = NewDataElementGroup(hashAlgorithmDEG, 4, h)
+	return h
+}
+
 // HashAlgorithmDataElement defines a hash algorithm
 type HashAlgorithmDataElement struct {
 	DataElement
 	// "1" for OHA, Owner Hashing
 	Usage *AlphaNumericDataElement
+	// "3" for SHA-256
 	// "999" for ZZZ (RIPEMD-160)
 	Algorithm *AlphaNumericDataElement
 	// "1" for IVC, Initialization value, clear text
@@ -249,6 +274,18 @@ func NewRDHSignatureAlgorithm() *SignatureAlgorithmDataElement {
 	return s
 }
 
+// NewPSSSignatureAlgorithm creates a SignatureAlgorithm for RSASSA-PSS, as
+// used by the RDH-10 and RAH-10 profiles
+func NewPSSSignatureAlgorithm() *SignatureAlgorithmDataElement {
+	s := &SignatureAlgorithmDataElement{
+		Usage:         NewAlphaNumeric("6", 3),
+		Algorithm:     NewAlphaNumeric("10", 3),
+		OperationMode: NewAlphaNumeric("19", 3),
+	}
+	s.DataElement = NewDataElementGroup(signatureAlgorithmDEG, 3, s)
+	return s
+}
+
 // A SignatureAlgorithmDataElement represents a signature algorithm
 type SignatureAlgorithmDataElement struct {
 	DataElement
@@ -258,6 +295,7 @@ type SignatureAlgorithmDataElement struct {
 	// "10" for RSA (RDH)
 	Algorithm *AlphaNumericDataElement
 	// "16" for DSMR, Digital Signature Scheme giving Message Recovery: ISO 9796 (RDH)
+	// "19" for RSASSA-PSS (RDH-10, RAH-10)
 	// "999" for ZZZ (DDV)
 	OperationMode *AlphaNumericDataElement
 }
@@ -429,3 +467,24 @@ func (c *CertificateDataElement) GroupDataElements() []DataElement {
 		c.Content,
 	}
 }
+
+// UnmarshalHBCI unmarshals value into the DataElement
+func (c *CertificateDataElement) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) < 2 {
+		return fmt.Errorf("malformed marshaled value")
+	}
+	c.DataElement = NewDataElementGroup(certificateDEG, 2, c)
+	c.CertificateType = &NumberDataElement{}
+	if err := c.CertificateType.UnmarshalHBCI(elements[0]); err != nil {
+		return err
+	}
+	c.Content = &BinaryDataElement{}
+	if err := c.Content.UnmarshalHBCI(elements[1]); err != nil {
+		return err
+	}
+	return nil
+}
@@ -13,6 +13,39 @@ func NewPinTanSecurityProfile(securityMethod int) *SecurityProfileDataElement {
 	return s
 }
 
+// NewRDHSecurityProfile returns a new SecurityProfile for the RDH security
+// method in the provided version
+func NewRDHSecurityProfile(securityMethodVersion int) *SecurityProfileDataElement {
+	s := &SecurityProfileDataElement{
+		SecurityMethod:        NewAlphaNumeric("RDH", 3),
+		SecurityMethodVersion: NewNumber(securityMethodVersion, 3),
+	}
+	s.DataElement = NewDataElementGroup(securityProfileDEG, 2, s)
+	return s
+}
+
+// NewSecurityProfile returns a new SecurityProfile for the provided security
+// method, i.e. "PIN", "RDH" or "RAH", and its version
+func NewSecurityProfile(securityMethod string, securityMethodVersion int) *SecurityProfileDataElement {
+	s := &SecurityProfileDataElement{
+		SecurityMethod:        NewAlphaNumeric(securityMethod, 3),
+		SecurityMethodVersion: NewNumber(securityMethodVersion, 3),
+	}
+	s.DataElement = NewDataElementGroup(securityProfileDEG, 2, s)
+	return s
+}
+
+// NewRAHSecurityProfile returns a new SecurityProfile for the RAH security
+// method in the provided version
+func NewRAHSecurityProfile(securityMethodVersion int) *SecurityProfileDataElement {
+	s := &SecurityProfileDataElement{
+		SecurityMethod:        NewAlphaNumeric("RAH", 3),
+		SecurityMethodVersion: NewNumber(securityMethodVersion, 3),
+	}
+	s.DataElement = NewDataElementGroup(securityProfileDEG, 2, s)
+	return s
+}
+
 // SecurityProfileDataElement defines a security method for the dialog flow
 type SecurityProfileDataElement struct {
 	DataElement
@@ -22,7 +55,6 @@ type SecurityProfileDataElement struct {
 
 // UnmarshalHBCI unmarshals value into the DataElement
 func (s *SecurityProfileDataElement) UnmarshalHBCI(value []byte) error {
-	s = &SecurityProfileDataElement{}
 	elements, err := ExtractElements(value)
 	if err != nil {
 		return err
@@ -30,7 +62,7 @@ func (s *SecurityProfileDataElement) UnmarshalHBCI(value []byte) error {
 	if len(elements) < 2 {
 		return fmt.Errorf("malformed marshaled value")
 	}
-	s.DataElement = NewDataElementGroup(securityProfileDEG, 5, s)
+	s.DataElement = NewDataElementGroup(securityProfileDEG, 2, s)
 	s.SecurityMethod = &AlphaNumericDataElement{}
 	err = s.SecurityMethod.UnmarshalHBCI(elements[0])
 	if err != nil {
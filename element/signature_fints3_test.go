@@ -0,0 +1,21 @@
+package element
+
+import "testing"
+
+func TestSecurityProfileDataElementUnmarshalHBCI(t *testing.T) {
+	profile := &SecurityProfileDataElement{}
+
+	err := profile.UnmarshalHBCI([]byte("RDH:10"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	if profile.SecurityMethod == nil || profile.SecurityMethod.Val() != "RDH" {
+		t.Logf("Expected security method to equal %q, got %v\n", "RDH", profile.SecurityMethod)
+		t.Fail()
+	}
+	if profile.SecurityMethodVersion == nil || profile.SecurityMethodVersion.Val() != 10 {
+		t.Logf("Expected security method version to equal 10, got %v\n", profile.SecurityMethodVersion)
+		t.Fail()
+	}
+}
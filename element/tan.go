@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/mitch000001/go-hbci/charset"
-	"github.com/mitch000001/go-hbci/internal"
 	"gopkg.in/yaml.v3"
 )
 
@@ -155,126 +154,33 @@ func (t *Tan2StepSubmissionProcessParametersV6) UnmarshalHBCI(value []byte) erro
 	return nil
 }
 
+//go:generate go run ../cmd/unmarshaler/unmarshaler_generator.go -group Tan2StepSubmissionProcessParameterV6 -group_type tan2StepSubmissionProcessParameterDEG
+
+// Tan2StepSubmissionProcessParameterV6 defines the parameters of a single
+// two step TAN process, Verfahrensparameter Zwei-Schritt-Verfahren #6
 type Tan2StepSubmissionProcessParameterV6 struct {
 	DataElement
-	SecurityFunction                       *CodeDataElement
-	TanProcess                             *CodeDataElement
-	TechnicalIDTanProcess                  *IdentificationDataElement
-	ZKATanProcess                          *AlphaNumericDataElement
-	ZKATanProcessVersion                   *AlphaNumericDataElement
-	TwoStepProcessName                     *AlphaNumericDataElement
-	TwoStepProcessMaxInputValue            *NumberDataElement
-	TwoStepProcessAllowedFormat            *CodeDataElement
-	TwoStepProcessReturnValueText          *AlphaNumericDataElement
-	TwoStepProcessReturnValueTextMaxLength *NumberDataElement
-	MultiTANAllowed                        *BooleanDataElement
-	TanTimeAndDialogReference              *CodeDataElement
-	JobCancellationAllowed                 *BooleanDataElement
-	SMSAccountRequired                     *CodeDataElement
-	IssuerAccountRequired                  *CodeDataElement
-	ChallengeClassRequired                 *BooleanDataElement
-	ChallengeStructured                    *BooleanDataElement
-	InitializationMode                     *CodeDataElement
-	TanMediumDescriptionRequired           *CodeDataElement
-	HHD_UCResponseRequired                 *BooleanDataElement
-	SupportedActiveTanMedia                *NumberDataElement
-}
-
-// Elements returns the elements of this DataElement.
-func (t *Tan2StepSubmissionProcessParameterV6) Elements() []DataElement {
-	return []DataElement{
-		t.SecurityFunction,
-		t.TanProcess,
-		t.TechnicalIDTanProcess,
-		t.ZKATanProcess,
-		t.ZKATanProcessVersion,
-		t.TwoStepProcessName,
-		t.TwoStepProcessMaxInputValue,
-		t.TwoStepProcessAllowedFormat,
-		t.TwoStepProcessReturnValueText,
-		t.TwoStepProcessReturnValueTextMaxLength,
-		t.MultiTANAllowed,
-		t.TanTimeAndDialogReference,
-		t.JobCancellationAllowed,
-		t.SMSAccountRequired,
-		t.IssuerAccountRequired,
-		t.ChallengeClassRequired,
-		t.ChallengeStructured,
-		t.InitializationMode,
-		t.TanMediumDescriptionRequired,
-		t.HHD_UCResponseRequired,
-		t.SupportedActiveTanMedia,
-	}
-}
-
-// // Val returns the underlying PinTanBusinessTransaction
-// func (t *Tan2StepSubmissionProcessParameterV6) Val() interface{} {
-// 	return domain.PinTanBusinessTransaction{
-// 	}
-// }
-
-// UnmarshalHBCI unmarshals value
-func (t *Tan2StepSubmissionProcessParameterV6) UnmarshalHBCI(value []byte) error {
-	elements, err := ExtractElements(value)
-	if err != nil {
-		return err
-	}
-	iter := internal.NewIterator(elements)
-	t.SecurityFunction = NewCode(iter.NextString(), 3, nil)
-	t.TanProcess = NewCode(iter.NextString(), 1, []string{"1", "2"})
-	t.TechnicalIDTanProcess = NewIdentification(iter.NextString())
-	t.ZKATanProcess = NewAlphaNumeric(iter.NextString(), 32)
-	t.ZKATanProcessVersion = NewAlphaNumeric(iter.NextString(), 10)
-	t.TwoStepProcessName = NewAlphaNumeric(iter.NextString(), 30)
-	var twoStepProcessMaxInputValue NumberDataElement
-	if err := twoStepProcessMaxInputValue.UnmarshalHBCI(iter.Next()); err != nil {
-		return fmt.Errorf("error unmarshaling TwoStepProcessMaxInputValue: %v", err)
-	}
-	t.TwoStepProcessMaxInputValue = &twoStepProcessMaxInputValue
-	t.TwoStepProcessAllowedFormat = NewCode(iter.NextString(), 1, nil)
-	t.TwoStepProcessReturnValueText = NewAlphaNumeric(iter.NextString(), 30)
-	var TwoStepProcessReturnValueTextMaxLength NumberDataElement
-	if err := TwoStepProcessReturnValueTextMaxLength.UnmarshalHBCI(iter.Next()); err != nil {
-		return fmt.Errorf("error unmarshaling TwoStepProcessReturnValueTextMaxLength: %v", err)
-	}
-	t.TwoStepProcessReturnValueTextMaxLength = &TwoStepProcessReturnValueTextMaxLength
-	var MultiTANAllowed BooleanDataElement
-	if err := MultiTANAllowed.UnmarshalHBCI(iter.Next()); err != nil {
-		return fmt.Errorf("error unmarshaling MultiTANAllowed: %v", err)
-	}
-	t.MultiTANAllowed = &MultiTANAllowed
-	t.TanTimeAndDialogReference = NewCode(iter.NextString(), 1, nil)
-	var JobCancellationAllowed BooleanDataElement
-	if err := JobCancellationAllowed.UnmarshalHBCI(iter.Next()); err != nil {
-		return fmt.Errorf("error unmarshaling JobCancellationAllowed: %v", err)
-	}
-	t.JobCancellationAllowed = &JobCancellationAllowed
-	t.SMSAccountRequired = NewCode(iter.NextString(), 1, []string{"0", "1", "2"})
-	t.IssuerAccountRequired = NewCode(iter.NextString(), 1, []string{"0", "2"})
-	var ChallengeClassRequired BooleanDataElement
-	if err := ChallengeClassRequired.UnmarshalHBCI(iter.Next()); err != nil {
-		return fmt.Errorf("error unmarshaling ChallengeClassRequired: %v", err)
-	}
-	t.ChallengeClassRequired = &ChallengeClassRequired
-	var ChallengeStructured BooleanDataElement
-	if err := ChallengeStructured.UnmarshalHBCI(iter.Next()); err != nil {
-		return fmt.Errorf("error unmarshaling ChallengeStructured: %v", err)
-	}
-	t.ChallengeStructured = &ChallengeStructured
-	t.InitializationMode = NewCode(iter.NextString(), -1, []string{"00", "01", "02"})
-	t.TanMediumDescriptionRequired = NewCode(iter.NextString(), 1, []string{"0", "1", "2"})
-	var HHD_UCResponseRequired BooleanDataElement
-	if err := HHD_UCResponseRequired.UnmarshalHBCI(iter.Next()); err != nil {
-		return fmt.Errorf("error unmarshaling HHD_UCResponseRequired: %v", err)
-	}
-	t.HHD_UCResponseRequired = &HHD_UCResponseRequired
-	var SupportedActiveTanMedia NumberDataElement
-	if err := SupportedActiveTanMedia.UnmarshalHBCI(iter.Next()); err != nil {
-		return fmt.Errorf("error unmarshaling SupportedActiveTanMedia: %v", err)
-	}
-	t.SupportedActiveTanMedia = &SupportedActiveTanMedia
-	t.DataElement = NewGroupDataElementGroup(pinTanBusinessTransactionParameterGDEG, 2, t)
-	return nil
+	SecurityFunction                       *CodeDataElement           `hbci:"code,3,codes=900-997"`
+	TanProcess                             *CodeDataElement           `hbci:"code,1,codes=1|2"`
+	TechnicalIDTanProcess                  *IdentificationDataElement `hbci:"id,35"`
+	ZKATanProcess                          *AlphaNumericDataElement   `hbci:"an,32,optional"`
+	ZKATanProcessVersion                   *AlphaNumericDataElement   `hbci:"an,10,optional"`
+	TwoStepProcessName                     *AlphaNumericDataElement   `hbci:"an,30"`
+	TwoStepProcessMaxInputValue            *NumberDataElement         `hbci:"num,2"`
+	TwoStepProcessAllowedFormat            *CodeDataElement           `hbci:"code,1,codes=1|2"`
+	TwoStepProcessReturnValueText          *AlphaNumericDataElement   `hbci:"an,30"`
+	TwoStepProcessReturnValueTextMaxLength *NumberDataElement         `hbci:"num,3"`
+	MultiTANAllowed                        *BooleanDataElement        `hbci:"jn"`
+	TanTimeAndDialogReference              *CodeDataElement           `hbci:"code,1,codes=1-4"`
+	JobCancellationAllowed                 *BooleanDataElement        `hbci:"jn"`
+	SMSAccountRequired                     *CodeDataElement           `hbci:"code,1,codes=0-2"`
+	IssuerAccountRequired                  *CodeDataElement           `hbci:"code,1,codes=0|2"`
+	ChallengeClassRequired                 *BooleanDataElement        `hbci:"jn"`
+	ChallengeStructured                    *BooleanDataElement        `hbci:"jn"`
+	InitializationMode                     *CodeDataElement           `hbci:"code,2,codes=00-02"`
+	TanMediumDescriptionRequired           *CodeDataElement           `hbci:"code,1,codes=0-2"`
+	HHD_UCResponseRequired                 *BooleanDataElement        `hbci:"jn"`
+	SupportedActiveTanMedia                *NumberDataElement         `hbci:"num,1,optional"`
 }
 
 func (t Tan2StepSubmissionProcessParameterV6) MarshalYAML() (interface{}, error) {
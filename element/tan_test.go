@@ -1,6 +1,19 @@
 package element
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
+
+// tan2StepSubmissionParameterV6Fixture contains the TAN processes of a savings
+// bank BPD
+const tan2StepSubmissionParameterV6Fixture = "J:N:0:910:2:HHD1.3.0:::chipTAN manuell:6:1:TAN-Nummer:3:J:2:N:0:0:N:N:00:0:N:1:" +
+	"911:2:HHD1.3.2OPT:HHDOPT1:1.3.2:chipTAN optisch:6:1:TAN-Nummer:3:J:2:N:0:0:N:N:00:0:N:1:" +
+	"912:2:HHD1.3.2USB:HHDUSB1:1.3.2:chipTAN-USB:6:1:TAN-Nummer:3:J:2:N:0:0:N:N:00:0:N:1:" +
+	"913:2:Q1S:Secoder_UC:1.2.0:chipTAN-QR:6:1:TAN-Nummer:3:J:2:N:0:0:N:N:00:0:N:1:" +
+	"920:2:smsTAN:::smsTAN:6:1:TAN-Nummer:3:J:2:N:0:0:N:N:00:2:N:5:" +
+	"921:2:pushTAN:::pushTAN:6:1:TAN-Nummer:3:J:2:N:0:0:N:N:00:2:N:2:" +
+	"900:2:iTAN:::iTAN:6:1:TAN-Nummer:3:J:2:N:0:0:N:N:00:0:N:0"
 
 func TestTan2StepSubmissionParameterV6_UnmarshalHBCI(t *testing.T) {
 	type fields struct {
@@ -19,15 +32,7 @@ func TestTan2StepSubmissionParameterV6_UnmarshalHBCI(t *testing.T) {
 		{
 			name:   "valid params",
 			fields: fields{},
-			value: []byte(
-				"J:N:0:910:2:HHD1.3.0:::chipTAN manuell:6:1:TAN-Nummer:3:J:2:N:0:0:N:N:00:0:N:1:" +
-					"911:2:HHD1.3.2OPT:HHDOPT1:1.3.2:chipTAN optisch:6:1:TAN-Nummer:3:J:2:N:0:0:N:N:00:0:N:1:" +
-					"912:2:HHD1.3.2USB:HHDUSB1:1.3.2:chipTAN-USB:6:1:TAN-Nummer:3:J:2:N:0:0:N:N:00:0:N:1:" +
-					"913:2:Q1S:Secoder_UC:1.2.0:chipTAN-QR:6:1:TAN-Nummer:3:J:2:N:0:0:N:N:00:0:N:1:" +
-					"920:2:smsTAN:::smsTAN:6:1:TAN-Nummer:3:J:2:N:0:0:N:N:00:2:N:5:" +
-					"921:2:pushTAN:::pushTAN:6:1:TAN-Nummer:3:J:2:N:0:0:N:N:00:2:N:2:" +
-					"900:2:iTAN:::iTAN:6:1:TAN-Nummer:3:J:2:N:0:0:N:N:00:0:N:0",
-			),
+			value:  []byte(tan2StepSubmissionParameterV6Fixture),
 		},
 	}
 	for _, tt := range tests {
@@ -45,3 +50,60 @@ func TestTan2StepSubmissionParameterV6_UnmarshalHBCI(t *testing.T) {
 		})
 	}
 }
+
+func TestTan2StepSubmissionParameterV6RoundTrip(t *testing.T) {
+	tests := []string{
+		tan2StepSubmissionParameterV6Fixture,
+		// the BPD of the fintstest server
+		"J:N:0:942:2:mobileTAN:::fintstest mobileTAN:6:1:TAN:99:N:1:N:0:0:N:N:00:0:N:1:" +
+			"946:2:Decoupled:::fintstest App:0:1:Freigabe:99:N:1:N:0:0:N:N:00:0:N:1",
+	}
+	for _, test := range tests {
+		param := &Tan2StepSubmissionParameterV6{}
+
+		err := param.UnmarshalHBCI([]byte(test))
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+		for _, elem := range param.ProcessParameters.array {
+			if err := elem.(*Tan2StepSubmissionProcessParameterV6).Validate(); err != nil {
+				t.Logf("Expected process parameter to be valid, got %T:%v\n", err, err)
+				t.Fail()
+			}
+		}
+		marshaled, err := param.MarshalHBCI()
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+		if string(marshaled) != test {
+			t.Logf("Expected marshaled value to equal\n%q\n\tgot\n%q\n", test, marshaled)
+			t.Fail()
+		}
+	}
+}
+
+func TestTan2StepSubmissionProcessParameterV6Validate(t *testing.T) {
+	tests := []struct {
+		value       string
+		expectedErr string
+	}{
+		{"899:2:iTAN:::iTAN:6:1:TAN-Nummer:3:J:2:N:0:0:N:N:00:0:N:0", "SecurityFunction is invalid"},
+		{"900:3:iTAN:::iTAN:6:1:TAN-Nummer:3:J:2:N:0:0:N:N:00:0:N:0", "TanProcess is invalid"},
+		{"900:2:iTAN:::iTAN:6:1:TAN-Nummer:3:J:2:N:0:1:N:N:00:0:N:0", "IssuerAccountRequired is invalid"},
+		{"900:2:iTAN:::iTAN:6:1:TAN-Nummer:3:J:2:N:0:0:N:N:03:0:N:0", "InitializationMode is invalid"},
+	}
+	for _, test := range tests {
+		param := &Tan2StepSubmissionProcessParameterV6{}
+		if err := param.UnmarshalHBCI([]byte(test.value)); err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+
+		err := param.Validate()
+
+		if err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+			t.Logf("%s: Expected error containing %q, got %v\n", test.value, test.expectedErr, err)
+			t.Fail()
+		}
+	}
+}
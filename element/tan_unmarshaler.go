@@ -0,0 +1,401 @@
+// Code generated by *generator.GroupDataElementGroupGenerator; DO NOT EDIT.
+
+package element
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Elements returns the elements of this DataElement.
+func (t *Tan2StepSubmissionProcessParameterV6) Elements() []DataElement {
+	return []DataElement{
+		t.SecurityFunction,
+		t.TanProcess,
+		t.TechnicalIDTanProcess,
+		t.ZKATanProcess,
+		t.ZKATanProcessVersion,
+		t.TwoStepProcessName,
+		t.TwoStepProcessMaxInputValue,
+		t.TwoStepProcessAllowedFormat,
+		t.TwoStepProcessReturnValueText,
+		t.TwoStepProcessReturnValueTextMaxLength,
+		t.MultiTANAllowed,
+		t.TanTimeAndDialogReference,
+		t.JobCancellationAllowed,
+		t.SMSAccountRequired,
+		t.IssuerAccountRequired,
+		t.ChallengeClassRequired,
+		t.ChallengeStructured,
+		t.InitializationMode,
+		t.TanMediumDescriptionRequired,
+		t.HHD_UCResponseRequired,
+		t.SupportedActiveTanMedia,
+	}
+}
+
+// UnmarshalHBCI unmarshals value into t
+func (t *Tan2StepSubmissionProcessParameterV6) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) > 0 && len(elements[0]) > 0 {
+		t.SecurityFunction = NewCode("", 3, []string{"900", "901", "902", "903", "904", "905", "906", "907", "908", "909", "910", "911", "912", "913", "914", "915", "916", "917", "918", "919", "920", "921", "922", "923", "924", "925", "926", "927", "928", "929", "930", "931", "932", "933", "934", "935", "936", "937", "938", "939", "940", "941", "942", "943", "944", "945", "946", "947", "948", "949", "950", "951", "952", "953", "954", "955", "956", "957", "958", "959", "960", "961", "962", "963", "964", "965", "966", "967", "968", "969", "970", "971", "972", "973", "974", "975", "976", "977", "978", "979", "980", "981", "982", "983", "984", "985", "986", "987", "988", "989", "990", "991", "992", "993", "994", "995", "996", "997"})
+		err = t.SecurityFunction.UnmarshalHBCI(elements[0])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling SecurityFunction: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element SecurityFunction")
+	}
+	if len(elements) > 1 && len(elements[1]) > 0 {
+		t.TanProcess = NewCode("", 1, []string{"1", "2"})
+		err = t.TanProcess.UnmarshalHBCI(elements[1])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling TanProcess: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element TanProcess")
+	}
+	if len(elements) > 2 && len(elements[2]) > 0 {
+		t.TechnicalIDTanProcess = &IdentificationDataElement{}
+		err = t.TechnicalIDTanProcess.UnmarshalHBCI(elements[2])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling TechnicalIDTanProcess: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element TechnicalIDTanProcess")
+	}
+	t.ZKATanProcess = nil
+	if len(elements) > 3 && len(elements[3]) > 0 {
+		t.ZKATanProcess = &AlphaNumericDataElement{}
+		err = t.ZKATanProcess.UnmarshalHBCI(elements[3])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling ZKATanProcess: %w", err)
+		}
+	}
+	t.ZKATanProcessVersion = nil
+	if len(elements) > 4 && len(elements[4]) > 0 {
+		t.ZKATanProcessVersion = &AlphaNumericDataElement{}
+		err = t.ZKATanProcessVersion.UnmarshalHBCI(elements[4])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling ZKATanProcessVersion: %w", err)
+		}
+	}
+	if len(elements) > 5 && len(elements[5]) > 0 {
+		t.TwoStepProcessName = &AlphaNumericDataElement{}
+		err = t.TwoStepProcessName.UnmarshalHBCI(elements[5])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling TwoStepProcessName: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element TwoStepProcessName")
+	}
+	if len(elements) > 6 && len(elements[6]) > 0 {
+		t.TwoStepProcessMaxInputValue = &NumberDataElement{}
+		err = t.TwoStepProcessMaxInputValue.UnmarshalHBCI(elements[6])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling TwoStepProcessMaxInputValue: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element TwoStepProcessMaxInputValue")
+	}
+	if len(elements) > 7 && len(elements[7]) > 0 {
+		t.TwoStepProcessAllowedFormat = NewCode("", 1, []string{"1", "2"})
+		err = t.TwoStepProcessAllowedFormat.UnmarshalHBCI(elements[7])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling TwoStepProcessAllowedFormat: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element TwoStepProcessAllowedFormat")
+	}
+	if len(elements) > 8 && len(elements[8]) > 0 {
+		t.TwoStepProcessReturnValueText = &AlphaNumericDataElement{}
+		err = t.TwoStepProcessReturnValueText.UnmarshalHBCI(elements[8])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling TwoStepProcessReturnValueText: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element TwoStepProcessReturnValueText")
+	}
+	if len(elements) > 9 && len(elements[9]) > 0 {
+		t.TwoStepProcessReturnValueTextMaxLength = &NumberDataElement{}
+		err = t.TwoStepProcessReturnValueTextMaxLength.UnmarshalHBCI(elements[9])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling TwoStepProcessReturnValueTextMaxLength: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element TwoStepProcessReturnValueTextMaxLength")
+	}
+	if len(elements) > 10 && len(elements[10]) > 0 {
+		t.MultiTANAllowed = &BooleanDataElement{}
+		err = t.MultiTANAllowed.UnmarshalHBCI(elements[10])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling MultiTANAllowed: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element MultiTANAllowed")
+	}
+	if len(elements) > 11 && len(elements[11]) > 0 {
+		t.TanTimeAndDialogReference = NewCode("", 1, []string{"1", "2", "3", "4"})
+		err = t.TanTimeAndDialogReference.UnmarshalHBCI(elements[11])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling TanTimeAndDialogReference: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element TanTimeAndDialogReference")
+	}
+	if len(elements) > 12 && len(elements[12]) > 0 {
+		t.JobCancellationAllowed = &BooleanDataElement{}
+		err = t.JobCancellationAllowed.UnmarshalHBCI(elements[12])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling JobCancellationAllowed: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element JobCancellationAllowed")
+	}
+	if len(elements) > 13 && len(elements[13]) > 0 {
+		t.SMSAccountRequired = NewCode("", 1, []string{"0", "1", "2"})
+		err = t.SMSAccountRequired.UnmarshalHBCI(elements[13])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling SMSAccountRequired: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element SMSAccountRequired")
+	}
+	if len(elements) > 14 && len(elements[14]) > 0 {
+		t.IssuerAccountRequired = NewCode("", 1, []string{"0", "2"})
+		err = t.IssuerAccountRequired.UnmarshalHBCI(elements[14])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling IssuerAccountRequired: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element IssuerAccountRequired")
+	}
+	if len(elements) > 15 && len(elements[15]) > 0 {
+		t.ChallengeClassRequired = &BooleanDataElement{}
+		err = t.ChallengeClassRequired.UnmarshalHBCI(elements[15])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling ChallengeClassRequired: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element ChallengeClassRequired")
+	}
+	if len(elements) > 16 && len(elements[16]) > 0 {
+		t.ChallengeStructured = &BooleanDataElement{}
+		err = t.ChallengeStructured.UnmarshalHBCI(elements[16])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling ChallengeStructured: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element ChallengeStructured")
+	}
+	if len(elements) > 17 && len(elements[17]) > 0 {
+		t.InitializationMode = NewCode("", 2, []string{"00", "01", "02"})
+		err = t.InitializationMode.UnmarshalHBCI(elements[17])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling InitializationMode: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element InitializationMode")
+	}
+	if len(elements) > 18 && len(elements[18]) > 0 {
+		t.TanMediumDescriptionRequired = NewCode("", 1, []string{"0", "1", "2"})
+		err = t.TanMediumDescriptionRequired.UnmarshalHBCI(elements[18])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling TanMediumDescriptionRequired: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element TanMediumDescriptionRequired")
+	}
+	if len(elements) > 19 && len(elements[19]) > 0 {
+		t.HHD_UCResponseRequired = &BooleanDataElement{}
+		err = t.HHD_UCResponseRequired.UnmarshalHBCI(elements[19])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling HHD_UCResponseRequired: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element HHD_UCResponseRequired")
+	}
+	t.SupportedActiveTanMedia = nil
+	if len(elements) > 20 && len(elements[20]) > 0 {
+		t.SupportedActiveTanMedia = &NumberDataElement{}
+		err = t.SupportedActiveTanMedia.UnmarshalHBCI(bytes.Join(elements[20:], []byte(":")))
+		if err != nil {
+			return fmt.Errorf("error unmarshaling SupportedActiveTanMedia: %w", err)
+		}
+	}
+	t.DataElement = NewGroupDataElementGroup(tan2StepSubmissionProcessParameterDEG, 21, t)
+	return nil
+}
+
+// Validate validates the elements of t against their definition
+func (t *Tan2StepSubmissionProcessParameterV6) Validate() error {
+	if t.SecurityFunction == nil {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: missing required element SecurityFunction")
+	}
+	if t.SecurityFunction.Length() > 3 {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: SecurityFunction exceeds the maximum length of 3")
+	}
+	if !t.SecurityFunction.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: SecurityFunction is invalid")
+	}
+	if t.TanProcess == nil {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: missing required element TanProcess")
+	}
+	if t.TanProcess.Length() > 1 {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: TanProcess exceeds the maximum length of 1")
+	}
+	if !t.TanProcess.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: TanProcess is invalid")
+	}
+	if t.TechnicalIDTanProcess == nil {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: missing required element TechnicalIDTanProcess")
+	}
+	if t.TechnicalIDTanProcess.Length() > 35 {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: TechnicalIDTanProcess exceeds the maximum length of 35")
+	}
+	if !t.TechnicalIDTanProcess.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: TechnicalIDTanProcess is invalid")
+	}
+	if t.ZKATanProcess != nil && t.ZKATanProcess.Length() > 32 {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: ZKATanProcess exceeds the maximum length of 32")
+	}
+	if t.ZKATanProcess != nil && !t.ZKATanProcess.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: ZKATanProcess is invalid")
+	}
+	if t.ZKATanProcessVersion != nil && t.ZKATanProcessVersion.Length() > 10 {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: ZKATanProcessVersion exceeds the maximum length of 10")
+	}
+	if t.ZKATanProcessVersion != nil && !t.ZKATanProcessVersion.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: ZKATanProcessVersion is invalid")
+	}
+	if t.TwoStepProcessName == nil {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: missing required element TwoStepProcessName")
+	}
+	if t.TwoStepProcessName.Length() > 30 {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: TwoStepProcessName exceeds the maximum length of 30")
+	}
+	if !t.TwoStepProcessName.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: TwoStepProcessName is invalid")
+	}
+	if t.TwoStepProcessMaxInputValue == nil {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: missing required element TwoStepProcessMaxInputValue")
+	}
+	if t.TwoStepProcessMaxInputValue.Length() > 2 {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: TwoStepProcessMaxInputValue exceeds the maximum length of 2")
+	}
+	if !t.TwoStepProcessMaxInputValue.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: TwoStepProcessMaxInputValue is invalid")
+	}
+	if t.TwoStepProcessAllowedFormat == nil {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: missing required element TwoStepProcessAllowedFormat")
+	}
+	if t.TwoStepProcessAllowedFormat.Length() > 1 {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: TwoStepProcessAllowedFormat exceeds the maximum length of 1")
+	}
+	if !t.TwoStepProcessAllowedFormat.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: TwoStepProcessAllowedFormat is invalid")
+	}
+	if t.TwoStepProcessReturnValueText == nil {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: missing required element TwoStepProcessReturnValueText")
+	}
+	if t.TwoStepProcessReturnValueText.Length() > 30 {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: TwoStepProcessReturnValueText exceeds the maximum length of 30")
+	}
+	if !t.TwoStepProcessReturnValueText.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: TwoStepProcessReturnValueText is invalid")
+	}
+	if t.TwoStepProcessReturnValueTextMaxLength == nil {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: missing required element TwoStepProcessReturnValueTextMaxLength")
+	}
+	if t.TwoStepProcessReturnValueTextMaxLength.Length() > 3 {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: TwoStepProcessReturnValueTextMaxLength exceeds the maximum length of 3")
+	}
+	if !t.TwoStepProcessReturnValueTextMaxLength.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: TwoStepProcessReturnValueTextMaxLength is invalid")
+	}
+	if t.MultiTANAllowed == nil {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: missing required element MultiTANAllowed")
+	}
+	if !t.MultiTANAllowed.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: MultiTANAllowed is invalid")
+	}
+	if t.TanTimeAndDialogReference == nil {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: missing required element TanTimeAndDialogReference")
+	}
+	if t.TanTimeAndDialogReference.Length() > 1 {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: TanTimeAndDialogReference exceeds the maximum length of 1")
+	}
+	if !t.TanTimeAndDialogReference.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: TanTimeAndDialogReference is invalid")
+	}
+	if t.JobCancellationAllowed == nil {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: missing required element JobCancellationAllowed")
+	}
+	if !t.JobCancellationAllowed.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: JobCancellationAllowed is invalid")
+	}
+	if t.SMSAccountRequired == nil {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: missing required element SMSAccountRequired")
+	}
+	if t.SMSAccountRequired.Length() > 1 {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: SMSAccountRequired exceeds the maximum length of 1")
+	}
+	if !t.SMSAccountRequired.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: SMSAccountRequired is invalid")
+	}
+	if t.IssuerAccountRequired == nil {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: missing required element IssuerAccountRequired")
+	}
+	if t.IssuerAccountRequired.Length() > 1 {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: IssuerAccountRequired exceeds the maximum length of 1")
+	}
+	if !t.IssuerAccountRequired.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: IssuerAccountRequired is invalid")
+	}
+	if t.ChallengeClassRequired == nil {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: missing required element ChallengeClassRequired")
+	}
+	if !t.ChallengeClassRequired.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: ChallengeClassRequired is invalid")
+	}
+	if t.ChallengeStructured == nil {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: missing required element ChallengeStructured")
+	}
+	if !t.ChallengeStructured.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: ChallengeStructured is invalid")
+	}
+	if t.InitializationMode == nil {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: missing required element InitializationMode")
+	}
+	if t.InitializationMode.Length() > 2 {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: InitializationMode exceeds the maximum length of 2")
+	}
+	if !t.InitializationMode.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: InitializationMode is invalid")
+	}
+	if t.TanMediumDescriptionRequired == nil {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: missing required element TanMediumDescriptionRequired")
+	}
+	if t.TanMediumDescriptionRequired.Length() > 1 {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: TanMediumDescriptionRequired exceeds the maximum length of 1")
+	}
+	if !t.TanMediumDescriptionRequired.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: TanMediumDescriptionRequired is invalid")
+	}
+	if t.HHD_UCResponseRequired == nil {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: missing required element HHD_UCResponseRequired")
+	}
+	if !t.HHD_UCResponseRequired.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: HHD_UCResponseRequired is invalid")
+	}
+	if t.SupportedActiveTanMedia != nil && t.SupportedActiveTanMedia.Length() > 1 {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: SupportedActiveTanMedia exceeds the maximum length of 1")
+	}
+	if t.SupportedActiveTanMedia != nil && !t.SupportedActiveTanMedia.IsValid() {
+		return fmt.Errorf("Tan2StepSubmissionProcessParameterV6: SupportedActiveTanMedia is invalid")
+	}
+	return nil
+}
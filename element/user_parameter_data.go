@@ -8,10 +8,10 @@ import (
 )
 
 // NewAccountLimit creates a new Account limit
-func NewAccountLimit(kind string, amount float64, currency string, days int) *AccountLimitDataElement {
+func NewAccountLimit(kind string, amount domain.Amount, days int) *AccountLimitDataElement {
 	a := &AccountLimitDataElement{
 		Kind:   NewAlphaNumeric(kind, 1),
-		Amount: NewAmount(amount, currency),
+		Amount: NewAmount(amount),
 		Days:   NewNumber(days, 3),
 	}
 	a.DataElement = NewDataElementGroup(accountLimitDEG, 3, a)
@@ -105,7 +105,7 @@ func NewAllowedBusinessTransaction(businessTransaction domain.BusinessTransactio
 	}
 	if businessTransaction.Limit != nil {
 		a.Kind = NewAlphaNumeric(businessTransaction.Limit.Kind, 1)
-		a.Amount = NewAmount(businessTransaction.Limit.Amount.Amount, businessTransaction.Limit.Amount.Currency)
+		a.Amount = NewAmount(businessTransaction.Limit.Amount)
 		a.Days = NewNumber(businessTransaction.Limit.Days, 3)
 	}
 	a.DataElement = NewDataElementGroup(allowedBusinessTransactionDEG, 5, a)
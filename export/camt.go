@@ -0,0 +1,332 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+const (
+	camt053Namespace      = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+	camtDateTimeLayout    = "2006-01-02T15:04:05"
+	camtDateLayout        = "2006-01-02"
+	camtBankTransactionDK = "DK"
+)
+
+// Camt053 exports transactions as ISO 20022 camt.053 bank to customer
+// statement, version 001.02 as used by the german banks. Each account
+// statement, as returned by domain.AccountTransactions.Statements, becomes one
+// Stmt element with its opening and closing balance.
+type Camt053 struct {
+	// Now returns the time of the export. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Export writes transactions as camt.053 document to w
+func (c *Camt053) Export(w io.Writer, transactions domain.AccountTransactions) error {
+	now := time.Now
+	if c.Now != nil {
+		now = c.Now
+	}
+	created := now().Format(camtDateTimeLayout)
+	document := camtDocument{
+		Namespace: camt053Namespace,
+		Header: camtGroupHeader{
+			MessageID: "go-hbci-" + now().Format("20060102150405"),
+			Created:   created,
+		},
+	}
+	ids := TransactionIDs(transactions)
+	var index int
+	for i, statement := range transactions.Statements() {
+		first, last := statement[0], statement[len(statement)-1]
+		currency := first.Amount.Currency
+		stmt := camtStatement{
+			ID:             fmt.Sprintf("%s-%d", first.Account.AccountID, i+1),
+			SequenceNumber: first.StatementNumber,
+			Created:        created,
+			Account:        newCamtAccount(first.Account, currency),
+			Balances: []camtBalance{
+				newCamtBalance("OPBD", first.AccountBalanceBefore, first.BookingDate, currency),
+				newCamtBalance("CLBD", last.AccountBalanceAfter, last.BookingDate, currency),
+			},
+		}
+		for _, tx := range statement {
+			stmt.Entries = append(stmt.Entries, newCamtEntry(ids[index], tx, currency))
+			index++
+		}
+		document.Statements = append(document.Statements, stmt)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("error writing camt.053 header: %w", err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("error encoding camt.053: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func newCamtAccount(account domain.AccountConnection, currency string) camtAccount {
+	camtAcct := camtAccount{Currency: currency}
+	if looksLikeIBAN(account.AccountID) {
+		camtAcct.ID.IBAN = account.AccountID
+	} else {
+		camtAcct.ID.Other = &camtOtherID{ID: account.AccountID}
+	}
+	if account.BankID != "" {
+		camtAcct.Servicer = &camtFinancialInstitution{
+			ClearingSystemMember: &camtClearingSystemMember{
+				ClearingSystem: "DEBLZ",
+				MemberID:       account.BankID,
+			},
+		}
+	}
+	return camtAcct
+}
+
+func newCamtBalance(code string, balance domain.Balance, bookingDate time.Time, currency string) camtBalance {
+	date := balance.TransmissionDate
+	if date.IsZero() {
+		date = bookingDate
+	}
+	return camtBalance{
+		Type:        code,
+		Amount:      newCamtAmount(balance.Amount.Abs(), currency),
+		CreditDebit: camtCreditDebit(balance.Amount),
+		Date:        date.Format(camtDateLayout),
+	}
+}
+
+func newCamtEntry(id string, tx domain.AccountTransaction, currency string) camtEntry {
+	bankTransactionCode := fmt.Sprintf("NMSC+%03d", tx.TransactionID)
+	if tx.MessageKeyAddition != 0 {
+		bankTransactionCode += fmt.Sprintf("+%03d", tx.MessageKeyAddition)
+	}
+	details := camtTransactionDetails{
+		References: camtReferences{
+			AccountServicerReference: tx.BankReference,
+			EndToEndID:               tx.EndToEndReference,
+			MandateID:                tx.MandateReference,
+		},
+		AdditionalInformation: tx.BookingText,
+	}
+	if remittanceInformation := description(tx); remittanceInformation != "" {
+		details.RemittanceInformation = &camtRemittanceInformation{Unstructured: remittanceInformation}
+	}
+	var counterparty *camtParty
+	if tx.Name != "" {
+		counterparty = &camtParty{Name: singleLine(tx.Name)}
+	}
+	var counterpartyAccount *camtAccountID
+	if tx.CounterpartyIBAN != "" {
+		counterpartyAccount = &camtAccountID{IBAN: tx.CounterpartyIBAN}
+	} else if tx.AccountID != "" {
+		counterpartyAccount = &camtAccountID{Other: &camtOtherID{ID: tx.AccountID}}
+	}
+	var counterpartyAgent *camtAgent
+	if tx.CounterpartyBIC != "" {
+		counterpartyAgent = &camtAgent{FinancialInstitution: camtFinancialInstitution{BIC: tx.CounterpartyBIC}}
+	}
+	parties := camtRelatedParties{}
+	agents := camtRelatedAgents{}
+	if tx.Amount.Sign() < 0 {
+		parties.Creditor, parties.CreditorAccount = counterparty, counterpartyAccount
+		agents.CreditorAgent = counterpartyAgent
+	} else {
+		parties.Debtor, parties.DebtorAccount = counterparty, counterpartyAccount
+		agents.DebtorAgent = counterpartyAgent
+	}
+	if tx.UltimateDebtor != "" {
+		parties.UltimateDebtor = &camtParty{Name: tx.UltimateDebtor}
+	}
+	if tx.UltimateCreditor != "" {
+		parties.UltimateCreditor = &camtParty{Name: tx.UltimateCreditor}
+	}
+	if tx.CreditorID != "" {
+		if parties.Creditor == nil {
+			parties.Creditor = &camtParty{}
+		}
+		parties.Creditor.ID = &camtPartyID{PrivateID: camtPrivateID{Other: camtSchemeID{ID: tx.CreditorID, Scheme: "SEPA"}}}
+	}
+	if parties != (camtRelatedParties{}) {
+		details.RelatedParties = &parties
+	}
+	if agents.DebtorAgent != nil || agents.CreditorAgent != nil {
+		details.RelatedAgents = &agents
+	}
+	return camtEntry{
+		Reference:                id,
+		Amount:                   newCamtAmount(tx.Amount.Abs(), currency),
+		CreditDebit:              camtCreditDebit(tx.Amount),
+		Status:                   "BOOK",
+		BookingDate:              camtDate{Date: tx.BookingDate.Format(camtDateLayout)},
+		ValutaDate:               camtDate{Date: valutaDate(tx).Format(camtDateLayout)},
+		AccountServicerReference: tx.BankReference,
+		BankTransactionCode:      camtBankTransactionCode{Code: bankTransactionCode, Issuer: camtBankTransactionDK},
+		Details:                  camtEntryDetails{Transaction: details},
+	}
+}
+
+func newCamtAmount(amount domain.Amount, currency string) camtAmount {
+	return camtAmount{Currency: currency, Value: amount.Decimal()}
+}
+
+func camtCreditDebit(amount domain.Amount) string {
+	if amount.Sign() < 0 {
+		return "DBIT"
+	}
+	return "CRDT"
+}
+
+// looksLikeIBAN reports whether value starts with a country code, in
+// contrast to a national account ID
+func looksLikeIBAN(value string) bool {
+	return len(value) > 4 && isUpperLetter(value[0]) && isUpperLetter(value[1])
+}
+
+func isUpperLetter(b byte) bool {
+	return b >= 'A' && b <= 'Z'
+}
+
+type camtDocument struct {
+	XMLName    xml.Name        `xml:"Document"`
+	Namespace  string          `xml:"xmlns,attr"`
+	Header     camtGroupHeader `xml:"BkToCstmrStmt>GrpHdr"`
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtGroupHeader struct {
+	MessageID string `xml:"MsgId"`
+	Created   string `xml:"CreDtTm"`
+}
+
+type camtStatement struct {
+	ID             string        `xml:"Id"`
+	SequenceNumber int           `xml:"ElctrncSeqNb,omitempty"`
+	Created        string        `xml:"CreDtTm"`
+	Account        camtAccount   `xml:"Acct"`
+	Balances       []camtBalance `xml:"Bal"`
+	Entries        []camtEntry   `xml:"Ntry"`
+}
+
+type camtAccount struct {
+	ID       camtAccountID             `xml:"Id"`
+	Currency string                    `xml:"Ccy,omitempty"`
+	Servicer *camtFinancialInstitution `xml:"Svcr>FinInstnId,omitempty"`
+}
+
+type camtAccountID struct {
+	IBAN  string       `xml:"IBAN,omitempty"`
+	Other *camtOtherID `xml:"Othr,omitempty"`
+}
+
+type camtOtherID struct {
+	ID string `xml:"Id"`
+}
+
+type camtFinancialInstitution struct {
+	BIC                  string                    `xml:"BIC,omitempty"`
+	ClearingSystemMember *camtClearingSystemMember `xml:"ClrSysMmbId,omitempty"`
+}
+
+type camtClearingSystemMember struct {
+	ClearingSystem string `xml:"ClrSysId>Cd"`
+	MemberID       string `xml:"MmbId"`
+}
+
+type camtBalance struct {
+	Type        string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amount      camtAmount `xml:"Amt"`
+	CreditDebit string     `xml:"CdtDbtInd"`
+	Date        string     `xml:"Dt>Dt"`
+}
+
+type camtAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type camtDate struct {
+	Date string `xml:"Dt"`
+}
+
+type camtEntry struct {
+	Reference                string                  `xml:"NtryRef"`
+	Amount                   camtAmount              `xml:"Amt"`
+	CreditDebit              string                  `xml:"CdtDbtInd"`
+	Status                   string                  `xml:"Sts"`
+	BookingDate              camtDate                `xml:"BookgDt"`
+	ValutaDate               camtDate                `xml:"ValDt"`
+	AccountServicerReference string                  `xml:"AcctSvcrRef,omitempty"`
+	BankTransactionCode      camtBankTransactionCode `xml:"BkTxCd>Prtry"`
+	Details                  camtEntryDetails        `xml:"NtryDtls"`
+}
+
+type camtBankTransactionCode struct {
+	Code   string `xml:"Cd"`
+	Issuer string `xml:"Issr"`
+}
+
+type camtEntryDetails struct {
+	Transaction camtTransactionDetails `xml:"TxDtls"`
+}
+
+type camtTransactionDetails struct {
+	References            camtReferences             `xml:"Refs"`
+	RelatedParties        *camtRelatedParties        `xml:"RltdPties,omitempty"`
+	RelatedAgents         *camtRelatedAgents         `xml:"RltdAgts,omitempty"`
+	RemittanceInformation *camtRemittanceInformation `xml:"RmtInf,omitempty"`
+	AdditionalInformation string                     `xml:"AddtlTxInf,omitempty"`
+}
+
+type camtReferences struct {
+	AccountServicerReference string `xml:"AcctSvcrRef,omitempty"`
+	EndToEndID               string `xml:"EndToEndId,omitempty"`
+	MandateID                string `xml:"MndtId,omitempty"`
+}
+
+type camtRelatedParties struct {
+	Debtor           *camtParty     `xml:"Dbtr,omitempty"`
+	DebtorAccount    *camtAccountID `xml:"DbtrAcct>Id,omitempty"`
+	UltimateDebtor   *camtParty     `xml:"UltmtDbtr,omitempty"`
+	Creditor         *camtParty     `xml:"Cdtr,omitempty"`
+	CreditorAccount  *camtAccountID `xml:"CdtrAcct>Id,omitempty"`
+	UltimateCreditor *camtParty     `xml:"UltmtCdtr,omitempty"`
+}
+
+type camtParty struct {
+	Name string       `xml:"Nm,omitempty"`
+	ID   *camtPartyID `xml:"Id,omitempty"`
+}
+
+type camtPartyID struct {
+	PrivateID camtPrivateID `xml:"PrvtId"`
+}
+
+type camtPrivateID struct {
+	Other camtSchemeID `xml:"Othr"`
+}
+
+type camtSchemeID struct {
+	ID     string `xml:"Id"`
+	Scheme string `xml:"SchmeNm>Prtry"`
+}
+
+type camtRelatedAgents struct {
+	DebtorAgent   *camtAgent `xml:"DbtrAgt,omitempty"`
+	CreditorAgent *camtAgent `xml:"CdtrAgt,omitempty"`
+}
+
+type camtAgent struct {
+	FinancialInstitution camtFinancialInstitution `xml:"FinInstnId"`
+}
+
+type camtRemittanceInformation struct {
+	Unstructured string `xml:"Ustrd"`
+}
@@ -0,0 +1,76 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+func TestCamt053Export(t *testing.T) {
+	transactions := testTransactions()
+	ids := TransactionIDs(transactions)
+	exporter := &Camt053{Now: func() time.Time { return time.Date(2021, 3, 3, 12, 0, 0, 0, time.UTC) }}
+	var buf bytes.Buffer
+
+	err := exporter.Export(&buf, transactions)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	var document camtDocument
+	if err := xml.Unmarshal(buf.Bytes(), &document); err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if document.Namespace != camt053Namespace {
+		t.Logf("Expected namespace %q, got %q\n", camt053Namespace, document.Namespace)
+		t.Fail()
+	}
+	if len(document.Statements) != 1 {
+		t.Fatalf("Expected one statement, got %d\n", len(document.Statements))
+	}
+	statement := document.Statements[0]
+	expectedBalances := []camtBalance{
+		{Type: "OPBD", Amount: camtAmount{Currency: "EUR", Value: "1234.56"}, CreditDebit: "CRDT", Date: "2021-03-01"},
+		{Type: "CLBD", Amount: camtAmount{Currency: "EUR", Value: "150.00"}, CreditDebit: "DBIT", Date: "2021-03-02"},
+	}
+	for i, balance := range expectedBalances {
+		if i >= len(statement.Balances) || statement.Balances[i] != balance {
+			t.Logf("Expected balances to equal\n%+v\n\tgot\n%+v\n", expectedBalances, statement.Balances)
+			t.Fail()
+			break
+		}
+	}
+	if statement.SequenceNumber != 3 {
+		t.Logf("Expected sequence number 3, got %d\n", statement.SequenceNumber)
+		t.Fail()
+	}
+	if len(statement.Entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d\n", len(statement.Entries))
+	}
+	entry := statement.Entries[0]
+	if entry.Reference != ids[0] || entry.CreditDebit != "DBIT" || entry.Amount.Value != "1434.56" {
+		t.Logf("Expected debit entry %s of 1434.56, got %+v\n", ids[0], entry)
+		t.Fail()
+	}
+	if entry.BankTransactionCode.Code != "NMSC+105" {
+		t.Logf("Expected bank transaction code NMSC+105, got %q\n", entry.BankTransactionCode.Code)
+		t.Fail()
+	}
+	details := entry.Details.Transaction
+	if details.References.EndToEndID != "RE-2021-0815" || details.References.MandateID != "M-4711" {
+		t.Logf("Expected SEPA references, got %+v\n", details.References)
+		t.Fail()
+	}
+	parties := details.RelatedParties
+	if parties == nil || parties.Creditor == nil || parties.Creditor.Name != "Stadtwerke \"Nord\"" ||
+		parties.CreditorAccount == nil || parties.CreditorAccount.IBAN != "DE89370400440532013000" {
+		t.Logf("Expected creditor Stadtwerke with IBAN, got %+v\n", parties)
+		t.Fail()
+	}
+	if credit := statement.Entries[1].Details.Transaction.RelatedParties; credit == nil || credit.Debtor == nil || credit.Debtor.Name != "Max Muster" {
+		t.Logf("Expected debtor Max Muster, got %+v\n", credit)
+		t.Fail()
+	}
+}
@@ -0,0 +1,161 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+// Column defines a column of a CSV export. Its value is used as header.
+type Column string
+
+// These represent the available CSV columns
+const (
+	ColumnID                    Column = "id"
+	ColumnAccount               Column = "account"
+	ColumnBookingDate           Column = "booking_date"
+	ColumnValutaDate            Column = "valuta_date"
+	ColumnAmount                Column = "amount"
+	ColumnCurrency              Column = "currency"
+	ColumnName                  Column = "name"
+	ColumnIBAN                  Column = "iban"
+	ColumnBIC                   Column = "bic"
+	ColumnBookingText           Column = "booking_text"
+	ColumnPurpose               Column = "purpose"
+	ColumnRemittanceInformation Column = "remittance_information"
+	ColumnEndToEndReference     Column = "end_to_end_reference"
+	ColumnMandateReference      Column = "mandate_reference"
+	ColumnCreditorID            Column = "creditor_id"
+	ColumnTransactionCode       Column = "transaction_code"
+	ColumnCategory              Column = "category"
+	ColumnBalanceBefore         Column = "balance_before"
+	ColumnBalanceAfter          Column = "balance_after"
+)
+
+// DefaultColumns are used if CSV.Columns is empty
+var DefaultColumns = []Column{
+	ColumnID,
+	ColumnAccount,
+	ColumnBookingDate,
+	ColumnValutaDate,
+	ColumnAmount,
+	ColumnCurrency,
+	ColumnName,
+	ColumnIBAN,
+	ColumnBIC,
+	ColumnBookingText,
+	ColumnPurpose,
+	ColumnCategory,
+}
+
+// CSV exports transactions as comma separated values with one transaction
+// per row
+type CSV struct {
+	// Columns defines the exported columns and their order. Defaults to
+	// DefaultColumns.
+	Columns []Column
+	// Locale defines the format of amounts and dates. Defaults to
+	// LocaleEnglish.
+	Locale *Locale
+	// Comma is the field delimiter. It defaults to ';' if the decimal
+	// separator of the locale is a comma, and to ',' otherwise.
+	Comma rune
+	// OmitHeader omits the header row with the column names
+	OmitHeader bool
+}
+
+// Export writes transactions as CSV to w
+func (c *CSV) Export(w io.Writer, transactions domain.AccountTransactions) error {
+	columns := c.Columns
+	if len(columns) == 0 {
+		columns = DefaultColumns
+	}
+	locale := LocaleEnglish
+	if c.Locale != nil {
+		locale = *c.Locale
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = c.Comma
+	if writer.Comma == 0 {
+		writer.Comma = ','
+		if locale.DecimalSeparator == "," {
+			writer.Comma = ';'
+		}
+	}
+	if !c.OmitHeader {
+		header := make([]string, len(columns))
+		for i, column := range columns {
+			header[i] = string(column)
+		}
+		if err := writer.Write(header); err != nil {
+			return fmt.Errorf("error writing CSV header: %w", err)
+		}
+	}
+	ids := TransactionIDs(transactions)
+	for i, tx := range transactions {
+		record := make([]string, len(columns))
+		for j, column := range columns {
+			value, err := csvValue(column, ids[i], tx, locale)
+			if err != nil {
+				return err
+			}
+			record[j] = value
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("error writing CSV record: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func csvValue(column Column, id string, tx domain.AccountTransaction, locale Locale) (string, error) {
+	switch column {
+	case ColumnID:
+		return id, nil
+	case ColumnAccount:
+		return tx.Account.AccountID, nil
+	case ColumnBookingDate:
+		return locale.formatDate(tx.BookingDate), nil
+	case ColumnValutaDate:
+		return locale.formatDate(tx.ValutaDate), nil
+	case ColumnAmount:
+		return locale.formatAmount(tx.Amount), nil
+	case ColumnCurrency:
+		return tx.Amount.Currency, nil
+	case ColumnName:
+		return tx.Name, nil
+	case ColumnIBAN:
+		return counterpartyIBAN(tx), nil
+	case ColumnBIC:
+		return counterpartyBIC(tx), nil
+	case ColumnBookingText:
+		return tx.BookingText, nil
+	case ColumnPurpose:
+		return singleLine(tx.Purpose + " " + tx.Purpose2), nil
+	case ColumnRemittanceInformation:
+		return tx.RemittanceInformation, nil
+	case ColumnEndToEndReference:
+		return tx.EndToEndReference, nil
+	case ColumnMandateReference:
+		return tx.MandateReference, nil
+	case ColumnCreditorID:
+		return tx.CreditorID, nil
+	case ColumnTransactionCode:
+		if tx.TransactionID == 0 {
+			return "", nil
+		}
+		return strconv.Itoa(tx.TransactionID), nil
+	case ColumnCategory:
+		return tx.Category.String(), nil
+	case ColumnBalanceBefore:
+		return locale.formatAmount(tx.AccountBalanceBefore.Amount), nil
+	case ColumnBalanceAfter:
+		return locale.formatAmount(tx.AccountBalanceAfter.Amount), nil
+	default:
+		return "", fmt.Errorf("unknown CSV column %q", column)
+	}
+}
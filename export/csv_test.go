@@ -0,0 +1,55 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCSVExport(t *testing.T) {
+	transactions := testTransactions()
+	ids := TransactionIDs(transactions)
+
+	tests := []struct {
+		description string
+		exporter    *CSV
+		expected    string
+	}{
+		{
+			"english locale",
+			&CSV{Columns: []Column{ColumnID, ColumnBookingDate, ColumnAmount, ColumnName, ColumnIBAN, ColumnCategory}},
+			"id,booking_date,amount,name,iban,category\n" +
+				ids[0] + ",2021-03-02,-1434.56,\"Stadtwerke \"\"Nord\"\"\",DE89370400440532013000,direct_debit\n" +
+				ids[1] + ",2021-03-02,50.00,Max Muster,987654321,transfer\n",
+		},
+		{
+			"german locale without header",
+			&CSV{Columns: []Column{ColumnValutaDate, ColumnAmount, ColumnCurrency}, Locale: &LocaleGerman, OmitHeader: true},
+			"01.03.2021;-1434,56;EUR\n" +
+				";50,00;EUR\n",
+		},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+
+		err := test.exporter.Export(&buf, transactions)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+		if buf.String() != test.expected {
+			t.Logf("%s: Expected CSV to equal\n%q\n\tgot\n%q\n", test.description, test.expected, buf.String())
+			t.Fail()
+		}
+	}
+}
+
+func TestCSVExportUnknownColumn(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := (&CSV{Columns: []Column{"foo"}}).Export(&buf, testTransactions())
+
+	if err == nil {
+		t.Logf("Expected error for unknown column\n")
+		t.Fail()
+	}
+}
@@ -0,0 +1,314 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	datevFormatVersion      = 700
+	datevFormatCategory     = 21
+	datevFormatName         = "Buchungsstapel"
+	datevFormatNameVersion  = 12
+	datevDateLayout         = "20060102"
+	datevCreatedLayout      = "20060102150405"
+	datevMaxBookingText     = 60
+	datevMaxDocumentField   = 36
+	datevDefaultAccountLen  = 4
+	datevBookingTypeFinance = 1
+)
+
+// datevColumns are the leading columns of the DATEV booking batch. The
+// remaining columns of the format are optional and are omitted.
+var datevColumns = []string{
+	"Umsatz (ohne Soll/Haben-Kz)",
+	"Soll/Haben-Kennzeichen",
+	"WKZ Umsatz",
+	"Kurs",
+	"Basis-Umsatz",
+	"WKZ Basis-Umsatz",
+	"Konto",
+	"Gegenkonto (ohne BU-Schlüssel)",
+	"BU-Schlüssel",
+	"Belegdatum",
+	"Belegfeld 1",
+	"Belegfeld 2",
+	"Skonto",
+	"Buchungstext",
+}
+
+// DATEVBooking defines the counter account of a transaction and its tax key
+// (BU-Schlüssel)
+type DATEVBooking struct {
+	CounterAccount string
+	TaxKey         string
+}
+
+// DATEVAccountMapping maps transactions to the counter account they are
+// booked on. It returns false if it has no mapping for the transaction.
+type DATEVAccountMapping interface {
+	Map(tx domain.AccountTransaction) (DATEVBooking, bool)
+}
+
+// DATEVAccountMappingFunc is an adapter to use ordinary functions as
+// DATEVAccountMapping
+type DATEVAccountMappingFunc func(tx domain.AccountTransaction) (DATEVBooking, bool)
+
+// Map calls f(tx)
+func (f DATEVAccountMappingFunc) Map(tx domain.AccountTransaction) (DATEVBooking, bool) {
+	return f(tx)
+}
+
+// DATEVRule maps all transactions matching Match to Booking
+type DATEVRule struct {
+	Match   TransactionMatcher
+	Booking DATEVBooking
+}
+
+// DATEVRules is a DATEVAccountMapping applying the first matching rule
+type DATEVRules []DATEVRule
+
+// Map returns the booking of the first rule matching tx
+func (d DATEVRules) Map(tx domain.AccountTransaction) (DATEVBooking, bool) {
+	for _, rule := range d {
+		if rule.Match(tx) {
+			return rule.Booking, true
+		}
+	}
+	return DATEVBooking{}, false
+}
+
+// TransactionMatcher reports whether a transaction matches a condition
+type TransactionMatcher func(tx domain.AccountTransaction) bool
+
+// MatchCategory matches transactions of the given category
+func MatchCategory(category domain.TransactionCategory) TransactionMatcher {
+	return func(tx domain.AccountTransaction) bool {
+		return tx.Category == category
+	}
+}
+
+// MatchCounterparty matches transactions with the given IBAN or account ID of
+// the counterparty
+func MatchCounterparty(iban string) TransactionMatcher {
+	return func(tx domain.AccountTransaction) bool {
+		return strings.EqualFold(counterpartyIBAN(tx), iban)
+	}
+}
+
+// MatchCreditorID matches direct debits of the given SEPA creditor
+func MatchCreditorID(creditorID string) TransactionMatcher {
+	return func(tx domain.AccountTransaction) bool {
+		return tx.CreditorID == creditorID
+	}
+}
+
+// MatchPurpose matches transactions whose name or purpose match re
+func MatchPurpose(re *regexp.Regexp) TransactionMatcher {
+	return func(tx domain.AccountTransaction) bool {
+		return re.MatchString(tx.Name) || re.MatchString(tx.Purpose) || re.MatchString(tx.Purpose2)
+	}
+}
+
+// DATEV exports transactions as DATEV booking batch (Buchungsstapel) in the
+// DATEV format, to be imported by a tax advisor. Each transaction is booked
+// between Account and the counter account returned by Mapping.
+//
+// All transactions must belong to the fiscal year starting at
+// FiscalYearStart, as DATEV dates carry no year.
+type DATEV struct {
+	// ConsultantNumber is the number of the tax advisor (Beraternummer)
+	ConsultantNumber int
+	// ClientNumber is the number of the client (Mandantennummer)
+	ClientNumber int
+	// FiscalYearStart is the beginning of the fiscal year (WJ-Beginn)
+	FiscalYearStart time.Time
+	// AccountLength is the length of the general ledger accounts
+	// (Sachkontenlänge). Defaults to 4.
+	AccountLength int
+	// ChartOfAccounts is the chart of accounts (Sachkontenrahmen), i.e. "03"
+	// for SKR03. It is optional.
+	ChartOfAccounts string
+	// Account is the general ledger account of the bank account, i.e. 1200
+	// in SKR03
+	Account string
+	// Mapping returns the counter account of a transaction. It is optional.
+	Mapping DATEVAccountMapping
+	// DefaultCounterAccount is used for transactions not mapped by Mapping,
+	// i.e. 1360 (Geldtransit) in SKR03
+	DefaultCounterAccount string
+	// Description is the description of the booking batch (Bezeichnung)
+	Description string
+	// Now returns the time of the export. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Export writes transactions as DATEV booking batch to w. The output is
+// encoded in Windows-1252, as expected by DATEV.
+func (d *DATEV) Export(w io.Writer, transactions domain.AccountTransactions) error {
+	if d.ConsultantNumber == 0 || d.ClientNumber == 0 {
+		return fmt.Errorf("DATEV: consultant and client number must be set")
+	}
+	if d.FiscalYearStart.IsZero() {
+		return fmt.Errorf("DATEV: fiscal year start must be set")
+	}
+	if d.Account == "" {
+		return fmt.Errorf("DATEV: account must be set")
+	}
+	fiscalYearEnd := d.FiscalYearStart.AddDate(1, 0, 0)
+	var from, to time.Time
+	for i, tx := range transactions {
+		if tx.BookingDate.Before(d.FiscalYearStart) || !tx.BookingDate.Before(fiscalYearEnd) {
+			return fmt.Errorf("DATEV: transaction booked at %s is not within the fiscal year", tx.BookingDate.Format("2006-01-02"))
+		}
+		if i == 0 || tx.BookingDate.Before(from) {
+			from = tx.BookingDate
+		}
+		if i == 0 || tx.BookingDate.After(to) {
+			to = tx.BookingDate
+		}
+	}
+	now := time.Now
+	if d.Now != nil {
+		now = d.Now
+	}
+	created := now()
+	accountLength := d.AccountLength
+	if accountLength == 0 {
+		accountLength = datevDefaultAccountLen
+	}
+
+	var records [][]string
+	records = append(records, []string{
+		datevString("EXTF"),
+		strconv.Itoa(datevFormatVersion),
+		strconv.Itoa(datevFormatCategory),
+		datevString(datevFormatName),
+		strconv.Itoa(datevFormatNameVersion),
+		created.Format(datevCreatedLayout) + fmt.Sprintf("%03d", created.Nanosecond()/int(time.Millisecond)),
+		"",
+		datevString("RE"),
+		datevString(""),
+		datevString(""),
+		strconv.Itoa(d.ConsultantNumber),
+		strconv.Itoa(d.ClientNumber),
+		d.FiscalYearStart.Format(datevDateLayout),
+		strconv.Itoa(accountLength),
+		datevDate(from),
+		datevDate(to),
+		datevString(d.Description),
+		datevString(""),
+		strconv.Itoa(datevBookingTypeFinance),
+		"0",
+		"0",
+		datevString("EUR"),
+		"",
+		datevString(""),
+		"",
+		"",
+		datevString(d.ChartOfAccounts),
+		"",
+		"",
+		datevString(""),
+		datevString(""),
+	})
+	header := make([]string, len(datevColumns))
+	for i, column := range datevColumns {
+		header[i] = datevString(column)
+	}
+	records = append(records, header)
+
+	ids := TransactionIDs(transactions)
+	for i, tx := range transactions {
+		booking := DATEVBooking{CounterAccount: d.DefaultCounterAccount}
+		if d.Mapping != nil {
+			if mapped, ok := d.Mapping.Map(tx); ok {
+				booking = mapped
+			}
+		}
+		if booking.CounterAccount == "" {
+			return fmt.Errorf("DATEV: no counter account for transaction %s", ids[i])
+		}
+		// incoming payments debit the bank account
+		debitCredit := "S"
+		if tx.Amount.Sign() < 0 {
+			debitCredit = "H"
+		}
+		currency := tx.Amount.Currency
+		if currency == "" {
+			currency = "EUR"
+		}
+		records = append(records, []string{
+			strings.Replace(tx.Amount.Abs().Decimal(), ".", ",", 1),
+			datevString(debitCredit),
+			datevString(currency),
+			"",
+			"",
+			datevString(""),
+			d.Account,
+			booking.CounterAccount,
+			datevString(booking.TaxKey),
+			tx.BookingDate.Format("0201"),
+			datevString(truncate(ids[i], datevMaxDocumentField)),
+			datevString(""),
+			"",
+			datevString(truncate(datevBookingText(tx), datevMaxBookingText)),
+		})
+	}
+
+	var buf strings.Builder
+	for _, record := range records {
+		buf.WriteString(strings.Join(record, ";"))
+		buf.WriteString("\r\n")
+	}
+	encoded, err := charmap.Windows1252.NewEncoder().String(toWindows1252(buf.String()))
+	if err != nil {
+		return fmt.Errorf("error encoding DATEV records: %w", err)
+	}
+	if _, err := io.WriteString(w, encoded); err != nil {
+		return fmt.Errorf("error writing DATEV records: %w", err)
+	}
+	return nil
+}
+
+// toWindows1252 replaces all characters without a representation in
+// Windows-1252 by a dot
+func toWindows1252(value string) string {
+	return strings.Map(func(r rune) rune {
+		if _, ok := charmap.Windows1252.EncodeRune(r); !ok {
+			return '.'
+		}
+		return r
+	}, value)
+}
+
+// datevBookingText returns the booking text of tx, consisting of the name of
+// the counterparty and the description
+func datevBookingText(tx domain.AccountTransaction) string {
+	var parts []string
+	if name := singleLine(tx.Name); name != "" {
+		parts = append(parts, name)
+	}
+	if text := description(tx); text != "" {
+		parts = append(parts, text)
+	}
+	return strings.Join(parts, " ")
+}
+
+func datevString(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+}
+
+func datevDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format(datevDateLayout)
+}
@@ -0,0 +1,111 @@
+package export
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func newTestDATEV() *DATEV {
+	return &DATEV{
+		ConsultantNumber: 1001,
+		ClientNumber:     1,
+		FiscalYearStart:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		ChartOfAccounts:  "03",
+		Account:          "1200",
+		Mapping: DATEVRules{
+			{Match: MatchCreditorID("DE98ZZZ09999999999"), Booking: DATEVBooking{CounterAccount: "4240", TaxKey: "9"}},
+			{Match: MatchPurpose(regexp.MustCompile("Geschenk")), Booking: DATEVBooking{CounterAccount: "2742"}},
+		},
+		DefaultCounterAccount: "1360",
+		Description:           "Bank März",
+		Now:                   func() time.Time { return time.Date(2021, 4, 1, 12, 30, 0, int(5*time.Millisecond), time.UTC) },
+	}
+}
+
+func TestDATEVExport(t *testing.T) {
+	transactions := testTransactions()
+	ids := TransactionIDs(transactions)
+	var buf bytes.Buffer
+
+	err := newTestDATEV().Export(&buf, transactions)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	decoded, err := charmap.Windows1252.NewDecoder().String(buf.String())
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	lines := strings.Split(decoded, "\r\n")
+	if len(lines) != 5 || lines[4] != "" {
+		t.Fatalf("Expected 4 records, got %q\n", lines)
+	}
+	expectedHeader := `"EXTF";700;21;"Buchungsstapel";12;20210401123000005;;"RE";"";"";1001;1;20210101;4;20210302;20210302;"Bank März";"";1;0;0;"EUR";;"";;;"03";;;"";""`
+	if lines[0] != expectedHeader {
+		t.Logf("Expected header to equal\n%s\n\tgot\n%s\n", expectedHeader, lines[0])
+		t.Fail()
+	}
+	if !strings.HasPrefix(lines[1], `"Umsatz (ohne Soll/Haben-Kz)";"Soll/Haben-Kennzeichen";`) {
+		t.Logf("Expected column header, got\n%s\n", lines[1])
+		t.Fail()
+	}
+	expectedRecords := []string{
+		`1434,56;"H";"EUR";;;"";1200;4240;"9";0203;"` + ids[0] + `";"";;"Stadtwerke ""Nord"" Rechnung 2021-0815"`,
+		`50,00;"S";"EUR";;;"";1200;2742;"";0203;"` + ids[1] + `";"";;"Max Muster Geschenk"`,
+	}
+	for i, expected := range expectedRecords {
+		if lines[i+2] != expected {
+			t.Logf("Expected record to equal\n%s\n\tgot\n%s\n", expected, lines[i+2])
+			t.Fail()
+		}
+	}
+}
+
+func TestDATEVExportDefaultCounterAccount(t *testing.T) {
+	exporter := newTestDATEV()
+	exporter.Mapping = nil
+	var buf bytes.Buffer
+
+	err := exporter.Export(&buf, testTransactions())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if strings.Count(buf.String(), ";1200;1360;") != 2 {
+		t.Logf("Expected both transactions to be booked on 1360, got\n%s\n", buf.String())
+		t.Fail()
+	}
+
+	exporter.DefaultCounterAccount = ""
+
+	err = exporter.Export(&buf, testTransactions())
+
+	if err == nil {
+		t.Logf("Expected error for transactions without counter account\n")
+		t.Fail()
+	}
+}
+
+func TestDATEVExportOutsideFiscalYear(t *testing.T) {
+	exporter := newTestDATEV()
+	transactions := testTransactions()
+	transactions = append(transactions, domain.AccountTransaction{
+		Amount:      domain.NewAmount(100, "EUR"),
+		BookingDate: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	var buf bytes.Buffer
+
+	err := exporter.Export(&buf, transactions)
+
+	if err == nil {
+		t.Logf("Expected error for transaction outside of the fiscal year\n")
+		t.Fail()
+	}
+}
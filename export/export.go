@@ -0,0 +1,111 @@
+// Package export renders account transactions into formats understood by
+// accounting software, like CSV, OFX, QIF, camt.053 XML, DATEV or the
+// plain-text journals of ledger, hledger and beancount.
+//
+// All formats carry stable transaction IDs, as returned by TransactionIDs, so
+// that importing tools can detect transactions they already know.
+package export
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/sync"
+)
+
+// Exporter renders account transactions into an export format
+type Exporter interface {
+	Export(w io.Writer, transactions domain.AccountTransactions) error
+}
+
+// transactionIDLength is the length of the fingerprint part of transaction
+// IDs. It leaves room for the occurrence suffix within the 35 characters
+// allowed for references by ISO 20022.
+const transactionIDLength = 32
+
+// TransactionIDs returns a stable ID for each of transactions, based on
+// sync.Fingerprint. Identical transactions, i.e. two purchases of the same
+// value at the same shop on one day, get a suffix with their occurrence, so
+// that the IDs are unique within transactions.
+func TransactionIDs(transactions domain.AccountTransactions) []string {
+	ids := make([]string, len(transactions))
+	occurrences := make(map[string]int)
+	for i, tx := range transactions {
+		fingerprint := sync.Fingerprint(tx)[:transactionIDLength]
+		occurrences[fingerprint]++
+		ids[i] = fingerprint
+		if occurrences[fingerprint] > 1 {
+			ids[i] += "-" + strconv.Itoa(occurrences[fingerprint])
+		}
+	}
+	return ids
+}
+
+// Locale defines how amounts and dates are formatted
+type Locale struct {
+	// DecimalSeparator separates the minor units of amounts
+	DecimalSeparator string
+	// DateLayout is the layout of dates as used by time.Time.Format
+	DateLayout string
+}
+
+// These represent common locales
+var (
+	LocaleEnglish = Locale{DecimalSeparator: ".", DateLayout: "2006-01-02"}
+	LocaleGerman  = Locale{DecimalSeparator: ",", DateLayout: "02.01.2006"}
+)
+
+func (l Locale) formatAmount(amount domain.Amount) string {
+	return strings.Replace(amount.Decimal(), ".", l.DecimalSeparator, 1)
+}
+
+func (l Locale) formatDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format(l.DateLayout)
+}
+
+// counterpartyIBAN returns the IBAN of the counterparty of tx, falling back to
+// the account ID for transactions without SEPA data
+func counterpartyIBAN(tx domain.AccountTransaction) string {
+	if tx.CounterpartyIBAN != "" {
+		return tx.CounterpartyIBAN
+	}
+	return tx.AccountID
+}
+
+// counterpartyBIC returns the BIC of the counterparty of tx, falling back to
+// the bank ID for transactions without SEPA data
+func counterpartyBIC(tx domain.AccountTransaction) string {
+	if tx.CounterpartyBIC != "" {
+		return tx.CounterpartyBIC
+	}
+	return tx.BankID
+}
+
+// description returns the remittance information of tx if present, and the
+// complete purpose otherwise
+func description(tx domain.AccountTransaction) string {
+	if tx.RemittanceInformation != "" {
+		return tx.RemittanceInformation
+	}
+	return strings.TrimSpace(strings.Join(strings.Fields(tx.Purpose+" "+tx.Purpose2), " "))
+}
+
+// valutaDate returns the valuta date of tx, falling back to the booking date
+func valutaDate(tx domain.AccountTransaction) time.Time {
+	if tx.ValutaDate.IsZero() {
+		return tx.BookingDate
+	}
+	return tx.ValutaDate
+}
+
+// singleLine replaces line breaks within value, as most formats are line
+// based
+func singleLine(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
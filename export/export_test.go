@@ -0,0 +1,74 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+func testTransactions() domain.AccountTransactions {
+	account := domain.AccountConnection{BankID: "12345678", AccountID: "1234123456", CountryCode: 280}
+	before := domain.Balance{Amount: domain.NewAmount(123456, "EUR"), TransmissionDate: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)}
+	after := domain.Balance{Amount: domain.NewAmount(-15000, "EUR"), TransmissionDate: time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC)}
+	return domain.AccountTransactions{
+		{
+			Account:               account,
+			Amount:                domain.NewAmount(-143456, "EUR"),
+			ValutaDate:            time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+			BookingDate:           time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC),
+			BookingText:           "FOLGELASTSCHRIFT",
+			Name:                  "Stadtwerke \"Nord\"",
+			Purpose:               "EREF+RE-2021-0815 MREF+M-4711 CRED+DE98ZZZ09999999999 SVWZ+Rechnung 2021-0815",
+			TransactionID:         105,
+			Category:              domain.TransactionCategoryDirectDebit,
+			StatementNumber:       3,
+			AccountBalanceBefore:  before,
+			AccountBalanceAfter:   after,
+			EndToEndReference:     "RE-2021-0815",
+			MandateReference:      "M-4711",
+			CreditorID:            "DE98ZZZ09999999999",
+			RemittanceInformation: "Rechnung 2021-0815",
+			CounterpartyIBAN:      "DE89370400440532013000",
+			CounterpartyBIC:       "COBADEFFXXX",
+		},
+		{
+			Account:              account,
+			Amount:               domain.NewAmount(5000, "EUR"),
+			BookingDate:          time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC),
+			Name:                 "Max Muster",
+			AccountID:            "987654321",
+			BankID:               "87654321",
+			Purpose:              "Geschenk",
+			TransactionID:        51,
+			Category:             domain.TransactionCategoryTransfer,
+			StatementNumber:      3,
+			AccountBalanceBefore: before,
+			AccountBalanceAfter:  after,
+		},
+	}
+}
+
+func TestTransactionIDs(t *testing.T) {
+	transactions := testTransactions()
+	transactions = append(transactions, transactions[1])
+
+	ids := TransactionIDs(transactions)
+
+	if len(ids) != 3 {
+		t.Fatalf("Expected 3 IDs, got %d\n", len(ids))
+	}
+	if ids[0] == ids[1] {
+		t.Logf("Expected IDs of different transactions to differ, got %q\n", ids[0])
+		t.Fail()
+	}
+	if ids[2] != ids[1]+"-2" {
+		t.Logf("Expected ID of repeated transaction to equal %q, got %q\n", ids[1]+"-2", ids[2])
+		t.Fail()
+	}
+	if again := TransactionIDs(transactions); strings.Join(again, ",") != strings.Join(ids, ",") {
+		t.Logf("Expected IDs to be stable, got %q and %q\n", ids, again)
+		t.Fail()
+	}
+}
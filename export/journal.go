@@ -0,0 +1,189 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+// These are the default accounts of journals
+const (
+	DefaultJournalAccount        = "Assets:Bank"
+	DefaultJournalIncomeAccount  = "Income:Unknown"
+	DefaultJournalExpenseAccount = "Expenses:Unknown"
+)
+
+const journalDateLayout = "2006-01-02"
+
+// Journal configures the accounts of the plain-text accounting formats. Each
+// transaction is booked between Account and a counter account, which is
+// looked up in CategoryAccounts and falls back to IncomeAccount or
+// ExpenseAccount, depending on the sign of the amount.
+type Journal struct {
+	// Account is the account of the bank account. Defaults to
+	// DefaultJournalAccount.
+	Account string
+	// IncomeAccount is the counter account for incoming payments. Defaults to
+	// DefaultJournalIncomeAccount.
+	IncomeAccount string
+	// ExpenseAccount is the counter account for outgoing payments. Defaults
+	// to DefaultJournalExpenseAccount.
+	ExpenseAccount string
+	// CategoryAccounts maps transaction categories to counter accounts
+	CategoryAccounts map[domain.TransactionCategory]string
+	// BalanceAssertions adds the closing balance of each account statement
+	// as balance assertion
+	BalanceAssertions bool
+}
+
+func (j Journal) account() string {
+	if j.Account == "" {
+		return DefaultJournalAccount
+	}
+	return j.Account
+}
+
+func (j Journal) counterAccount(tx domain.AccountTransaction) string {
+	if account, ok := j.CategoryAccounts[tx.Category]; ok {
+		return account
+	}
+	if tx.Amount.Sign() < 0 {
+		if j.ExpenseAccount == "" {
+			return DefaultJournalExpenseAccount
+		}
+		return j.ExpenseAccount
+	}
+	if j.IncomeAccount == "" {
+		return DefaultJournalIncomeAccount
+	}
+	return j.IncomeAccount
+}
+
+// closingBalance returns the closing balance of statement and its date
+func closingBalance(statement domain.AccountTransactions) (domain.Amount, time.Time) {
+	last := statement[len(statement)-1]
+	date := last.AccountBalanceAfter.TransmissionDate
+	if date.IsZero() {
+		date = last.BookingDate
+	}
+	return last.AccountBalanceAfter.Amount, date
+}
+
+func journalAmount(amount domain.Amount) string {
+	currency := amount.Currency
+	if currency == "" {
+		currency = "EUR"
+	}
+	return amount.Decimal() + " " + currency
+}
+
+// Ledger exports transactions as journal for ledger and hledger. The stable
+// transaction ID is written as id tag.
+type Ledger struct {
+	Journal
+}
+
+// Export writes transactions as ledger journal to w
+func (l *Ledger) Export(w io.Writer, transactions domain.AccountTransactions) error {
+	buf := bufio.NewWriter(w)
+	ids := TransactionIDs(transactions)
+	var index int
+	for _, statement := range transactions.Statements() {
+		for i, tx := range statement {
+			payee := singleLine(tx.Name)
+			note := description(tx)
+			if payee == "" {
+				payee, note = note, ""
+			}
+			fmt.Fprintf(buf, "%s * %s\n", tx.BookingDate.Format(journalDateLayout), payee)
+			if note != "" {
+				fmt.Fprintf(buf, "    ; %s\n", note)
+			}
+			fmt.Fprintf(buf, "    ; id: %s\n", ids[index])
+			assertion := ""
+			if l.BalanceAssertions && i == len(statement)-1 {
+				balance, _ := closingBalance(statement)
+				assertion = " = " + journalAmount(balance)
+			}
+			fmt.Fprintf(buf, "    %s  %s%s\n", l.account(), journalAmount(tx.Amount), assertion)
+			fmt.Fprintf(buf, "    %s\n\n", l.counterAccount(tx))
+			index++
+		}
+	}
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("error writing ledger journal: %w", err)
+	}
+	return nil
+}
+
+// Beancount exports transactions as beancount journal. The stable transaction
+// ID is written as id metadata.
+type Beancount struct {
+	Journal
+	// OpenAccounts adds open directives for all used accounts, dated at the
+	// first booking date
+	OpenAccounts bool
+}
+
+var beancountStringReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+func beancountString(value string) string {
+	return `"` + beancountStringReplacer.Replace(singleLine(value)) + `"`
+}
+
+// Export writes transactions as beancount journal to w. Balance assertions
+// are dated the day after the closing balance, as beancount checks balances
+// at the beginning of a day.
+func (b *Beancount) Export(w io.Writer, transactions domain.AccountTransactions) error {
+	buf := bufio.NewWriter(w)
+	if b.OpenAccounts && len(transactions) != 0 {
+		b.writeOpenDirectives(buf, transactions)
+	}
+	ids := TransactionIDs(transactions)
+	var index int
+	for _, statement := range transactions.Statements() {
+		for _, tx := range statement {
+			fmt.Fprintf(
+				buf, "%s * %s %s\n",
+				tx.BookingDate.Format(journalDateLayout), beancountString(tx.Name), beancountString(description(tx)),
+			)
+			fmt.Fprintf(buf, "  id: %s\n", beancountString(ids[index]))
+			fmt.Fprintf(buf, "  %s  %s\n", b.account(), journalAmount(tx.Amount))
+			fmt.Fprintf(buf, "  %s\n\n", b.counterAccount(tx))
+			index++
+		}
+		if b.BalanceAssertions {
+			balance, date := closingBalance(statement)
+			fmt.Fprintf(buf, "%s balance %s  %s\n\n", date.AddDate(0, 0, 1).Format(journalDateLayout), b.account(), journalAmount(balance))
+		}
+	}
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("error writing beancount journal: %w", err)
+	}
+	return nil
+}
+
+func (b *Beancount) writeOpenDirectives(w io.Writer, transactions domain.AccountTransactions) {
+	openDate := transactions[0].BookingDate
+	accounts := map[string]bool{b.account(): true}
+	for _, tx := range transactions {
+		if tx.BookingDate.Before(openDate) {
+			openDate = tx.BookingDate
+		}
+		accounts[b.counterAccount(tx)] = true
+	}
+	var names []string
+	for name := range accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "%s open %s\n", openDate.Format(journalDateLayout), name)
+	}
+	fmt.Fprint(w, "\n")
+}
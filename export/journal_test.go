@@ -0,0 +1,70 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+func TestLedgerExport(t *testing.T) {
+	transactions := testTransactions()
+	ids := TransactionIDs(transactions)
+	exporter := &Ledger{Journal{
+		Account:           "Assets:Bank:Giro",
+		CategoryAccounts:  map[domain.TransactionCategory]string{domain.TransactionCategoryDirectDebit: "Expenses:Utilities"},
+		BalanceAssertions: true,
+	}}
+	var buf bytes.Buffer
+
+	err := exporter.Export(&buf, transactions)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	expected := "2021-03-02 * Stadtwerke \"Nord\"\n" +
+		"    ; Rechnung 2021-0815\n" +
+		"    ; id: " + ids[0] + "\n" +
+		"    Assets:Bank:Giro  -1434.56 EUR\n" +
+		"    Expenses:Utilities\n\n" +
+		"2021-03-02 * Max Muster\n" +
+		"    ; Geschenk\n" +
+		"    ; id: " + ids[1] + "\n" +
+		"    Assets:Bank:Giro  50.00 EUR = -150.00 EUR\n" +
+		"    Income:Unknown\n\n"
+	if buf.String() != expected {
+		t.Logf("Expected ledger journal to equal\n%s\n\tgot\n%s\n", expected, buf.String())
+		t.Fail()
+	}
+}
+
+func TestBeancountExport(t *testing.T) {
+	transactions := testTransactions()
+	ids := TransactionIDs(transactions)
+	exporter := &Beancount{Journal: Journal{BalanceAssertions: true}, OpenAccounts: true}
+	var buf bytes.Buffer
+
+	err := exporter.Export(&buf, transactions)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	expected := "2021-03-02 open Assets:Bank\n" +
+		"2021-03-02 open Expenses:Unknown\n" +
+		"2021-03-02 open Income:Unknown\n\n" +
+		"2021-03-02 * \"Stadtwerke \\\"Nord\\\"\" \"Rechnung 2021-0815\"\n" +
+		"  id: \"" + ids[0] + "\"\n" +
+		"  Assets:Bank  -1434.56 EUR\n" +
+		"  Expenses:Unknown\n\n" +
+		"2021-03-02 * \"Max Muster\" \"Geschenk\"\n" +
+		"  id: \"" + ids[1] + "\"\n" +
+		"  Assets:Bank  50.00 EUR\n" +
+		"  Income:Unknown\n\n" +
+		"2021-03-03 balance Assets:Bank  -150.00 EUR\n\n"
+	if buf.String() != expected {
+		t.Logf("Expected beancount journal to equal\n%s\n\tgot\n%s\n", expected, buf.String())
+		t.Fail()
+	}
+}
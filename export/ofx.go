@@ -0,0 +1,205 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+const (
+	ofxHeader         = `<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>`
+	ofxDateTimeLayout = "20060102150405"
+	ofxDateLayout     = "20060102"
+	// ofxMaxNameLength is the maximum length of the payee name in OFX
+	ofxMaxNameLength = 32
+)
+
+// OFX exports transactions as Open Financial Exchange 2.2 document, as
+// imported by GnuCash, among others. It creates one statement per account.
+type OFX struct {
+	// AccountType is the OFX account type, i.e. CHECKING or SAVINGS. Defaults
+	// to CHECKING.
+	AccountType string
+	// Now returns the time of the export. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Export writes transactions as OFX document to w
+func (o *OFX) Export(w io.Writer, transactions domain.AccountTransactions) error {
+	now := time.Now
+	if o.Now != nil {
+		now = o.Now
+	}
+	accountType := o.AccountType
+	if accountType == "" {
+		accountType = "CHECKING"
+	}
+	document := ofxDocument{
+		SignOn: ofxSignOn{
+			Status:     ofxStatusOK,
+			ServerDate: now().Format(ofxDateTimeLayout),
+			Language:   "GER",
+		},
+	}
+	ids := TransactionIDs(transactions)
+	statements := make(map[domain.AccountConnection]*ofxStatement)
+	for i, tx := range transactions {
+		statement, ok := statements[tx.Account]
+		if !ok {
+			statement = &ofxStatement{
+				Currency: tx.Amount.Currency,
+				Account: ofxAccount{
+					BankID:      tx.Account.BankID,
+					AccountID:   tx.Account.AccountID,
+					AccountType: accountType,
+				},
+				start: tx.BookingDate,
+				end:   tx.BookingDate,
+			}
+			statements[tx.Account] = statement
+			document.Statements = append(document.Statements, ofxStatementResponse{
+				TransactionUID: fmt.Sprintf("%d", len(document.Statements)+1),
+				Status:         ofxStatusOK,
+				Statement:      statement,
+			})
+		}
+		if tx.BookingDate.Before(statement.start) {
+			statement.start = tx.BookingDate
+		}
+		if tx.BookingDate.After(statement.end) {
+			statement.end = tx.BookingDate
+		}
+		statement.Transactions.Transactions = append(statement.Transactions.Transactions, ofxTransaction{
+			Type:      ofxTransactionType(tx),
+			Posted:    tx.BookingDate.Format(ofxDateLayout),
+			User:      valutaDate(tx).Format(ofxDateLayout),
+			Amount:    tx.Amount.Decimal(),
+			FITID:     ids[i],
+			Reference: tx.EndToEndReference,
+			Name:      truncate(singleLine(tx.Name), ofxMaxNameLength),
+			Memo:      description(tx),
+		})
+		if !tx.AccountBalanceAfter.TransmissionDate.IsZero() {
+			statement.LedgerBalance = &ofxBalance{
+				Amount: tx.AccountBalanceAfter.Amount.Decimal(),
+				AsOf:   tx.AccountBalanceAfter.TransmissionDate.Format(ofxDateLayout),
+			}
+		}
+	}
+	for _, statement := range statements {
+		statement.Transactions.Start = statement.start.Format(ofxDateLayout)
+		statement.Transactions.End = statement.end.Format(ofxDateLayout)
+	}
+	if _, err := io.WriteString(w, xml.Header+ofxHeader+"\n"); err != nil {
+		return fmt.Errorf("error writing OFX header: %w", err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("error encoding OFX: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ofxTransactionType maps the category of tx to an OFX transaction type
+func ofxTransactionType(tx domain.AccountTransaction) string {
+	switch tx.Category {
+	case domain.TransactionCategoryDirectDebit:
+		if tx.Amount.Sign() < 0 {
+			return "DIRECTDEBIT"
+		}
+	case domain.TransactionCategoryCardPayment:
+		return "POS"
+	case domain.TransactionCategoryCashWithdrawal:
+		return "ATM"
+	case domain.TransactionCategoryCashDeposit:
+		return "CASH"
+	case domain.TransactionCategoryFee:
+		return "FEE"
+	case domain.TransactionCategoryInterest:
+		return "INT"
+	case domain.TransactionCategoryStandingOrder:
+		return "REPEATPMT"
+	case domain.TransactionCategoryCheque:
+		return "CHECK"
+	case domain.TransactionCategoryInternalTransfer:
+		return "XFER"
+	}
+	if tx.Amount.Sign() < 0 {
+		return "DEBIT"
+	}
+	return "CREDIT"
+}
+
+func truncate(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) > length {
+		return string(runes[:length])
+	}
+	return value
+}
+
+var ofxStatusOK = ofxStatus{Code: 0, Severity: "INFO"}
+
+type ofxDocument struct {
+	XMLName    xml.Name               `xml:"OFX"`
+	SignOn     ofxSignOn              `xml:"SIGNONMSGSRSV1>SONRS"`
+	Statements []ofxStatementResponse `xml:"BANKMSGSRSV1>STMTTRNRS"`
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxSignOn struct {
+	Status     ofxStatus `xml:"STATUS"`
+	ServerDate string    `xml:"DTSERVER"`
+	Language   string    `xml:"LANGUAGE"`
+}
+
+type ofxStatementResponse struct {
+	TransactionUID string        `xml:"TRNUID"`
+	Status         ofxStatus     `xml:"STATUS"`
+	Statement      *ofxStatement `xml:"STMTRS"`
+}
+
+type ofxStatement struct {
+	Currency      string             `xml:"CURDEF"`
+	Account       ofxAccount         `xml:"BANKACCTFROM"`
+	Transactions  ofxTransactionList `xml:"BANKTRANLIST"`
+	LedgerBalance *ofxBalance        `xml:"LEDGERBAL,omitempty"`
+	start, end    time.Time
+}
+
+type ofxAccount struct {
+	BankID      string `xml:"BANKID"`
+	AccountID   string `xml:"ACCTID"`
+	AccountType string `xml:"ACCTTYPE"`
+}
+
+type ofxTransactionList struct {
+	Start        string           `xml:"DTSTART"`
+	End          string           `xml:"DTEND"`
+	Transactions []ofxTransaction `xml:"STMTTRN"`
+}
+
+type ofxTransaction struct {
+	Type      string `xml:"TRNTYPE"`
+	Posted    string `xml:"DTPOSTED"`
+	User      string `xml:"DTUSER"`
+	Amount    string `xml:"TRNAMT"`
+	FITID     string `xml:"FITID"`
+	Reference string `xml:"REFNUM,omitempty"`
+	Name      string `xml:"NAME,omitempty"`
+	Memo      string `xml:"MEMO,omitempty"`
+}
+
+type ofxBalance struct {
+	Amount string `xml:"BALAMT"`
+	AsOf   string `xml:"DTASOF"`
+}
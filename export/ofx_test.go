@@ -0,0 +1,57 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOFXExport(t *testing.T) {
+	transactions := testTransactions()
+	ids := TransactionIDs(transactions)
+	exporter := &OFX{Now: func() time.Time { return time.Date(2021, 3, 3, 12, 0, 0, 0, time.UTC) }}
+	var buf bytes.Buffer
+
+	err := exporter.Export(&buf, transactions)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	if !strings.Contains(buf.String(), `<?OFX OFXHEADER="200" VERSION="220"`) {
+		t.Logf("Expected OFX header, got\n%s\n", buf.String())
+		t.Fail()
+	}
+	var document ofxDocument
+	if err := xml.Unmarshal(buf.Bytes(), &document); err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if len(document.Statements) != 1 {
+		t.Fatalf("Expected one statement, got %d\n", len(document.Statements))
+	}
+	statement := document.Statements[0].Statement
+	if statement.Account.AccountID != "1234123456" || statement.Currency != "EUR" {
+		t.Logf("Expected statement for account 1234123456 in EUR, got %+v\n", statement)
+		t.Fail()
+	}
+	if statement.LedgerBalance == nil || statement.LedgerBalance.Amount != "-150.00" {
+		t.Logf("Expected ledger balance of -150.00, got %+v\n", statement.LedgerBalance)
+		t.Fail()
+	}
+	expected := []ofxTransaction{
+		{Type: "DIRECTDEBIT", Posted: "20210302", User: "20210301", Amount: "-1434.56", FITID: ids[0], Reference: "RE-2021-0815", Name: "Stadtwerke \"Nord\"", Memo: "Rechnung 2021-0815"},
+		{Type: "CREDIT", Posted: "20210302", User: "20210302", Amount: "50.00", FITID: ids[1], Name: "Max Muster", Memo: "Geschenk"},
+	}
+	actual := statement.Transactions.Transactions
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected %d transactions, got %d\n", len(expected), len(actual))
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Logf("Expected transaction to equal\n%+v\n\tgot\n%+v\n", expected[i], actual[i])
+			t.Fail()
+		}
+	}
+}
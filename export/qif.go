@@ -0,0 +1,54 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+// defaultQIFDateLayout is the date layout understood by most QIF importers
+const defaultQIFDateLayout = "01/02/2006"
+
+// QIF exports transactions in the Quicken Interchange Format as bank account
+// entries
+type QIF struct {
+	// Account is the name of the account the transactions are imported into.
+	// If it is empty, no account header is written and the importing tool
+	// asks for the account.
+	Account string
+	// DateLayout is the layout of the dates. Defaults to MM/DD/YYYY.
+	DateLayout string
+}
+
+// Export writes transactions as QIF to w. QIF has no field for transaction
+// IDs, so the stable ID is written as check number.
+func (q *QIF) Export(w io.Writer, transactions domain.AccountTransactions) error {
+	dateLayout := q.DateLayout
+	if dateLayout == "" {
+		dateLayout = defaultQIFDateLayout
+	}
+	buf := bufio.NewWriter(w)
+	if q.Account != "" {
+		fmt.Fprintf(buf, "!Account\nN%s\nTBank\n^\n", singleLine(q.Account))
+	}
+	fmt.Fprint(buf, "!Type:Bank\n")
+	ids := TransactionIDs(transactions)
+	for i, tx := range transactions {
+		fmt.Fprintf(buf, "D%s\n", tx.BookingDate.Format(dateLayout))
+		fmt.Fprintf(buf, "T%s\n", tx.Amount.Decimal())
+		fmt.Fprintf(buf, "N%s\n", ids[i])
+		if tx.Name != "" {
+			fmt.Fprintf(buf, "P%s\n", singleLine(tx.Name))
+		}
+		if memo := description(tx); memo != "" {
+			fmt.Fprintf(buf, "M%s\n", memo)
+		}
+		fmt.Fprint(buf, "^\n")
+	}
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("error writing QIF: %w", err)
+	}
+	return nil
+}
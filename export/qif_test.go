@@ -0,0 +1,27 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestQIFExport(t *testing.T) {
+	transactions := testTransactions()
+	ids := TransactionIDs(transactions)
+	var buf bytes.Buffer
+
+	err := (&QIF{Account: "Girokonto"}).Export(&buf, transactions)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	expected := "!Account\nNGirokonto\nTBank\n^\n" +
+		"!Type:Bank\n" +
+		"D03/02/2021\nT-1434.56\nN" + ids[0] + "\nPStadtwerke \"Nord\"\nMRechnung 2021-0815\n^\n" +
+		"D03/02/2021\nT50.00\nN" + ids[1] + "\nPMax Muster\nMGeschenk\n^\n"
+	if buf.String() != expected {
+		t.Logf("Expected QIF to equal\n%q\n\tgot\n%q\n", expected, buf.String())
+		t.Fail()
+	}
+}
@@ -0,0 +1,25 @@
+// Package fintstest provides a local FinTS 3.0 PIN/TAN bank simulator for
+// integration tests.
+//
+// The Server runs in-process on a httptest.Server and keeps real dialog state.
+// It answers the synchronisation and dialog initialization with configurable
+// bank and user parameter data, serves balances (HKSAL) and account
+// transactions (HKKAZ and HKCAZ) from fixture data, splits large results into
+// pages linked by continuation references, issues TAN challenges with TAN
+// process 4 or the decoupled process and ends dialogs.
+//
+// A typical test looks like this:
+//
+//	server := fintstest.NewServer(fintstest.Config{
+//		Accounts: []fintstest.Account{account},
+//	})
+//	defer server.Close()
+//
+//	c, err := client.New(client.Config{
+//		URL:         server.URL,
+//		BankID:      server.BankID(),
+//		AccountID:   server.UserID(),
+//		PIN:         server.PIN(),
+//		HBCIVersion: 300,
+//	})
+package fintstest
@@ -0,0 +1,189 @@
+package fintstest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+// Account represents an account served by the Server.
+type Account struct {
+	AccountID   string
+	IBAN        string
+	BIC         string
+	Name        string
+	ProductName string
+	Currency    string
+	// Balance is the current booked balance, i.e. the balance after all
+	// Transactions.
+	Balance      domain.Amount
+	Transactions []Transaction
+}
+
+// Transaction represents a booked transaction of an Account.
+type Transaction struct {
+	BookingDate     time.Time
+	ValutaDate      time.Time
+	Amount          domain.Amount
+	BookingText     string
+	TransactionCode int
+	Name            string
+	IBAN            string
+	BIC             string
+	Purpose         string
+	Reference       string
+}
+
+func (a Account) currency() string {
+	if a.Currency == "" {
+		return "EUR"
+	}
+	return a.Currency
+}
+
+// sortedTransactions returns the transactions of a ordered by booking date.
+func (a Account) sortedTransactions() []Transaction {
+	transactions := make([]Transaction, len(a.Transactions))
+	copy(transactions, a.Transactions)
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return transactions[i].BookingDate.Before(transactions[j].BookingDate)
+	})
+	return transactions
+}
+
+// transactionsBetween returns the transactions booked within from and to. A
+// zero from or to is treated as unbounded.
+func (a Account) transactionsBetween(from, to time.Time) []Transaction {
+	var transactions []Transaction
+	for _, tx := range a.sortedTransactions() {
+		if !from.IsZero() && tx.BookingDate.Before(from) {
+			continue
+		}
+		if !to.IsZero() && tx.BookingDate.After(to) {
+			continue
+		}
+		transactions = append(transactions, tx)
+	}
+	return transactions
+}
+
+// balanceBefore returns the balance of a before the given transaction was
+// booked, derived from the current balance.
+func (a Account) balanceBefore(tx Transaction) domain.Amount {
+	balance := a.Balance.MinorUnits
+	for _, other := range a.Transactions {
+		if !other.BookingDate.Before(tx.BookingDate) {
+			balance -= other.Amount.MinorUnits
+		}
+	}
+	return domain.NewAmount(balance, a.currency())
+}
+
+// mt940 renders transactions as MT940 statement of a.
+func (a Account) mt940(bankID string, statementNumber int, transactions []Transaction) string {
+	var buf strings.Builder
+	opening := a.Balance
+	closing := a.Balance
+	openingDate, closingDate := time.Now(), time.Now()
+	if len(transactions) != 0 {
+		first, last := transactions[0], transactions[len(transactions)-1]
+		opening = a.balanceBefore(first)
+		closing = opening
+		for _, tx := range transactions {
+			closing.MinorUnits += tx.Amount.MinorUnits
+		}
+		openingDate, closingDate = first.BookingDate, last.BookingDate
+	}
+	fmt.Fprintf(&buf, "\r\n:20:STARTUMS\r\n")
+	fmt.Fprintf(&buf, ":25:%s/%s\r\n", bankID, a.AccountID)
+	fmt.Fprintf(&buf, ":28C:%d/1\r\n", statementNumber)
+	fmt.Fprintf(&buf, ":60F:%s%s%s%s\r\n", debitCredit(opening), openingDate.Format("060102"), a.currency(), opening.Abs().FormatHBCI())
+	for _, tx := range transactions {
+		valutaDate := tx.ValutaDate
+		if valutaDate.IsZero() {
+			valutaDate = tx.BookingDate
+		}
+		reference := tx.Reference
+		if reference == "" {
+			reference = "NONREF"
+		}
+		fmt.Fprintf(
+			&buf, ":61:%s%s%s%sN%03d%s\r\n",
+			valutaDate.Format("060102"), tx.BookingDate.Format("0102"), debitCredit(tx.Amount),
+			tx.Amount.Abs().FormatHBCI(), transactionCode(tx), reference,
+		)
+		fmt.Fprintf(&buf, ":86:%03d?00%s", transactionCode(tx), tx.BookingText)
+		for i, line := range chunk(tx.Purpose, 27, 10) {
+			fmt.Fprintf(&buf, "?2%d%s", i, line)
+		}
+		fmt.Fprintf(&buf, "?30%s?31%s?32%s\r\n", tx.BIC, tx.IBAN, tx.Name)
+	}
+	fmt.Fprintf(&buf, ":62F:%s%s%s%s\r\n-", debitCredit(closing), closingDate.Format("060102"), a.currency(), closing.Abs().FormatHBCI())
+	return buf.String()
+}
+
+// camt renders transactions as camt.052 document of a.
+func (a Account) camt(transactions []Transaction) string {
+	var buf strings.Builder
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
+	buf.WriteString(`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.052.001.02"><BkToCstmrAcctRpt>`)
+	fmt.Fprintf(&buf, `<GrpHdr><MsgId>fintstest</MsgId><CreDtTm>%s</CreDtTm></GrpHdr>`, time.Now().Format("2006-01-02T15:04:05"))
+	fmt.Fprintf(&buf, `<Rpt><Id>fintstest</Id><Acct><Id><IBAN>%s</IBAN></Id><Ccy>%s</Ccy></Acct>`, xmlEscape(a.IBAN), a.currency())
+	for _, tx := range transactions {
+		indicator := "CRDT"
+		if tx.Amount.Sign() < 0 {
+			indicator = "DBIT"
+		}
+		valutaDate := tx.ValutaDate
+		if valutaDate.IsZero() {
+			valutaDate = tx.BookingDate
+		}
+		fmt.Fprintf(&buf, `<Ntry><Amt Ccy="%s">%s</Amt><CdtDbtInd>%s</CdtDbtInd><Sts>BOOK</Sts>`, tx.Amount.Currency, tx.Amount.Abs().Decimal(), indicator)
+		fmt.Fprintf(&buf, `<BookgDt><Dt>%s</Dt></BookgDt><ValDt><Dt>%s</Dt></ValDt>`, tx.BookingDate.Format("2006-01-02"), valutaDate.Format("2006-01-02"))
+		fmt.Fprintf(&buf, `<NtryDtls><TxDtls><RltdPties><Cdtr><Nm>%s</Nm></Cdtr><CdtrAcct><Id><IBAN>%s</IBAN></Id></CdtrAcct></RltdPties>`, xmlEscape(tx.Name), xmlEscape(tx.IBAN))
+		fmt.Fprintf(&buf, `<RmtInf><Ustrd>%s</Ustrd></RmtInf></TxDtls></NtryDtls></Ntry>`, xmlEscape(tx.Purpose))
+	}
+	buf.WriteString(`</Rpt></BkToCstmrAcctRpt></Document>`)
+	return buf.String()
+}
+
+func debitCredit(amount domain.Amount) string {
+	if amount.Sign() < 0 {
+		return "D"
+	}
+	return "C"
+}
+
+func transactionCode(tx Transaction) int {
+	if tx.TransactionCode != 0 {
+		return tx.TransactionCode
+	}
+	if tx.Amount.Sign() < 0 {
+		return 20
+	}
+	return 166
+}
+
+// chunk splits s into at most max parts of the given size.
+func chunk(s string, size, max int) []string {
+	var parts []string
+	runes := []rune(s)
+	for len(runes) > 0 && len(parts) < max {
+		n := size
+		if len(runes) < n {
+			n = len(runes)
+		}
+		parts = append(parts, string(runes[:n]))
+		runes = runes[n:]
+	}
+	return parts
+}
+
+var xmlReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+func xmlEscape(s string) string {
+	return xmlReplacer.Replace(s)
+}
@@ -0,0 +1,282 @@
+package fintstest
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitch000001/go-hbci/charset"
+	"github.com/mitch000001/go-hbci/element"
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// rawSegment represents a segment of a client message split into its data
+// elements. The first element is the segment header.
+type rawSegment struct {
+	ID       string
+	Number   int
+	Version  int
+	elements [][]byte
+}
+
+// Element returns the unescaped data element at position i, where 1 is the
+// first element after the segment header.
+func (r rawSegment) Element(i int) string {
+	if i >= len(r.elements) {
+		return ""
+	}
+	return unescape(r.elements[i])
+}
+
+// Group returns the unescaped elements of the data element group at position i.
+func (r rawSegment) Group(i int) []string {
+	if i >= len(r.elements) || len(r.elements[i]) == 0 {
+		return nil
+	}
+	elements, err := element.ExtractElements(r.elements[i])
+	if err != nil {
+		return []string{r.Element(i)}
+	}
+	group := make([]string, len(elements))
+	for j, e := range elements {
+		group[j] = unescape(e)
+	}
+	return group
+}
+
+func parseRawSegment(seg []byte) (rawSegment, error) {
+	elements, err := segment.ExtractElements(seg)
+	if err != nil {
+		return rawSegment{}, err
+	}
+	if len(elements) == 0 {
+		return rawSegment{}, fmt.Errorf("empty segment")
+	}
+	header := strings.Split(string(elements[0]), ":")
+	if len(header) < 3 {
+		return rawSegment{}, fmt.Errorf("malformed segment header %q", elements[0])
+	}
+	number, err := strconv.Atoi(header[1])
+	if err != nil {
+		return rawSegment{}, fmt.Errorf("malformed segment number %q", header[1])
+	}
+	version, err := strconv.Atoi(header[2])
+	if err != nil {
+		return rawSegment{}, fmt.Errorf("malformed segment version %q", header[2])
+	}
+	return rawSegment{ID: header[0], Number: number, Version: version, elements: elements}, nil
+}
+
+// request represents a message sent by the client.
+type request struct {
+	DialogID      string
+	MessageNumber int
+	PIN           string
+	TAN           string
+	Segments      []rawSegment
+}
+
+// Find returns the first segment with the given ID.
+func (r *request) Find(id string) (rawSegment, bool) {
+	for _, seg := range r.Segments {
+		if seg.ID == id {
+			return seg, true
+		}
+	}
+	return rawSegment{}, false
+}
+
+func parseRequest(body []byte) (*request, error) {
+	outer, err := extractSegments(body)
+	if err != nil {
+		return nil, err
+	}
+	if len(outer) == 0 || outer[0].ID != segment.MessageHeaderID {
+		return nil, fmt.Errorf("missing message header")
+	}
+	req := &request{DialogID: outer[0].Element(3)}
+	req.MessageNumber, err = strconv.Atoi(outer[0].Element(4))
+	if err != nil {
+		return nil, fmt.Errorf("malformed message number: %w", err)
+	}
+	segments := outer
+	for _, seg := range outer {
+		if seg.ID != "HNVSD" {
+			continue
+		}
+		encryptedData := &element.BinaryDataElement{}
+		if err := encryptedData.UnmarshalHBCI(seg.elements[1]); err != nil {
+			return nil, fmt.Errorf("malformed encrypted data: %w", err)
+		}
+		segments, err = extractSegments(encryptedData.Val())
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, seg := range segments {
+		if seg.ID == "HNSHA" {
+			pinTan := seg.Group(3)
+			if len(pinTan) > 0 {
+				req.PIN = pinTan[0]
+			}
+			if len(pinTan) > 1 {
+				req.TAN = pinTan[1]
+			}
+			continue
+		}
+		switch seg.ID {
+		case segment.MessageHeaderID, "HNHBS", "HNVSK", "HNSHK":
+			continue
+		}
+		req.Segments = append(req.Segments, seg)
+	}
+	return req, nil
+}
+
+func extractSegments(body []byte) ([]rawSegment, error) {
+	rawSegments, err := message.NewSegmentExtractor(body).Extract()
+	if err != nil {
+		return nil, err
+	}
+	segments := make([]rawSegment, len(rawSegments))
+	for i, raw := range rawSegments {
+		segments[i], err = parseRawSegment(raw)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return segments, nil
+}
+
+// ack represents a return code sent within HIRMG or HIRMS.
+type ack struct {
+	Code   int
+	Text   string
+	Params []string
+}
+
+func (a ack) marshal() string {
+	fields := []string{fmt.Sprintf("%04d", a.Code), "", escape(a.Text)}
+	for _, param := range a.Params {
+		fields = append(fields, escape(param))
+	}
+	return strings.Join(fields, ":")
+}
+
+// responseSegment represents a segment of the response message. The segment
+// number is assigned when marshaling the message.
+type responseSegment struct {
+	ID        string
+	Version   int
+	Reference int
+	Elements  []string
+}
+
+// response represents a message sent by the server.
+type response struct {
+	messageAcks []ack
+	segments    []responseSegment
+}
+
+// AddMessageAck adds a return code related to the whole message.
+func (r *response) AddMessageAck(a ack) {
+	r.messageAcks = append(r.messageAcks, a)
+}
+
+// AddSegmentAcks adds return codes related to seg.
+func (r *response) AddSegmentAcks(seg rawSegment, acks ...ack) {
+	elements := make([]string, len(acks))
+	for i, a := range acks {
+		elements[i] = a.marshal()
+	}
+	r.segments = append(r.segments, responseSegment{ID: "HIRMS", Version: 2, Reference: seg.Number, Elements: elements})
+}
+
+// Add adds a segment with already escaped elements.
+func (r *response) Add(id string, version, reference int, elements ...string) {
+	r.segments = append(r.segments, responseSegment{ID: id, Version: version, Reference: reference, Elements: elements})
+}
+
+// HasError returns true if any message acknowledgement is an error.
+func (r *response) HasError() bool {
+	for _, a := range r.messageAcks {
+		if a.Code >= 9000 {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *response) marshal(dialogID string, messageNumber int, bankID, userID, systemID string) []byte {
+	var inner bytes.Buffer
+	messageAcks := r.messageAcks
+	if len(messageAcks) == 0 {
+		messageAcks = []ack{{Code: 10, Text: "Nachricht entgegengenommen."}}
+	}
+	ackElements := make([]string, len(messageAcks))
+	for i, a := range messageAcks {
+		ackElements[i] = a.marshal()
+	}
+	segments := append([]responseSegment{{ID: "HIRMG", Version: 2, Elements: ackElements}}, r.segments...)
+	for i, seg := range segments {
+		header := fmt.Sprintf("%s:%d:%d", seg.ID, i+2, seg.Version)
+		if seg.Reference != 0 {
+			header += ":" + strconv.Itoa(seg.Reference)
+		}
+		inner.WriteString(header)
+		for _, e := range seg.Elements {
+			inner.WriteString("+")
+			inner.WriteString(e)
+		}
+		inner.WriteString("'")
+	}
+	innerBytes := charset.ToISO8859_1(inner.String())
+
+	now := time.Now()
+	encryptionHeader := fmt.Sprintf(
+		"HNVSK:998:3+PIN:1+998+1+2::%s+1:%s:%s+2:2:13:@8@%s:5:1+280:%s:%s:V:0:0+0'",
+		escape(systemID), now.Format("20060102"), now.Format("150405"),
+		"\x00\x00\x00\x00\x00\x00\x00\x00", escape(bankID), escape(userID),
+	)
+	encryptedData := fmt.Sprintf("HNVSD:999:1+@%d@", len(innerBytes))
+	messageEnd := fmt.Sprintf("HNHBS:%d:1+%d'", len(segments)+2, messageNumber)
+	headerSuffix := fmt.Sprintf("+300+%s+%d+%s:%d'", escape(dialogID), messageNumber, escape(dialogID), messageNumber)
+	length := len("HNHBK:1:3+") + 12 + len(headerSuffix) + len(encryptionHeader) + len(encryptedData) + len(innerBytes) + 1 + len(messageEnd)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "HNHBK:1:3+%012d%s", length, headerSuffix)
+	buf.WriteString(encryptionHeader)
+	buf.WriteString(encryptedData)
+	buf.Write(innerBytes)
+	buf.WriteString("'")
+	buf.WriteString(messageEnd)
+	return buf.Bytes()
+}
+
+var escapeReplacer = strings.NewReplacer("?", "??", "+", "?+", ":", "?:", "'", "?'", "@", "?@")
+
+// escape escapes all syntax characters within s.
+func escape(s string) string {
+	return escapeReplacer.Replace(s)
+}
+
+// unescape removes all escape characters from b and converts it to UTF-8.
+func unescape(b []byte) string {
+	var buf bytes.Buffer
+	for i := 0; i < len(b); i++ {
+		if b[i] == '?' && i+1 < len(b) {
+			i++
+		}
+		buf.WriteByte(b[i])
+	}
+	return charset.ToUTF8(buf.Bytes())
+}
+
+// binary returns data marshaled as binary data element. The length is
+// computed from the ISO-8859-1 encoding used on the wire.
+func binary(data string) string {
+	return fmt.Sprintf("@%d@%s", len(charset.ToISO8859_1(data)), data)
+}
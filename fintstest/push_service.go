@@ -0,0 +1,158 @@
+package fintstest
+
+import (
+	"sort"
+	"strconv"
+)
+
+// pushServiceSegmentIDs lists the business transactions the server offers
+// push services for.
+var pushServiceSegmentIDs = []string{"HKCAZ", "HKTAN"}
+
+// pushRegistration represents a client product registered for push services.
+type pushRegistration struct {
+	productName    string
+	productVersion string
+	manufacturer   string
+	clientSystem   string
+	token          string
+	segmentIDs     []string
+}
+
+// PushRegistrations returns the segment IDs registered for push services,
+// keyed by client system name.
+func (s *Server) PushRegistrations() map[string][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	registrations := make(map[string][]string)
+	for name, r := range s.pushRegistrations {
+		registrations[name] = append([]string(nil), r.segmentIDs...)
+	}
+	return registrations
+}
+
+func (s *Server) pushSegmentIDs(seg rawSegment, start int) ([]string, *ack) {
+	var segmentIDs []string
+	for i := start; i < len(seg.elements); i++ {
+		id := seg.Element(i)
+		if id == "" {
+			continue
+		}
+		supported := false
+		for _, supportedID := range pushServiceSegmentIDs {
+			supported = supported || id == supportedID
+		}
+		if !supported {
+			return nil, &ack{Code: 9210, Text: "Push-Service für " + id + " nicht unterstützt."}
+		}
+		segmentIDs = append(segmentIDs, id)
+	}
+	return segmentIDs, nil
+}
+
+func (s *Server) pushServiceResponse(id string, seg rawSegment, r *pushRegistration, res *response) {
+	elements := []string{escape(r.token), ""}
+	for _, segmentID := range r.segmentIDs {
+		elements = append(elements, escape(segmentID))
+	}
+	res.Add(id, 1, seg.Number, elements...)
+}
+
+func (s *Server) registerPushServices(seg rawSegment, res *response) *ack {
+	if s.config.PushServiceURL == "" {
+		return &ack{Code: 9010, Text: "Geschäftsvorfall nicht unterstützt."}
+	}
+	segmentIDs, errAck := s.pushSegmentIDs(seg, 5)
+	if errAck != nil {
+		return errAck
+	}
+	r := &pushRegistration{
+		productName:    seg.Element(1),
+		productVersion: seg.Element(2),
+		manufacturer:   seg.Element(3),
+		clientSystem:   seg.Element(4),
+		token:          s.nextID("TOKEN"),
+		segmentIDs:     segmentIDs,
+	}
+	if r.clientSystem == "" {
+		return &ack{Code: 9160, Text: "Kundensystemname fehlt."}
+	}
+	s.pushRegistrations[r.clientSystem] = r
+	res.AddSegmentAcks(seg, ack{Code: 20, Text: "Registrierung erfolgt."})
+	s.pushServiceResponse("HIPUR", seg, r, res)
+	return nil
+}
+
+func (s *Server) changePushServices(seg rawSegment, res *response) *ack {
+	var r *pushRegistration
+	for _, registration := range s.pushRegistrations {
+		if registration.token == seg.Element(1) {
+			r = registration
+		}
+	}
+	if r == nil || r.clientSystem != seg.Element(5) {
+		return &ack{Code: 9210, Text: "Token ungültig."}
+	}
+	segmentIDs, errAck := s.pushSegmentIDs(seg, 6)
+	if errAck != nil {
+		return errAck
+	}
+	if len(segmentIDs) == 0 {
+		delete(s.pushRegistrations, r.clientSystem)
+		res.AddSegmentAcks(seg, ack{Code: 20, Text: "Registrierung aufgehoben."})
+		return nil
+	}
+	for i, field := range []*string{&r.productName, &r.productVersion, &r.manufacturer} {
+		if value := seg.Element(i + 2); value != "" {
+			*field = value
+		}
+	}
+	r.token = s.nextID("TOKEN")
+	r.segmentIDs = segmentIDs
+	res.AddSegmentAcks(seg, ack{Code: 20, Text: "Registrierung geändert."})
+	s.pushServiceResponse("HIPUA", seg, r, res)
+	return nil
+}
+
+func (s *Server) pushServiceClientProducts(seg rawSegment, res *response) *ack {
+	var names []string
+	for name := range s.pushRegistrations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		res.AddSegmentAcks(seg, ack{Code: 3010, Text: "Keine Registrierungen vorhanden."})
+		return nil
+	}
+	maxEntries, _ := strconv.Atoi(seg.Element(1))
+	offset, end, next, errAck := s.page(seg.Element(2), len(names), maxEntries)
+	if errAck != nil {
+		return errAck
+	}
+	acks := []ack{{Code: 20, Text: "Auftrag ausgeführt."}}
+	if next != "" {
+		acks = append(acks, continuationAck(next))
+	}
+	res.AddSegmentAcks(seg, acks...)
+	for _, name := range names[offset:end] {
+		r := s.pushRegistrations[name]
+		res.Add(
+			"HIPRB", 1, seg.Number,
+			escape(r.productName),
+			escape(r.productVersion),
+			escape(r.manufacturer),
+			escape(r.clientSystem),
+		)
+	}
+	return nil
+}
+
+func (s *Server) deregisterPushServices(seg rawSegment, res *response) *ack {
+	name := seg.Element(1)
+	if _, ok := s.pushRegistrations[name]; !ok {
+		return &ack{Code: 9210, Text: "Kundensystem nicht registriert."}
+	}
+	delete(s.pushRegistrations, name)
+	res.AddSegmentAcks(seg, ack{Code: 20, Text: "Registrierung gelöscht."})
+	return nil
+}
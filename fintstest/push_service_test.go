@@ -0,0 +1,106 @@
+package fintstest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+func TestServerPushServices(t *testing.T) {
+	server := NewServer(Config{
+		Accounts:       []Account{testAccount()},
+		PageSize:       1,
+		PushServiceURL: "wss://push.example.com/fints",
+	})
+	defer server.Close()
+
+	c := newTestClient(t, server)
+
+	params, ok, err := c.PushServiceParameters()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	expectedParams := domain.PushServiceParameters{
+		WebSocketURL:    "wss://push.example.com/fints",
+		PollingInterval: 60 * time.Second,
+		UseUserID:       true,
+		SegmentIDs:      []string{"HKCAZ", "HKTAN"},
+	}
+	if !ok || !reflect.DeepEqual(expectedParams, params) {
+		t.Logf("Expected push service parameters to equal\n%+#v\n\tgot\n%+#v (%t)\n", expectedParams, params, ok)
+		t.Fail()
+	}
+
+	laptop := domain.PushServiceClientProduct{
+		ProductName:      "go-hbci",
+		ProductVersion:   "1.0",
+		Manufacturer:     "mitch000001",
+		ClientSystemName: "Laptop",
+	}
+	registration, err := c.RegisterPushServices(laptop, []string{"HKCAZ"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if registration.Token == "" {
+		t.Logf("Expected registration to contain a token\n")
+		t.Fail()
+	}
+	phone := laptop
+	phone.ClientSystemName = "Phone"
+	_, err = c.RegisterPushServices(phone, []string{"HKTAN"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	updated, err := c.UpdatePushServices(registration.Token, laptop, []string{"HKCAZ", "HKTAN"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if updated.Token == "" || updated.Token == registration.Token {
+		t.Logf("Expected token to be refreshed, got %q\n", updated.Token)
+		t.Fail()
+	}
+	if !reflect.DeepEqual([]string{"HKCAZ", "HKTAN"}, updated.SegmentIDs) {
+		t.Logf("Expected registered segment IDs to equal [HKCAZ HKTAN], got %v\n", updated.SegmentIDs)
+		t.Fail()
+	}
+
+	products, err := c.PushServiceClientProducts()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if !reflect.DeepEqual([]domain.PushServiceClientProduct{laptop, phone}, products) {
+		t.Logf("Expected client products to equal\n%+#v\n\tgot\n%+#v\n", []domain.PushServiceClientProduct{laptop, phone}, products)
+		t.Fail()
+	}
+
+	err = c.DeregisterPushServices("Phone")
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	expectedRegistrations := map[string][]string{"Laptop": {"HKCAZ", "HKTAN"}}
+	if !reflect.DeepEqual(expectedRegistrations, server.PushRegistrations()) {
+		t.Logf("Expected registrations to equal %v, got %v\n", expectedRegistrations, server.PushRegistrations())
+		t.Fail()
+	}
+	if server.OpenDialogs() != 0 {
+		t.Logf("Expected all dialogs to be ended, got %d open dialogs\n", server.OpenDialogs())
+		t.Fail()
+	}
+}
+
+func TestServerWithoutPushServices(t *testing.T) {
+	server := NewServer(Config{Accounts: []Account{testAccount()}})
+	defer server.Close()
+
+	_, ok, err := newTestClient(t, server).PushServiceParameters()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if ok {
+		t.Logf("Expected no push service parameters\n")
+		t.Fail()
+	}
+}
@@ -0,0 +1,146 @@
+package fintstest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/client"
+	"github.com/mitch000001/go-hbci/dialog"
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/transport"
+	https "github.com/mitch000001/go-hbci/transport/https"
+)
+
+func newRecoveryTestClient(t *testing.T, server *Server, recovery *dialog.RecoveryPolicy, tr transport.Transport) *client.Client {
+	c, err := client.New(client.Config{
+		URL:         server.URL,
+		BankID:      server.BankID(),
+		AccountID:   server.UserID(),
+		PIN:         server.PIN(),
+		HBCIVersion: 300,
+		Transport:   tr,
+		Recovery:    recovery,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	return c
+}
+
+// abortingTransport aborts all dialogs of server before sending the first
+// message containing a segment with the given ID.
+func abortingTransport(server *Server, segmentID string) transport.Transport {
+	aborted := false
+	return transport.Func(func(req *transport.Request) (*transport.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		decoded, err := base64.StdEncoding.DecodeString(string(body))
+		if err != nil {
+			return nil, err
+		}
+		if !aborted && bytes.Contains(decoded, []byte("'"+segmentID+":")) {
+			aborted = true
+			server.AbortDialogs()
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		return https.New().Do(req)
+	})
+}
+
+func TestServerDialogRecovery(t *testing.T) {
+	account := testAccount()
+	server := NewServer(Config{Accounts: []Account{account}, PageSize: 2})
+	defer server.Close()
+
+	var causes []error
+	var recoveryErrs []error
+	recovery := &dialog.RecoveryPolicy{
+		MaxAttempts:    1,
+		BeforeRecovery: func(attempt int, cause error) { causes = append(causes, cause) },
+		AfterRecovery:  func(attempt int, err error) { recoveryErrs = append(recoveryErrs, err) },
+	}
+	c := newRecoveryTestClient(t, server, recovery, nil)
+
+	timeframe := domain.Timeframe{
+		StartDate: domain.Date(2020, 3, 1, time.UTC),
+		EndDate:   domain.Date(2020, 3, 31, time.UTC),
+	}
+	it := c.SepaTransactionsIter(internationalAccount(account), timeframe, false)
+	defer it.Close()
+
+	var transactions []domain.AccountTransaction
+	for it.Next() {
+		transactions = append(transactions, it.Page()...)
+		// simulate a dialog timeout between the pages
+		server.AbortDialogs()
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	if len(transactions) != 3 {
+		t.Logf("Expected 3 transactions, got %d\n", len(transactions))
+		t.Fail()
+	}
+	if len(causes) != 1 || !errors.Is(causes[0], domain.ErrDialogAborted) {
+		t.Logf("Expected one recovery from an aborted dialog, got %v\n", causes)
+		t.Fail()
+	}
+	if len(recoveryErrs) != 1 || recoveryErrs[0] != nil {
+		t.Logf("Expected one successful recovery, got %v\n", recoveryErrs)
+		t.Fail()
+	}
+}
+
+func TestServerDialogRecoveryDisabled(t *testing.T) {
+	account := testAccount()
+	server := NewServer(Config{Accounts: []Account{account}})
+	defer server.Close()
+
+	c := newRecoveryTestClient(t, server, &dialog.RecoveryPolicy{}, abortingTransport(server, "HKSAL"))
+
+	_, err := c.SepaAccountBalances(internationalAccount(account), false, "")
+
+	if !errors.Is(err, domain.ErrDialogAborted) {
+		t.Logf("Expected error to be ErrDialogAborted, got %T:%v\n", err, err)
+		t.Fail()
+	}
+}
+
+func TestServerDialogRecoveryOnlyRetriesReadOnlyJobs(t *testing.T) {
+	server := NewServer(Config{
+		Accounts:       []Account{testAccount()},
+		PushServiceURL: "wss://push.example.com/fints",
+	})
+	defer server.Close()
+
+	c := newRecoveryTestClient(t, server, &dialog.RecoveryPolicy{MaxAttempts: 3}, abortingTransport(server, "HKPUR"))
+	product := domain.PushServiceClientProduct{ClientSystemName: "Laptop"}
+
+	_, err := c.RegisterPushServices(product, []string{"HKCAZ"})
+
+	if !errors.Is(err, domain.ErrDialogAborted) {
+		t.Logf("Expected error to be ErrDialogAborted, got %T:%v\n", err, err)
+		t.Fail()
+	}
+	var registrations int
+	for _, id := range server.ReceivedSegments() {
+		if id == "HKPUR" {
+			registrations++
+		}
+	}
+	if registrations != 1 {
+		t.Logf("Expected HKPUR to be sent once, got %d\n", registrations)
+		t.Fail()
+	}
+	if server.OpenDialogs() != 0 {
+		t.Logf("Expected no open dialogs, got %d\n", server.OpenDialogs())
+		t.Fail()
+	}
+}
@@ -0,0 +1,688 @@
+package fintstest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+// TANMode defines how the Server authorizes business transactions.
+type TANMode int
+
+const (
+	// TANModeNone executes business transactions without TAN
+	TANModeNone TANMode = iota
+	// TANModeProcess4 answers business transactions with a TAN challenge
+	// which has to be answered with TAN process 2 and Config.TAN
+	TANModeProcess4
+	// TANModeDecoupled answers business transactions with a challenge which
+	// is approved out of band via Server.Approve. The client polls the status
+	// with TAN process S.
+	TANModeDecoupled
+)
+
+// Defaults used for zero values within Config.
+const (
+	DefaultBankID   = "10000000"
+	DefaultBankName = "fintstest"
+	DefaultUserID   = "testuser"
+	DefaultPIN      = "12345"
+	DefaultTAN      = "123456"
+)
+
+// Config configures the simulated bank institute.
+type Config struct {
+	BankID   string
+	BankName string
+	UserID   string
+	PIN      string
+	// BPDVersion and UPDVersion are the versions of the bank and user
+	// parameter data. Clients with older versions receive the parameter data
+	// in the dialog initialization.
+	BPDVersion int
+	UPDVersion int
+	Accounts   []Account
+	// PageSize limits the number of entries per response. Larger results
+	// are split into pages linked by continuation references. Zero means
+	// unlimited.
+	PageSize int
+	TANMode  TANMode
+	// TAN is the valid TAN for TANModeProcess4.
+	TAN string
+	// TANRequired lists the segment IDs needing a TAN. It defaults to all
+	// business transactions if TANMode is not TANModeNone.
+	TANRequired []string
+	// PushServiceURL is the WebSocket URL announced in the push service
+	// parameters. The server offers push services only if it is set.
+	PushServiceURL string
+}
+
+func (c *Config) setDefaults() {
+	if c.BankID == "" {
+		c.BankID = DefaultBankID
+	}
+	if c.BankName == "" {
+		c.BankName = DefaultBankName
+	}
+	if c.UserID == "" {
+		c.UserID = DefaultUserID
+	}
+	if c.PIN == "" {
+		c.PIN = DefaultPIN
+	}
+	if c.TAN == "" {
+		c.TAN = DefaultTAN
+	}
+	if c.BPDVersion == 0 {
+		c.BPDVersion = 1
+	}
+	if c.UPDVersion == 0 {
+		c.UPDVersion = 1
+	}
+	if c.TANMode != TANModeNone && len(c.TANRequired) == 0 {
+		c.TANRequired = []string{"HKSAL", "HKKAZ", "HKCAZ"}
+	}
+}
+
+// NewServer starts and returns a new Server. The caller should call Close when
+// finished, to shut it down.
+func NewServer(config Config) *Server {
+	config.setDefaults()
+	s := &Server{
+		config:            config,
+		dialogs:           make(map[string]*dialogState),
+		challenges:        make(map[string]*challenge),
+		pushRegistrations: make(map[string]*pushRegistration),
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// Server is a FinTS 3.0 PIN/TAN server simulating a bank institute.
+type Server struct {
+	*httptest.Server
+	config     Config
+	mu         sync.Mutex
+	dialogs    map[string]*dialogState
+	challenges map[string]*challenge
+	sequence   int
+	received   []string
+	// pushRegistrations holds the client products registered for push
+	// services, keyed by client system name
+	pushRegistrations map[string]*pushRegistration
+}
+
+type dialogState struct {
+	systemID      string
+	messageNumber int
+	// pin is the PIN valid when the dialog was initialized. A changed PIN
+	// takes effect with the next dialog.
+	pin string
+}
+
+// challenge represents a business transaction waiting for a TAN.
+type challenge struct {
+	reference string
+	dialogID  string
+	segments  []rawSegment
+	approved  bool
+}
+
+// BankID returns the bank ID of the simulated bank institute.
+func (s *Server) BankID() string { return s.config.BankID }
+
+// UserID returns the user ID accepted by the server.
+func (s *Server) UserID() string { return s.config.UserID }
+
+// PIN returns the PIN currently accepted by the server.
+func (s *Server) PIN() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.config.PIN
+}
+
+// TAN returns the TAN accepted by the server.
+func (s *Server) TAN() string { return s.config.TAN }
+
+// ReceivedSegments returns the IDs of all segments received so far, in order.
+func (s *Server) ReceivedSegments() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	received := make([]string, len(s.received))
+	copy(received, s.received)
+	return received
+}
+
+// OpenDialogs returns the number of dialogs not ended yet.
+func (s *Server) OpenDialogs() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.dialogs)
+}
+
+// PendingChallenges returns the job references of all TAN challenges not
+// answered yet.
+func (s *Server) PendingChallenges() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var references []string
+	for reference := range s.challenges {
+		references = append(references, reference)
+	}
+	return references
+}
+
+// Approve approves the decoupled TAN challenge with the given job reference,
+// as if the user confirmed it within the banking app.
+func (s *Server) Approve(jobReference string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.challenges[jobReference]
+	if !ok {
+		return fmt.Errorf("unknown job reference %q", jobReference)
+	}
+	c.approved = true
+	return nil
+}
+
+// AbortDialogs drops all open dialogs, as if they timed out. The server
+// answers further messages within those dialogs with 9800.
+func (s *Server) AbortDialogs() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dialogs = make(map[string]*dialogState)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, r.Body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	res := s.handle(body)
+	s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/vnd.hbci")
+	encoder := base64.NewEncoder(base64.StdEncoding, w)
+	_, _ = io.Copy(encoder, bytes.NewReader(res))
+	_ = encoder.Close()
+}
+
+func (s *Server) handle(body []byte) []byte {
+	req, err := parseRequest(body)
+	if err != nil {
+		res := &response{}
+		res.AddMessageAck(ack{Code: 9110, Text: "Unbekannter Aufbau: " + err.Error()})
+		return res.marshal("0", 1, s.config.BankID, s.config.UserID, "0")
+	}
+	for _, seg := range req.Segments {
+		s.received = append(s.received, seg.ID)
+	}
+	res := &response{}
+	dialogID := req.DialogID
+	var systemID string
+	if identification, ok := req.Find("HKIDN"); ok {
+		dialogID, systemID = s.initDialog(req, identification, res)
+	} else {
+		systemID = s.continueDialog(req, res)
+	}
+	return res.marshal(dialogID, req.MessageNumber, s.config.BankID, s.config.UserID, systemID)
+}
+
+func (s *Server) nextID(prefix string) string {
+	s.sequence++
+	return fmt.Sprintf("%s%08d", prefix, s.sequence)
+}
+
+func (s *Server) initDialog(req *request, identification rawSegment, res *response) (string, string) {
+	bankID := identification.Group(1)
+	if len(bankID) < 2 || bankID[1] != s.config.BankID {
+		res.AddMessageAck(ack{Code: 9800, Text: "Dialog abgebrochen"})
+		res.AddSegmentAcks(identification, ack{Code: 9210, Text: "Kreditinstitut unbekannt"})
+		return "0", "0"
+	}
+	if identification.Element(2) != s.config.UserID || req.PIN != s.config.PIN {
+		res.AddMessageAck(ack{Code: 9800, Text: "Dialog abgebrochen"})
+		res.AddSegmentAcks(identification, ack{Code: 9942, Text: "PIN falsch"})
+		return "0", "0"
+	}
+	systemID := identification.Element(3)
+	if sync, ok := req.Find("HKSYN"); ok {
+		systemID = s.nextID("SYS")
+		res.AddSegmentAcks(sync, ack{Code: 20, Text: "Auftrag ausgeführt."})
+		res.Add("HISYN", sync.Version+1, sync.Number, escape(systemID))
+	}
+	dialogID := s.nextID("DLG")
+	s.dialogs[dialogID] = &dialogState{systemID: systemID, messageNumber: req.MessageNumber, pin: s.config.PIN}
+
+	identificationAcks := []ack{{Code: 20, Text: "Auftrag ausgeführt."}}
+	if preparation, ok := req.Find("HKVVB"); ok {
+		var preparationAcks []ack
+		bpdVersion, _ := strconv.Atoi(preparation.Element(1))
+		updVersion, _ := strconv.Atoi(preparation.Element(2))
+		if bpdVersion < s.config.BPDVersion {
+			s.addBankParameterData(res, preparation.Number)
+			preparationAcks = append(preparationAcks, ack{Code: 3050, Text: "BPD nicht mehr aktuell, aktuelle Version enthalten."})
+			if s.config.TANMode != TANModeNone {
+				identificationAcks = append(identificationAcks, ack{
+					Code:   domain.ReturnCodeSupportedSecurityFunction,
+					Text:   "Zugelassene Zwei-Schritt-Verfahren für den Benutzer.",
+					Params: []string{s.securityFunction()},
+				})
+			}
+		}
+		if updVersion < s.config.UPDVersion {
+			s.addUserParameterData(res, preparation.Number)
+			preparationAcks = append(preparationAcks, ack{Code: 3050, Text: "UPD nicht mehr aktuell, aktuelle Version enthalten."})
+		}
+		if len(preparationAcks) == 0 {
+			preparationAcks = append(preparationAcks, ack{Code: 20, Text: "Informationen fehlerfrei entgegengenommen."})
+		}
+		res.AddSegmentAcks(preparation, preparationAcks...)
+	}
+	res.AddSegmentAcks(identification, identificationAcks...)
+	res.AddMessageAck(ack{Code: 10, Text: "Nachricht entgegengenommen."})
+	return dialogID, systemID
+}
+
+func (s *Server) continueDialog(req *request, res *response) string {
+	state, ok := s.dialogs[req.DialogID]
+	if !ok {
+		res.AddMessageAck(ack{Code: 9800, Text: "Dialog abgebrochen - Dialog unbekannt"})
+		return "0"
+	}
+	if req.MessageNumber != state.messageNumber+1 {
+		delete(s.dialogs, req.DialogID)
+		res.AddMessageAck(ack{Code: 9800, Text: "Dialog abgebrochen - Nachrichtennummer ungültig"})
+		return state.systemID
+	}
+	state.messageNumber = req.MessageNumber
+	if req.PIN != state.pin {
+		delete(s.dialogs, req.DialogID)
+		res.AddMessageAck(ack{Code: 9800, Text: "Dialog abgebrochen"})
+		if len(req.Segments) > 0 {
+			res.AddSegmentAcks(req.Segments[0], ack{Code: 9942, Text: "PIN falsch"})
+		}
+		return state.systemID
+	}
+	if end, ok := req.Find("HKEND"); ok {
+		delete(s.dialogs, req.DialogID)
+		res.AddMessageAck(ack{Code: 100, Text: "Dialog beendet."})
+		res.AddSegmentAcks(end, ack{Code: 20, Text: "Auftrag ausgeführt."})
+		return state.systemID
+	}
+	if tan, ok := req.Find("HKTAN"); ok && tan.Element(1) != "4" {
+		s.answerChallenge(req, tan, res)
+	} else {
+		s.execute(req, req.Segments, res)
+	}
+	if res.HasError() {
+		return state.systemID
+	}
+	if len(res.messageAcks) == 0 {
+		res.AddMessageAck(ack{Code: 10, Text: "Nachricht entgegengenommen."})
+	}
+	return state.systemID
+}
+
+// execute processes all business transactions within segments.
+func (s *Server) execute(req *request, segments []rawSegment, res *response) {
+	var tan rawSegment
+	var hasTan bool
+	for _, seg := range segments {
+		if seg.ID == "HKTAN" {
+			tan, hasTan = seg, true
+		}
+	}
+	var business []rawSegment
+	for _, seg := range segments {
+		switch seg.ID {
+		case "HKTAN":
+			continue
+		}
+		business = append(business, seg)
+	}
+	if hasTan && s.needsTan(business) {
+		s.issueChallenge(req, tan, business, res)
+		return
+	}
+	if !hasTan && s.needsTan(business) {
+		res.AddMessageAck(ack{Code: 9050, Text: "Die Nachricht enthält Fehler."})
+		res.AddSegmentAcks(business[0], ack{Code: domain.ReturnCodeStrongAuthenticationRequired, Text: "Starke Kundenauthentifizierung notwendig."})
+		return
+	}
+	if hasTan {
+		res.AddSegmentAcks(tan, ack{Code: 20, Text: "Auftrag ausgeführt."})
+	}
+	s.executeBusinessTransactions(business, res)
+}
+
+func (s *Server) needsTan(segments []rawSegment) bool {
+	for _, seg := range segments {
+		for _, id := range s.config.TANRequired {
+			if seg.ID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *Server) executeBusinessTransactions(segments []rawSegment, res *response) {
+	for _, seg := range segments {
+		var err *ack
+		switch seg.ID {
+		case "HKSAL":
+			err = s.balances(seg, res)
+		case "HKKAZ":
+			err = s.transactions(seg, res)
+		case "HKCAZ":
+			err = s.camtTransactions(seg, res)
+		case "HKPAE":
+			err = s.changePIN(seg, res)
+		case "HKPUR":
+			err = s.registerPushServices(seg, res)
+		case "HKPUA":
+			err = s.changePushServices(seg, res)
+		case "HKPRB":
+			err = s.pushServiceClientProducts(seg, res)
+		case "HKPRL":
+			err = s.deregisterPushServices(seg, res)
+		default:
+			err = &ack{Code: 9010, Text: "Geschäftsvorfall nicht unterstützt."}
+		}
+		if err != nil {
+			res.AddSegmentAcks(seg, *err)
+			res.AddMessageAck(ack{Code: 9050, Text: "Die Nachricht enthält Fehler."})
+		}
+	}
+}
+
+func (s *Server) issueChallenge(req *request, tan rawSegment, segments []rawSegment, res *response) {
+	reference := s.nextID("JOB")
+	s.challenges[reference] = &challenge{reference: reference, dialogID: req.DialogID, segments: segments}
+	var tanAck ack
+	var challengeText string
+	switch s.config.TANMode {
+	case TANModeDecoupled:
+		tanAck = ack{Code: 3955, Text: "Sicherheitsfreigabe erfolgt über anderen Kanal."}
+		challengeText = "Bitte bestätigen Sie den Auftrag in Ihrer Banking-App."
+	default:
+		tanAck = ack{Code: 30, Text: "Auftrag empfangen - Sicherheitsfreigabe erforderlich."}
+		challengeText = "Bitte geben Sie die TAN ein."
+	}
+	res.AddMessageAck(ack{Code: 3060, Text: "Bitte beachten Sie die enthaltenen Warnungen/Hinweise."})
+	res.AddSegmentAcks(tan, tanAck)
+	res.Add("HITAN", 6, tan.Number, "4", "", escape(reference), escape(challengeText))
+}
+
+func (s *Server) answerChallenge(req *request, tan rawSegment, res *response) {
+	process := tan.Element(1)
+	var c *challenge
+	for i := 2; i < len(tan.elements); i++ {
+		if found, ok := s.challenges[tan.Element(i)]; ok {
+			c = found
+			break
+		}
+	}
+	if c == nil || c.dialogID != req.DialogID {
+		res.AddMessageAck(ack{Code: 9050, Text: "Die Nachricht enthält Fehler."})
+		res.AddSegmentAcks(tan, ack{Code: 9210, Text: "Auftragsreferenz unbekannt."})
+		return
+	}
+	switch process {
+	case "2":
+		if s.config.TANMode != TANModeProcess4 {
+			break
+		}
+		if req.TAN != s.config.TAN {
+			res.AddMessageAck(ack{Code: 9050, Text: "Die Nachricht enthält Fehler."})
+			res.AddSegmentAcks(tan, ack{Code: 9941, Text: "TAN ungültig."})
+			return
+		}
+		c.approved = true
+	case "S":
+		if s.config.TANMode != TANModeDecoupled {
+			break
+		}
+		if !c.approved {
+			res.AddSegmentAcks(tan, ack{Code: 3956, Text: "Starke Kundenauthentifizierung noch ausstehend."})
+			res.Add("HITAN", 6, tan.Number, "S", "", escape(c.reference))
+			return
+		}
+	}
+	if !c.approved {
+		res.AddMessageAck(ack{Code: 9050, Text: "Die Nachricht enthält Fehler."})
+		res.AddSegmentAcks(tan, ack{Code: 9010, Text: fmt.Sprintf("TAN-Prozess %q nicht zulässig.", process)})
+		return
+	}
+	delete(s.challenges, c.reference)
+	res.AddSegmentAcks(tan, ack{Code: 20, Text: "Auftrag ausgeführt."})
+	res.Add("HITAN", 6, tan.Number, escape(process), "", escape(c.reference))
+	s.executeBusinessTransactions(c.segments, res)
+}
+
+func (s *Server) securityFunction() string {
+	if s.config.TANMode == TANModeDecoupled {
+		return "946"
+	}
+	return "942"
+}
+
+func (s *Server) addBankParameterData(res *response, reference int) {
+	c := s.config
+	res.Add("HIBPA", 3, reference, strconv.Itoa(c.BPDVersion), "280:"+escape(c.BankID), escape(c.BankName), "3", "1", "300", "0")
+	pinTanTransactions := []string{"5", "20", "6", "Benutzerkennung", "Kunden-ID"}
+	for _, id := range []string{"HKSAL", "HKKAZ", "HKCAZ", "HKTAN"} {
+		needsTan := "N"
+		if s.needsTan([]rawSegment{{ID: id}}) {
+			needsTan = "J"
+		}
+		pinTanTransactions = append(pinTanTransactions, id, needsTan)
+	}
+	res.Add("HIPINS", 1, reference, "1", "1", "0", strings.Join(pinTanTransactions, ":"))
+	res.Add(
+		"HITANS", 6, reference, "1", "1", "0",
+		"J:N:0:"+
+			"942:2:mobileTAN:::fintstest mobileTAN:6:1:TAN:99:N:1:N:0:0:N:N:00:0:N:1:"+
+			"946:2:Decoupled:::fintstest App:0:1:Freigabe:99:N:1:N:0:0:N:N:00:0:N:1",
+	)
+	res.Add("HISALS", 7, reference, "1", "1", "0")
+	res.Add("HIKAZS", 7, reference, "1", "1", "0", "90:J:N")
+	res.Add("HICAZS", 1, reference, "1", "1", "0", "90:J:N:"+escape(camtFormat))
+	if c.PushServiceURL != "" {
+		res.Add("HIPURS", 1, reference, "1", "1", "0", escape(c.PushServiceURL)+":60:J:"+strings.Join(pushServiceSegmentIDs, ":"))
+	}
+}
+
+func (s *Server) addUserParameterData(res *response, reference int) {
+	c := s.config
+	res.Add("HIUPA", 4, reference, escape(c.UserID), strconv.Itoa(c.UPDVersion), "0")
+	for _, account := range c.Accounts {
+		res.Add(
+			"HIUPD", 6, reference,
+			escape(account.AccountID)+"::280:"+escape(c.BankID),
+			escape(account.IBAN),
+			escape(c.UserID),
+			"1",
+			account.currency(),
+			escape(account.Name),
+			"",
+			escape(account.ProductName),
+			"",
+			"HKSAL:1",
+			"HKKAZ:1",
+			"HKCAZ:1",
+		)
+	}
+}
+
+const camtFormat = "urn:iso:std:iso:20022:tech:xsd:camt.052.001.02"
+
+// findAccounts returns the accounts addressed by the account connection
+// within group. If allAccounts is true, all accounts are returned.
+func (s *Server) findAccounts(group []string, allAccounts bool) []Account {
+	if allAccounts {
+		return s.config.Accounts
+	}
+	for _, account := range s.config.Accounts {
+		for _, field := range group {
+			if field != "" && (field == account.IBAN || field == account.AccountID) {
+				return []Account{account}
+			}
+		}
+	}
+	return nil
+}
+
+// page returns the offset for continuationReference and the end of the page
+// for a result of the given size, together with the reference of the next
+// page.
+func (s *Server) page(continuationReference string, size, maxEntries int) (int, int, string, *ack) {
+	offset := 0
+	if continuationReference != "" {
+		var err error
+		offset, err = strconv.Atoi(strings.TrimPrefix(continuationReference, "P"))
+		if err != nil || !strings.HasPrefix(continuationReference, "P") || offset > size {
+			return 0, 0, "", &ack{Code: 9210, Text: "Aufsetzpunkt ungültig."}
+		}
+	}
+	pageSize := s.config.PageSize
+	if maxEntries > 0 && (pageSize == 0 || maxEntries < pageSize) {
+		pageSize = maxEntries
+	}
+	end := size
+	if pageSize > 0 && offset+pageSize < size {
+		end = offset + pageSize
+	}
+	next := ""
+	if end < size {
+		next = fmt.Sprintf("P%d", end)
+	}
+	return offset, end, next, nil
+}
+
+func continuationAck(reference string) ack {
+	return ack{Code: domain.ReturnCodeAdditionalInformation, Text: "Es liegen weitere Informationen vor.", Params: []string{reference}}
+}
+
+func (s *Server) accountConnection(account Account, version int) string {
+	if version >= 7 {
+		return fmt.Sprintf("%s:%s:%s::280:%s", escape(account.IBAN), escape(account.BIC), escape(account.AccountID), escape(s.config.BankID))
+	}
+	return fmt.Sprintf("%s::280:%s", escape(account.AccountID), escape(s.config.BankID))
+}
+
+func (s *Server) balances(seg rawSegment, res *response) *ack {
+	accounts := s.findAccounts(seg.Group(1), seg.Element(2) == "J")
+	if len(accounts) == 0 {
+		return &ack{Code: 9010, Text: "Konto nicht gefunden."}
+	}
+	maxEntries, _ := strconv.Atoi(seg.Element(3))
+	offset, end, next, errAck := s.page(seg.Element(4), len(accounts), maxEntries)
+	if errAck != nil {
+		return errAck
+	}
+	acks := []ack{{Code: 20, Text: "Auftrag ausgeführt."}}
+	if next != "" {
+		acks = append(acks, continuationAck(next))
+	}
+	res.AddSegmentAcks(seg, acks...)
+	today := time.Now().Format("20060102")
+	for _, account := range accounts[offset:end] {
+		balance := account.Balance.WithCurrency(account.currency())
+		res.Add(
+			"HISAL", seg.Version, seg.Number,
+			s.accountConnection(account, seg.Version),
+			escape(account.ProductName),
+			account.currency(),
+			fmt.Sprintf("%s:%s:%s:%s", debitCredit(balance), balance.Abs().FormatHBCI(), account.currency(), today),
+		)
+	}
+	return nil
+}
+
+func (s *Server) changePIN(seg rawSegment, res *response) *ack {
+	pin := seg.Element(1)
+	if pin == "" || pin == s.config.PIN {
+		return &ack{Code: 9942, Text: "Neue PIN ungültig."}
+	}
+	s.config.PIN = pin
+	res.AddSegmentAcks(seg, ack{Code: 20, Text: "PIN geändert."})
+	return nil
+}
+
+func parseDate(value string) time.Time {
+	date, err := time.Parse("20060102", value)
+	if err != nil {
+		return time.Time{}
+	}
+	return date
+}
+
+func (s *Server) transactions(seg rawSegment, res *response) *ack {
+	accounts := s.findAccounts(seg.Group(1), false)
+	if len(accounts) == 0 {
+		return &ack{Code: 9010, Text: "Konto nicht gefunden."}
+	}
+	account := accounts[0]
+	transactions := account.transactionsBetween(parseDate(seg.Element(3)), parseDate(seg.Element(4)))
+	maxEntries, _ := strconv.Atoi(seg.Element(5))
+	offset, end, next, errAck := s.page(seg.Element(6), len(transactions), maxEntries)
+	if errAck != nil {
+		return errAck
+	}
+	acks := []ack{{Code: 20, Text: "Auftrag ausgeführt."}}
+	if len(transactions) == 0 {
+		acks = []ack{{Code: 3010, Text: "Keine Umsätze im angegebenen Zeitraum vorhanden."}}
+	}
+	if next != "" {
+		acks = append(acks, continuationAck(next))
+	}
+	res.AddSegmentAcks(seg, acks...)
+	if len(transactions) == 0 {
+		return nil
+	}
+	statementNumber := 1
+	if s.config.PageSize > 0 {
+		statementNumber = offset/s.config.PageSize + 1
+	}
+	mt940 := account.mt940(s.config.BankID, statementNumber, transactions[offset:end])
+	res.Add("HIKAZ", seg.Version, seg.Number, binary(mt940))
+	return nil
+}
+
+func (s *Server) camtTransactions(seg rawSegment, res *response) *ack {
+	accounts := s.findAccounts(seg.Group(1), false)
+	if len(accounts) == 0 {
+		return &ack{Code: 9010, Text: "Konto nicht gefunden."}
+	}
+	account := accounts[0]
+	transactions := account.transactionsBetween(parseDate(seg.Element(4)), parseDate(seg.Element(5)))
+	maxEntries, _ := strconv.Atoi(seg.Element(6))
+	offset, end, next, errAck := s.page(seg.Element(7), len(transactions), maxEntries)
+	if errAck != nil {
+		return errAck
+	}
+	acks := []ack{{Code: 20, Text: "Auftrag ausgeführt."}}
+	if next != "" {
+		acks = append(acks, continuationAck(next))
+	}
+	res.AddSegmentAcks(seg, acks...)
+	res.Add(
+		"HICAZ", seg.Version, seg.Number,
+		s.accountConnection(account, 7),
+		escape(camtFormat),
+		binary(account.camt(transactions[offset:end])),
+	)
+	return nil
+}
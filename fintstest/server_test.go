@@ -0,0 +1,436 @@
+package fintstest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/client"
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+func testAccount() Account {
+	date := func(day int) time.Time { return time.Date(2020, 3, day, 0, 0, 0, 0, time.UTC) }
+	return Account{
+		AccountID:   "1234567",
+		IBAN:        "DE89370400440532013000",
+		BIC:         "TESTDEFFXXX",
+		Name:        "Max Muster",
+		ProductName: "Girokonto",
+		Balance:     domain.NewAmount(123456, "EUR"),
+		Transactions: []Transaction{
+			{BookingDate: date(2), Amount: domain.NewAmount(250000, "EUR"), BookingText: "GUTSCHRIFT", Name: "Arbeitgeber", Purpose: "Gehalt Maerz"},
+			{BookingDate: date(3), Amount: domain.NewAmount(-80000, "EUR"), BookingText: "DAUERAUFTRAG", Name: "Vermieter", Purpose: "Miete"},
+			{BookingDate: date(5), Amount: domain.NewAmount(-1999, "EUR"), BookingText: "LASTSCHRIFT", Name: "Shop", Purpose: "Einkauf"},
+		},
+	}
+}
+
+func newTestClient(t *testing.T, server *Server) *client.Client {
+	c, err := client.New(client.Config{
+		URL:         server.URL,
+		BankID:      server.BankID(),
+		AccountID:   server.UserID(),
+		PIN:         server.PIN(),
+		HBCIVersion: 300,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	return c
+}
+
+func internationalAccount(account Account) domain.InternationalAccountConnection {
+	return domain.InternationalAccountConnection{
+		IBAN:      account.IBAN,
+		BIC:       account.BIC,
+		AccountID: account.AccountID,
+		BankID:    domain.BankID{CountryCode: 280, ID: DefaultBankID},
+	}
+}
+
+func TestServerAccounts(t *testing.T) {
+	server := NewServer(Config{Accounts: []Account{testAccount()}})
+	defer server.Close()
+
+	accounts, err := newTestClient(t, server).Accounts()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	if len(accounts) != 1 {
+		t.Fatalf("Expected 1 account, got %d\n", len(accounts))
+	}
+	if accounts[0].AccountConnection.AccountID != "1234567" {
+		t.Logf("Expected account ID %q, got %q\n", "1234567", accounts[0].AccountConnection.AccountID)
+		t.Fail()
+	}
+	if server.OpenDialogs() != 0 {
+		t.Logf("Expected all dialogs to be ended, got %d open dialogs\n", server.OpenDialogs())
+		t.Fail()
+	}
+}
+
+func TestServerBalances(t *testing.T) {
+	second := testAccount()
+	second.AccountID = "7654321"
+	second.IBAN = "DE27100777770209299700"
+	second.Balance = domain.NewAmount(-5000, "EUR")
+	server := NewServer(Config{Accounts: []Account{testAccount(), second}, PageSize: 1})
+	defer server.Close()
+
+	balances, err := newTestClient(t, server).SepaAccountBalances(internationalAccount(testAccount()), true, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	if len(balances) != 2 {
+		t.Fatalf("Expected 2 balances, got %d\n", len(balances))
+	}
+	expected := []domain.Amount{domain.NewAmount(123456, "EUR"), domain.NewAmount(-5000, "EUR")}
+	for i, balance := range balances {
+		if balance.BookedBalance.Amount != expected[i] {
+			t.Logf("Expected balance %d to equal %s, got %s\n", i, expected[i], balance.BookedBalance.Amount)
+			t.Fail()
+		}
+	}
+}
+
+func TestServerTransactions(t *testing.T) {
+	account := testAccount()
+	server := NewServer(Config{Accounts: []Account{account}, PageSize: 2})
+	defer server.Close()
+
+	timeframe := domain.Timeframe{
+		StartDate: domain.Date(2020, 3, 1, time.UTC),
+		EndDate:   domain.Date(2020, 3, 31, time.UTC),
+	}
+	transactions, err := newTestClient(t, server).SepaAccountTransactions(internationalAccount(account), timeframe, false, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	var amounts []domain.Amount
+	var purposes []string
+	for _, tx := range transactions {
+		amounts = append(amounts, tx.Amount)
+		purposes = append(purposes, tx.Purpose)
+	}
+	expectedAmounts := []domain.Amount{
+		domain.NewAmount(250000, "EUR"), domain.NewAmount(-80000, "EUR"), domain.NewAmount(-1999, "EUR"),
+	}
+	if !reflect.DeepEqual(amounts, expectedAmounts) {
+		t.Logf("Expected amounts %v, got %v\n", expectedAmounts, amounts)
+		t.Fail()
+	}
+	expectedPurposes := []string{"Gehalt Maerz", "Miete", "Einkauf"}
+	if !reflect.DeepEqual(purposes, expectedPurposes) {
+		t.Logf("Expected purposes %q, got %q\n", expectedPurposes, purposes)
+		t.Fail()
+	}
+	if len(transactions) == 3 && transactions[2].AccountBalanceAfter.Amount != account.Balance {
+		t.Logf("Expected closing balance %s, got %s\n", account.Balance, transactions[2].AccountBalanceAfter.Amount)
+		t.Fail()
+	}
+	var kaz int
+	for _, id := range server.ReceivedSegments() {
+		if id == "HKKAZ" {
+			kaz++
+		}
+	}
+	if kaz != 2 {
+		t.Logf("Expected 2 HKKAZ requests, got %d\n", kaz)
+		t.Fail()
+	}
+}
+
+func TestServerWrongPIN(t *testing.T) {
+	server := NewServer(Config{Accounts: []Account{testAccount()}})
+	defer server.Close()
+
+	c, err := client.New(client.Config{
+		URL:         server.URL,
+		BankID:      server.BankID(),
+		AccountID:   server.UserID(),
+		PIN:         "wrong",
+		HBCIVersion: 300,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	_, err = c.Accounts()
+
+	if !errors.Is(err, domain.ErrPinWrong) {
+		t.Logf("Expected error to be ErrPinWrong, got %T:%v\n", err, err)
+		t.Fail()
+	}
+
+	received := len(server.ReceivedSegments())
+
+	_, err = c.Accounts()
+
+	if !errors.Is(err, client.ErrPinRejected) || !errors.Is(err, domain.ErrPinWrong) {
+		t.Logf("Expected error to be ErrPinRejected, got %T:%v\n", err, err)
+		t.Fail()
+	}
+	if len(server.ReceivedSegments()) != received {
+		t.Logf("Expected rejected PIN not to be sent again, got segments %v\n", server.ReceivedSegments()[received:])
+		t.Fail()
+	}
+
+	c.SetPIN(server.PIN())
+
+	_, err = c.Accounts()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+}
+
+func TestServerChangePIN(t *testing.T) {
+	server := NewServer(Config{Accounts: []Account{testAccount()}})
+	defer server.Close()
+
+	oldPIN := server.PIN()
+	c := newTestClient(t, server)
+
+	err := c.ChangePIN(oldPIN, "54321")
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	if server.PIN() != "54321" {
+		t.Logf("Expected PIN to be changed to %q, got %q\n", "54321", server.PIN())
+		t.Fail()
+	}
+	if server.OpenDialogs() != 0 {
+		t.Logf("Expected all dialogs to be ended, got %d open dialogs\n", server.OpenDialogs())
+		t.Fail()
+	}
+	_, err = c.Accounts()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	err = c.ChangePIN("54321", "54321")
+
+	if !errors.Is(err, domain.ErrPinWrong) {
+		t.Logf("Expected error to be ErrPinWrong, got %T:%v\n", err, err)
+		t.Fail()
+	}
+	_, err = c.Accounts()
+	if err != nil {
+		t.Logf("Expected rejected new PIN not to block the current PIN, got %T:%v\n", err, err)
+		t.Fail()
+	}
+
+	err = c.ChangePIN(oldPIN, "67890")
+
+	if !errors.Is(err, domain.ErrPinWrong) {
+		t.Logf("Expected error to be ErrPinWrong, got %T:%v\n", err, err)
+		t.Fail()
+	}
+	if server.PIN() != "54321" {
+		t.Logf("Expected PIN not to be changed, got %q\n", server.PIN())
+		t.Fail()
+	}
+}
+
+func TestServerChangePINTanRequired(t *testing.T) {
+	server := NewServer(Config{
+		Accounts:    []Account{testAccount()},
+		TANMode:     TANModeProcess4,
+		TANRequired: []string{"HKPAE"},
+	})
+	defer server.Close()
+
+	oldPIN := server.PIN()
+	c := newTestClient(t, server)
+
+	err := c.ChangePIN(oldPIN, "54321")
+
+	if !errors.Is(err, domain.ErrTanRequired) {
+		t.Logf("Expected error to be ErrTanRequired, got %T:%v\n", err, err)
+		t.Fail()
+	}
+	if server.PIN() != oldPIN {
+		t.Logf("Expected PIN not to be changed, got %q\n", server.PIN())
+		t.Fail()
+	}
+	_, err = c.Accounts()
+	if err != nil {
+		t.Logf("Expected client to keep the old PIN, got %T:%v\n", err, err)
+		t.Fail()
+	}
+}
+
+// rawDialog sends hand crafted messages to the server, as the client does not
+// answer TAN challenges yet.
+type rawDialog struct {
+	t             *testing.T
+	server        *Server
+	dialogID      string
+	messageNumber int
+}
+
+func (d *rawDialog) send(pinTan string, segments ...string) (acks []string, response map[string][]rawSegment) {
+	d.messageNumber++
+	var inner string
+	for i, seg := range segments {
+		inner += fmt.Sprintf(seg, i+3)
+	}
+	inner = fmt.Sprintf("HNSHK:2:4+PIN:1+999+1+1+1+1::SYS+1+1:20200301:120000+1:999:1+6:10:16+280:%s:%s:S:0:0'", DefaultBankID, DefaultUserID) +
+		inner + fmt.Sprintf("HNSHA:%d:2+1++%s'", len(segments)+3, pinTan)
+	body := fmt.Sprintf("HNHBK:1:3+000000000000+300+%s+%d'HNVSD:999:1+@%d@%s'HNHBS:%d:1+%d'", d.dialogID, d.messageNumber, len(inner), inner, len(segments)+4, d.messageNumber)
+
+	raw := d.server.handle([]byte(body))
+
+	req, err := parseRequest(raw)
+	if err != nil {
+		d.t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	outer, _ := extractSegments(raw)
+	d.dialogID = outer[0].Element(3)
+	response = make(map[string][]rawSegment)
+	for _, seg := range req.Segments {
+		response[seg.ID] = append(response[seg.ID], seg)
+		if seg.ID == "HIRMG" || seg.ID == "HIRMS" {
+			for i := 1; i < len(seg.elements); i++ {
+				acks = append(acks, seg.Group(i)[0])
+			}
+		}
+	}
+	return acks, response
+}
+
+func (d *rawDialog) init() {
+	acks, _ := d.send(DefaultPIN, "HKIDN:%d:2+280:"+DefaultBankID+"+"+DefaultUserID+"+SYS+1'", "HKVVB:%d:3+1+1+0+test+1'")
+	if !contains(acks, "0010") {
+		d.t.Fatalf("Expected dialog to be initialized, got %q\n", acks)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+const balanceRequest = "HKSAL:%d:7+DE89370400440532013000:TESTDEFFXXX:1234567::280:" + DefaultBankID + "+N'"
+
+func TestServerTANProcess4(t *testing.T) {
+	server := NewServer(Config{Accounts: []Account{testAccount()}, TANMode: TANModeProcess4})
+	defer server.Close()
+	d := &rawDialog{t: t, server: server, dialogID: "0"}
+	d.init()
+
+	acks, response := d.send(DefaultPIN, balanceRequest, "HKTAN:%d:6+4+HKSAL'")
+
+	if !contains(acks, "0030") || len(response["HITAN"]) != 1 {
+		t.Fatalf("Expected TAN challenge, got %q\n", acks)
+	}
+	jobReference := response["HITAN"][0].Element(3)
+	if len(response["HISAL"]) != 0 {
+		t.Logf("Expected no balance before TAN is provided\n")
+		t.Fail()
+	}
+
+	acks, _ = d.send(DefaultPIN+":000000", "HKTAN:%d:6+2++++"+jobReference+"+N'")
+
+	if !contains(acks, "9941") {
+		t.Logf("Expected wrong TAN to be rejected, got %q\n", acks)
+		t.Fail()
+	}
+
+	acks, response = d.send(DefaultPIN+":"+DefaultTAN, "HKTAN:%d:6+2++++"+jobReference+"+N'")
+
+	if !contains(acks, "0020") || len(response["HISAL"]) != 1 {
+		t.Logf("Expected balance after TAN is provided, got %q\n", acks)
+		t.Fail()
+	}
+	if len(server.PendingChallenges()) != 0 {
+		t.Logf("Expected no pending challenges, got %q\n", server.PendingChallenges())
+		t.Fail()
+	}
+}
+
+func TestServerTANDecoupled(t *testing.T) {
+	server := NewServer(Config{Accounts: []Account{testAccount()}, TANMode: TANModeDecoupled})
+	defer server.Close()
+	d := &rawDialog{t: t, server: server, dialogID: "0"}
+	d.init()
+
+	acks, response := d.send(DefaultPIN, balanceRequest, "HKTAN:%d:6+4+HKSAL'")
+
+	if !contains(acks, "3955") || len(response["HITAN"]) != 1 {
+		t.Fatalf("Expected decoupled challenge, got %q\n", acks)
+	}
+	jobReference := response["HITAN"][0].Element(3)
+
+	acks, response = d.send(DefaultPIN, "HKTAN:%d:6+S++++"+jobReference+"+N'")
+
+	if !contains(acks, "3956") || len(response["HISAL"]) != 0 {
+		t.Logf("Expected pending approval, got %q\n", acks)
+		t.Fail()
+	}
+
+	if err := server.Approve(jobReference); err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	acks, response = d.send(DefaultPIN, "HKTAN:%d:6+S++++"+jobReference+"+N'")
+
+	if !contains(acks, "0020") || len(response["HISAL"]) != 1 {
+		t.Logf("Expected balance after approval, got %q\n", acks)
+		t.Fail()
+	}
+}
+
+func TestServerTANRequired(t *testing.T) {
+	server := NewServer(Config{Accounts: []Account{testAccount()}, TANMode: TANModeProcess4})
+	defer server.Close()
+	d := &rawDialog{t: t, server: server, dialogID: "0"}
+	d.init()
+
+	acks, _ := d.send(DefaultPIN, balanceRequest)
+
+	if !contains(acks, "9075") {
+		t.Logf("Expected strong authentication to be required, got %q\n", acks)
+		t.Fail()
+	}
+}
+
+func TestServerCamtTransactions(t *testing.T) {
+	server := NewServer(Config{Accounts: []Account{testAccount()}, PageSize: 2})
+	defer server.Close()
+	d := &rawDialog{t: t, server: server, dialogID: "0"}
+	d.init()
+	request := "HKCAZ:%d:1+DE89370400440532013000:TESTDEFFXXX:1234567::280:" + DefaultBankID + "+" + escape(camtFormat) + "+N+20200301+20200331"
+
+	acks, response := d.send(DefaultPIN, request+"'")
+
+	if !contains(acks, "3040") || len(response["HICAZ"]) != 1 {
+		t.Fatalf("Expected first page with continuation reference, got %q\n", acks)
+	}
+	camt := response["HICAZ"][0].Element(3)
+	if !strings.Contains(camt, `<Amt Ccy="EUR">2500.00</Amt>`) || strings.Contains(camt, "19.99") {
+		t.Logf("Expected first page to contain the first two transactions, got %s\n", camt)
+		t.Fail()
+	}
+
+	acks, response = d.send(DefaultPIN, request+"++P2'")
+
+	if contains(acks, "3040") || len(response["HICAZ"]) != 1 {
+		t.Fatalf("Expected last page, got %q\n", acks)
+	}
+	camt = response["HICAZ"][0].Element(3)
+	if !strings.Contains(camt, `<Amt Ccy="EUR">19.99</Amt><CdtDbtInd>DBIT</CdtDbtInd>`) {
+		t.Logf("Expected second page to contain the last transaction, got %s\n", camt)
+		t.Fail()
+	}
+}
@@ -0,0 +1,393 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mitch000001/go-hbci/internal"
+)
+
+// GroupDataElementIdentifier represents a group data element definition for
+// the generator
+type GroupDataElementIdentifier struct {
+	Name string
+	// TypeName is the name of the DataElementType constant of the group
+	TypeName string
+}
+
+// NewGroupDataElementGroupGenerator creates a new generator for the provided
+// group data element
+func NewGroupDataElementGroupGenerator(group GroupDataElementIdentifier, packageName string, fileSet *token.FileSet, file *ast.File) *GroupDataElementGroupGenerator {
+	return &GroupDataElementGroupGenerator{
+		group:       group,
+		packageName: packageName,
+		fileSet:     fileSet,
+		file:        file,
+	}
+}
+
+// GroupDataElementGroupGenerator generates the Elements, UnmarshalHBCI and
+// Validate methods for group data elements. The data elements of the group are
+// defined by hbci struct tags on the fields of the group, in the order of the
+// fields. Repeated data elements are slices of data elements and must define
+// their maximum count, unless they are the last element of the group. Code
+// data elements are unmarshaled with the valid codes defined by their tag.
+type GroupDataElementGroupGenerator struct {
+	group       GroupDataElementIdentifier
+	packageName string
+	fileSet     *token.FileSet
+	file        *ast.File
+}
+
+// Generate generates the methods for the group data element definition and
+// provides them as an io.Reader
+func (g *GroupDataElementGroupGenerator) Generate() (io.Reader, error) {
+	if g.group.TypeName == "" {
+		return nil, fmt.Errorf("no data element type provided for group %q", g.group.Name)
+	}
+	fields, imports, err := g.extractFields()
+	if err != nil {
+		return nil, err
+	}
+	r, _ := utf8.DecodeRuneInString(g.group.Name)
+	templObj := &groupTemplateObject{
+		CodeGenerator: fmt.Sprintf("%T", g),
+		Package:       g.packageName,
+		Name:          g.group.Name,
+		NameVar:       string(unicode.ToLower(r)),
+		TypeName:      g.group.TypeName,
+		Imports:       imports,
+		Fields:        fields,
+	}
+	for _, f := range fields {
+		if f.Repeated {
+			templObj.Repeated = true
+		} else if f.Last {
+			templObj.JoinsRemainder = true
+		}
+	}
+	executor := &groupTemplateExecutor{templObj}
+	return executor.execute()
+}
+
+// extractFields returns the fields defining data elements and the import
+// paths of the packages of their types
+func (g *GroupDataElementGroupGenerator) extractFields() ([]groupField, []string, error) {
+	object := g.file.Scope.Lookup(g.group.Name)
+	if object == nil {
+		return nil, nil, fmt.Errorf("no group with name %q found", g.group.Name)
+	}
+	typeSpec, ok := object.Decl.(*ast.TypeSpec)
+	if !ok {
+		return nil, nil, fmt.Errorf("%q is no type declaration", g.group.Name)
+	}
+	structType, ok := typeSpec.Type.(*ast.StructType)
+	if !ok {
+		return nil, nil, fmt.Errorf("%q is no struct", g.group.Name)
+	}
+	var fields []groupField
+	var imports []string
+	for _, f := range structType.Fields.List {
+		if f.Tag == nil || f.Names == nil {
+			continue
+		}
+		rawTag, err := strconv.Unquote(f.Tag.Value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("malformed struct tag %s: %w", f.Tag.Value, err)
+		}
+		tagValue, ok := reflect.StructTag(rawTag).Lookup(internal.StructTagName)
+		if !ok {
+			continue
+		}
+		tag, err := internal.ParseStructTag(tagValue)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: malformed struct tag: %w", nodeToString(f.Names[0], g.fileSet), err)
+		}
+		fieldType := f.Type
+		arrayType, repeated := fieldType.(*ast.ArrayType)
+		if repeated {
+			if arrayType.Len != nil {
+				return nil, nil, fmt.Errorf("Unexpected type found: %q", nodeToString(f.Type, g.fileSet))
+			}
+			fieldType = arrayType.Elt
+		}
+		starExpr, ok := fieldType.(*ast.StarExpr)
+		if !ok {
+			return nil, nil, fmt.Errorf("Unexpected type found: %q", nodeToString(f.Type, g.fileSet))
+		}
+		if sel, ok := starExpr.X.(*ast.SelectorExpr); ok {
+			importPath, err := g.importPath(sel)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !containsString(imports, importPath) {
+				imports = append(imports, importPath)
+			}
+		}
+		typeDecl := nodeToString(starExpr.X, g.fileSet)
+		if len(tag.Codes) != 0 && typeDecl != "CodeDataElement" && !strings.HasSuffix(typeDecl, ".CodeDataElement") {
+			return nil, nil, fmt.Errorf("%s: codes defined for %s, which is no CodeDataElement", f.Names[0].Name, typeDecl)
+		}
+		for _, name := range f.Names {
+			if !repeated && (tag.Max != 0 || tag.Min > 1) {
+				return nil, nil, fmt.Errorf("%s: repetitions defined for a single data element", name.Name)
+			}
+			fields = append(fields, groupField{
+				Name:     name.Name,
+				TypeDecl: typeDecl,
+				Tag:      tag,
+				Repeated: repeated,
+			})
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil, fmt.Errorf("no data elements defined for group %q", g.group.Name)
+	}
+	var index int
+	for i := range fields {
+		fields[i].Index = index
+		fields[i].Last = i == len(fields)-1
+		if !fields[i].Repeated {
+			index++
+			continue
+		}
+		if fields[i].Tag.Max == 0 && !fields[i].Last {
+			return nil, nil, fmt.Errorf("%s: repeated data elements without max must be the last element", fields[i].Name)
+		}
+		index += fields[i].Tag.Max
+	}
+	return fields, imports, nil
+}
+
+// importPath returns the import path of the package referenced by sel
+func (g *GroupDataElementGroupGenerator) importPath(sel *ast.SelectorExpr) (string, error) {
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", fmt.Errorf("Unexpected Selector: %q", nodeToString(sel, g.fileSet))
+	}
+	for _, imp := range g.file.Imports {
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			return "", err
+		}
+		name := path.Base(importPath)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if name == ident.Name {
+			return importPath, nil
+		}
+	}
+	return "", fmt.Errorf("no import found for package %q", ident.Name)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type groupTemplateObject struct {
+	CodeGenerator  string
+	Package        string
+	Name           string
+	NameVar        string
+	TypeName       string
+	Imports        []string
+	Fields         []groupField
+	Repeated       bool
+	JoinsRemainder bool
+}
+
+type groupField struct {
+	Name     string
+	TypeDecl string
+	Tag      internal.StructTag
+	Index    int
+	Repeated bool
+	Last     bool
+}
+
+// End returns the index after the last repetition of the field
+func (g groupField) End() int {
+	return g.Index + g.Tag.Max
+}
+
+// New returns the expression creating an empty data element of the field to
+// unmarshal into. Code data elements are created with their valid codes.
+func (g groupField) New() string {
+	if len(g.Tag.Codes) == 0 {
+		return "&" + g.TypeDecl + "{}"
+	}
+	constructor := strings.TrimSuffix(g.TypeDecl, "CodeDataElement") + "NewCode"
+	return fmt.Sprintf("%s(\"\", %d, %#v)", constructor, g.Tag.MaxLength, g.Tag.Codes)
+}
+
+type groupTemplateExecutor struct {
+	templateObject *groupTemplateObject
+}
+
+func (g *groupTemplateExecutor) execute() (io.Reader, error) {
+	t, err := template.New("executor").Parse(groupExecutorTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing template: %v", err)
+	}
+	t, err = t.Parse(groupElementsTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing template: %v", err)
+	}
+	t, err = t.Parse(groupUnmarshalingTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing template: %v", err)
+	}
+	t, err = t.Parse(groupValidationTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing template: %v", err)
+	}
+	t, err = t.Parse(groupPackageDeclTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing template: %v", err)
+	}
+	t, err = t.Parse(generationNoticeTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing template: %v", err)
+	}
+	var buf bytes.Buffer
+	err = t.Execute(&buf, g.templateObject)
+	if err != nil {
+		return nil, fmt.Errorf("%T: Error while executing template: %v", g, err)
+	}
+	return &buf, nil
+}
+
+const groupExecutorTemplate = `{{template "generation_notice" .}}
+{{template "group_package_declaration" .}}
+{{template "group_elements" .}}
+{{template "group_unmarshaler" .}}
+{{template "group_validation" .}}
+`
+
+const groupPackageDeclTemplate = `{{define "group_package_declaration"}}package {{.Package}}
+
+import (
+{{- if .JoinsRemainder }}
+	"bytes"
+{{- end }}
+	"fmt"
+{{- if .Imports }}
+{{ range .Imports }}
+	"{{ . }}"
+{{- end }}
+{{- end }}
+){{end}}
+`
+
+const groupElementsTemplate = `{{define "group_elements"}}
+// Elements returns the elements of this DataElement.
+func ({{.NameVar}} *{{.Name}}) Elements() []DataElement {
+{{- if .Repeated }}
+	var elements []DataElement
+{{- range .Fields }}{{ if .Repeated }}
+	for _, elem := range {{ $.NameVar }}.{{ .Name }} {
+		elements = append(elements, elem)
+	}{{ if not .Last }}
+	for i := len({{ $.NameVar }}.{{ .Name }}); i < {{ .Tag.Max }}; i++ {
+		elements = append(elements, (*{{ .TypeDecl }})(nil))
+	}{{ end }}{{ else }}
+	elements = append(elements, {{ $.NameVar }}.{{ .Name }}){{ end }}{{ end }}
+	return elements
+{{- else }}
+	return []DataElement{
+{{- range .Fields }}
+		{{ $.NameVar }}.{{ .Name }},{{ end }}
+	}
+{{- end }}
+}{{end}}
+`
+
+const groupUnmarshalingTemplate = `{{define "group_unmarshaler"}}
+// UnmarshalHBCI unmarshals value into {{.NameVar}}
+func ({{.NameVar}} *{{.Name}}) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+{{- range .Fields }}{{ if .Repeated }}
+	{{ $.NameVar }}.{{ .Name }} = nil
+	for i := {{ .Index }}; i < len(elements){{ if not .Last }} && i < {{ .End }}{{ end }}; i++ {
+		if len(elements[i]) == 0 {
+			continue
+		}
+		elem := {{ .New }}
+		if err := elem.UnmarshalHBCI(elements[i]); err != nil {
+			return fmt.Errorf("error unmarshaling {{ .Name }}: %w", err)
+		}
+		{{ $.NameVar }}.{{ .Name }} = append({{ $.NameVar }}.{{ .Name }}, elem)
+	}{{ if .Tag.Min }}
+	if len({{ $.NameVar }}.{{ .Name }}) < {{ .Tag.Min }} {
+		return fmt.Errorf("malformed marshaled value: {{ .Name }} requires at least {{ .Tag.Min }} elements, got %d", len({{ $.NameVar }}.{{ .Name }}))
+	}{{ end }}{{ else }}{{ if .Tag.Optional }}
+	{{ $.NameVar }}.{{ .Name }} = nil{{ end }}
+	if len(elements) > {{ .Index }} && len(elements[{{ .Index }}]) > 0 {
+		{{ $.NameVar }}.{{ .Name }} = {{ .New }}
+		{{ if .Last }}err = {{ $.NameVar }}.{{ .Name }}.UnmarshalHBCI(bytes.Join(elements[{{ .Index }}:], []byte(":"))){{ else }}err = {{ $.NameVar }}.{{ .Name }}.UnmarshalHBCI(elements[{{ .Index }}]){{ end }}
+		if err != nil {
+			return fmt.Errorf("error unmarshaling {{ .Name }}: %w", err)
+		}
+	}{{ if not .Tag.Optional }} else {
+		return fmt.Errorf("malformed marshaled value: missing required element {{ .Name }}")
+	}{{ end }}{{ end }}{{ end }}
+	{{ .NameVar }}.DataElement = NewGroupDataElementGroup({{ .TypeName }}, {{ if .Repeated }}len({{ .NameVar }}.Elements()){{ else }}{{ len .Fields }}{{ end }}, {{ .NameVar }})
+	return nil
+}{{end}}
+`
+
+const groupValidationTemplate = `{{define "group_validation"}}
+// Validate validates the elements of {{.NameVar}} against their definition
+func ({{.NameVar}} *{{.Name}}) Validate() error {
+{{- range .Fields }}{{ if .Repeated }}{{ if and .Tag.Min .Tag.Max }}
+	if len({{ $.NameVar }}.{{ .Name }}) < {{ .Tag.Min }} || len({{ $.NameVar }}.{{ .Name }}) > {{ .Tag.Max }} {
+		return fmt.Errorf("{{ $.Name }}: {{ .Name }} has %d elements, must have between {{ .Tag.Min }} and {{ .Tag.Max }}", len({{ $.NameVar }}.{{ .Name }}))
+	}{{ else if .Tag.Min }}
+	if len({{ $.NameVar }}.{{ .Name }}) < {{ .Tag.Min }} {
+		return fmt.Errorf("{{ $.Name }}: {{ .Name }} has %d elements, must have at least {{ .Tag.Min }}", len({{ $.NameVar }}.{{ .Name }}))
+	}{{ else if .Tag.Max }}
+	if len({{ $.NameVar }}.{{ .Name }}) > {{ .Tag.Max }} {
+		return fmt.Errorf("{{ $.Name }}: {{ .Name }} has %d elements, must have at most {{ .Tag.Max }}", len({{ $.NameVar }}.{{ .Name }}))
+	}{{ end }}
+	for _, elem := range {{ $.NameVar }}.{{ .Name }} {
+		if elem == nil {
+			return fmt.Errorf("{{ $.Name }}: {{ .Name }} contains an empty element")
+		}{{ if .Tag.MaxLength }}
+		if elem.Length() > {{ .Tag.MaxLength }} {
+			return fmt.Errorf("{{ $.Name }}: {{ .Name }} exceeds the maximum length of {{ .Tag.MaxLength }}")
+		}{{ end }}
+		if !elem.IsValid() {
+			return fmt.Errorf("{{ $.Name }}: {{ .Name }} is invalid")
+		}
+	}{{ else }}{{ $guard := "" }}{{ if .Tag.Optional }}{{ $guard = printf "%s.%s != nil && " $.NameVar .Name }}{{ else }}
+	if {{ $.NameVar }}.{{ .Name }} == nil {
+		return fmt.Errorf("{{ $.Name }}: missing required element {{ .Name }}")
+	}{{ end }}{{ if .Tag.MaxLength }}
+	if {{ $guard }}{{ $.NameVar }}.{{ .Name }}.Length() > {{ .Tag.MaxLength }} {
+		return fmt.Errorf("{{ $.Name }}: {{ .Name }} exceeds the maximum length of {{ .Tag.MaxLength }}")
+	}{{ end }}
+	if {{ $guard }}!{{ $.NameVar }}.{{ .Name }}.IsValid() {
+		return fmt.Errorf("{{ $.Name }}: {{ .Name }} is invalid")
+	}{{ end }}{{ end }}
+	return nil
+}{{end}}
+`
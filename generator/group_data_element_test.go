@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+func TestGroupDataElementGroupGeneratorGenerate(t *testing.T) {
+	tests := []struct {
+		group        string
+		expectedFile string
+	}{
+		{"TestGroupDataElement", "test_files/test_group_data_element_unmarshaler.go"},
+		{"TestRepeatedGroupDataElement", "test_files/test_repeated_group_data_element_unmarshaler.go"},
+	}
+	for _, test := range tests {
+		fileSet := token.NewFileSet()
+		f, err := parser.ParseFile(fileSet, "test_files/test_group_data_element.go", nil, 0)
+		if err != nil {
+			t.Logf("Error while parsing source: %T:%v\n", err, err)
+			t.FailNow()
+		}
+
+		expectedSrc, err := os.ReadFile(test.expectedFile)
+		if err != nil {
+			t.Logf("Error reading test fixtures: %v", err)
+			t.FailNow()
+		}
+
+		generator := NewGroupDataElementGroupGenerator(GroupDataElementIdentifier{Name: test.group, TypeName: "testGDEG"}, "test_files", fileSet, f)
+
+		reader, err := generator.Generate()
+
+		if err != nil {
+			t.Logf("%s: Expected no error, got %T:%v\n", test.group, err, err)
+			t.Fail()
+			continue
+		}
+
+		generatedSourcebytes, err := io.ReadAll(reader)
+		if err != nil {
+			t.Logf("Error while parsing source: %T:%v\n", err, err)
+			t.FailNow()
+		}
+		if !bytes.Equal(expectedSrc, generatedSourcebytes) {
+			diffs := diffmatchpatch.New().DiffMain(string(expectedSrc), string(generatedSourcebytes), true)
+			t.Logf("%s: Expected generated sources to equal\n%s\n\tgot\n%s\n", test.group, expectedSrc, generatedSourcebytes)
+			t.Logf("Diff: \n%s\n", diffPrettyPrint(diffs))
+			t.Fail()
+		}
+	}
+}
+
+func TestGroupDataElementGroupGeneratorGenerateErrors(t *testing.T) {
+	tests := []struct {
+		group           GroupDataElementIdentifier
+		expectedMessage string
+	}{
+		{
+			GroupDataElementIdentifier{Name: "TestGroupDataElementUnboundedRepetition", TypeName: "testGDEG"},
+			"Ids: repeated data elements without max must be the last element",
+		},
+		{
+			GroupDataElementIdentifier{Name: "TestGroupDataElementRepeatedSingleElement", TypeName: "testGDEG"},
+			"Abc: repetitions defined for a single data element",
+		},
+		{
+			GroupDataElementIdentifier{Name: "TestGroupDataElementCodesOnNoCode", TypeName: "testGDEG"},
+			"Abc: codes defined for element.AlphaNumericDataElement, which is no CodeDataElement",
+		},
+		{
+			GroupDataElementIdentifier{Name: "TestGroupDataElementUnboundedRepetition"},
+			`no data element type provided for group "TestGroupDataElementUnboundedRepetition"`,
+		},
+		{
+			GroupDataElementIdentifier{Name: "UnknownGroup", TypeName: "testGDEG"},
+			`no group with name "UnknownGroup" found`,
+		},
+	}
+	for _, test := range tests {
+		fileSet := token.NewFileSet()
+		f, err := parser.ParseFile(fileSet, "test_files/test_group_data_element_invalid.go", nil, 0)
+		if err != nil {
+			t.Logf("Error while parsing source: %T:%v\n", err, err)
+			t.FailNow()
+		}
+
+		generator := NewGroupDataElementGroupGenerator(test.group, "test_files", fileSet, f)
+
+		_, err = generator.Generate()
+
+		if err == nil {
+			t.Logf("%s: Expected error, got nil\n", test.group.Name)
+			t.Fail()
+			continue
+		}
+		if err.Error() != test.expectedMessage {
+			t.Logf("%s: Expected error message to equal\n%q\n\tgot\n%q\n", test.group.Name, test.expectedMessage, err.Error())
+			t.Fail()
+		}
+	}
+}
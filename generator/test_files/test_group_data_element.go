@@ -0,0 +1,19 @@
+package test_files
+
+import "github.com/mitch000001/go-hbci/element"
+
+type TestGroupDataElement struct {
+	DataElement
+	Abc  *element.AlphaNumericDataElement   `hbci:"an,6"`
+	Def  *element.NumberDataElement         `hbci:"num,3,optional"`
+	Ids  []*element.AlphaNumericDataElement `hbci:"an,6,optional,max=3"`
+	Kind *element.CodeDataElement           `hbci:"code,2,codes=A|01-02"`
+	Xyz  *element.BooleanDataElement        `hbci:"jn"`
+	Foo  string
+}
+
+type TestRepeatedGroupDataElement struct {
+	DataElement
+	Abc *element.AlphaNumericDataElement `hbci:"an,35" yaml:"abc"`
+	Ids []*element.NumberDataElement     `hbci:"num,min=2"`
+}
@@ -0,0 +1,19 @@
+package test_files
+
+import "github.com/mitch000001/go-hbci/element"
+
+type TestGroupDataElementUnboundedRepetition struct {
+	DataElement
+	Ids []*element.AlphaNumericDataElement `hbci:"an,6"`
+	Xyz *element.BooleanDataElement        `hbci:"jn"`
+}
+
+type TestGroupDataElementRepeatedSingleElement struct {
+	DataElement
+	Abc *element.AlphaNumericDataElement `hbci:"an,6,max=2"`
+}
+
+type TestGroupDataElementCodesOnNoCode struct {
+	DataElement
+	Abc *element.AlphaNumericDataElement `hbci:"an,6,codes=A|B"`
+}
@@ -0,0 +1,131 @@
+// Code generated by *generator.GroupDataElementGroupGenerator; DO NOT EDIT.
+
+package test_files
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/mitch000001/go-hbci/element"
+)
+
+// Elements returns the elements of this DataElement.
+func (t *TestGroupDataElement) Elements() []DataElement {
+	var elements []DataElement
+	elements = append(elements, t.Abc)
+	elements = append(elements, t.Def)
+	for _, elem := range t.Ids {
+		elements = append(elements, elem)
+	}
+	for i := len(t.Ids); i < 3; i++ {
+		elements = append(elements, (*element.AlphaNumericDataElement)(nil))
+	}
+	elements = append(elements, t.Kind)
+	elements = append(elements, t.Xyz)
+	return elements
+}
+
+// UnmarshalHBCI unmarshals value into t
+func (t *TestGroupDataElement) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) > 0 && len(elements[0]) > 0 {
+		t.Abc = &element.AlphaNumericDataElement{}
+		err = t.Abc.UnmarshalHBCI(elements[0])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling Abc: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element Abc")
+	}
+	t.Def = nil
+	if len(elements) > 1 && len(elements[1]) > 0 {
+		t.Def = &element.NumberDataElement{}
+		err = t.Def.UnmarshalHBCI(elements[1])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling Def: %w", err)
+		}
+	}
+	t.Ids = nil
+	for i := 2; i < len(elements) && i < 5; i++ {
+		if len(elements[i]) == 0 {
+			continue
+		}
+		elem := &element.AlphaNumericDataElement{}
+		if err := elem.UnmarshalHBCI(elements[i]); err != nil {
+			return fmt.Errorf("error unmarshaling Ids: %w", err)
+		}
+		t.Ids = append(t.Ids, elem)
+	}
+	if len(elements) > 5 && len(elements[5]) > 0 {
+		t.Kind = element.NewCode("", 2, []string{"A", "01", "02"})
+		err = t.Kind.UnmarshalHBCI(elements[5])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling Kind: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element Kind")
+	}
+	if len(elements) > 6 && len(elements[6]) > 0 {
+		t.Xyz = &element.BooleanDataElement{}
+		err = t.Xyz.UnmarshalHBCI(bytes.Join(elements[6:], []byte(":")))
+		if err != nil {
+			return fmt.Errorf("error unmarshaling Xyz: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element Xyz")
+	}
+	t.DataElement = NewGroupDataElementGroup(testGDEG, len(t.Elements()), t)
+	return nil
+}
+
+// Validate validates the elements of t against their definition
+func (t *TestGroupDataElement) Validate() error {
+	if t.Abc == nil {
+		return fmt.Errorf("TestGroupDataElement: missing required element Abc")
+	}
+	if t.Abc.Length() > 6 {
+		return fmt.Errorf("TestGroupDataElement: Abc exceeds the maximum length of 6")
+	}
+	if !t.Abc.IsValid() {
+		return fmt.Errorf("TestGroupDataElement: Abc is invalid")
+	}
+	if t.Def != nil && t.Def.Length() > 3 {
+		return fmt.Errorf("TestGroupDataElement: Def exceeds the maximum length of 3")
+	}
+	if t.Def != nil && !t.Def.IsValid() {
+		return fmt.Errorf("TestGroupDataElement: Def is invalid")
+	}
+	if len(t.Ids) > 3 {
+		return fmt.Errorf("TestGroupDataElement: Ids has %d elements, must have at most 3", len(t.Ids))
+	}
+	for _, elem := range t.Ids {
+		if elem == nil {
+			return fmt.Errorf("TestGroupDataElement: Ids contains an empty element")
+		}
+		if elem.Length() > 6 {
+			return fmt.Errorf("TestGroupDataElement: Ids exceeds the maximum length of 6")
+		}
+		if !elem.IsValid() {
+			return fmt.Errorf("TestGroupDataElement: Ids is invalid")
+		}
+	}
+	if t.Kind == nil {
+		return fmt.Errorf("TestGroupDataElement: missing required element Kind")
+	}
+	if t.Kind.Length() > 2 {
+		return fmt.Errorf("TestGroupDataElement: Kind exceeds the maximum length of 2")
+	}
+	if !t.Kind.IsValid() {
+		return fmt.Errorf("TestGroupDataElement: Kind is invalid")
+	}
+	if t.Xyz == nil {
+		return fmt.Errorf("TestGroupDataElement: missing required element Xyz")
+	}
+	if !t.Xyz.IsValid() {
+		return fmt.Errorf("TestGroupDataElement: Xyz is invalid")
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Code generated by *generator.GroupDataElementGroupGenerator; DO NOT EDIT.
+
+package test_files
+
+import (
+	"fmt"
+
+	"github.com/mitch000001/go-hbci/element"
+)
+
+// Elements returns the elements of this DataElement.
+func (t *TestRepeatedGroupDataElement) Elements() []DataElement {
+	var elements []DataElement
+	elements = append(elements, t.Abc)
+	for _, elem := range t.Ids {
+		elements = append(elements, elem)
+	}
+	return elements
+}
+
+// UnmarshalHBCI unmarshals value into t
+func (t *TestRepeatedGroupDataElement) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) > 0 && len(elements[0]) > 0 {
+		t.Abc = &element.AlphaNumericDataElement{}
+		err = t.Abc.UnmarshalHBCI(elements[0])
+		if err != nil {
+			return fmt.Errorf("error unmarshaling Abc: %w", err)
+		}
+	} else {
+		return fmt.Errorf("malformed marshaled value: missing required element Abc")
+	}
+	t.Ids = nil
+	for i := 1; i < len(elements); i++ {
+		if len(elements[i]) == 0 {
+			continue
+		}
+		elem := &element.NumberDataElement{}
+		if err := elem.UnmarshalHBCI(elements[i]); err != nil {
+			return fmt.Errorf("error unmarshaling Ids: %w", err)
+		}
+		t.Ids = append(t.Ids, elem)
+	}
+	if len(t.Ids) < 2 {
+		return fmt.Errorf("malformed marshaled value: Ids requires at least 2 elements, got %d", len(t.Ids))
+	}
+	t.DataElement = NewGroupDataElementGroup(testGDEG, len(t.Elements()), t)
+	return nil
+}
+
+// Validate validates the elements of t against their definition
+func (t *TestRepeatedGroupDataElement) Validate() error {
+	if t.Abc == nil {
+		return fmt.Errorf("TestRepeatedGroupDataElement: missing required element Abc")
+	}
+	if t.Abc.Length() > 35 {
+		return fmt.Errorf("TestRepeatedGroupDataElement: Abc exceeds the maximum length of 35")
+	}
+	if !t.Abc.IsValid() {
+		return fmt.Errorf("TestRepeatedGroupDataElement: Abc is invalid")
+	}
+	if len(t.Ids) < 2 {
+		return fmt.Errorf("TestRepeatedGroupDataElement: Ids has %d elements, must have at least 2", len(t.Ids))
+	}
+	for _, elem := range t.Ids {
+		if elem == nil {
+			return fmt.Errorf("TestRepeatedGroupDataElement: Ids contains an empty element")
+		}
+		if !elem.IsValid() {
+			return fmt.Errorf("TestRepeatedGroupDataElement: Ids is invalid")
+		}
+	}
+	return nil
+}
@@ -10,6 +10,19 @@ import (
 
 func ExtractElements([]byte) ([][]byte, error)                { return nil, nil }
 func SegmentFromHeaderBytes([]byte, Segment) (Segment, error) { return nil, nil }
+func NewGroupDataElementGroup(DataElementType, int, GroupDataElementGroup) DataElement {
+	return nil
+}
+
+type DataElement = element.DataElement
+
+type DataElementType int
+
+const testGDEG DataElementType = iota
+
+type GroupDataElementGroup interface {
+	Elements() []DataElement
+}
 
 type Segment interface {
 	ID() string
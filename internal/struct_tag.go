@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StructTagName is the name of the struct tag defining data elements
+const StructTagName = "hbci"
+
+// AnyDataElementGroup is the type of struct tags matching all data element
+// groups
+const AnyDataElementGroup = "deg"
+
+// StructTag represents the parsed struct tag of a data element. The tag starts
+// with the type of the data element, followed by its maximum length and the
+// options optional, min=N and max=N, i.e. `hbci:"an,6,optional,max=99"`.
+//
+// Code data elements define their valid values with the option codes, which
+// separates the values by "|" and allows ranges of numeric codes, i.e.
+// `hbci:"code,3,codes=1|2|900-997"`.
+type StructTag struct {
+	Type      string
+	MaxLength int
+	Optional  bool
+	Min       int
+	Max       int
+	Codes     []string
+}
+
+// ParseStructTag parses the value of a data element struct tag. Min defaults
+// to 1 for required data elements.
+func ParseStructTag(tag string) (StructTag, error) {
+	parts := strings.Split(tag, ",")
+	if parts[0] == "" {
+		return StructTag{}, fmt.Errorf("missing data element type")
+	}
+	parsed := StructTag{Type: parts[0]}
+	for _, part := range parts[1:] {
+		var err error
+		switch {
+		case part == "optional":
+			parsed.Optional = true
+		case strings.HasPrefix(part, "min="):
+			parsed.Min, err = strconv.Atoi(strings.TrimPrefix(part, "min="))
+		case strings.HasPrefix(part, "max="):
+			parsed.Max, err = strconv.Atoi(strings.TrimPrefix(part, "max="))
+		case strings.HasPrefix(part, "codes="):
+			parsed.Codes, err = parseCodes(strings.TrimPrefix(part, "codes="))
+		default:
+			parsed.MaxLength, err = strconv.Atoi(part)
+		}
+		if err != nil {
+			return StructTag{}, fmt.Errorf("malformed option %q: %w", part, err)
+		}
+	}
+	if parsed.Min == 0 && !parsed.Optional {
+		parsed.Min = 1
+	}
+	if parsed.Max != 0 && parsed.Max < parsed.Min {
+		return StructTag{}, fmt.Errorf("max=%d is less than min=%d", parsed.Max, parsed.Min)
+	}
+	return parsed, nil
+}
+
+// parseCodes parses the values of the codes option. Ranges keep the number of
+// digits of their lower bound, i.e. 00-02 yields 00, 01 and 02.
+func parseCodes(value string) ([]string, error) {
+	var codes []string
+	for _, code := range strings.Split(value, "|") {
+		if code == "" {
+			return nil, fmt.Errorf("empty code")
+		}
+		lower, upper, isRange := strings.Cut(code, "-")
+		if !isRange {
+			codes = append(codes, code)
+			continue
+		}
+		from, err := strconv.Atoi(lower)
+		if err != nil {
+			return nil, err
+		}
+		to, err := strconv.Atoi(upper)
+		if err != nil {
+			return nil, err
+		}
+		if to < from {
+			return nil, fmt.Errorf("range %s is empty", code)
+		}
+		for i := from; i <= to; i++ {
+			codes = append(codes, fmt.Sprintf("%0*d", len(lower), i))
+		}
+	}
+	return codes, nil
+}
@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStructTag(t *testing.T) {
+	tests := []struct {
+		tag      string
+		expected StructTag
+		err      bool
+	}{
+		{"an,35", StructTag{Type: "an", MaxLength: 35, Min: 1}, false},
+		{"ktv,optional", StructTag{Type: "ktv", Optional: true}, false},
+		{"an,6,min=2,max=99", StructTag{Type: "an", MaxLength: 6, Min: 2, Max: 99}, false},
+		{"", StructTag{}, true},
+		{"an,foo", StructTag{}, true},
+		{"an,min=3,max=2", StructTag{}, true},
+		{"code,1,codes=1|2", StructTag{Type: "code", MaxLength: 1, Min: 1, Codes: []string{"1", "2"}}, false},
+		{"code,3,codes=1|998-999", StructTag{Type: "code", MaxLength: 3, Min: 1, Codes: []string{"1", "998", "999"}}, false},
+		{"code,2,codes=00-02", StructTag{Type: "code", MaxLength: 2, Min: 1, Codes: []string{"00", "01", "02"}}, false},
+		{"code,1,codes=1||2", StructTag{}, true},
+		{"code,1,codes=2-1", StructTag{}, true},
+		{"code,1,codes=a-b", StructTag{}, true},
+	}
+	for _, test := range tests {
+		parsed, err := ParseStructTag(test.tag)
+
+		if (err != nil) != test.err {
+			t.Logf("%q: Expected error %t, got %v\n", test.tag, test.err, err)
+			t.Fail()
+		}
+		if !reflect.DeepEqual(parsed, test.expected) {
+			t.Logf("%q: Expected tag to equal %+v, got %+v\n", test.tag, test.expected, parsed)
+			t.Fail()
+		}
+	}
+}
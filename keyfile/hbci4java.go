@@ -0,0 +1,332 @@
+package keyfile
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+// ErrWrongPassphrase is returned if a key file can not be decrypted with the
+// provided passphrase
+var ErrWrongPassphrase = errors.New("wrong passphrase")
+
+var (
+	hbci4JavaCipherSalt = []byte{0x26, 0x19, 0x38, 0xa7, 0x99, 0xbc, 0xf1, 0x55}
+)
+
+const (
+	hbci4JavaCipherIterations = 987
+	hbci4JavaRootElement      = "RDHNewData"
+	hbci4JavaOwnerBank        = "inst"
+	hbci4JavaOwnerUser        = "user"
+	hbci4JavaPartPublic       = "public"
+	hbci4JavaPartPrivate      = "private"
+	hbci4JavaSecurityMethod   = "RDH"
+	germanCountryCode         = 280
+)
+
+type hbci4JavaPassport struct {
+	XMLName     xml.Name
+	Country     string                 `xml:"country"`
+	BLZ         string                 `xml:"blz"`
+	Host        string                 `xml:"host"`
+	Port        string                 `xml:"port"`
+	FilterType  string                 `xml:"filtertype"`
+	UserID      string                 `xml:"userid"`
+	CustomerID  string                 `xml:"customerid"`
+	SysID       string                 `xml:"sysid"`
+	SigID       string                 `xml:"sigid"`
+	RDHProfile  string                 `xml:"rdhprofile,omitempty"`
+	HBCIVersion string                 `xml:"hbciversion"`
+	BPD         hbci4JavaProperties    `xml:"bpd"`
+	UPD         hbci4JavaProperties    `xml:"upd"`
+	Keys        []hbci4JavaPassportKey `xml:"key"`
+}
+
+type hbci4JavaProperties struct {
+	Entries []struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value,attr"`
+	} `xml:"entry"`
+}
+
+type hbci4JavaPassportKey struct {
+	Owner      string `xml:"owner,attr"`
+	Type       string `xml:"type,attr"`
+	Part       string `xml:"part,attr"`
+	Country    string `xml:"country"`
+	BLZ        string `xml:"blz"`
+	UserID     string `xml:"userid"`
+	KeyNumber  string `xml:"keynum"`
+	KeyVersion string `xml:"keyversion"`
+	KeyData    string `xml:"keydata"`
+}
+
+// ReadHBCI4JavaPassport reads a RDHNew passport file of HBCI4Java from r,
+// decrypting it with passphrase. It returns an error wrapping
+// ErrWrongPassphrase if the file can not be decrypted.
+func ReadHBCI4JavaPassport(r io.Reader, passphrase string) (*KeyFile, error) {
+	encrypted, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading passport: %w", err)
+	}
+	decrypted, err := newPBEWithMD5AndDES(passphrase, hbci4JavaCipherSalt, hbci4JavaCipherIterations).decrypt(encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting passport: %w", err)
+	}
+	var passport hbci4JavaPassport
+	if err := xml.Unmarshal(decrypted, &passport); err != nil {
+		// a wrong passphrase can yield valid padding by chance
+		return nil, fmt.Errorf("error unmarshaling passport: %v: %w", err, ErrWrongPassphrase)
+	}
+	countryCode, err := parseCountry(passport.Country)
+	if err != nil {
+		return nil, err
+	}
+	k := &KeyFile{
+		BankID:         domain.BankID{CountryCode: countryCode, ID: passport.BLZ},
+		UserID:         passport.UserID,
+		CustomerID:     passport.CustomerID,
+		SystemID:       passport.SysID,
+		Host:           passport.Host,
+		HBCIVersion:    passport.HBCIVersion,
+		SecurityMethod: hbci4JavaSecurityMethod,
+	}
+	if k.SignatureID, err = parseOptionalInt(passport.SigID); err != nil {
+		return nil, fmt.Errorf("malformed sigid: %w", err)
+	}
+	if k.Port, err = parseOptionalInt(passport.Port); err != nil {
+		return nil, fmt.Errorf("malformed port: %w", err)
+	}
+	if k.ProfileVersion, err = parseOptionalInt(passport.RDHProfile); err != nil {
+		return nil, fmt.Errorf("malformed rdhprofile: %w", err)
+	}
+	for _, key := range []struct {
+		dest    **domain.RSAKey
+		owner   string
+		keyType domain.KeyType
+	}{
+		{&k.SigningKey, hbci4JavaOwnerUser, domain.KeyTypeSigning},
+		{&k.EncryptionKey, hbci4JavaOwnerUser, domain.KeyTypeEncryption},
+		{&k.BankSigningKey, hbci4JavaOwnerBank, domain.KeyTypeSigning},
+		{&k.BankEncryptionKey, hbci4JavaOwnerBank, domain.KeyTypeEncryption},
+	} {
+		*key.dest, err = passport.readKey(key.owner, key.keyType)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s key %s: %w", key.owner, key.keyType, err)
+		}
+	}
+	return k, nil
+}
+
+func (p *hbci4JavaPassport) findKey(owner string, keyType domain.KeyType, part string) *hbci4JavaPassportKey {
+	for i, key := range p.Keys {
+		if key.Owner == owner && key.Type == keyType.String() && key.Part == part {
+			return &p.Keys[i]
+		}
+	}
+	return nil
+}
+
+// readKey returns the key for owner and keyType. For user keys the private
+// part is used, as it contains the public part as well.
+func (p *hbci4JavaPassport) readKey(owner string, keyType domain.KeyType) (*domain.RSAKey, error) {
+	part := hbci4JavaPartPublic
+	if owner == hbci4JavaOwnerUser {
+		part = hbci4JavaPartPrivate
+	}
+	key := p.findKey(owner, keyType, part)
+	if key == nil {
+		return nil, nil
+	}
+	keyName, err := key.keyName(keyType)
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(key.KeyData), ""))
+	if err != nil {
+		return nil, fmt.Errorf("malformed keydata: %w", err)
+	}
+	encoded, err := unmarshalJavaKey(data)
+	if err != nil {
+		return nil, err
+	}
+	if part == hbci4JavaPartPrivate {
+		parsed, err := x509.ParsePKCS8PrivateKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing private key: %w", err)
+		}
+		privateKey, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", parsed)
+		}
+		return domain.NewRSAKey(domain.NewPrivateKey(keyType, privateKey), keyName), nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing public key: %w", err)
+	}
+	publicKey, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key type %T", parsed)
+	}
+	pubKey := domain.NewEncryptionKey(publicKey.N.Bytes(), big.NewInt(int64(publicKey.E)).Bytes())
+	pubKey.Type = keyType.String()
+	return domain.NewRSAKey(pubKey, keyName), nil
+}
+
+func (k *hbci4JavaPassportKey) keyName(keyType domain.KeyType) (*domain.KeyName, error) {
+	countryCode, err := parseCountry(k.Country)
+	if err != nil {
+		return nil, err
+	}
+	keyNumber, err := strconv.Atoi(k.KeyNumber)
+	if err != nil {
+		return nil, fmt.Errorf("malformed keynum: %w", err)
+	}
+	keyVersion, err := strconv.Atoi(k.KeyVersion)
+	if err != nil {
+		return nil, fmt.Errorf("malformed keyversion: %w", err)
+	}
+	return &domain.KeyName{
+		BankID:     domain.BankID{CountryCode: countryCode, ID: k.BLZ},
+		UserID:     k.UserID,
+		KeyType:    keyType,
+		KeyNumber:  keyNumber,
+		KeyVersion: keyVersion,
+	}, nil
+}
+
+// WriteHBCI4JavaPassport writes the key file as RDHNew passport file of
+// HBCI4Java to w, encrypted with passphrase. Bank and user parameter data are
+// not part of a KeyFile, so HBCI4Java fetches them again on first use. As the
+// passport only holds RDH profiles, an error is returned for other security
+// methods.
+func (k *KeyFile) WriteHBCI4JavaPassport(w io.Writer, passphrase string) error {
+	if k.SecurityMethod != "" && k.SecurityMethod != hbci4JavaSecurityMethod {
+		return fmt.Errorf("security method %s not supported by HBCI4Java passports", k.SecurityMethod)
+	}
+	passport := hbci4JavaPassport{
+		XMLName:     xml.Name{Local: hbci4JavaRootElement},
+		Country:     formatCountry(k.BankID.CountryCode),
+		BLZ:         k.BankID.ID,
+		Host:        k.Host,
+		Port:        strconv.Itoa(k.Port),
+		FilterType:  "None",
+		UserID:      k.UserID,
+		CustomerID:  k.CustomerID,
+		SysID:       k.SystemID,
+		SigID:       strconv.Itoa(k.SignatureID),
+		HBCIVersion: k.HBCIVersion,
+	}
+	if k.ProfileVersion != 0 {
+		passport.RDHProfile = strconv.Itoa(k.ProfileVersion)
+	}
+	for _, key := range []struct {
+		key   *domain.RSAKey
+		owner string
+	}{
+		{k.BankSigningKey, hbci4JavaOwnerBank},
+		{k.BankEncryptionKey, hbci4JavaOwnerBank},
+		{k.SigningKey, hbci4JavaOwnerUser},
+		{k.EncryptionKey, hbci4JavaOwnerUser},
+	} {
+		if key.key == nil {
+			continue
+		}
+		keys, err := marshalHBCI4JavaKeys(key.key, key.owner)
+		if err != nil {
+			return err
+		}
+		passport.Keys = append(passport.Keys, keys...)
+	}
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(passport); err != nil {
+		return fmt.Errorf("error marshaling passport: %w", err)
+	}
+	encrypted, err := newPBEWithMD5AndDES(passphrase, hbci4JavaCipherSalt, hbci4JavaCipherIterations).encrypt(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("error encrypting passport: %w", err)
+	}
+	if _, err := w.Write(encrypted); err != nil {
+		return fmt.Errorf("error writing passport: %w", err)
+	}
+	return nil
+}
+
+// marshalHBCI4JavaKeys returns the public part of key and, if available, its
+// private part
+func marshalHBCI4JavaKeys(key *domain.RSAKey, owner string) ([]hbci4JavaPassportKey, error) {
+	keyName := key.KeyName()
+	newKey := func(part string, encoded []byte) hbci4JavaPassportKey {
+		return hbci4JavaPassportKey{
+			Owner:      owner,
+			Type:       keyName.KeyType.String(),
+			Part:       part,
+			Country:    formatCountry(keyName.BankID.CountryCode),
+			BLZ:        keyName.BankID.ID,
+			UserID:     keyName.UserID,
+			KeyNumber:  strconv.Itoa(keyName.KeyNumber),
+			KeyVersion: strconv.Itoa(keyName.KeyVersion),
+			KeyData:    base64.StdEncoding.EncodeToString(marshalJavaKey(part == hbci4JavaPartPrivate, encoded)),
+		}
+	}
+	publicKey := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(key.Modulus),
+		E: int(new(big.Int).SetBytes(key.Exponent).Int64()),
+	}
+	encodedPublicKey, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling public key: %w", err)
+	}
+	keys := []hbci4JavaPassportKey{newKey(hbci4JavaPartPublic, encodedPublicKey)}
+	if privateKey := key.PrivateKey(); privateKey != nil {
+		encodedPrivateKey, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling private key: %w", err)
+		}
+		keys = append(keys, newKey(hbci4JavaPartPrivate, encodedPrivateKey))
+	}
+	return keys, nil
+}
+
+// parseCountry parses the country of HBCI4Java, which is either an ISO 3166
+// code or the numeric country code used within HBCI
+func parseCountry(country string) (int, error) {
+	switch country {
+	case "DE", "":
+		return germanCountryCode, nil
+	}
+	countryCode, err := strconv.Atoi(country)
+	if err != nil {
+		return 0, fmt.Errorf("unsupported country %q", country)
+	}
+	return countryCode, nil
+}
+
+func formatCountry(countryCode int) string {
+	if countryCode == germanCountryCode {
+		return "DE"
+	}
+	return strconv.Itoa(countryCode)
+}
+
+func parseOptionalInt(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(strings.TrimSpace(value))
+}
@@ -0,0 +1,263 @@
+package keyfile
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/dialog"
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+func TestHBCI4JavaPassportRoundTrip(t *testing.T) {
+	keyFile := newTestKeyFile(t)
+
+	var buf bytes.Buffer
+	err := keyFile.WriteHBCI4JavaPassport(&buf, "secret")
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("<userid>")) {
+		t.Logf("Expected passport to be encrypted\n")
+		t.Fail()
+	}
+
+	actual, err := ReadHBCI4JavaPassport(&buf, "secret")
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	if actual.BankID != keyFile.BankID {
+		t.Logf("Expected BankID to equal %v, got %v\n", keyFile.BankID, actual.BankID)
+		t.Fail()
+	}
+	for _, field := range []struct{ name, expected, actual string }{
+		{"UserID", keyFile.UserID, actual.UserID},
+		{"CustomerID", keyFile.CustomerID, actual.CustomerID},
+		{"SystemID", keyFile.SystemID, actual.SystemID},
+		{"Host", keyFile.Host, actual.Host},
+		{"HBCIVersion", keyFile.HBCIVersion, actual.HBCIVersion},
+	} {
+		if field.expected != field.actual {
+			t.Logf("Expected %s to equal %q, got %q\n", field.name, field.expected, field.actual)
+			t.Fail()
+		}
+	}
+	if actual.SignatureID != keyFile.SignatureID || actual.Port != keyFile.Port || actual.SecurityMethod != keyFile.SecurityMethod || actual.ProfileVersion != keyFile.ProfileVersion {
+		t.Logf("Expected numeric fields to equal %+v, got %+v\n", keyFile, actual)
+		t.Fail()
+	}
+
+	for _, key := range []struct {
+		name     string
+		expected *domain.RSAKey
+		actual   *domain.RSAKey
+		private  bool
+	}{
+		{"SigningKey", keyFile.SigningKey, actual.SigningKey, true},
+		{"EncryptionKey", keyFile.EncryptionKey, actual.EncryptionKey, true},
+		{"BankSigningKey", keyFile.BankSigningKey, actual.BankSigningKey, false},
+		{"BankEncryptionKey", keyFile.BankEncryptionKey, actual.BankEncryptionKey, false},
+	} {
+		if key.actual == nil {
+			t.Logf("Expected %s not to be nil\n", key.name)
+			t.Fail()
+			continue
+		}
+		if !reflect.DeepEqual(key.expected.KeyName(), key.actual.KeyName()) {
+			t.Logf("Expected %s key name to equal %+v, got %+v\n", key.name, key.expected.KeyName(), key.actual.KeyName())
+			t.Fail()
+		}
+		if !bytes.Equal(key.expected.Modulus, key.actual.Modulus) || !bytes.Equal(key.expected.Exponent, key.actual.Exponent) {
+			t.Logf("Expected %s public part to equal\n", key.name)
+			t.Fail()
+		}
+		if key.actual.Type != key.expected.Type {
+			t.Logf("Expected %s type to equal %q, got %q\n", key.name, key.expected.Type, key.actual.Type)
+			t.Fail()
+		}
+		if key.private && (key.actual.PrivateKey() == nil || key.actual.PrivateKey().D.Cmp(key.expected.PrivateKey().D) != 0) {
+			t.Logf("Expected %s private part to equal\n", key.name)
+			t.Fail()
+		}
+		if !key.private && key.actual.PrivateKey() != nil {
+			t.Logf("Expected %s to have no private part\n", key.name)
+			t.Fail()
+		}
+	}
+}
+
+func TestReadHBCI4JavaPassportWrongPassphrase(t *testing.T) {
+	keyFile := newTestKeyFile(t)
+
+	var buf bytes.Buffer
+	err := keyFile.WriteHBCI4JavaPassport(&buf, "secret")
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	_, err = ReadHBCI4JavaPassport(&buf, "wrong")
+	if !errors.Is(err, ErrWrongPassphrase) {
+		t.Logf("Expected error to be ErrWrongPassphrase, got %T:%v\n", err, err)
+		t.Fail()
+	}
+}
+
+func TestJavaKeyRoundTrip(t *testing.T) {
+	encoded := []byte{0x30, 0x03, 0x02, 0x01, 0x00}
+
+	for _, private := range []bool{true, false} {
+		serialized := marshalJavaKey(private, encoded)
+		if !bytes.HasPrefix(serialized, javaStreamMagic) {
+			t.Logf("Expected serialized key to start with stream magic, got %X\n", serialized[:4])
+			t.Fail()
+		}
+
+		actual, err := unmarshalJavaKey(serialized)
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+		if !bytes.Equal(encoded, actual) {
+			t.Logf("Expected encoded key to equal %X, got %X\n", encoded, actual)
+			t.Fail()
+		}
+	}
+
+	actual, err := unmarshalJavaKey(encoded)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if !bytes.Equal(encoded, actual) {
+		t.Logf("Expected plain encoded key to be returned as is, got %X\n", actual)
+		t.Fail()
+	}
+}
+
+func TestKeyFileRDHConfig(t *testing.T) {
+	keyFile := newTestKeyFile(t)
+
+	config, err := keyFile.RDHConfig(dialog.Config{
+		HBCIURL:     "https://localhost",
+		HBCIVersion: segment.FINTS300,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	d, err := dialog.NewRDHDialog(config)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if d.ClientSystemID != keyFile.SystemID {
+		t.Logf("Expected client system ID to equal %q, got %q\n", keyFile.SystemID, d.ClientSystemID)
+		t.Fail()
+	}
+	if d.SigningKey() != keyFile.SigningKey || d.BankEncryptionKey() != keyFile.BankEncryptionKey {
+		t.Logf("Expected dialog to use the keys of the key file\n")
+		t.Fail()
+	}
+
+	exported := NewKeyFile(d, "customer")
+	if exported.SystemID != keyFile.SystemID || exported.BankID != keyFile.BankID || exported.UserID != keyFile.UserID {
+		t.Logf("Expected exported key file to equal %+v, got %+v\n", keyFile, exported)
+		t.Fail()
+	}
+	if exported.SignatureID != keyFile.SignatureID || exported.SecurityMethod != keyFile.SecurityMethod || exported.ProfileVersion != keyFile.ProfileVersion {
+		t.Logf("Expected exported key file to equal %+v, got %+v\n", keyFile, exported)
+		t.Fail()
+	}
+}
+
+func TestKeyFileRDHConfigProfile(t *testing.T) {
+	tests := []struct {
+		securityMethod string
+		profileVersion int
+		expected       *message.RDHProfile
+		expectErr      bool
+	}{
+		{"", 0, nil, false},
+		{"RDH", 2, message.RDH2Profile, false},
+		{"RDH", 10, message.RDH10Profile, false},
+		{"RAH", 10, message.RAH10Profile, false},
+		{"RDH", 5, nil, true},
+		{"RAH", 0, nil, true},
+		{"DDV", 1, nil, true},
+	}
+	for _, test := range tests {
+		keyFile := &KeyFile{SecurityMethod: test.securityMethod, ProfileVersion: test.profileVersion}
+
+		config, err := keyFile.RDHConfig(dialog.Config{})
+
+		if test.expectErr && err == nil {
+			t.Logf("Expected error for profile %s-%d\n", test.securityMethod, test.profileVersion)
+			t.Fail()
+		}
+		if !test.expectErr && err != nil {
+			t.Logf("Expected no error for profile %s-%d, got %T:%v\n", test.securityMethod, test.profileVersion, err, err)
+			t.Fail()
+		}
+		if config.Profile != test.expected {
+			t.Logf("Expected profile of %s-%d to equal %v, got %v\n", test.securityMethod, test.profileVersion, test.expected, config.Profile)
+			t.Fail()
+		}
+	}
+}
+
+func TestWriteHBCI4JavaPassportRAH(t *testing.T) {
+	keyFile := newTestKeyFile(t)
+	keyFile.SecurityMethod = "RAH"
+	keyFile.ProfileVersion = 10
+
+	err := keyFile.WriteHBCI4JavaPassport(&bytes.Buffer{}, "secret")
+	if err == nil {
+		t.Logf("Expected error for RAH profile\n")
+		t.Fail()
+	}
+}
+
+func newTestKeyFile(t *testing.T) *KeyFile {
+	bankID := domain.BankID{CountryCode: 280, ID: "10000000"}
+	newKey := func(keyType domain.KeyType, userID string, number int, private bool) *domain.RSAKey {
+		var key *domain.PublicKey
+		var err error
+		if keyType == domain.KeyTypeSigning {
+			key, err = domain.GenerateSigningKey()
+		} else {
+			key, err = domain.GenerateEncryptionKey()
+		}
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+		if !private {
+			publicKey := domain.NewEncryptionKey(key.Modulus, key.Exponent)
+			publicKey.Type = keyType.String()
+			key = publicKey
+		}
+		return domain.NewRSAKey(key, &domain.KeyName{
+			BankID:     bankID,
+			UserID:     userID,
+			KeyType:    keyType,
+			KeyNumber:  number,
+			KeyVersion: number + 1,
+		})
+	}
+	return &KeyFile{
+		BankID:            bankID,
+		UserID:            "user",
+		CustomerID:        "customer",
+		SystemID:          "SYSTEM123",
+		SignatureID:       42,
+		Host:              "hbci.example.com",
+		Port:              3000,
+		HBCIVersion:       "300",
+		SecurityMethod:    "RDH",
+		ProfileVersion:    2,
+		SigningKey:        newKey(domain.KeyTypeSigning, "user", 1, true),
+		EncryptionKey:     newKey(domain.KeyTypeEncryption, "user", 1, true),
+		BankSigningKey:    newKey(domain.KeyTypeSigning, "10000000", 3, false),
+		BankEncryptionKey: newKey(domain.KeyTypeEncryption, "10000000", 4, false),
+	}
+}
@@ -0,0 +1,124 @@
+package keyfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// Java serializes RSA keys as java.security.KeyRep, which holds the key in
+// its encoded form, i.e. X.509 for public and PKCS#8 for private keys.
+
+var (
+	javaStreamMagic = []byte{0xAC, 0xED, 0x00, 0x05}
+	// javaByteArrayClassDesc is the class descriptor of byte[] followed by
+	// the end of its annotations and a null super class
+	javaByteArrayClassDesc = []byte{
+		0x72, 0x00, 0x02, '[', 'B',
+		0xAC, 0xF3, 0x17, 0xF8, 0x06, 0x08, 0x54, 0xE0,
+		0x02, 0x00, 0x00, 0x78, 0x70,
+	}
+)
+
+const (
+	javaTCNull          = 0x70
+	javaTCReference     = 0x71
+	javaTCClassDesc     = 0x72
+	javaTCObject        = 0x73
+	javaTCString        = 0x74
+	javaTCArray         = 0x75
+	javaTCEndBlockData  = 0x78
+	javaTCEnum          = 0x7E
+	javaSCSerializable  = 0x02
+	javaSCEnum          = 0x10
+	javaBaseWireHandle  = 0x7E0000
+	javaKeyRepClassName = "java.security.KeyRep"
+)
+
+// unmarshalJavaKey returns the encoded key contained in data. data may either
+// be a serialized java.security.KeyRep or the encoded key itself.
+func unmarshalJavaKey(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, javaStreamMagic) {
+		return data, nil
+	}
+	if !bytes.Contains(data, []byte(javaKeyRepClassName)) {
+		return nil, fmt.Errorf("malformed key data: no serialized %s", javaKeyRepClassName)
+	}
+	idx := bytes.Index(data, javaByteArrayClassDesc)
+	if idx == -1 {
+		return nil, fmt.Errorf("malformed key data: missing encoded key")
+	}
+	data = data[idx+len(javaByteArrayClassDesc):]
+	if len(data) < 4 {
+		return nil, fmt.Errorf("malformed key data: missing encoded key length")
+	}
+	length := int(binary.BigEndian.Uint32(data))
+	data = data[4:]
+	if length > len(data) {
+		return nil, fmt.Errorf("malformed key data: encoded key too short")
+	}
+	return data[:length], nil
+}
+
+// marshalJavaKey serializes the encoded key as java.security.KeyRep
+func marshalJavaKey(private bool, encoded []byte) []byte {
+	keyType, format := "PUBLIC", "X.509"
+	if private {
+		keyType, format = "PRIVATE", "PKCS#8"
+	}
+	var buf bytes.Buffer
+	writeString := func(s string) {
+		binary.Write(&buf, binary.BigEndian, uint16(len(s)))
+		buf.WriteString(s)
+	}
+	writeClassDesc := func(name string, serialVersionUID uint64, flags byte) {
+		buf.WriteByte(javaTCClassDesc)
+		writeString(name)
+		binary.Write(&buf, binary.BigEndian, serialVersionUID)
+		buf.WriteByte(flags)
+	}
+	buf.Write(javaStreamMagic)
+	buf.WriteByte(javaTCObject)
+	// handle 0: class descriptor of KeyRep
+	writeClassDesc(javaKeyRepClassName, 0xBDF94FB3889AA543, javaSCSerializable)
+	binary.Write(&buf, binary.BigEndian, uint16(4))
+	buf.WriteByte('L')
+	writeString("algorithm")
+	buf.WriteByte(javaTCString) // handle 1
+	writeString("Ljava/lang/String;")
+	buf.WriteByte('[')
+	writeString("encoded")
+	buf.WriteByte(javaTCString) // handle 2
+	writeString("[B")
+	buf.WriteByte('L')
+	writeString("format")
+	buf.WriteByte(javaTCReference)
+	binary.Write(&buf, binary.BigEndian, uint32(javaBaseWireHandle+1))
+	buf.WriteByte('L')
+	writeString("type")
+	buf.WriteByte(javaTCString) // handle 3
+	writeString("Ljava/security/KeyRep$Type;")
+	buf.WriteByte(javaTCEndBlockData)
+	buf.WriteByte(javaTCNull)
+	// handle 4: the KeyRep object itself, followed by its field values
+	buf.WriteByte(javaTCString) // handle 5
+	writeString("RSA")
+	buf.WriteByte(javaTCArray)
+	buf.Write(javaByteArrayClassDesc) // handle 6, handle 7 for the array
+	binary.Write(&buf, binary.BigEndian, uint32(len(encoded)))
+	buf.Write(encoded)
+	buf.WriteByte(javaTCString) // handle 8
+	writeString(format)
+	buf.WriteByte(javaTCEnum)
+	writeClassDesc("java.security.KeyRep$Type", 0, javaSCSerializable|javaSCEnum) // handle 9
+	binary.Write(&buf, binary.BigEndian, uint16(0))
+	buf.WriteByte(javaTCEndBlockData)
+	writeClassDesc("java.lang.Enum", 0, javaSCSerializable|javaSCEnum) // handle 10
+	binary.Write(&buf, binary.BigEndian, uint16(0))
+	buf.WriteByte(javaTCEndBlockData)
+	buf.WriteByte(javaTCNull)
+	// handle 11 is the enum constant, named by handle 12
+	buf.WriteByte(javaTCString)
+	writeString(keyType)
+	return buf.Bytes()
+}
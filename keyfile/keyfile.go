@@ -0,0 +1,109 @@
+// Package keyfile provides import and export of RDH key files created by
+// other HBCI software.
+//
+// A KeyFile contains the user keys with their private parts, the public keys
+// of the bank and the synchronized client system ID. Importing it allows to
+// continue using an already initialized RDH access without sending new keys
+// and INI letters to the bank.
+//
+// The only supported format is the passport format of HBCI4Java (RDHNew), as
+// used by e.g. Hibiscus. The binary key file format of the ZKA, used by e.g.
+// StarMoney and SFirm, is out of scope of this package. Keys stored within it
+// have to be converted with the originating software.
+package keyfile
+
+import (
+	"fmt"
+
+	"github.com/mitch000001/go-hbci/dialog"
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/message"
+)
+
+// KeyFile represents the contents of a RDH key file
+type KeyFile struct {
+	BankID     domain.BankID
+	UserID     string
+	CustomerID string
+	// SystemID is the client system ID synchronized with the bank
+	SystemID string
+	// SignatureID is the signature ID of the next signed message
+	SignatureID int
+	// Host and Port define the address of the bank server
+	Host string
+	Port int
+	// HBCIVersion as stored by the originating software, e.g. "300"
+	HBCIVersion string
+	// SecurityMethod is the security method of the profile, either "RDH" or
+	// "RAH". An empty value denotes "RDH".
+	SecurityMethod string
+	// ProfileVersion is the version of the security profile. Zero denotes
+	// the default profile of dialog.RDHConfig.
+	ProfileVersion int
+	// SigningKey and EncryptionKey are the user keys including their
+	// private parts
+	SigningKey    *domain.RSAKey
+	EncryptionKey *domain.RSAKey
+	// BankSigningKey and BankEncryptionKey are the public keys of the bank.
+	// BankSigningKey is nil for banks not signing their messages.
+	BankSigningKey    *domain.RSAKey
+	BankEncryptionKey *domain.RSAKey
+}
+
+// NewKeyFile creates a KeyFile from the keys and the client system ID of d
+func NewKeyFile(d *dialog.RDHDialog, customerID string) *KeyFile {
+	signingKeyName := d.SigningKey().KeyName()
+	return &KeyFile{
+		BankID:            signingKeyName.BankID,
+		UserID:            signingKeyName.UserID,
+		CustomerID:        customerID,
+		SystemID:          d.ClientSystemID,
+		SignatureID:       d.SignatureID(),
+		SecurityMethod:    d.Profile().SecurityMethod,
+		ProfileVersion:    d.Profile().Version,
+		SigningKey:        d.SigningKey(),
+		EncryptionKey:     d.EncryptionKey(),
+		BankSigningKey:    d.BankSigningKey(),
+		BankEncryptionKey: d.BankEncryptionKey(),
+	}
+}
+
+// RDHConfig returns a RDHConfig based on config, populated with the keys, the
+// client system ID, the signature ID and the security profile of the key file.
+// It returns an error if the security profile is unknown.
+func (k *KeyFile) RDHConfig(config dialog.Config) (dialog.RDHConfig, error) {
+	config.BankID = k.BankID
+	config.UserID = k.UserID
+	profile, err := k.profile()
+	if err != nil {
+		return dialog.RDHConfig{}, err
+	}
+	return dialog.RDHConfig{
+		Config:            config,
+		SigningKey:        k.SigningKey,
+		EncryptionKey:     k.EncryptionKey,
+		BankSigningKey:    k.BankSigningKey,
+		BankEncryptionKey: k.BankEncryptionKey,
+		ClientSystemID:    k.SystemID,
+		SignatureID:       k.SignatureID,
+		Profile:           profile,
+	}, nil
+}
+
+// profile returns the security profile of the key file, or nil for the default
+// profile
+func (k *KeyFile) profile() (*message.RDHProfile, error) {
+	securityMethod := k.SecurityMethod
+	if securityMethod == "" {
+		securityMethod = message.RDH2Profile.SecurityMethod
+	}
+	if securityMethod == message.RDH2Profile.SecurityMethod && k.ProfileVersion == 0 {
+		return nil, nil
+	}
+	for _, profile := range []*message.RDHProfile{message.RDH2Profile, message.RDH10Profile, message.RAH10Profile} {
+		if profile.SecurityMethod == securityMethod && profile.Version == k.ProfileVersion {
+			return profile, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported security profile %s-%d", securityMethod, k.ProfileVersion)
+}
@@ -0,0 +1,64 @@
+package keyfile
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/md5"
+	"fmt"
+)
+
+// pbeWithMD5AndDES implements the password based encryption scheme 1 of
+// PKCS#5 with MD5 and DES-CBC, as provided by the Java cipher
+// "PBEWithMD5AndDES".
+type pbeWithMD5AndDES struct {
+	key []byte
+	iv  []byte
+}
+
+func newPBEWithMD5AndDES(passphrase string, salt []byte, iterations int) *pbeWithMD5AndDES {
+	// Java only uses the lower 8 bit of each character
+	runes := []rune(passphrase)
+	password := make([]byte, len(runes))
+	for i, r := range runes {
+		password[i] = byte(r)
+	}
+	derived := md5.Sum(append(password, salt...))
+	for i := 1; i < iterations; i++ {
+		derived = md5.Sum(derived[:])
+	}
+	return &pbeWithMD5AndDES{key: derived[:8], iv: derived[8:]}
+}
+
+func (p *pbeWithMD5AndDES) encrypt(data []byte) ([]byte, error) {
+	block, err := des.NewCipher(p.key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+	padLength := des.BlockSize - len(data)%des.BlockSize
+	encrypted := append(append([]byte(nil), data...), bytes.Repeat([]byte{byte(padLength)}, padLength)...)
+	cipher.NewCBCEncrypter(block, p.iv).CryptBlocks(encrypted, encrypted)
+	return encrypted, nil
+}
+
+func (p *pbeWithMD5AndDES) decrypt(data []byte) ([]byte, error) {
+	block, err := des.NewCipher(p.key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+	if len(data) == 0 || len(data)%des.BlockSize != 0 {
+		return nil, fmt.Errorf("malformed encrypted data: length not a multiple of the block size")
+	}
+	decrypted := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, p.iv).CryptBlocks(decrypted, data)
+	padLength := int(decrypted[len(decrypted)-1])
+	if padLength == 0 || padLength > des.BlockSize {
+		return nil, ErrWrongPassphrase
+	}
+	for _, b := range decrypted[len(decrypted)-padLength:] {
+		if int(b) != padLength {
+			return nil, ErrWrongPassphrase
+		}
+	}
+	return decrypted[:len(decrypted)-padLength], nil
+}
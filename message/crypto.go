@@ -1,6 +1,10 @@
 package message
 
 import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/rand"
 	"fmt"
 
 	"github.com/mitch000001/go-hbci/domain"
@@ -57,3 +61,127 @@ func (p *pinTanCryptoProvider) WriteEncryptionHeader(header segment.EncryptionHe
 	header.SetEncryptionKeyName(p.key.KeyName())
 	header.SetEncryptionAlgorithm(element.NewPinTanEncryptionAlgorithm())
 }
+
+// encryptionHeaderReader is implemented by CryptoProviders which need data from
+// the encryption header of a received message to decrypt it
+type encryptionHeaderReader interface {
+	ReadEncryptionHeader(header segment.EncryptionHeader) error
+}
+
+// NewRDHCryptoProvider creates a new CryptoProvider for the RDH-2 profile.
+// Messages are encrypted with a random message key, which itself is encrypted
+// with the bank encryption key. Received messages are decrypted with userKey.
+func NewRDHCryptoProvider(bankKey *domain.RSAKey, userKey *domain.RSAKey, clientSystemID string) CryptoProvider {
+	return RDH2Profile.NewCryptoProvider(bankKey, userKey, clientSystemID)
+}
+
+type rdhCryptoProvider struct {
+	profile             *RDHProfile
+	bankKey             *domain.RSAKey
+	userKey             *domain.RSAKey
+	clientSystemID      string
+	securityFn          string
+	encryptedMessageKey []byte
+}
+
+func (r *rdhCryptoProvider) SetClientSystemID(clientSystemID string) {
+	r.clientSystemID = clientSystemID
+}
+
+func (r *rdhCryptoProvider) SetSecurityFunction(securityFn string) {
+	r.securityFn = securityFn
+}
+
+func (r *rdhCryptoProvider) Encrypt(message []byte) ([]byte, error) {
+	if r.bankKey == nil {
+		return nil, fmt.Errorf("no bank encryption key available")
+	}
+	messageKey := make([]byte, r.profile.messageKeyLength)
+	if _, err := rand.Read(messageKey); err != nil {
+		return nil, fmt.Errorf("error generating message key: %w", err)
+	}
+	encryptedMessageKey, err := r.profile.encryptMessageKey(r.bankKey, messageKey)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting message key: %w", err)
+	}
+	block, err := r.profile.newCipher(messageKey)
+	if err != nil {
+		return nil, err
+	}
+	encrypted := padMessage(message, block.BlockSize())
+	cipher.NewCBCEncrypter(block, make([]byte, block.BlockSize())).CryptBlocks(encrypted, encrypted)
+	r.encryptedMessageKey = encryptedMessageKey
+	return encrypted, nil
+}
+
+func (r *rdhCryptoProvider) Decrypt(encryptedMessage []byte) ([]byte, error) {
+	if r.encryptedMessageKey == nil {
+		return nil, fmt.Errorf("no message key available")
+	}
+	if r.userKey == nil {
+		return nil, fmt.Errorf("no user encryption key available")
+	}
+	messageKey, err := r.profile.decryptMessageKey(r.userKey, r.encryptedMessageKey)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting message key: %w", err)
+	}
+	block, err := r.profile.newCipher(messageKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(encryptedMessage)%block.BlockSize() != 0 {
+		return nil, fmt.Errorf("malformed encrypted message: length not a multiple of the block size")
+	}
+	decrypted := make([]byte, len(encryptedMessage))
+	cipher.NewCBCDecrypter(block, make([]byte, block.BlockSize())).CryptBlocks(decrypted, encryptedMessage)
+	return unpadMessage(decrypted, block.BlockSize())
+}
+
+func (r *rdhCryptoProvider) ReadEncryptionHeader(header segment.EncryptionHeader) error {
+	messageKey := header.MessageKey()
+	if len(messageKey) == 0 {
+		return fmt.Errorf("malformed encryption header: missing message key")
+	}
+	r.encryptedMessageKey = messageKey
+	return nil
+}
+
+func (r *rdhCryptoProvider) WriteEncryptionHeader(header segment.EncryptionHeader) {
+	header.SetClientSystemID(r.clientSystemID)
+	header.SetSecurityProfile(r.securityFn)
+	header.SetSecurityMethod(r.profile.SecurityMethod, r.profile.Version)
+	header.SetEncryptionKeyName(r.bankKey.KeyName())
+	header.SetEncryptionAlgorithm(r.profile.encryptionAlgorithm(r.encryptedMessageKey))
+}
+
+// tripleDESCipher returns a 2-Key-Triple-DES cipher for the 16 byte key
+func tripleDESCipher(key []byte) (cipher.Block, error) {
+	if len(key) != 16 {
+		return nil, fmt.Errorf("malformed message key: expected 16 bytes, got %d", len(key))
+	}
+	block, err := des.NewTripleDESCipher(append(append([]byte(nil), key...), key[:8]...))
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+	return block, nil
+}
+
+// padMessage pads message to a multiple of blockSize. The padding consists
+// of zeros and a final byte containing the padding length.
+func padMessage(message []byte, blockSize int) []byte {
+	padLength := blockSize - len(message)%blockSize
+	padding := bytes.Repeat([]byte{0}, padLength)
+	padding[padLength-1] = byte(padLength)
+	return append(append([]byte(nil), message...), padding...)
+}
+
+func unpadMessage(message []byte, blockSize int) ([]byte, error) {
+	if len(message) == 0 {
+		return nil, fmt.Errorf("malformed decrypted message: empty")
+	}
+	padLength := int(message[len(message)-1])
+	if padLength == 0 || padLength > blockSize || padLength > len(message) {
+		return nil, fmt.Errorf("malformed decrypted message: invalid padding")
+	}
+	return message[:len(message)-padLength], nil
+}
@@ -1,6 +1,7 @@
 package message
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/mitch000001/go-hbci/domain"
@@ -50,6 +51,7 @@ type decryptedMessage struct {
 	acknowledgements []domain.Acknowledgement
 	unmarshaler      *Unmarshaler
 	hbciVersion      segment.HBCIVersion
+	signatureStatus  SignatureStatus
 }
 
 // MarshalHBCI marshals d to HBCI wire format
@@ -128,3 +130,27 @@ func (d *decryptedMessage) SupportedSegments() []segment.VersionedSegment {
 	}
 	return versionedSegments
 }
+
+func (d *decryptedMessage) SignatureStatus() SignatureStatus {
+	return d.signatureStatus
+}
+
+func (d *decryptedMessage) verifySignature(verifier SignatureVerifier) error {
+	data, header, signatureEnd, err := signedData(d.unmarshaler.MarshaledSegments())
+	if errors.Is(err, ErrMissingSignature) {
+		d.signatureStatus = SignatureMissing
+		return err
+	}
+	if err == nil {
+		err = verifyKeyName(header, verifier)
+	}
+	if err == nil {
+		err = verifier.Verify(data, signatureEnd.SignatureData())
+	}
+	if err != nil {
+		d.signatureStatus = SignatureInvalid
+		return err
+	}
+	d.signatureStatus = SignatureValid
+	return nil
+}
@@ -20,6 +20,8 @@ type DialogInitializationClientMessage struct {
 	PublicSigningKeyRequest    *segment.PublicKeyRequestSegment
 	PublicEncryptionKeyRequest *segment.PublicKeyRequestSegment
 	PublicKeyRequest           *segment.PublicKeyRequestSegment
+	PublicSigningKeyRenewal    *segment.PublicKeyRenewalSegment
+	PublicEncryptionKeyRenewal *segment.PublicKeyRenewalSegment
 	hbciVersion                segment.HBCIVersion
 }
 
@@ -37,6 +39,8 @@ func (d *DialogInitializationClientMessage) HBCISegments() []segment.ClientSegme
 		d.PublicSigningKeyRequest,
 		d.PublicEncryptionKeyRequest,
 		d.PublicKeyRequest,
+		d.PublicSigningKeyRenewal,
+		d.PublicEncryptionKeyRenewal,
 	}
 }
 
@@ -47,6 +51,8 @@ func (d *DialogInitializationClientMessage) jobs() []segment.Segment {
 		d.TanRequest,
 		d.PublicSigningKeyRequest,
 		d.PublicEncryptionKeyRequest,
+		d.PublicSigningKeyRenewal,
+		d.PublicEncryptionKeyRenewal,
 	}
 }
 
@@ -50,6 +50,11 @@ func (e *EncryptedMessage) HBCISegments() []segment.ClientSegment {
 
 // Decrypt decrypts the message using the CryptoProvider
 func (e *EncryptedMessage) Decrypt(provider CryptoProvider) (BankMessage, error) {
+	if reader, ok := provider.(encryptionHeaderReader); ok && e.EncryptionHeader != nil {
+		if err := reader.ReadEncryptionHeader(e.EncryptionHeader); err != nil {
+			return nil, err
+		}
+	}
 	decryptedMessageBytes, err := provider.Decrypt(e.EncryptedData.Data.Val())
 	if err != nil {
 		return nil, err
@@ -40,3 +40,41 @@ func TestEncryptedPinTanMessageDecrypt(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestEncryptedRDHMessageDecrypt(t *testing.T) {
+	keyName := domain.NewInitialKeyName(280, "1", "userID", domain.KeyTypeEncryption)
+	pubKey, err := domain.GenerateEncryptionKey()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	userKey := domain.NewRSAKey(pubKey, keyName)
+
+	bankProvider := NewRDHCryptoProvider(userKey, nil, "0")
+	syncSegment := "HISYN:2:3:8+newClientSystemID'"
+	acknowledgement := "HIRMG:2:2:1+0100::Dialog beendet'"
+	body := fmt.Sprintf("%s%s", acknowledgement, syncSegment)
+	encryptedBody, err := bankProvider.Encrypt([]byte(body))
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	header := segment.NewMessageHeaderSegment(1, 300, "abcde", 1)
+	end := segment.NewMessageEndSegment(4, 1)
+	encryptedMessage := NewEncryptedMessage(header, end, segment.FINTS300)
+	encryptedMessage.EncryptionHeader = segment.FINTS300.PinTanEncryptionHeader("0", *keyName)
+	bankProvider.WriteEncryptionHeader(encryptedMessage.EncryptionHeader)
+	encryptedMessage.EncryptedData = segment.NewEncryptedDataSegment(encryptedBody)
+
+	provider := NewRDHCryptoProvider(nil, userKey, "0")
+	decryptedMessage, err := encryptedMessage.Decrypt(provider)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	actualSyncSegment := decryptedMessage.FindMarshaledSegment("HISYN")
+
+	if syncSegment != string(actualSyncSegment) {
+		t.Logf("Expected decrypted message to include SynchronisationResponse, got %q\n", actualSyncSegment)
+		t.Fail()
+	}
+}
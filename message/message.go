@@ -33,6 +33,9 @@ type BankMessage interface {
 	Message
 	Acknowledgements() []domain.Acknowledgement
 	SupportedSegments() []segment.VersionedSegment
+	// SignatureStatus returns the result of the verification of the bank
+	// signature, see VerifySignature
+	SignatureStatus() SignatureStatus
 }
 
 // HBCIMessage represents a basic set of message for introspecting HBCI messages
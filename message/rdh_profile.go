@@ -0,0 +1,214 @@
+package message
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/element"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// RDHProfile defines the algorithms used within a RDH or RAH security
+// profile
+type RDHProfile struct {
+	// SecurityMethod is either "RDH" or "RAH"
+	SecurityMethod string
+	// Version is the version of the security method
+	Version int
+	// KeySize is the size of the RSA keys in bits
+	KeySize int
+
+	hashAlgorithm       func() *element.HashAlgorithmDataElement
+	signatureAlgorithm  func() *element.SignatureAlgorithmDataElement
+	sign                func(key *domain.RSAKey, message []byte) ([]byte, error)
+	verify              func(key *domain.RSAKey, message, signature []byte) error
+	encryptionAlgorithm func(encryptedMessageKey []byte) *element.EncryptionAlgorithmDataElement
+	messageKeyLength    int
+	newCipher           func(messageKey []byte) (cipher.Block, error)
+	encryptMessageKey   func(key *domain.RSAKey, messageKey []byte) ([]byte, error)
+	decryptMessageKey   func(key *domain.RSAKey, encryptedMessageKey []byte) ([]byte, error)
+	iniHash             func(key *domain.RSAKey) []byte
+}
+
+var (
+	// RDH2Profile signs with ISO 9796-1 over RIPEMD-160 hashes and encrypts
+	// with 2-Key-Triple-DES message keys
+	RDH2Profile = &RDHProfile{
+		SecurityMethod:      "RDH",
+		Version:             2,
+		KeySize:             768,
+		hashAlgorithm:       element.NewDefaultHashAlgorithm,
+		signatureAlgorithm:  element.NewRDHSignatureAlgorithm,
+		sign:                signRIPEMD160,
+		verify:              verifyRIPEMD160,
+		encryptionAlgorithm: element.NewRDHEncryptionAlgorithm,
+		messageKeyLength:    16,
+		newCipher:           tripleDESCipher,
+		encryptMessageKey:   encryptMessageKeyRaw,
+		decryptMessageKey:   decryptMessageKeyRaw(16),
+		iniHash:             iniHashRIPEMD160,
+	}
+	// RDH10Profile signs with RSASSA-PSS over SHA-256 hashes and encrypts with
+	// AES-256 message keys, which are encrypted with RSAES-OAEP
+	RDH10Profile = &RDHProfile{
+		SecurityMethod:      "RDH",
+		Version:             10,
+		KeySize:             2048,
+		hashAlgorithm:       element.NewSHA256HashAlgorithm,
+		signatureAlgorithm:  element.NewPSSSignatureAlgorithm,
+		sign:                signPSS,
+		verify:              verifyPSS,
+		encryptionAlgorithm: element.NewAESEncryptionAlgorithm,
+		messageKeyLength:    32,
+		newCipher:           aesCipher,
+		encryptMessageKey:   encryptMessageKeyOAEP,
+		decryptMessageKey:   decryptMessageKeyOAEP,
+		iniHash:             iniHashSHA256,
+	}
+	// RAH10Profile signs with RSASSA-PSS over double SHA-256 hashes and
+	// encrypts with AES-256 message keys, which are zero padded and encrypted
+	// with the plain RSA function
+	RAH10Profile = &RDHProfile{
+		SecurityMethod:      "RAH",
+		Version:             10,
+		KeySize:             2048,
+		hashAlgorithm:       element.NewSHA256SHA256HashAlgorithm,
+		signatureAlgorithm:  element.NewPSSSignatureAlgorithm,
+		sign:                signDoublePSS,
+		verify:              verifyDoublePSS,
+		encryptionAlgorithm: element.NewAESEncryptionAlgorithm,
+		messageKeyLength:    32,
+		newCipher:           aesCipher,
+		encryptMessageKey:   encryptMessageKeyRaw,
+		decryptMessageKey:   decryptMessageKeyRaw(32),
+		iniHash:             iniHashSHA256,
+	}
+)
+
+// String returns the name of the profile, e.g. "RDH-10"
+func (r *RDHProfile) String() string {
+	return fmt.Sprintf("%s-%d", r.SecurityMethod, r.Version)
+}
+
+// IniHash returns the hash of key as printed on INI letters and used to
+// verify the public keys of the bank
+func (r *RDHProfile) IniHash(key *domain.RSAKey) []byte {
+	return r.iniHash(key)
+}
+
+// NewSignatureProvider creates a new SignatureProvider for signingKey which
+// signs according to the profile. signatureID is used for the first signature
+// and incremented with every further one.
+func (r *RDHProfile) NewSignatureProvider(signingKey *domain.RSAKey, signatureID int) RDHSignatureProvider {
+	return &rdhSignatureProvider{
+		profile:          r,
+		signingKey:       signingKey,
+		controlReference: generateControlReference(signingKey),
+		signatureID:      signatureID,
+		securityFn:       "1",
+	}
+}
+
+// NewSignatureVerifier creates a new SignatureVerifier which verifies
+// signatures of the bank according to the profile, using bankSigningKey
+func (r *RDHProfile) NewSignatureVerifier(bankSigningKey *domain.RSAKey) SignatureVerifier {
+	return &rdhSignatureVerifier{
+		profile:        r,
+		bankSigningKey: bankSigningKey,
+	}
+}
+
+// NewCryptoProvider creates a new CryptoProvider which encrypts according to
+// the profile. Messages are encrypted with a random message key, which itself
+// is encrypted with bankKey. Received messages are decrypted with userKey.
+func (r *RDHProfile) NewCryptoProvider(bankKey *domain.RSAKey, userKey *domain.RSAKey, clientSystemID string) CryptoProvider {
+	return &rdhCryptoProvider{
+		profile:        r,
+		bankKey:        bankKey,
+		userKey:        userKey,
+		clientSystemID: clientSystemID,
+		securityFn:     "1",
+	}
+}
+
+// iniHashRIPEMD160 hashes exponent and modulus padded to at least 128 bytes
+func iniHashRIPEMD160(key *domain.RSAKey) []byte {
+	return key.IniHash(ripemd160.New(), 128)
+}
+
+// iniHashSHA256 hashes exponent and modulus padded to at least 256 bytes
+func iniHashSHA256(key *domain.RSAKey) []byte {
+	return key.IniHash(sha256.New(), 256)
+}
+
+func signRIPEMD160(key *domain.RSAKey, message []byte) ([]byte, error) {
+	return key.Sign(HashSum(string(message)))
+}
+
+func verifyRIPEMD160(key *domain.RSAKey, message, signature []byte) error {
+	return key.Verify(HashSum(string(message)), signature)
+}
+
+func signPSS(key *domain.RSAKey, message []byte) ([]byte, error) {
+	hashed := sha256.Sum256(message)
+	return key.SignPSS(hashed[:])
+}
+
+func verifyPSS(key *domain.RSAKey, message, signature []byte) error {
+	hashed := sha256.Sum256(message)
+	return key.VerifyPSS(hashed[:], signature)
+}
+
+// signDoublePSS signs the SHA-256 hash of the SHA-256 hash of message
+func signDoublePSS(key *domain.RSAKey, message []byte) ([]byte, error) {
+	hashed := sha256.Sum256(message)
+	return signPSS(key, hashed[:])
+}
+
+func verifyDoublePSS(key *domain.RSAKey, message, signature []byte) error {
+	hashed := sha256.Sum256(message)
+	return verifyPSS(key, hashed[:], signature)
+}
+
+func encryptMessageKeyRaw(key *domain.RSAKey, messageKey []byte) ([]byte, error) {
+	return key.EncryptRaw(messageKey)
+}
+
+// decryptMessageKeyRaw returns a function decrypting the message key with the
+// plain RSA function. The message key is contained within the last
+// keyLength bytes.
+func decryptMessageKeyRaw(keyLength int) func(key *domain.RSAKey, encryptedMessageKey []byte) ([]byte, error) {
+	return func(key *domain.RSAKey, encryptedMessageKey []byte) ([]byte, error) {
+		decrypted, err := key.DecryptRaw(encryptedMessageKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(decrypted) < keyLength {
+			return nil, fmt.Errorf("malformed message key")
+		}
+		return decrypted[len(decrypted)-keyLength:], nil
+	}
+}
+
+func encryptMessageKeyOAEP(key *domain.RSAKey, messageKey []byte) ([]byte, error) {
+	return key.EncryptOAEP(messageKey)
+}
+
+func decryptMessageKeyOAEP(key *domain.RSAKey, encryptedMessageKey []byte) ([]byte, error) {
+	return key.DecryptOAEP(encryptedMessageKey)
+}
+
+// aesCipher returns an AES-256 cipher for the 32 byte key
+func aesCipher(key []byte) (cipher.Block, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("malformed message key: expected 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+	return block, nil
+}
@@ -1,8 +1,10 @@
 package message
 
 import (
+	"bytes"
 	"crypto/rand"
 	"crypto/rsa"
+	"errors"
 	"fmt"
 	"hash/crc32"
 	"io"
@@ -89,18 +91,22 @@ func (p *pinTanSignatureProvider) WriteSignature(end segment.SignatureEnd, signa
 	end.SetControlReference(p.controlReference)
 }
 
-// NewRDHSignatureProvider creates a new SignatureProvider for the given signingKey
+// NewRDHSignatureProvider creates a new SignatureProvider for the given
+// signingKey, using the RDH-2 profile
 func NewRDHSignatureProvider(signingKey *domain.RSAKey, signatureID int) SignatureProvider {
-	controlReference := generateControlReference(signingKey)
-	return &rdhSignatureProvider{
-		signingKey:       signingKey,
-		controlReference: controlReference,
-		signatureID:      signatureID,
-		securityFn:       "1",
-	}
+	return RDH2Profile.NewSignatureProvider(signingKey, signatureID)
+}
+
+// A RDHSignatureProvider is a SignatureProvider signing with RSA keys. It
+// keeps track of the signature IDs used within the signature headers.
+type RDHSignatureProvider interface {
+	SignatureProvider
+	// SignatureID returns the signature ID of the next signature
+	SignatureID() int
 }
 
 type rdhSignatureProvider struct {
+	profile          *RDHProfile
 	signingKey       *domain.RSAKey
 	clientSystemID   string
 	controlReference string
@@ -117,19 +123,192 @@ func (r *rdhSignatureProvider) SetSecurityFunction(securityFn string) {
 }
 
 func (r *rdhSignatureProvider) Sign(message []byte) ([]byte, error) {
-	hashSum := HashSum(string(message))
-	return r.signingKey.Sign(hashSum)
+	return r.profile.sign(r.signingKey, message)
 }
 
+// WriteSignatureHeader writes the signature header and advances the signature
+// ID, as the bank rejects signature IDs it has already seen.
 func (r *rdhSignatureProvider) WriteSignatureHeader(header segment.SignatureHeader) {
 	header.SetSecurityFunction(r.securityFn)
+	header.SetSecurityMethod(r.profile.SecurityMethod, r.profile.Version)
+	header.SetHashAlgorithm(r.profile.hashAlgorithm())
+	header.SetSignatureAlgorithm(r.profile.signatureAlgorithm())
 	header.SetClientSystemID(r.clientSystemID)
 	header.SetSigningKeyName(r.signingKey.KeyName())
 	header.SetSignatureID(r.signatureID)
 	header.SetControlReference(r.controlReference)
+	r.signatureID++
+}
+
+func (r *rdhSignatureProvider) SignatureID() int {
+	return r.signatureID
 }
 
 func (r *rdhSignatureProvider) WriteSignature(end segment.SignatureEnd, signature []byte) {
 	end.SetSignature(signature)
 	end.SetControlReference(r.controlReference)
 }
+
+var (
+	// ErrMissingSignature is returned if a bank message which should be
+	// verified contains no signature
+	ErrMissingSignature = errors.New("bank message is not signed")
+	// ErrInvalidSignature is returned if the signature of a bank message does
+	// not match the message
+	ErrInvalidSignature = errors.New("bank signature is invalid")
+)
+
+// SignatureStatus describes the result of the verification of a bank
+// signature
+type SignatureStatus int
+
+const (
+	// SignatureNotVerified marks messages which were not verified
+	SignatureNotVerified SignatureStatus = iota
+	// SignatureMissing marks messages without signature
+	SignatureMissing
+	// SignatureValid marks messages with a verified signature
+	SignatureValid
+	// SignatureInvalid marks messages whose signature does not match
+	SignatureInvalid
+)
+
+func (s SignatureStatus) String() string {
+	switch s {
+	case SignatureNotVerified:
+		return "not verified"
+	case SignatureMissing:
+		return "missing"
+	case SignatureValid:
+		return "valid"
+	case SignatureInvalid:
+		return "invalid"
+	default:
+		return fmt.Sprintf("SignatureStatus(%d)", int(s))
+	}
+}
+
+// A SignatureVerifier verifies signatures created by the bank
+type SignatureVerifier interface {
+	// KeyName returns the name of the bank key the signatures are verified
+	// with
+	KeyName() domain.KeyName
+	Verify(message []byte, signature []byte) error
+}
+
+type signatureVerifiable interface {
+	verifySignature(verifier SignatureVerifier) error
+}
+
+// VerifySignature verifies the signature of bankMessage with verifier. The
+// signed data ranges from the signature header to the last segment before the
+// signature end. The signature header must name the key of verifier and the
+// message must not contain business segments outside of the signed data. The
+// result is available via bankMessage.SignatureStatus afterwards.
+//
+// If bankMessage is not signed, an error wrapping ErrMissingSignature is
+// returned. If the signature does not match, the error wraps
+// ErrInvalidSignature.
+func VerifySignature(bankMessage BankMessage, verifier SignatureVerifier) error {
+	message, ok := bankMessage.(signatureVerifiable)
+	if !ok {
+		return fmt.Errorf("signature verification not supported for message type %T", bankMessage)
+	}
+	return message.verifySignature(verifier)
+}
+
+// unsignedSegmentIDs contains the IDs of segments which are allowed outside of
+// the signed data
+var unsignedSegmentIDs = []string{"HNHBK", "HNHBS", "HNVSK", "HNVSD"}
+
+// signedData returns the signed part of the marshaled segments as well as the
+// signature header and end. It returns an error wrapping ErrMissingSignature
+// if there is no complete signature and an error wrapping ErrInvalidSignature
+// if segments outside of the signed data are no message or encryption
+// segments.
+func signedData(segments [][]byte) ([]byte, *segment.SignatureHeaderSegment, *segment.SignatureEndSegment, error) {
+	start, end := -1, -1
+	for i, seg := range segments {
+		if start == -1 && bytes.HasPrefix(seg, []byte("HNSHK:")) {
+			start = i
+		}
+		if bytes.HasPrefix(seg, []byte("HNSHA:")) {
+			end = i
+		}
+	}
+	if start == -1 || end < start {
+		return nil, nil, nil, ErrMissingSignature
+	}
+	for i, seg := range segments {
+		if i >= start && i <= end {
+			continue
+		}
+		if !isUnsignedSegment(seg) {
+			return nil, nil, nil, fmt.Errorf("%w: segment %q outside of signed data", ErrInvalidSignature, segmentID(seg))
+		}
+	}
+	header := &segment.SignatureHeaderSegment{}
+	if err := header.UnmarshalHBCI(segments[start]); err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: malformed signature header: %v", ErrInvalidSignature, err)
+	}
+	signatureEnd := &segment.SignatureEndSegment{}
+	if err := signatureEnd.UnmarshalHBCI(segments[end]); err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: malformed signature end: %v", ErrInvalidSignature, err)
+	}
+	if header.ControlReference() != signatureEnd.ControlReference() {
+		return nil, nil, nil, fmt.Errorf(
+			"%w: control reference mismatch: header %q, end %q",
+			ErrInvalidSignature, header.ControlReference(), signatureEnd.ControlReference(),
+		)
+	}
+	if len(signatureEnd.SignatureData()) == 0 {
+		return nil, nil, nil, ErrMissingSignature
+	}
+	return bytes.Join(segments[start:end], nil), header, signatureEnd, nil
+}
+
+func isUnsignedSegment(seg []byte) bool {
+	id := segmentID(seg)
+	for _, unsignedID := range unsignedSegmentIDs {
+		if id == unsignedID {
+			return true
+		}
+	}
+	return false
+}
+
+func segmentID(seg []byte) string {
+	if i := bytes.IndexByte(seg, ':'); i != -1 {
+		return string(seg[:i])
+	}
+	return string(seg)
+}
+
+// verifyKeyName returns an error wrapping ErrInvalidSignature if header does
+// not name the key of verifier
+func verifyKeyName(header *segment.SignatureHeaderSegment, verifier SignatureVerifier) error {
+	keyName, ok := header.SigningKeyName()
+	if !ok {
+		return fmt.Errorf("%w: signature header contains no key name", ErrInvalidSignature)
+	}
+	if expected := verifier.KeyName(); keyName != expected {
+		return fmt.Errorf("%w: signed with key %v, expected %v", ErrInvalidSignature, keyName, expected)
+	}
+	return nil
+}
+
+type rdhSignatureVerifier struct {
+	profile        *RDHProfile
+	bankSigningKey *domain.RSAKey
+}
+
+func (r *rdhSignatureVerifier) KeyName() domain.KeyName {
+	return r.bankSigningKey.KeyName()
+}
+
+func (r *rdhSignatureVerifier) Verify(message []byte, signature []byte) error {
+	if err := r.profile.verify(r.bankSigningKey, message, signature); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return nil
+}
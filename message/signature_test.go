@@ -0,0 +1,108 @@
+package message
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+func TestVerifySignature(t *testing.T) {
+	body := "HIRMG:3:2+0010::Nachricht entgegengenommen.'"
+	for _, profile := range []*RDHProfile{RDH2Profile, RDH10Profile} {
+		t.Run(profile.String(), func(t *testing.T) {
+			bankKey := newTestProfileKey(t, profile, domain.KeyTypeSigning)
+			publicKey := domain.NewEncryptionKey(bankKey.Modulus, bankKey.Exponent)
+			publicKey.Type = domain.KeyTypeSigning.String()
+			keyName := bankKey.KeyName()
+			verifier := profile.NewSignatureVerifier(domain.NewRSAKey(publicKey, &keyName))
+			otherKeyName := keyName
+			otherKeyName.KeyVersion = 2
+
+			tests := []struct {
+				description    string
+				rawMessage     string
+				expectedErr    error
+				expectedStatus SignatureStatus
+			}{
+				{
+					"valid signature",
+					signedTestMessage(t, profile, bankKey, body),
+					nil,
+					SignatureValid,
+				},
+				{
+					"modified message",
+					strings.Replace(signedTestMessage(t, profile, bankKey, body), "0010", "0020", 1),
+					ErrInvalidSignature,
+					SignatureInvalid,
+				},
+				{
+					"foreign key",
+					signedTestMessage(t, profile, newTestProfileKey(t, profile, domain.KeyTypeSigning), body),
+					ErrInvalidSignature,
+					SignatureInvalid,
+				},
+				{
+					"other key version",
+					signedTestMessage(t, profile, domain.NewRSAKey(bankKey.PublicKey, &otherKeyName), body),
+					ErrInvalidSignature,
+					SignatureInvalid,
+				},
+				{
+					"segment after signature",
+					signedTestMessage(t, profile, bankKey, body) + "HIRMS:5:2:3+0020::Auftrag ausgeführt.'",
+					ErrInvalidSignature,
+					SignatureInvalid,
+				},
+				{
+					"unsigned message",
+					body,
+					ErrMissingSignature,
+					SignatureMissing,
+				},
+			}
+			for _, test := range tests {
+				header := segment.NewMessageHeaderSegment(1, 300, "abcde", 1)
+				bankMessage, err := NewDecryptedMessage(header, nil, []byte(test.rawMessage))
+				if err != nil {
+					t.Fatalf("%s: Expected no error, got %T:%v\n", test.description, err, err)
+				}
+				if bankMessage.SignatureStatus() != SignatureNotVerified {
+					t.Logf("%s: Expected status to equal %v before verification, got %v\n", test.description, SignatureNotVerified, bankMessage.SignatureStatus())
+					t.Fail()
+				}
+
+				err = VerifySignature(bankMessage, verifier)
+
+				if !errors.Is(err, test.expectedErr) || (err == nil) != (test.expectedErr == nil) {
+					t.Logf("%s: Expected error to be %v, got %T:%v\n", test.description, test.expectedErr, err, err)
+					t.Fail()
+				}
+				if bankMessage.SignatureStatus() != test.expectedStatus {
+					t.Logf("%s: Expected status to equal %v, got %v\n", test.description, test.expectedStatus, bankMessage.SignatureStatus())
+					t.Fail()
+				}
+			}
+		})
+	}
+}
+
+func signedTestMessage(t *testing.T, profile *RDHProfile, key *domain.RSAKey, body string) string {
+	provider := profile.NewSignatureProvider(key, 1)
+	provider.SetClientSystemID("0")
+	header := segment.FINTS300.SignatureHeader()
+	provider.WriteSignatureHeader(header)
+	header.SetPosition(func() int { return 2 })
+	signedData := header.String() + body
+	signature, err := provider.Sign([]byte(signedData))
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	end := segment.FINTS300.SignatureEnd()
+	provider.WriteSignature(end, signature)
+	end.SetPosition(func() int { return 4 })
+	return signedData + end.String()
+}
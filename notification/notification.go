@@ -0,0 +1,222 @@
+// Package notification implements the client side of the FinTS push services
+// (Echtzeitbenachrichtigungen). The bank institute sends notifications about
+// events like new account transactions over a WebSocket connection, which
+// allows clients to fetch data on demand instead of polling.
+//
+// The connection itself is not part of this package. Any connection can be
+// plugged in by implementing Receiver.
+package notification
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+// Message class names
+const (
+	// ClassFinTS marks notifications about business transactions
+	ClassFinTS = "FINTS"
+	// ClassInfo marks informational notifications, e.g. about maintenance
+	ClassInfo = "INFO"
+)
+
+// TokenReturnCode is the return code of acknowledgements carrying a token
+// valid for one WebSocket connection
+const TokenReturnCode = 3090
+
+// Notification represents a single notification sent by the bank institute
+type Notification struct {
+	// Class is the message class, either ClassFinTS or ClassInfo
+	Class string
+	// Version is the format version of the message class
+	Version string
+	// Timestamp is the time the notification was created
+	Timestamp time.Time
+	// Transactions is set for notifications of ClassFinTS
+	Transactions []Transaction
+	// Infos is set for notifications of ClassInfo
+	Infos []Info
+}
+
+// Transaction notifies about an event concerning a business transaction
+type Transaction struct {
+	MessageID string
+	// SegmentID identifies the business transaction, e.g. HKCAZ for new
+	// account transactions
+	SegmentID string
+	// Execute reports whether the job was approved and executed
+	Execute bool
+	// AdditionalInfo contains further data identifying the affected object,
+	// e.g. the IBAN of the account, keyed by data element name
+	AdditionalInfo map[string]string
+	Language       string
+	Subject        string
+	Text           string
+}
+
+// Info represents an informational message
+type Info struct {
+	MessageID string
+	Language  string
+	Subject   string
+	Text      string
+}
+
+type jsonNotification struct {
+	MessageClasses []struct {
+		Name      string `json:"NAME"`
+		Version   string `json:"VERS"`
+		Timestamp string `json:"TIMESTAMP"`
+	} `json:"MCLASS"`
+	Transactions []struct {
+		MessageID      string `json:"MESSAGEID"`
+		SegmentID      string `json:"SEGMENTID"`
+		Execute        string `json:"EXECUTE"`
+		AdditionalInfo []struct {
+			DataElement string `json:"DATAELEMENT"`
+			Data        string `json:"DATA"`
+		} `json:"ADDINFO"`
+		Language string `json:"LANG"`
+		Subject  string `json:"SUBJECT"`
+		Text     string `json:"FREE"`
+	} `json:"TRANSACTION"`
+	Infos []struct {
+		MessageID string `json:"MESSAGEID"`
+		Language  string `json:"LANG"`
+		Subject   string `json:"SUBJECT"`
+		Text      string `json:"FREE"`
+	} `json:"INFO"`
+}
+
+// Parse parses the JSON payload of a notification
+func Parse(payload []byte) (*Notification, error) {
+	var raw jsonNotification
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("error unmarshaling notification: %w", err)
+	}
+	if len(raw.MessageClasses) != 1 {
+		return nil, fmt.Errorf("malformed notification: expected one message class, got %d", len(raw.MessageClasses))
+	}
+	class := raw.MessageClasses[0]
+	timestamp, err := time.Parse(time.RFC3339, class.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("malformed notification: error parsing timestamp: %w", err)
+	}
+	n := &Notification{
+		Class:     class.Name,
+		Version:   class.Version,
+		Timestamp: timestamp,
+	}
+	switch n.Class {
+	case ClassFinTS:
+		for _, t := range raw.Transactions {
+			transaction := Transaction{
+				MessageID: t.MessageID,
+				SegmentID: t.SegmentID,
+				Execute:   t.Execute == "J",
+				Language:  t.Language,
+				Subject:   t.Subject,
+				Text:      t.Text,
+			}
+			if len(t.AdditionalInfo) > 0 {
+				transaction.AdditionalInfo = make(map[string]string)
+				for _, info := range t.AdditionalInfo {
+					transaction.AdditionalInfo[info.DataElement] = info.Data
+				}
+			}
+			n.Transactions = append(n.Transactions, transaction)
+		}
+	case ClassInfo:
+		for _, i := range raw.Infos {
+			n.Infos = append(n.Infos, Info{
+				MessageID: i.MessageID,
+				Language:  i.Language,
+				Subject:   i.Subject,
+				Text:      i.Text,
+			})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported message class %q", n.Class)
+	}
+	return n, nil
+}
+
+// A Receiver receives the raw payloads of notifications, e.g. from a
+// WebSocket connection to the URL provided in the bank parameter data.
+//
+// Receive blocks until a payload is available or ctx is done. It returns
+// io.EOF if the connection was closed regularly.
+type Receiver interface {
+	Receive(ctx context.Context) ([]byte, error)
+}
+
+// A Handler handles notifications
+type Handler interface {
+	HandleNotification(n *Notification)
+}
+
+// HandlerFunc is an adapter to use ordinary functions as Handler
+type HandlerFunc func(n *Notification)
+
+// HandleNotification calls f(n)
+func (f HandlerFunc) HandleNotification(n *Notification) {
+	f(n)
+}
+
+// Listen receives notifications from r and passes them to h until ctx is
+// done or r returns an error. Malformed payloads are passed to errorHandler
+// if not nil and skipped otherwise.
+//
+// Listen returns nil if r returned io.EOF, and the error of r or ctx
+// otherwise.
+func Listen(ctx context.Context, r Receiver, h Handler, errorHandler func(payload []byte, err error)) error {
+	for {
+		payload, err := r.Receive(ctx)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		n, err := Parse(payload)
+		if err != nil {
+			if errorHandler != nil {
+				errorHandler(payload, err)
+			}
+			continue
+		}
+		h.HandleNotification(n)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// Authorization returns the value of the Authorization header to establish a
+// WebSocket connection with token. The userID is only part of the credentials
+// if params.UseUserID is set.
+func Authorization(params domain.PushServiceParameters, userID, token string) string {
+	if !params.UseUserID {
+		userID = "NOTPROVIDED"
+	}
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(userID+":"+token))
+}
+
+// TokenFromAcknowledgements returns the token valid for one WebSocket
+// connection, if acknowledgements contain one
+func TokenFromAcknowledgements(acknowledgements []domain.Acknowledgement) (string, bool) {
+	for _, ack := range acknowledgements {
+		if ack.Code == TokenReturnCode && len(ack.Params) > 0 {
+			return strings.TrimSpace(ack.Params[0]), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,185 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		payload  string
+		expected *Notification
+	}{
+		{
+			`{"MCLASS":[{"NAME":"FINTS","VERS":"1.0","TIMESTAMP":"2021-05-13T12:21:50Z"}],"TRANSACTION":[{"MESSAGEID":"47110815","SEGMENTID":"HKTAN","EXECUTE":"J"}]}`,
+			&Notification{
+				Class:     ClassFinTS,
+				Version:   "1.0",
+				Timestamp: time.Date(2021, 5, 13, 12, 21, 50, 0, time.UTC),
+				Transactions: []Transaction{
+					{MessageID: "47110815", SegmentID: "HKTAN", Execute: true},
+				},
+			},
+		},
+		{
+			`{"MCLASS":[{"NAME":"FINTS","VERS":"1.0","TIMESTAMP":"2021-05-13T12:21:50Z"}],"TRANSACTION":[{"MESSAGEID":"47081511","SEGMENTID":"HKCAZ","EXECUTE":"N","ADDINFO":[{"DATAELEMENT":"IBAN","DATA":"DE18940594210019609759"}],"LANG":"DE","FREE":"Es liegen neue Umsätze vor."}]}`,
+			&Notification{
+				Class:     ClassFinTS,
+				Version:   "1.0",
+				Timestamp: time.Date(2021, 5, 13, 12, 21, 50, 0, time.UTC),
+				Transactions: []Transaction{
+					{
+						MessageID:      "47081511",
+						SegmentID:      "HKCAZ",
+						AdditionalInfo: map[string]string{"IBAN": "DE18940594210019609759"},
+						Language:       "DE",
+						Text:           "Es liegen neue Umsätze vor.",
+					},
+				},
+			},
+		},
+		{
+			`{"MCLASS":[{"NAME":"INFO","VERS":"1.0","TIMESTAMP":"2021-03-25T12:25:34Z"}],"INFO":[{"MESSAGEID":"08471115","LANG":"DE","SUBJECT":"Wartungsarbeiten","FREE":"Der FinTS-Service ist eingeschränkt verfügbar."}]}`,
+			&Notification{
+				Class:     ClassInfo,
+				Version:   "1.0",
+				Timestamp: time.Date(2021, 3, 25, 12, 25, 34, 0, time.UTC),
+				Infos: []Info{
+					{
+						MessageID: "08471115",
+						Language:  "DE",
+						Subject:   "Wartungsarbeiten",
+						Text:      "Der FinTS-Service ist eingeschränkt verfügbar.",
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		actual, err := Parse([]byte(test.payload))
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+		if !reflect.DeepEqual(test.expected, actual) {
+			t.Logf("Expected notification to equal\n%+#v\n\tgot\n%+#v\n", test.expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	payloads := []string{
+		`{`,
+		`{"MCLASS":[]}`,
+		`{"MCLASS":[{"NAME":"FINTS","VERS":"1.0","TIMESTAMP":"13.05.2021"}]}`,
+		`{"MCLASS":[{"NAME":"FOO","VERS":"1.0","TIMESTAMP":"2021-05-13T12:21:50Z"}]}`,
+	}
+	for _, payload := range payloads {
+		_, err := Parse([]byte(payload))
+		if err == nil {
+			t.Logf("Expected error for payload %q\n", payload)
+			t.Fail()
+		}
+	}
+}
+
+type receiverFunc func(ctx context.Context) ([]byte, error)
+
+func (f receiverFunc) Receive(ctx context.Context) ([]byte, error) {
+	return f(ctx)
+}
+
+func TestListen(t *testing.T) {
+	payloads := []string{
+		`{"MCLASS":[{"NAME":"FINTS","VERS":"1.0","TIMESTAMP":"2021-05-13T12:21:50Z"}],"TRANSACTION":[{"MESSAGEID":"1","SEGMENTID":"HKCAZ","EXECUTE":"N"}]}`,
+		`garbage`,
+		`{"MCLASS":[{"NAME":"FINTS","VERS":"1.0","TIMESTAMP":"2021-05-13T12:21:50Z"}],"TRANSACTION":[{"MESSAGEID":"2","SEGMENTID":"HKCAZ","EXECUTE":"N"}]}`,
+	}
+	receiver := receiverFunc(func(ctx context.Context) ([]byte, error) {
+		if len(payloads) == 0 {
+			return nil, io.EOF
+		}
+		payload := payloads[0]
+		payloads = payloads[1:]
+		return []byte(payload), nil
+	})
+	var messageIDs []string
+	handler := HandlerFunc(func(n *Notification) {
+		messageIDs = append(messageIDs, n.Transactions[0].MessageID)
+	})
+	var malformed int
+	errorHandler := func(payload []byte, err error) {
+		malformed++
+	}
+
+	err := Listen(context.Background(), receiver, handler, errorHandler)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if !reflect.DeepEqual([]string{"1", "2"}, messageIDs) {
+		t.Logf("Expected handled message IDs to equal [1 2], got %v\n", messageIDs)
+		t.Fail()
+	}
+	if malformed != 1 {
+		t.Logf("Expected one malformed payload, got %d\n", malformed)
+		t.Fail()
+	}
+
+	receiverErr := errors.New("connection lost")
+	err = Listen(context.Background(), receiverFunc(func(ctx context.Context) ([]byte, error) {
+		return nil, receiverErr
+	}), handler, nil)
+
+	if !errors.Is(err, receiverErr) {
+		t.Logf("Expected error %v, got %T:%v\n", receiverErr, err, err)
+		t.Fail()
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	token := "550e8400-e29b-11d4-a716-446655440000"
+
+	actual := Authorization(domain.PushServiceParameters{UseUserID: true}, "26314255", token)
+
+	expected := "Basic MjYzMTQyNTU6NTUwZTg0MDAtZTI5Yi0xMWQ0LWE3MTYtNDQ2NjU1NDQwMDAw"
+	if expected != actual {
+		t.Logf("Expected authorization to equal %q, got %q\n", expected, actual)
+		t.Fail()
+	}
+
+	actual = Authorization(domain.PushServiceParameters{UseUserID: false}, "26314255", token)
+
+	expected = "Basic Tk9UUFJPVklERUQ6NTUwZTg0MDAtZTI5Yi0xMWQ0LWE3MTYtNDQ2NjU1NDQwMDAw"
+	if expected != actual {
+		t.Logf("Expected authorization to equal %q, got %q\n", expected, actual)
+		t.Fail()
+	}
+}
+
+func TestTokenFromAcknowledgements(t *testing.T) {
+	acks := []domain.Acknowledgement{
+		domain.NewMessageAcknowledgement(20, "", "Auftrag ausgeführt", nil),
+		domain.NewSegmentAcknowledgement(3090, "", "Token für Push-Services", []string{"550e8400"}),
+	}
+
+	token, ok := TokenFromAcknowledgements(acks)
+
+	if !ok || token != "550e8400" {
+		t.Logf("Expected token %q, got %q (%t)\n", "550e8400", token, ok)
+		t.Fail()
+	}
+
+	_, ok = TokenFromAcknowledgements(acks[:1])
+
+	if ok {
+		t.Logf("Expected no token\n")
+		t.Fail()
+	}
+}
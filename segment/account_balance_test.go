@@ -16,10 +16,10 @@ func TestAccountBalanceResponseSegmentUnmarshalHBCI(t *testing.T) {
 		AccountConnection:  element.NewAccountConnection(domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}),
 		AccountProductName: element.NewAlphaNumeric("Sichteinlagen", 35),
 		AccountCurrency:    element.NewCurrency("EUR"),
-		BookedBalance:      element.NewBalance(domain.Amount{Amount: 1000.15, Currency: "EUR"}, date, false),
-		EarmarkedBalance:   element.NewBalance(domain.Amount{Amount: 20, Currency: "EUR"}, date, false),
-		CreditLimit:        element.NewAmount(500, "EUR"),
-		AvailableAmount:    element.NewAmount(1499.85, "EUR"),
+		BookedBalance:      element.NewBalance(domain.NewAmount(100015, "EUR"), date, false),
+		EarmarkedBalance:   element.NewBalance(domain.NewAmount(2000, "EUR"), date, false),
+		CreditLimit:        element.NewAmount(domain.NewAmount(50000, "EUR")),
+		AvailableAmount:    element.NewAmount(domain.NewAmount(149985, "EUR")),
 	}
 	expectedSegment.Segment = NewReferencingBasicSegment(4, 3, expectedSegment)
 
@@ -26,12 +26,13 @@ func AccountTransactionRequestBuilder(versions []int) (func(account domain.Accou
 	return nil, fmt.Errorf("unsupported versions %v", versions)
 }
 
+// SepaAccountTransactionRequestBuilder returns the constructor for the highest
+// version of SEPA account transaction requests within versions. Only version 7
+// uses international account connections, so it returns an error if versions
+// does not contain 7.
 func SepaAccountTransactionRequestBuilder(versions []int) (func(account domain.InternationalAccountConnection, allAccounts bool) *AccountTransactionRequestSegment, error) {
 	sort.Sort(sort.Reverse(sort.IntSlice(versions)))
 	for _, version := range versions {
-		if version > 6 {
-			continue
-		}
 		switch version {
 		case 7:
 			return NewAccountTransactionRequestSegmentV7, nil
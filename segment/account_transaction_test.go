@@ -0,0 +1,27 @@
+package segment
+
+import (
+	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+func TestSepaAccountTransactionRequestBuilder(t *testing.T) {
+	account := domain.InternationalAccountConnection{IBAN: "DE89370400440532013000", BIC: "COBADEFFXXX"}
+
+	builder, err := SepaAccountTransactionRequestBuilder([]int{5, 6, 7})
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	request := builder(account, false)
+	if _, ok := request.AccountTransactionRequest.(*AccountTransactionRequestV7); !ok {
+		t.Logf("Expected request to be of version 7, got %T\n", request.AccountTransactionRequest)
+		t.Fail()
+	}
+
+	_, err = SepaAccountTransactionRequestBuilder([]int{5, 6})
+	if err == nil {
+		t.Logf("Expected error for versions without international account connections\n")
+		t.Fail()
+	}
+}
@@ -13,8 +13,13 @@ type EncryptionHeader interface {
 	ClientSegment
 	SetClientSystemID(clientSystemID string)
 	SetSecurityProfile(securityFn string)
+	// SetSecurityMethod overrides the security profile with the provided
+	// method and version. It is a no-op for versions without security profile.
+	SetSecurityMethod(securityMethod string, securityMethodVersion int)
 	SetEncryptionKeyName(keyName domain.KeyName)
 	SetEncryptionAlgorithm(algorithm *element.EncryptionAlgorithmDataElement)
+	// MessageKey returns the encrypted message key, if any
+	MessageKey() []byte
 }
 
 func NewPinTanEncryptionHeaderSegment(clientSystemId string, keyName domain.KeyName) *EncryptionHeaderSegment {
@@ -115,6 +120,15 @@ func (e *EncryptionHeaderV2) SetSecurityProfile(securityFn string) {
 	// NO OP
 }
 
+func (e *EncryptionHeaderV2) SetSecurityMethod(securityMethod string, securityMethodVersion int) {}
+
+func (e *EncryptionHeaderV2) MessageKey() []byte {
+	if e.EncryptionAlgorithm == nil || e.EncryptionAlgorithm.Key == nil {
+		return nil
+	}
+	return e.EncryptionAlgorithm.Key.Val()
+}
+
 func NewPinTanEncryptionHeaderSegmentV3(clientSystemId string, keyName domain.KeyName) *EncryptionHeaderSegment {
 	e := &EncryptionHeaderSegmentV3{
 		SecurityProfile:      element.NewPinTanSecurityProfile(1),
@@ -193,9 +207,24 @@ func (e *EncryptionHeaderSegmentV3) SetClientSystemID(clientSystemId string) {
 }
 
 func (e *EncryptionHeaderSegmentV3) SetSecurityProfile(securityFn string) {
-	if securityFn == "999" {
+	switch securityFn {
+	case "999":
 		e.SecurityProfile = element.NewPinTanSecurityProfile(1)
-	} else {
+	case "1":
+		e.SecurityProfile = element.NewRDHSecurityProfile(2)
+		e.SecurityFunction = element.NewCode("4", 3, []string{"4", "998"})
+	default:
 		e.SecurityProfile = element.NewPinTanSecurityProfile(2)
 	}
 }
+
+func (e *EncryptionHeaderSegmentV3) SetSecurityMethod(securityMethod string, securityMethodVersion int) {
+	e.SecurityProfile = element.NewSecurityProfile(securityMethod, securityMethodVersion)
+}
+
+func (e *EncryptionHeaderSegmentV3) MessageKey() []byte {
+	if e.EncryptionAlgorithm == nil || e.EncryptionAlgorithm.Key == nil {
+		return nil
+	}
+	return e.EncryptionAlgorithm.Key.Val()
+}
@@ -19,12 +19,12 @@ func NewPublicKeyRenewalSegment(number int, keyName domain.KeyName, pubKey *doma
 		KeyName:    element.NewKeyName(keyName),
 		PublicKey:  element.NewPublicKey(pubKey),
 	}
-	p.Segment = NewBasicSegment(number, p)
+	p.ClientSegment = NewBasicSegment(number, p)
 	return p
 }
 
 type PublicKeyRenewalSegment struct {
-	Segment
+	ClientSegment
 	// "2" für ‘Key-Management-Nachricht erwartet Antwort’
 	MessageID *element.NumberDataElement
 	// "112" für ‘Certificate Replacement’ (Ersatz des Zertifikats))
@@ -101,6 +101,8 @@ func NewPublicKeyTransmissionSegment(dialogId string, number int, messageReferen
 	return p
 }
 
+//go:generate go run ../cmd/unmarshaler/unmarshaler_generator.go -segment PublicKeyTransmissionSegment
+
 type PublicKeyTransmissionSegment struct {
 	Segment
 	// "1" für ‘Key-Management-Nachricht ist Antwort’
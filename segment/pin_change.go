@@ -0,0 +1,33 @@
+package segment
+
+import "github.com/mitch000001/go-hbci/element"
+
+// PinChangeRequestID is the segment ID of the PIN change request
+const PinChangeRequestID = "HKPAE"
+
+// NewPinChangeRequestSegment returns a request to change the PIN to newPin.
+// The current PIN is transmitted within the signature as usual.
+func NewPinChangeRequestSegment(newPin string) *PinChangeRequestSegment {
+	p := &PinChangeRequestSegment{
+		NewPin: element.NewAlphaNumeric(newPin, 99),
+	}
+	p.ClientSegment = NewBasicSegment(3, p)
+	return p
+}
+
+// PinChangeRequestSegment represents the request to change the PIN
+type PinChangeRequestSegment struct {
+	ClientSegment
+	NewPin *element.AlphaNumericDataElement
+}
+
+func (p *PinChangeRequestSegment) Version() int         { return 1 }
+func (p *PinChangeRequestSegment) ID() string           { return PinChangeRequestID }
+func (p *PinChangeRequestSegment) referencedId() string { return "" }
+func (p *PinChangeRequestSegment) sender() string       { return senderUser }
+
+func (p *PinChangeRequestSegment) elements() []element.DataElement {
+	return []element.DataElement{
+		p.NewPin,
+	}
+}
@@ -0,0 +1,297 @@
+package segment
+
+import (
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/element"
+)
+
+// Segment IDs of the push service business transactions
+const (
+	PushServiceRegistrationRequestID    = "HKPUR"
+	PushServiceRegistrationResponseID   = "HIPUR"
+	PushServiceChangeRequestID          = "HKPUA"
+	PushServiceChangeResponseID         = "HIPUA"
+	PushServiceClientProductsRequestID  = "HKPRB"
+	PushServiceClientProductsResponseID = "HIPRB"
+	PushServiceDeregistrationRequestID  = "HKPRL"
+)
+
+func init() {
+	KnownSegments.mustAddToIndex(VersionedSegment{PushServiceRegistrationResponseID, 1}, func() Segment { return &PushServiceRegistrationResponseSegment{} })
+	KnownSegments.mustAddToIndex(VersionedSegment{PushServiceChangeResponseID, 1}, func() Segment { return &PushServiceChangeResponseSegment{} })
+	KnownSegments.mustAddToIndex(VersionedSegment{PushServiceClientProductsResponseID, 1}, func() Segment { return &PushServiceClientProductResponseSegment{} })
+	mustCheckTaggedSegments(
+		&PushServiceRegistrationRequestSegment{},
+		&PushServiceChangeRequestSegment{},
+		&PushServiceClientProductsRequestSegment{},
+		&PushServiceDeregistrationRequestSegment{},
+		&PushServiceRegistrationResponseSegment{},
+		&PushServiceChangeResponseSegment{},
+		&PushServiceClientProductResponseSegment{},
+	)
+}
+
+// NewPushServiceRegistrationRequestSegment returns a request to register the
+// client product for push services of the business transactions identified
+// by segmentIDs.
+func NewPushServiceRegistrationRequestSegment(product domain.PushServiceClientProduct, segmentIDs []string) *PushServiceRegistrationRequestSegment {
+	p := &PushServiceRegistrationRequestSegment{
+		ProductName:      element.NewAlphaNumeric(product.ProductName, 25),
+		ProductVersion:   element.NewAlphaNumeric(product.ProductVersion, 5),
+		Manufacturer:     element.NewAlphaNumeric(product.Manufacturer, 35),
+		ClientSystemName: element.NewAlphaNumeric(product.ClientSystemName, 32),
+		SegmentIDs:       segmentIDElements(segmentIDs),
+	}
+	p.ClientSegment = NewBasicSegment(3, p)
+	return p
+}
+
+// PushServiceRegistrationRequestSegment represents the request to register a
+// client product for push services
+type PushServiceRegistrationRequestSegment struct {
+	ClientSegment
+	ProductName      *element.AlphaNumericDataElement   `hbci:"an,25"`
+	ProductVersion   *element.AlphaNumericDataElement   `hbci:"an,5"`
+	Manufacturer     *element.AlphaNumericDataElement   `hbci:"an,35"`
+	ClientSystemName *element.AlphaNumericDataElement   `hbci:"an,32"`
+	SegmentIDs       []*element.AlphaNumericDataElement `hbci:"an,6,max=999"`
+}
+
+func (p *PushServiceRegistrationRequestSegment) Version() int         { return 1 }
+func (p *PushServiceRegistrationRequestSegment) ID() string           { return PushServiceRegistrationRequestID }
+func (p *PushServiceRegistrationRequestSegment) referencedId() string { return "" }
+func (p *PushServiceRegistrationRequestSegment) sender() string       { return senderUser }
+
+func (p *PushServiceRegistrationRequestSegment) elements() []element.DataElement {
+	return taggedElements(p)
+}
+
+// NewPushServiceChangeRequestSegment returns a request to change the
+// registration of the client product identified by token. The registered
+// business transactions are replaced by segmentIDs, no segmentIDs cancel the
+// registration. The request also refreshes the token.
+func NewPushServiceChangeRequestSegment(token string, product domain.PushServiceClientProduct, segmentIDs []string) *PushServiceChangeRequestSegment {
+	p := &PushServiceChangeRequestSegment{
+		Token:            element.NewAlphaNumeric(token, 80),
+		ClientSystemName: element.NewAlphaNumeric(product.ClientSystemName, 32),
+		SegmentIDs:       segmentIDElements(segmentIDs),
+	}
+	if product.ProductName != "" {
+		p.ProductName = element.NewAlphaNumeric(product.ProductName, 25)
+	}
+	if product.ProductVersion != "" {
+		p.ProductVersion = element.NewAlphaNumeric(product.ProductVersion, 5)
+	}
+	if product.Manufacturer != "" {
+		p.Manufacturer = element.NewAlphaNumeric(product.Manufacturer, 35)
+	}
+	p.ClientSegment = NewBasicSegment(3, p)
+	return p
+}
+
+// PushServiceChangeRequestSegment represents the request to change the
+// registration for push services
+type PushServiceChangeRequestSegment struct {
+	ClientSegment
+	Token            *element.AlphaNumericDataElement   `hbci:"an,80"`
+	ProductName      *element.AlphaNumericDataElement   `hbci:"an,25,optional"`
+	ProductVersion   *element.AlphaNumericDataElement   `hbci:"an,5,optional"`
+	Manufacturer     *element.AlphaNumericDataElement   `hbci:"an,35,optional"`
+	ClientSystemName *element.AlphaNumericDataElement   `hbci:"an,32"`
+	SegmentIDs       []*element.AlphaNumericDataElement `hbci:"an,6,optional,max=999"`
+}
+
+func (p *PushServiceChangeRequestSegment) Version() int         { return 1 }
+func (p *PushServiceChangeRequestSegment) ID() string           { return PushServiceChangeRequestID }
+func (p *PushServiceChangeRequestSegment) referencedId() string { return "" }
+func (p *PushServiceChangeRequestSegment) sender() string       { return senderUser }
+
+func (p *PushServiceChangeRequestSegment) elements() []element.DataElement {
+	return taggedElements(p)
+}
+
+// NewPushServiceClientProductsRequestSegment returns a request for all client
+// products registered for push services
+func NewPushServiceClientProductsRequestSegment(maxEntries int, continuationReference string) *PushServiceClientProductsRequestSegment {
+	p := &PushServiceClientProductsRequestSegment{}
+	if maxEntries > 0 {
+		p.MaxEntries = element.NewNumber(maxEntries, 4)
+	}
+	if continuationReference != "" {
+		p.ContinuationReference = element.NewAlphaNumeric(continuationReference, 35)
+	}
+	p.ClientSegment = NewBasicSegment(3, p)
+	return p
+}
+
+// PushServiceClientProductsRequestSegment represents the request for all
+// client products registered for push services
+type PushServiceClientProductsRequestSegment struct {
+	ClientSegment
+	MaxEntries            *element.NumberDataElement       `hbci:"num,4,optional"`
+	ContinuationReference *element.AlphaNumericDataElement `hbci:"an,35,optional"`
+}
+
+func (p *PushServiceClientProductsRequestSegment) Version() int { return 1 }
+func (p *PushServiceClientProductsRequestSegment) ID() string {
+	return PushServiceClientProductsRequestID
+}
+func (p *PushServiceClientProductsRequestSegment) referencedId() string { return "" }
+func (p *PushServiceClientProductsRequestSegment) sender() string       { return senderUser }
+
+func (p *PushServiceClientProductsRequestSegment) elements() []element.DataElement {
+	return taggedElements(p)
+}
+
+// NewPushServiceDeregistrationRequestSegment returns a request to deregister
+// the client system with the given name from push services
+func NewPushServiceDeregistrationRequestSegment(clientSystemName string) *PushServiceDeregistrationRequestSegment {
+	p := &PushServiceDeregistrationRequestSegment{
+		ClientSystemName: element.NewAlphaNumeric(clientSystemName, 32),
+	}
+	p.ClientSegment = NewBasicSegment(3, p)
+	return p
+}
+
+// PushServiceDeregistrationRequestSegment represents the request to
+// deregister a client product from push services
+type PushServiceDeregistrationRequestSegment struct {
+	ClientSegment
+	ClientSystemName *element.AlphaNumericDataElement `hbci:"an,32"`
+}
+
+func (p *PushServiceDeregistrationRequestSegment) Version() int { return 1 }
+func (p *PushServiceDeregistrationRequestSegment) ID() string {
+	return PushServiceDeregistrationRequestID
+}
+func (p *PushServiceDeregistrationRequestSegment) referencedId() string { return "" }
+func (p *PushServiceDeregistrationRequestSegment) sender() string       { return senderUser }
+
+func (p *PushServiceDeregistrationRequestSegment) elements() []element.DataElement {
+	return taggedElements(p)
+}
+
+// PushServiceRegistrationResponseSegment represents the response to a
+// registration for push services
+type PushServiceRegistrationResponseSegment struct {
+	Segment
+	Token      *element.AlphaNumericDataElement   `hbci:"an,80,optional"`
+	ValidUntil *element.TimestampDataElement      `hbci:"deg,optional"`
+	SegmentIDs []*element.AlphaNumericDataElement `hbci:"an,6,optional,max=999"`
+}
+
+func (p *PushServiceRegistrationResponseSegment) Version() int { return 1 }
+func (p *PushServiceRegistrationResponseSegment) ID() string {
+	return PushServiceRegistrationResponseID
+}
+func (p *PushServiceRegistrationResponseSegment) referencedId() string {
+	return PushServiceRegistrationRequestID
+}
+func (p *PushServiceRegistrationResponseSegment) sender() string { return senderBank }
+
+func (p *PushServiceRegistrationResponseSegment) elements() []element.DataElement {
+	return taggedElements(p)
+}
+
+// UnmarshalHBCI unmarshals value into p
+func (p *PushServiceRegistrationResponseSegment) UnmarshalHBCI(value []byte) error {
+	return unmarshalTaggedSegment(p, value)
+}
+
+// Registration returns the registration sent by the bank institute
+func (p *PushServiceRegistrationResponseSegment) Registration() domain.PushServiceRegistration {
+	return pushServiceRegistration(p.Token, p.ValidUntil, p.SegmentIDs)
+}
+
+// PushServiceChangeResponseSegment represents the response to a change of
+// the registration for push services
+type PushServiceChangeResponseSegment struct {
+	Segment
+	Token      *element.AlphaNumericDataElement   `hbci:"an,80,optional"`
+	ValidUntil *element.TimestampDataElement      `hbci:"deg,optional"`
+	SegmentIDs []*element.AlphaNumericDataElement `hbci:"an,6,optional,max=999"`
+}
+
+func (p *PushServiceChangeResponseSegment) Version() int { return 1 }
+func (p *PushServiceChangeResponseSegment) ID() string {
+	return PushServiceChangeResponseID
+}
+func (p *PushServiceChangeResponseSegment) referencedId() string {
+	return PushServiceChangeRequestID
+}
+func (p *PushServiceChangeResponseSegment) sender() string { return senderBank }
+
+func (p *PushServiceChangeResponseSegment) elements() []element.DataElement {
+	return taggedElements(p)
+}
+
+// UnmarshalHBCI unmarshals value into p
+func (p *PushServiceChangeResponseSegment) UnmarshalHBCI(value []byte) error {
+	return unmarshalTaggedSegment(p, value)
+}
+
+// Registration returns the changed registration sent by the bank institute.
+// The token is empty if the registration was cancelled.
+func (p *PushServiceChangeResponseSegment) Registration() domain.PushServiceRegistration {
+	return pushServiceRegistration(p.Token, p.ValidUntil, p.SegmentIDs)
+}
+
+// PushServiceClientProductResponseSegment represents a client product
+// registered for push services
+type PushServiceClientProductResponseSegment struct {
+	Segment
+	ProductName      *element.AlphaNumericDataElement `hbci:"an,25"`
+	ProductVersion   *element.AlphaNumericDataElement `hbci:"an,5"`
+	Manufacturer     *element.AlphaNumericDataElement `hbci:"an,35"`
+	ClientSystemName *element.AlphaNumericDataElement `hbci:"an,32"`
+}
+
+func (p *PushServiceClientProductResponseSegment) Version() int { return 1 }
+func (p *PushServiceClientProductResponseSegment) ID() string {
+	return PushServiceClientProductsResponseID
+}
+func (p *PushServiceClientProductResponseSegment) referencedId() string {
+	return PushServiceClientProductsRequestID
+}
+func (p *PushServiceClientProductResponseSegment) sender() string { return senderBank }
+
+func (p *PushServiceClientProductResponseSegment) elements() []element.DataElement {
+	return taggedElements(p)
+}
+
+// UnmarshalHBCI unmarshals value into p
+func (p *PushServiceClientProductResponseSegment) UnmarshalHBCI(value []byte) error {
+	return unmarshalTaggedSegment(p, value)
+}
+
+// ClientProduct returns the registered client product
+func (p *PushServiceClientProductResponseSegment) ClientProduct() domain.PushServiceClientProduct {
+	return domain.PushServiceClientProduct{
+		ProductName:      p.ProductName.Val(),
+		ProductVersion:   p.ProductVersion.Val(),
+		Manufacturer:     p.Manufacturer.Val(),
+		ClientSystemName: p.ClientSystemName.Val(),
+	}
+}
+
+func segmentIDElements(segmentIDs []string) []*element.AlphaNumericDataElement {
+	elements := make([]*element.AlphaNumericDataElement, len(segmentIDs))
+	for i, id := range segmentIDs {
+		elements[i] = element.NewAlphaNumeric(id, 5)
+	}
+	return elements
+}
+
+func pushServiceRegistration(token *element.AlphaNumericDataElement, validUntil *element.TimestampDataElement, segmentIDs []*element.AlphaNumericDataElement) domain.PushServiceRegistration {
+	var registration domain.PushServiceRegistration
+	if token != nil {
+		registration.Token = token.Val()
+	}
+	if validUntil != nil {
+		registration.ValidUntil = validUntil.Val()
+	}
+	for _, id := range segmentIDs {
+		registration.SegmentIDs = append(registration.SegmentIDs, id.Val())
+	}
+	return registration
+}
@@ -0,0 +1,48 @@
+package segment
+
+import (
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/element"
+)
+
+const PushServiceParameterID = "HIPURS"
+
+type PushServiceParameter interface {
+	BankSegment
+	PushServiceParameters() domain.PushServiceParameters
+}
+
+//go:generate go run ../cmd/unmarshaler/unmarshaler_generator.go -segment PushServiceParameterSegment -segment_interface PushServiceParameter -segment_versions="PushServiceParameterV1:1:Segment"
+
+type PushServiceParameterSegment struct {
+	PushServiceParameter
+}
+
+// PushServiceParameterV1
+//
+// Push-Services Registrierung Parameter
+type PushServiceParameterV1 struct {
+	Segment
+	MaxJobs       *element.NumberDataElement               `yaml:"MaxJobs"`
+	MinSignatures *element.NumberDataElement               `yaml:"MinSignatures"`
+	SecurityClass *element.NumberDataElement               `yaml:"SecurityClass"`
+	Params        *element.PushServiceParameterDataElement `yaml:"Params"`
+}
+
+func (p *PushServiceParameterV1) Version() int         { return 1 }
+func (p *PushServiceParameterV1) ID() string           { return PushServiceParameterID }
+func (p *PushServiceParameterV1) referencedId() string { return ProcessingPreparationID }
+func (p *PushServiceParameterV1) sender() string       { return senderBank }
+
+func (p *PushServiceParameterV1) elements() []element.DataElement {
+	return []element.DataElement{
+		p.MaxJobs,
+		p.MinSignatures,
+		p.SecurityClass,
+		p.Params,
+	}
+}
+
+func (p *PushServiceParameterV1) PushServiceParameters() domain.PushServiceParameters {
+	return p.Params.Val()
+}
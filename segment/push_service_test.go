@@ -0,0 +1,90 @@
+package segment
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+func TestPushServiceRegistrationRequestSegment(t *testing.T) {
+	product := domain.PushServiceClientProduct{
+		ProductName:      "go-hbci",
+		ProductVersion:   "1.0",
+		Manufacturer:     "mitch000001",
+		ClientSystemName: "Laptop",
+	}
+
+	marshaled := NewPushServiceRegistrationRequestSegment(product, []string{"HKCAZ", "HKTAN"}).String()
+
+	expected := "HKPUR:3:1:+go-hbci+1.0+mitch000001+Laptop+HKCAZ+HKTAN'"
+	if expected != marshaled {
+		t.Logf("Expected marshaled value to equal\n%q\n\tgot\n%q\n", expected, marshaled)
+		t.Fail()
+	}
+
+	marshaled = NewPushServiceChangeRequestSegment("abc", domain.PushServiceClientProduct{ClientSystemName: "Laptop"}, nil).String()
+
+	expected = "HKPUA:3:1:+abc++++Laptop'"
+	if expected != marshaled {
+		t.Logf("Expected marshaled value to equal\n%q\n\tgot\n%q\n", expected, marshaled)
+		t.Fail()
+	}
+}
+
+func TestPushServiceRegistrationResponseSegmentUnmarshalHBCI(t *testing.T) {
+	test := "HIPUR:4:1:3+550e8400-e29b-11d4-a716-446655440000+20211231:235959+HKCAZ+HKTAN'"
+
+	registrationResponse := &PushServiceRegistrationResponseSegment{}
+
+	err := registrationResponse.UnmarshalHBCI([]byte(test))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	registration := registrationResponse.Registration()
+	if registration.Token != "550e8400-e29b-11d4-a716-446655440000" {
+		t.Logf("Expected token %q, got %q\n", "550e8400-e29b-11d4-a716-446655440000", registration.Token)
+		t.Fail()
+	}
+	validUntil := registration.ValidUntil
+	if validUntil.Year() != 2021 || validUntil.Month() != time.December || validUntil.Day() != 31 || validUntil.Hour() != 23 {
+		t.Logf("Expected token to be valid until 2021-12-31 23:59:59, got %s\n", validUntil)
+		t.Fail()
+	}
+	if !reflect.DeepEqual([]string{"HKCAZ", "HKTAN"}, registration.SegmentIDs) {
+		t.Logf("Expected segment IDs to equal [HKCAZ HKTAN], got %v\n", registration.SegmentIDs)
+		t.Fail()
+	}
+
+	marshaled := registrationResponse.String()
+
+	if marshaled != test {
+		t.Logf("Expected unmarshaled value to equal\n%q\n\tgot\n%q\n", test, marshaled)
+		t.Fail()
+	}
+}
+
+func TestPushServiceParameterV1UnmarshalHBCI(t *testing.T) {
+	test := "HIPURS:12:1:4+1+1+0+wss?://push.example.com:60:N:HKCAZ:HKTAN'"
+
+	params := &PushServiceParameterV1{}
+
+	err := params.UnmarshalHBCI([]byte(test))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	expected := domain.PushServiceParameters{
+		WebSocketURL:    "wss://push.example.com",
+		PollingInterval: time.Minute,
+		SegmentIDs:      []string{"HKCAZ", "HKTAN"},
+	}
+	if !reflect.DeepEqual(expected, params.PushServiceParameters()) {
+		t.Logf("Expected parameters to equal\n%+#v\n\tgot\n%+#v\n", expected, params.PushServiceParameters())
+		t.Fail()
+	}
+}
@@ -94,6 +94,9 @@ func (s *segment) String() string {
 }
 
 func (s *segment) MarshalHBCI() ([]byte, error) {
+	if err := validateTaggedElements(s.segment); err != nil {
+		return nil, err
+	}
 	elementBytes := make([][]byte, len(s.segment.elements())+1)
 	headerBytes, err := s.header.MarshalHBCI()
 	if err != nil {
@@ -22,6 +22,8 @@ type signatureEndSegment interface {
 	ClientSegment
 	SignatureEnd
 	Unmarshaler
+	ControlReference() string
+	SignatureData() []byte
 }
 
 //go:generate go run ../cmd/unmarshaler/unmarshaler_generator.go -segment SignatureEndSegment -segment_interface signatureEndSegment -segment_versions="SignatureEndV1:1:ClientSegment,SignatureEndV2:2:ClientSegment"
@@ -58,6 +60,22 @@ func (s *SignatureEndV1) SetSignature(signature []byte) {
 	s.Signature = element.NewBinary(signature, 512)
 }
 
+// ControlReference returns the security control reference
+func (s *SignatureEndV1) ControlReference() string {
+	if s.SecurityControlRef == nil {
+		return ""
+	}
+	return s.SecurityControlRef.Val()
+}
+
+// SignatureData returns the signature, or nil if the segment contains none
+func (s *SignatureEndV1) SignatureData() []byte {
+	if s.Signature == nil {
+		return nil
+	}
+	return s.Signature.Val()
+}
+
 func (s *SignatureEndV1) SetPinTan(pin, tan string) {
 	s.PinTan = element.NewPinTan(pin, tan)
 }
@@ -99,6 +117,22 @@ func (s *SignatureEndV2) SetSignature(signature []byte) {
 	s.Signature = element.NewBinary(signature, 512)
 }
 
+// ControlReference returns the security control reference
+func (s *SignatureEndV2) ControlReference() string {
+	if s.SecurityControlRef == nil {
+		return ""
+	}
+	return s.SecurityControlRef.Val()
+}
+
+// SignatureData returns the signature, or nil if the segment contains none
+func (s *SignatureEndV2) SignatureData() []byte {
+	if s.Signature == nil {
+		return nil
+	}
+	return s.Signature.Val()
+}
+
 func (s *SignatureEndV2) SetPinTan(pin, tan string) {
 	s.CustomSignature = element.NewCustomSignature(pin, tan)
 }
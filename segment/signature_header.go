@@ -13,6 +13,11 @@ type SignatureHeader interface {
 	SetSigningKeyName(keyName domain.KeyName)
 	SetSignatureID(signatureId int)
 	SetSecurityFunction(string)
+	// SetSecurityMethod overrides the security profile with the provided
+	// method and version. It is a no-op for versions without security profile.
+	SetSecurityMethod(securityMethod string, securityMethodVersion int)
+	SetHashAlgorithm(*element.HashAlgorithmDataElement)
+	SetSignatureAlgorithm(*element.SignatureAlgorithmDataElement)
 	SetControlReference(string)
 }
 
@@ -83,6 +88,8 @@ type SignatureHeaderSegment struct {
 type signatureHeaderSegment interface {
 	SignatureHeader
 	Unmarshaler
+	ControlReference() string
+	SigningKeyName() (domain.KeyName, bool)
 }
 
 type SignatureHeaderV3 struct {
@@ -112,14 +119,41 @@ func (s *SignatureHeaderV3) SetSecurityFunction(securityFn string) {
 	s.SecurityFunction = element.NewAlphaNumeric(securityFn, 3)
 }
 
+func (s *SignatureHeaderV3) SetSecurityMethod(securityMethod string, securityMethodVersion int) {}
+
+func (s *SignatureHeaderV3) SetHashAlgorithm(algorithm *element.HashAlgorithmDataElement) {
+	s.HashAlgorithm = algorithm
+}
+
+func (s *SignatureHeaderV3) SetSignatureAlgorithm(algorithm *element.SignatureAlgorithmDataElement) {
+	s.SignatureAlgorithm = algorithm
+}
+
 func (s *SignatureHeaderV3) SetControlReference(controlReference string) {
 	s.SecurityControlRef = element.NewAlphaNumeric(controlReference, 14)
 }
 
+// ControlReference returns the security control reference
+func (s *SignatureHeaderV3) ControlReference() string {
+	if s.SecurityControlRef == nil {
+		return ""
+	}
+	return s.SecurityControlRef.Val()
+}
+
 func (s *SignatureHeaderV3) SetSigningKeyName(keyName domain.KeyName) {
 	s.KeyName = element.NewKeyName(keyName)
 }
 
+// SigningKeyName returns the name of the signing key. It returns false if the
+// header contains no key name.
+func (s *SignatureHeaderV3) SigningKeyName() (domain.KeyName, bool) {
+	if s.KeyName == nil {
+		return domain.KeyName{}, false
+	}
+	return s.KeyName.Val(), true
+}
+
 func (s *SignatureHeaderV3) SetClientSystemID(clientSystemId string) {
 	s.SecurityID = element.NewRDHSecurityIdentification(element.SecurityHolderMessageSender, clientSystemId)
 }
@@ -212,21 +246,53 @@ type SignatureHeaderSegmentV4 struct {
 
 func (s *SignatureHeaderSegmentV4) SetSecurityFunction(securityFn string) {
 	s.SecurityFunction = element.NewCode(securityFn, 3, []string{"1", "2", "999", securityFn})
-	if securityFn == "999" {
+	switch securityFn {
+	case "999":
 		s.SecurityProfile = element.NewPinTanSecurityProfile(1)
-	} else {
+	case "1":
+		s.SecurityProfile = element.NewRDHSecurityProfile(2)
+	default:
 		s.SecurityProfile = element.NewPinTanSecurityProfile(2)
 	}
 }
 
+func (s *SignatureHeaderSegmentV4) SetSecurityMethod(securityMethod string, securityMethodVersion int) {
+	s.SecurityProfile = element.NewSecurityProfile(securityMethod, securityMethodVersion)
+}
+
+func (s *SignatureHeaderSegmentV4) SetHashAlgorithm(algorithm *element.HashAlgorithmDataElement) {
+	s.HashAlgorithm = algorithm
+}
+
+func (s *SignatureHeaderSegmentV4) SetSignatureAlgorithm(algorithm *element.SignatureAlgorithmDataElement) {
+	s.SignatureAlgorithm = algorithm
+}
+
 func (s *SignatureHeaderSegmentV4) SetControlReference(controlReference string) {
 	s.SecurityControlRef = element.NewAlphaNumeric(controlReference, 14)
 }
 
+// ControlReference returns the security control reference
+func (s *SignatureHeaderSegmentV4) ControlReference() string {
+	if s.SecurityControlRef == nil {
+		return ""
+	}
+	return s.SecurityControlRef.Val()
+}
+
 func (s *SignatureHeaderSegmentV4) SetSigningKeyName(keyName domain.KeyName) {
 	s.KeyName = element.NewKeyName(keyName)
 }
 
+// SigningKeyName returns the name of the signing key. It returns false if the
+// header contains no key name.
+func (s *SignatureHeaderSegmentV4) SigningKeyName() (domain.KeyName, bool) {
+	if s.KeyName == nil {
+		return domain.KeyName{}, false
+	}
+	return s.KeyName.Val(), true
+}
+
 func (s *SignatureHeaderSegmentV4) SetClientSystemID(clientSystemId string) {
 	s.SecurityID = element.NewRDHSecurityIdentification(element.SecurityHolderMessageSender, clientSystemId)
 }
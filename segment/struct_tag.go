@@ -0,0 +1,299 @@
+package segment
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/mitch000001/go-hbci/element"
+	"github.com/mitch000001/go-hbci/internal"
+)
+
+// Segments can define their data elements with struct tags instead of
+// hand-written elements and UnmarshalHBCI methods. Every field with an hbci
+// tag is a data element of the segment, in the order of the fields:
+//
+//	type ExampleSegment struct {
+//		Segment
+//		Name    *element.AlphaNumericDataElement      `hbci:"an,35"`
+//		Account *element.AccountConnectionDataElement `hbci:"ktv,optional"`
+//		IDs     []*element.AlphaNumericDataElement    `hbci:"an,6,optional,max=99"`
+//	}
+//
+//	func (e *ExampleSegment) elements() []element.DataElement {
+//		return taggedElements(e)
+//	}
+//
+//	func (e *ExampleSegment) UnmarshalHBCI(value []byte) error {
+//		return unmarshalTaggedSegment(e, value)
+//	}
+//
+// The tag starts with the type of the data element, followed by its maximum
+// length and the options optional, min=N, max=N and codes=A|B for the valid
+// values of code data elements. The type is one of the short names of data
+// elements, i.e. an, num or ktv, or deg for any data element group. Group
+// data elements are fields of the respective group type. Repeated data
+// elements are slices, which are padded to max if they are followed by
+// further data elements. Only the last data element may be repeated without
+// max. Segments are validated against their tags when they are marshaled,
+// including the Validate methods of group data elements generated from the
+// same tags.
+//
+// Like the generated unmarshalers, unmarshaling passes surplus elements to
+// the last field: a repeated last field takes them as further repetitions,
+// any other last field gets them joined with '+' as its value.
+//
+// Tagged segment types are passed to mustCheckTaggedSegments within an init
+// function of their file, so malformed struct tags panic when the package is
+// loaded instead of on the first use of the segment.
+
+// taggedField represents a struct field defining a data element
+type taggedField struct {
+	index    int
+	name     string
+	tag      internal.StructTag
+	repeated bool
+}
+
+var (
+	dataElementType     = reflect.TypeOf((*element.DataElement)(nil)).Elem()
+	segmentType         = reflect.TypeOf((*Segment)(nil)).Elem()
+	codeDataElementType = reflect.TypeOf((*element.CodeDataElement)(nil))
+	taggedFieldsMap     sync.Map
+)
+
+// taggedFields returns the fields of typ defining data elements. It panics if
+// a struct tag is malformed, used on a field which is no data element or
+// repeats a data element without max which is not the last one, as these are
+// programming errors.
+func taggedFields(typ reflect.Type) []taggedField {
+	if fields, ok := taggedFieldsMap.Load(typ); ok {
+		return fields.([]taggedField)
+	}
+	var fields []taggedField
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag, ok := field.Tag.Lookup(internal.StructTagName)
+		if !ok {
+			continue
+		}
+		parsed, err := internal.ParseStructTag(tag)
+		if err != nil {
+			panic(fmt.Errorf("%s.%s: malformed struct tag: %w", typ, field.Name, err))
+		}
+		fieldType := field.Type
+		repeated := fieldType.Kind() == reflect.Slice
+		if repeated {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() != reflect.Ptr || !fieldType.Implements(dataElementType) {
+			panic(fmt.Errorf("%s.%s: struct tag on field of type %s, which is no data element", typ, field.Name, field.Type))
+		}
+		if !repeated && (parsed.Max != 0 || parsed.Min > 1) {
+			panic(fmt.Errorf("%s.%s: repetitions defined for a single data element", typ, field.Name))
+		}
+		if len(parsed.Codes) != 0 && fieldType != codeDataElementType {
+			panic(fmt.Errorf("%s.%s: codes defined for field of type %s, which is no code data element", typ, field.Name, field.Type))
+		}
+		fields = append(fields, taggedField{index: i, name: field.Name, tag: parsed, repeated: repeated})
+	}
+	for i, field := range fields {
+		if field.repeated && field.tag.Max == 0 && i != len(fields)-1 {
+			panic(fmt.Errorf("%s.%s: repeated data elements without max must be the last element", typ, field.name))
+		}
+	}
+	taggedFieldsMap.Store(typ, fields)
+	return fields
+}
+
+// mustCheckTaggedSegments checks the struct tags of segments, which are
+// pointers to tagged segment types. It panics if a struct tag is malformed.
+func mustCheckTaggedSegments(segments ...interface{}) {
+	for _, seg := range segments {
+		taggedFields(reflect.TypeOf(seg).Elem())
+	}
+}
+
+// taggedElements returns the data elements of seg defined by struct tags.
+// Repeated data elements are padded with empty elements up to their maximum
+// count, if they are followed by further data elements.
+func taggedElements(seg interface{}) []element.DataElement {
+	value := reflect.ValueOf(seg).Elem()
+	fields := taggedFields(value.Type())
+	var elements []element.DataElement
+	for i, field := range fields {
+		fieldValue := value.Field(field.index)
+		if !field.repeated {
+			elements = append(elements, fieldValue.Interface().(element.DataElement))
+			continue
+		}
+		for j := 0; j < fieldValue.Len(); j++ {
+			elements = append(elements, fieldValue.Index(j).Interface().(element.DataElement))
+		}
+		if i == len(fields)-1 {
+			continue
+		}
+		empty := reflect.Zero(fieldValue.Type().Elem()).Interface().(element.DataElement)
+		for j := fieldValue.Len(); j < field.tag.Max; j++ {
+			elements = append(elements, empty)
+		}
+	}
+	return elements
+}
+
+// unmarshalTaggedSegment unmarshals value into seg, which defines its data
+// elements by struct tags. It returns an error if a required data element is
+// missing.
+func unmarshalTaggedSegment(seg basicSegment, value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) == 0 {
+		return fmt.Errorf("malformed marshaled value: no elements extracted")
+	}
+	basic, err := SegmentFromHeaderBytes(elements[0], seg)
+	if err != nil {
+		return err
+	}
+	structValue := reflect.ValueOf(seg).Elem()
+	for i := 0; i < structValue.NumField(); i++ {
+		field := structValue.Type().Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Interface && field.Type.Implements(segmentType) {
+			structValue.Field(i).Set(reflect.ValueOf(basic))
+			break
+		}
+	}
+	return unmarshalTaggedElements(structValue, elements[1:])
+}
+
+func unmarshalTaggedElements(structValue reflect.Value, elements [][]byte) error {
+	fields := taggedFields(structValue.Type())
+	var position int
+	for i, field := range fields {
+		last := i == len(fields)-1
+		fieldValue := structValue.Field(field.index)
+		if field.repeated {
+			if position > len(elements) {
+				position = len(elements)
+			}
+			count := len(elements) - position
+			if !last && field.tag.Max != 0 && field.tag.Max < count {
+				count = field.tag.Max
+			}
+			repetitions := reflect.MakeSlice(fieldValue.Type(), 0, count)
+			for _, marshaled := range elements[position : position+count] {
+				if len(marshaled) == 0 {
+					continue
+				}
+				elem, err := unmarshalTaggedElement(field, fieldValue.Type().Elem(), marshaled)
+				if err != nil {
+					return fmt.Errorf("error unmarshaling %s: %w", field.name, err)
+				}
+				repetitions = reflect.Append(repetitions, elem)
+			}
+			if repetitions.Len() < field.tag.Min {
+				return fmt.Errorf("malformed marshaled value: %s requires %d elements, got %d", field.name, field.tag.Min, repetitions.Len())
+			}
+			if repetitions.Len() != 0 {
+				fieldValue.Set(repetitions)
+			}
+			position += count
+			continue
+		}
+		var marshaled []byte
+		if position < len(elements) {
+			marshaled = elements[position]
+			if last && position < len(elements)-1 {
+				marshaled = bytes.Join(elements[position:], []byte("+"))
+			}
+		}
+		position++
+		if len(marshaled) == 0 {
+			if !field.tag.Optional {
+				return fmt.Errorf("malformed marshaled value: missing required element %s", field.name)
+			}
+			continue
+		}
+		elem, err := unmarshalTaggedElement(field, fieldValue.Type(), marshaled)
+		if err != nil {
+			return fmt.Errorf("error unmarshaling %s: %w", field.name, err)
+		}
+		fieldValue.Set(elem)
+	}
+	return nil
+}
+
+// unmarshalTaggedElement unmarshals marshaled into a new data element of typ.
+// Code data elements are created with the valid codes of field.
+func unmarshalTaggedElement(field taggedField, typ reflect.Type, marshaled []byte) (reflect.Value, error) {
+	elem := reflect.New(typ.Elem())
+	if len(field.tag.Codes) != 0 {
+		elem = reflect.ValueOf(element.NewCode("", field.tag.MaxLength, field.tag.Codes))
+	}
+	if err := elem.Interface().(element.DataElement).UnmarshalHBCI(marshaled); err != nil {
+		return reflect.Value{}, err
+	}
+	return elem, nil
+}
+
+// validateTaggedElements validates the data elements of seg against their
+// struct tags. Segments without struct tags are always valid.
+func validateTaggedElements(seg interface{}) error {
+	value := reflect.ValueOf(seg)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	value = value.Elem()
+	for _, field := range taggedFields(value.Type()) {
+		fieldValue := value.Field(field.index)
+		if !field.repeated {
+			if fieldValue.IsNil() {
+				if !field.tag.Optional {
+					return fmt.Errorf("%s: missing required element %s", value.Type(), field.name)
+				}
+				continue
+			}
+			if err := validateTaggedElement(field, fieldValue.Interface().(element.DataElement)); err != nil {
+				return fmt.Errorf("%s: %w", value.Type(), err)
+			}
+			continue
+		}
+		count := fieldValue.Len()
+		if count < field.tag.Min || (field.tag.Max != 0 && count > field.tag.Max) {
+			return fmt.Errorf("%s: %s has %d elements, must have between %d and %d", value.Type(), field.name, count, field.tag.Min, field.tag.Max)
+		}
+		for j := 0; j < count; j++ {
+			if fieldValue.Index(j).IsNil() {
+				return fmt.Errorf("%s: %s contains an empty element", value.Type(), field.name)
+			}
+			if err := validateTaggedElement(field, fieldValue.Index(j).Interface().(element.DataElement)); err != nil {
+				return fmt.Errorf("%s: %w", value.Type(), err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateTaggedElement(field taggedField, elem element.DataElement) error {
+	if typed, ok := elem.(interface {
+		Type() element.DataElementType
+	}); ok && field.tag.Type != internal.AnyDataElementGroup {
+		if typ := typed.Type().String(); typ != field.tag.Type {
+			return fmt.Errorf("%s is of type %s, expected %s", field.name, typ, field.tag.Type)
+		}
+	}
+	if field.tag.MaxLength != 0 && elem.Length() > field.tag.MaxLength {
+		return fmt.Errorf("%s exceeds the maximum length of %d", field.name, field.tag.MaxLength)
+	}
+	if !elem.IsValid() {
+		return fmt.Errorf("%s is invalid", field.name)
+	}
+	if validator, ok := elem.(interface{ Validate() error }); ok {
+		if err := validator.Validate(); err != nil {
+			return fmt.Errorf("%s is invalid: %w", field.name, err)
+		}
+	}
+	return nil
+}
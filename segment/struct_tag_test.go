@@ -0,0 +1,325 @@
+package segment
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/element"
+)
+
+type taggedTestSegment struct {
+	Segment
+	Name   *element.AlphaNumericDataElement   `hbci:"an,10"`
+	IDs    []*element.AlphaNumericDataElement `hbci:"an,6,optional,max=2"`
+	Amount *element.AmountDataElement         `hbci:"deg,optional"`
+	Count  *element.NumberDataElement         `hbci:"num,3,optional"`
+}
+
+func (t *taggedTestSegment) Version() int         { return 1 }
+func (t *taggedTestSegment) ID() string           { return "HITST" }
+func (t *taggedTestSegment) referencedId() string { return "" }
+func (t *taggedTestSegment) sender() string       { return senderBank }
+
+func (t *taggedTestSegment) elements() []element.DataElement {
+	return taggedElements(t)
+}
+
+func (t *taggedTestSegment) UnmarshalHBCI(value []byte) error {
+	return unmarshalTaggedSegment(t, value)
+}
+
+type unboundedTaggedTestSegment struct {
+	Segment
+	IDs  []*element.AlphaNumericDataElement `hbci:"an,6,optional"`
+	Name *element.AlphaNumericDataElement   `hbci:"an,10"`
+}
+
+func TestTaggedFieldsUnboundedRepetitionNotLast(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Logf("Expected panic for unbounded repeated element followed by further elements\n")
+			t.Fail()
+		}
+	}()
+
+	taggedFields(reflect.TypeOf(unboundedTaggedTestSegment{}))
+}
+
+type codedTaggedTestSegment struct {
+	Segment
+	Kind *element.CodeDataElement `hbci:"code,1,codes=1|2"`
+}
+
+func (c *codedTaggedTestSegment) Version() int         { return 1 }
+func (c *codedTaggedTestSegment) ID() string           { return "HITST" }
+func (c *codedTaggedTestSegment) referencedId() string { return "" }
+func (c *codedTaggedTestSegment) sender() string       { return senderBank }
+
+func (c *codedTaggedTestSegment) elements() []element.DataElement {
+	return taggedElements(c)
+}
+
+func (c *codedTaggedTestSegment) UnmarshalHBCI(value []byte) error {
+	return unmarshalTaggedSegment(c, value)
+}
+
+func TestTaggedSegmentCodes(t *testing.T) {
+	tests := []struct {
+		marshaled string
+		valid     bool
+	}{
+		{"HITST:3:1+2'", true},
+		{"HITST:3:1+3'", false},
+	}
+	for _, test := range tests {
+		seg := &codedTaggedTestSegment{}
+		err := seg.UnmarshalHBCI([]byte(test.marshaled))
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+
+		_, err = seg.Segment.(*segment).MarshalHBCI()
+
+		if test.valid && err != nil {
+			t.Logf("%s: Expected no error, got %T:%v\n", test.marshaled, err, err)
+			t.Fail()
+		}
+		if !test.valid && (err == nil || !strings.Contains(err.Error(), "Kind is invalid")) {
+			t.Logf("%s: Expected error for invalid code, got %v\n", test.marshaled, err)
+			t.Fail()
+		}
+	}
+}
+
+type codesOnNoCodeTestSegment struct {
+	Segment
+	Name *element.AlphaNumericDataElement `hbci:"an,10,codes=A|B"`
+}
+
+func TestTaggedFieldsCodesOnNoCode(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Logf("Expected panic for codes defined on a field which is no code data element\n")
+			t.Fail()
+		}
+	}()
+
+	taggedFields(reflect.TypeOf(codesOnNoCodeTestSegment{}))
+}
+
+func TestTaggedSegmentMarshalHBCI(t *testing.T) {
+	seg := &taggedTestSegment{
+		Name:   element.NewAlphaNumeric("abc", 10),
+		IDs:    []*element.AlphaNumericDataElement{element.NewAlphaNumeric("HKCAZ", 6)},
+		Amount: element.NewAmount(domain.NewAmount(1250, "EUR")),
+	}
+	seg.Segment = NewBasicSegment(3, seg)
+
+	marshaled, err := seg.Segment.(*segment).MarshalHBCI()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	expected := "HITST:3:1+abc+HKCAZ++12,5:EUR'"
+	if string(marshaled) != expected {
+		t.Logf("Expected marshaled value to equal\n%q\n\tgot\n%q\n", expected, marshaled)
+		t.Fail()
+	}
+}
+
+func TestTaggedSegmentMarshalHBCIValidation(t *testing.T) {
+	tests := []struct {
+		description string
+		segment     *taggedTestSegment
+		expectedErr string
+	}{
+		{
+			"missing required element",
+			&taggedTestSegment{},
+			"missing required element Name",
+		},
+		{
+			"too long element",
+			&taggedTestSegment{Name: element.NewAlphaNumeric("abcdefghijk", 20)},
+			"Name exceeds the maximum length of 10",
+		},
+		{
+			"too many repetitions",
+			&taggedTestSegment{
+				Name: element.NewAlphaNumeric("abc", 10),
+				IDs: []*element.AlphaNumericDataElement{
+					element.NewAlphaNumeric("HKCAZ", 6),
+					element.NewAlphaNumeric("HKTAN", 6),
+					element.NewAlphaNumeric("HKSAL", 6),
+				},
+			},
+			"IDs has 3 elements, must have between 0 and 2",
+		},
+	}
+	for _, test := range tests {
+		test.segment.Segment = NewBasicSegment(3, test.segment)
+
+		_, err := test.segment.Segment.(*segment).MarshalHBCI()
+
+		if err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+			t.Logf("%s: Expected error containing %q, got %v\n", test.description, test.expectedErr, err)
+			t.Fail()
+		}
+	}
+}
+
+type mistypedTestSegment struct {
+	Segment
+	Count *element.NumberDataElement `hbci:"an,3"`
+}
+
+func (m *mistypedTestSegment) Version() int         { return 1 }
+func (m *mistypedTestSegment) ID() string           { return "HITST" }
+func (m *mistypedTestSegment) referencedId() string { return "" }
+func (m *mistypedTestSegment) sender() string       { return senderBank }
+
+func (m *mistypedTestSegment) elements() []element.DataElement {
+	return taggedElements(m)
+}
+
+func TestTaggedSegmentMarshalHBCIWrongType(t *testing.T) {
+	seg := &mistypedTestSegment{Count: element.NewNumber(7, 3)}
+	seg.Segment = NewBasicSegment(3, seg)
+
+	_, err := seg.Segment.(*segment).MarshalHBCI()
+
+	expectedErr := "Count is of type num, expected an"
+	if err == nil || !strings.Contains(err.Error(), expectedErr) {
+		t.Logf("Expected error containing %q, got %v\n", expectedErr, err)
+		t.Fail()
+	}
+}
+
+func TestTaggedSegmentUnmarshalHBCI(t *testing.T) {
+	tests := []struct {
+		description string
+		marshaled   string
+		expected    *taggedTestSegment
+	}{
+		{
+			"all elements",
+			"HITST:3:1+abc+HKCAZ+HKTAN+12,5:EUR+7'",
+			&taggedTestSegment{
+				Name: element.NewAlphaNumeric("abc", 3),
+				IDs: []*element.AlphaNumericDataElement{
+					element.NewAlphaNumeric("HKCAZ", 5),
+					element.NewAlphaNumeric("HKTAN", 5),
+				},
+				Amount: element.NewAmount(domain.NewAmount(1250, "EUR")),
+				Count:  element.NewNumber(7, 1),
+			},
+		},
+		{
+			"padded repetitions",
+			"HITST:3:1+abc+HKCAZ++12,5:EUR'",
+			&taggedTestSegment{
+				Name: element.NewAlphaNumeric("abc", 3),
+				IDs: []*element.AlphaNumericDataElement{
+					element.NewAlphaNumeric("HKCAZ", 5),
+				},
+				Amount: element.NewAmount(domain.NewAmount(1250, "EUR")),
+			},
+		},
+		{
+			"only required elements",
+			"HITST:3:1+abc'",
+			&taggedTestSegment{
+				Name: element.NewAlphaNumeric("abc", 3),
+			},
+		},
+	}
+	for _, test := range tests {
+		seg := &taggedTestSegment{}
+
+		err := seg.UnmarshalHBCI([]byte(test.marshaled))
+
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %T:%v\n", test.description, err, err)
+		}
+		if seg.Segment == nil {
+			t.Fatalf("%s: Expected segment header to be unmarshaled\n", test.description)
+		}
+		expectedElements := fmt.Sprintf("%v", test.expected.elements())
+		if actualElements := fmt.Sprintf("%v", seg.elements()); actualElements != expectedElements {
+			t.Logf("%s: Expected elements to equal\n%s\n\tgot\n%s\n", test.description, expectedElements, actualElements)
+			t.Fail()
+		}
+		marshaled, err := seg.Segment.(*segment).MarshalHBCI()
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %T:%v\n", test.description, err, err)
+		}
+		if string(marshaled) != test.marshaled {
+			t.Logf("%s: Expected marshaled segment to equal\n%q\n\tgot\n%q\n", test.description, test.marshaled, marshaled)
+			t.Fail()
+		}
+	}
+}
+
+func TestTaggedSegmentUnmarshalHBCIMissingRequiredElement(t *testing.T) {
+	seg := &taggedTestSegment{}
+
+	err := seg.UnmarshalHBCI([]byte("HITST:3:1++HKCAZ'"))
+
+	if err == nil {
+		t.Logf("Expected error for missing required element\n")
+		t.Fail()
+	}
+}
+
+type surplusTaggedTestSegment struct {
+	Segment
+	Name *element.AlphaNumericDataElement `hbci:"an,10"`
+	Rest *element.AlphaNumericDataElement `hbci:"an,20,optional"`
+}
+
+func (s *surplusTaggedTestSegment) Version() int         { return 1 }
+func (s *surplusTaggedTestSegment) ID() string           { return "HITST" }
+func (s *surplusTaggedTestSegment) referencedId() string { return "" }
+func (s *surplusTaggedTestSegment) sender() string       { return senderBank }
+
+func (s *surplusTaggedTestSegment) elements() []element.DataElement {
+	return taggedElements(s)
+}
+
+func (s *surplusTaggedTestSegment) UnmarshalHBCI(value []byte) error {
+	return unmarshalTaggedSegment(s, value)
+}
+
+func TestTaggedSegmentUnmarshalHBCISurplusElements(t *testing.T) {
+	seg := &surplusTaggedTestSegment{}
+
+	err := seg.UnmarshalHBCI([]byte("HITST:3:1+abc+def+ghi'"))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if seg.Rest == nil || seg.Rest.Val() != "def+ghi" {
+		t.Logf("Expected surplus elements to be joined into the last element, got %v\n", seg.Rest)
+		t.Fail()
+	}
+}
+
+func TestKnownSegmentsTaggedFields(t *testing.T) {
+	for id, segmentFn := range KnownSegments.segmentMap {
+		func() {
+			defer func() {
+				if err := recover(); err != nil {
+					t.Logf("%s: Expected valid struct tags, got %v\n", id, err)
+					t.Fail()
+				}
+			}()
+			typ := reflect.TypeOf(segmentFn())
+			if typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Struct {
+				taggedFields(typ.Elem())
+			}
+		}()
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"bytes"
 	"fmt"
 	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/mitch000001/go-hbci/charset"
+	"github.com/mitch000001/go-hbci/domain"
 	"github.com/mitch000001/go-hbci/internal"
 )
 
@@ -29,6 +33,11 @@ type AlphaNumericTag struct {
 	*tag
 }
 
+// NewAlphaNumericTag returns a new AlphaNumericTag with the given id and value
+func NewAlphaNumericTag(id, value string) *AlphaNumericTag {
+	return &AlphaNumericTag{&tag{id: id, value: value}}
+}
+
 // Unmarshal unmarshals value into a
 func (a *AlphaNumericTag) Unmarshal(value []byte) error {
 	elements, err := extractTagElements(value)
@@ -49,6 +58,14 @@ func (a *AlphaNumericTag) Val() string {
 	return a.value.(string)
 }
 
+// Marshal marshals a into its S.W.I.F.T. representation
+func (a *AlphaNumericTag) Marshal() ([]byte, error) {
+	if a.tag == nil || a.id == "" {
+		return nil, fmt.Errorf("%T: missing tag", a)
+	}
+	return append([]byte(a.id), encodeSwiftText(a.Val())...), nil
+}
+
 // A NumberTag represents numeric data in a S.W.I.F.T. tag
 type NumberTag struct {
 	*tag
@@ -159,17 +176,12 @@ func (c *CustomFieldTag) Unmarshal(value []byte) error {
 	marshaledFields = bytes.Replace(
 		marshaledFields, []byte{'\r', '\n'}, []byte{}, -1,
 	)
-	var fields []fieldKeyIndex
-	for _, fieldKey := range customFieldTagFieldKeys {
-		if idx := bytes.Index(marshaledFields, fieldKey); idx != -1 {
-			fields = append(fields, fieldKeyIndex{fieldKey, idx})
-		}
-	}
+	fields := customFieldKeyIndices(marshaledFields)
 
 	getFieldValue := func(currentFieldKeyIndex, nextFieldKeyIndex int) string {
-		return charset.ToUTF8(
+		return unescapeCustomFieldValue(charset.ToUTF8(
 			marshaledFields[currentFieldKeyIndex+3 : nextFieldKeyIndex],
-		)
+		))
 	}
 	for i, fieldKeyIndex := range fields {
 		var nextFieldKeyIndex int
@@ -211,6 +223,183 @@ func (c *CustomFieldTag) Unmarshal(value []byte) error {
 	return nil
 }
 
+// customFieldKeyIndices returns the field keys within marshaledFields in
+// order of appearance. Escaped question marks do not start a field.
+func customFieldKeyIndices(marshaledFields []byte) []fieldKeyIndex {
+	var fields []fieldKeyIndex
+	for i := 0; i < len(marshaledFields); i++ {
+		if marshaledFields[i] != '?' {
+			continue
+		}
+		if bytes.HasPrefix(marshaledFields[i:], []byte("??")) {
+			i++
+			continue
+		}
+		for _, fieldKey := range customFieldTagFieldKeys {
+			if bytes.HasPrefix(marshaledFields[i:], fieldKey) {
+				fields = append(fields, fieldKeyIndex{fieldKey, i})
+				i += len(fieldKey) - 1
+				break
+			}
+		}
+	}
+	return fields
+}
+
+// Category classifies the transaction by its business transaction code.
+// Returned direct debits and credit transfers are classified as returns,
+// regardless of the category of their code.
+func (c *CustomFieldTag) Category() domain.TransactionCategory {
+	if domain.IsReturn(c.TransactionID, c.MessageKeyAddition) {
+		return domain.TransactionCategoryReturn
+	}
+	transactionCode, _ := domain.LookupTransactionCode(c.TransactionID)
+	return transactionCode.Category
+}
+
+// maxPurposeLines and maxPurpose2Lines are the number of subfields available
+// for the purpose in field 86, ?20 to ?29 and ?60 to ?63
+const (
+	maxPurposeLines  = 10
+	maxPurpose2Lines = 4
+)
+
+// Marshal marshals c into its S.W.I.F.T. representation. Question marks
+// within the values are escaped by doubling them, as they would start a new
+// subfield. Each escaped purpose line must fit into a subfield of 27
+// characters. The name is split into the subfields ?32 and ?33 and truncated
+// if it exceeds both.
+//
+// The lines of the marshaled tag are wrapped between subfields, so that no
+// line exceeds 65 characters. The number of lines is not limited to the six
+// lines of the S.W.I.F.T. standard, as the subfields of field 86 defined by
+// the Deutsche Kreditwirtschaft hold more than 390 characters.
+func (c *CustomFieldTag) Marshal() ([]byte, error) {
+	if c.TransactionID < 0 || c.TransactionID > 999 {
+		return nil, fmt.Errorf("%T: malformed transaction ID %d", c, c.TransactionID)
+	}
+	if len(c.Purpose) > maxPurposeLines {
+		return nil, fmt.Errorf("%T: too many purpose lines: %d", c, len(c.Purpose))
+	}
+	if len(c.Purpose2) > maxPurpose2Lines {
+		return nil, fmt.Errorf("%T: too many purpose lines: %d", c, len(c.Purpose2))
+	}
+	var subfields []string
+	addSubfield := func(key, value string) {
+		if value != "" {
+			subfields = append(subfields, key+escapeCustomFieldValue(value))
+		}
+	}
+	addSubfield("?00", c.BookingText)
+	addSubfield("?10", c.PrimanotenNumber)
+	for i, line := range c.Purpose {
+		if escapedLength([]rune(line)) > maxPurposeLineLength {
+			return nil, fmt.Errorf("%T: escaped purpose line exceeds %d characters: %q", c, maxPurposeLineLength, line)
+		}
+		addSubfield(fmt.Sprintf("?2%d", i), line)
+	}
+	addSubfield("?30", c.BankID)
+	addSubfield("?31", c.AccountID)
+	name := wrapText(c.Name, maxPurposeLineLength)
+	for i := 0; i < len(name) && i < 2; i++ {
+		addSubfield(fmt.Sprintf("?3%d", i+2), name[i])
+	}
+	if c.MessageKeyAddition != 0 {
+		addSubfield("?34", fmt.Sprintf("%03d", c.MessageKeyAddition))
+	}
+	for i, line := range c.Purpose2 {
+		if escapedLength([]rune(line)) > maxPurposeLineLength {
+			return nil, fmt.Errorf("%T: escaped purpose line exceeds %d characters: %q", c, maxPurposeLineLength, line)
+		}
+		addSubfield(fmt.Sprintf("?6%d", i), line)
+	}
+	line := tagID(c.Tag, ":86:") + fmt.Sprintf("%03d", c.TransactionID)
+	var buf bytes.Buffer
+	for _, subfield := range subfields {
+		if utf8.RuneCountInString(line)+utf8.RuneCountInString(subfield) > maxLineLength {
+			buf.Write(encodeSwiftText(line))
+			buf.WriteString("\r\n")
+			line = ""
+		}
+		line += subfield
+	}
+	buf.Write(encodeSwiftText(line))
+	return buf.Bytes(), nil
+}
+
+// maxLineLength is the maximum length of a line within a S.W.I.F.T. message
+const maxLineLength = 65
+
+var (
+	customFieldValueEscaper   = strings.NewReplacer("?", "??", "\r", " ", "\n", " ")
+	customFieldValueUnescaper = strings.NewReplacer("??", "?")
+)
+
+func escapeCustomFieldValue(value string) string {
+	return customFieldValueEscaper.Replace(value)
+}
+
+func unescapeCustomFieldValue(value string) string {
+	return customFieldValueUnescaper.Replace(value)
+}
+
+// encodeSwiftText encodes value in ISO-8859-1, the encoding used by the bank
+// institutes. Characters without a representation in ISO-8859-1 are replaced
+// by a dot.
+func encodeSwiftText(value string) []byte {
+	value = strings.Map(func(r rune) rune {
+		if r > unicode.MaxLatin1 {
+			return '.'
+		}
+		return r
+	}, value)
+	return charset.ToISO8859_1(value)
+}
+
+// wrapText splits value into lines of at most width characters once escaped
+// by escapeCustomFieldValue. Lines are wrapped at spaces, words exceeding
+// width are split. As the lines are escaped afterwards, an escaped question
+// mark is never split.
+func wrapText(value string, width int) []string {
+	var lines []string
+	var line []rune
+	for _, word := range strings.Fields(value) {
+		runes := []rune(word)
+		if len(line) != 0 && escapedLength(line)+1+escapedLength(runes) <= width {
+			line = append(append(line, ' '), runes...)
+			continue
+		}
+		if len(line) != 0 {
+			lines = append(lines, string(line))
+		}
+		for escapedLength(runes) > width {
+			n, length := 0, 0
+			for ; length+escapedLength(runes[n:n+1]) <= width; n++ {
+				length += escapedLength(runes[n : n+1])
+			}
+			lines = append(lines, string(runes[:n]))
+			runes = runes[n:]
+		}
+		line = runes
+	}
+	if len(line) != 0 {
+		lines = append(lines, string(line))
+	}
+	return lines
+}
+
+// escapedLength returns the number of characters of value once escaped by
+// escapeCustomFieldValue
+func escapedLength(value []rune) int {
+	length := len(value)
+	for _, r := range value {
+		if r == '?' {
+			length++
+		}
+	}
+	return length
+}
+
 type fieldKeyIndex struct {
 	fieldKey []byte
 	index    int
@@ -3,6 +3,8 @@ package swift
 import (
 	"reflect"
 	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
 )
 
 func TestCustomFieldTagUnmarshal(t *testing.T) {
@@ -35,3 +37,29 @@ func TestCustomFieldTagUnmarshal(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestCustomFieldTagCategory(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected domain.TransactionCategory
+	}{
+		{":86:166?00GUTSCHR. UEBERWEISUNG?20SVWZ+Miete?3497", domain.TransactionCategoryTransfer},
+		{":86:105?00FOLGELASTSCHRIFT?20SVWZ+Beitrag", domain.TransactionCategoryDirectDebit},
+		{":86:105?00RUECKLASTSCHRIFT?20SVWZ+Beitrag?34901", domain.TransactionCategoryReturn},
+		{":86:808?00GEBUEHREN", domain.TransactionCategoryFee},
+	}
+	for _, test := range tests {
+		tag := &CustomFieldTag{}
+
+		err := tag.Unmarshal([]byte(test.input))
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+
+		if tag.Category() != test.expected {
+			t.Logf("%s: Expected category to equal %q, got %q\n", test.input, test.expected, tag.Category())
+			t.Fail()
+		}
+	}
+}
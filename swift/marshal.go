@@ -0,0 +1,237 @@
+package swift
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+// maxReferenceLength is the maximum length of the customer reference in a
+// transaction tag
+const maxReferenceLength = 16
+
+// defaultJobReference is used as job reference for marshaled statements
+const defaultJobReference = "STARTUMS"
+
+// MT940Marshaler marshals account transactions into MT940 messages
+type MT940Marshaler interface {
+	MarshalMT940([]domain.AccountTransaction) ([]byte, error)
+}
+
+// NewMT940MessagesMarshaler returns a MT940Marshaler which creates one MT940
+// message per account statement.
+func NewMT940MessagesMarshaler() MT940Marshaler {
+	return &mt940MessagesMarshaler{}
+}
+
+type mt940MessagesMarshaler struct{}
+
+// MarshalMT940 marshals transactions into MT940 messages, one per statement
+// as returned by domain.AccountTransactions.Statements.
+func (m *mt940MessagesMarshaler) MarshalMT940(transactions []domain.AccountTransaction) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, statement := range domain.AccountTransactions(transactions).Statements() {
+		mt, err := NewMT940(statement)
+		if err != nil {
+			return nil, fmt.Errorf("error creating MT940: %w", err)
+		}
+		marshaled, err := mt.Marshal()
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling MT940: %w", err)
+		}
+		buf.Write(marshaled)
+	}
+	return buf.Bytes(), nil
+}
+
+// NewMT940 creates a MT940 statement from transactions. Account, statement
+// number and balances are taken from the first and the last transaction. If
+// the balances do not carry a date, the booking dates of the transactions are
+// used.
+//
+// Purposes exceeding the subfields of field 86 are truncated, references
+// exceeding 16 characters are cut off.
+func NewMT940(transactions []domain.AccountTransaction) (*MT940, error) {
+	if len(transactions) == 0 {
+		return nil, fmt.Errorf("no transactions")
+	}
+	first, last := transactions[0], transactions[len(transactions)-1]
+	currency := first.Amount.Currency
+	if currency == "" {
+		currency = first.AccountBalanceBefore.Amount.Currency
+	}
+	if currency == "" {
+		currency = "EUR"
+	}
+	m := &MT940{
+		JobReference:    NewAlphaNumericTag(":20:", defaultJobReference),
+		Account:         &AccountTag{Tag: ":25:", BankID: first.Account.BankID, AccountID: first.Account.AccountID},
+		StatementNumber: &StatementNumberTag{Tag: ":28C:", Number: first.StatementNumber},
+		StartingBalance: newBalanceTag(":60F:", first.AccountBalanceBefore, first.BookingDate, currency),
+		ClosingBalance:  newBalanceTag(":62F:", last.AccountBalanceAfter, last.BookingDate, currency),
+	}
+	for _, transaction := range transactions {
+		if transaction.Amount.Currency != "" && transaction.Amount.Currency != currency {
+			return nil, fmt.Errorf("currency mismatch: %s != %s", transaction.Amount.Currency, currency)
+		}
+		m.Transactions = append(m.Transactions, &TransactionSequence{
+			Transaction: newTransactionTag(transaction),
+			Description: newCustomFieldTag(transaction),
+		})
+	}
+	return m, nil
+}
+
+func newBalanceTag(tag string, balance domain.Balance, bookingDate time.Time, currency string) *BalanceTag {
+	date := balance.TransmissionDate
+	if date.IsZero() {
+		date = bookingDate
+	}
+	return &BalanceTag{
+		Tag:                  tag,
+		DebitCreditIndicator: debitCredit(balance.Amount),
+		BookingDate:          domain.NewShortDate(date),
+		Currency:             currency,
+		Amount:               balance.Amount.Abs().WithCurrency(currency),
+	}
+}
+
+func newTransactionTag(transaction domain.AccountTransaction) *TransactionTag {
+	valutaDate := transaction.ValutaDate
+	if valutaDate.IsZero() {
+		valutaDate = transaction.BookingDate
+	}
+	return &TransactionTag{
+		Tag:                  ":61:",
+		ValutaDate:           domain.NewShortDate(valutaDate),
+		BookingDate:          domain.NewShortDate(transaction.BookingDate),
+		DebitCreditIndicator: debitCredit(transaction.Amount),
+		Amount:               transaction.Amount.Abs(),
+		BookingKey:           "MSC",
+		Reference:            truncate(transaction.Reference, maxReferenceLength),
+		BankReference:        truncate(transaction.BankReference, maxReferenceLength),
+	}
+}
+
+func newCustomFieldTag(transaction domain.AccountTransaction) *CustomFieldTag {
+	purpose := transaction.Purpose
+	if purpose == "" {
+		purpose = sepaPurposeFromTransaction(transaction)
+	}
+	lines := wrapText(purpose, maxPurposeLineLength)
+	lines2 := wrapText(transaction.Purpose2, maxPurposeLineLength)
+	if len(lines) > maxPurposeLines {
+		lines2 = append(lines[maxPurposeLines:], lines2...)
+		lines = lines[:maxPurposeLines]
+	}
+	if len(lines2) > maxPurpose2Lines {
+		lines2 = lines2[:maxPurpose2Lines]
+	}
+	bankID, accountID := transaction.BankID, transaction.AccountID
+	if bankID == "" {
+		bankID = transaction.CounterpartyBIC
+	}
+	if accountID == "" {
+		accountID = transaction.CounterpartyIBAN
+	}
+	return &CustomFieldTag{
+		Tag:                ":86:",
+		TransactionID:      transaction.TransactionID,
+		BookingText:        transaction.BookingText,
+		Purpose:            lines,
+		BankID:             bankID,
+		AccountID:          accountID,
+		Name:               transaction.Name,
+		MessageKeyAddition: transaction.MessageKeyAddition,
+		Purpose2:           lines2,
+	}
+}
+
+// sepaPurposeFromTransaction composes a purpose with SEPA qualifiers from the
+// SEPA fields of transaction
+func sepaPurposeFromTransaction(transaction domain.AccountTransaction) string {
+	var parts []string
+	add := func(qualifier, value string) {
+		if value != "" {
+			parts = append(parts, qualifier+value)
+		}
+	}
+	add("EREF+", transaction.EndToEndReference)
+	add("KREF+", transaction.CustomerReference)
+	add("MREF+", transaction.MandateReference)
+	add("CRED+", transaction.CreditorID)
+	add("SVWZ+", transaction.RemittanceInformation)
+	add("ABWA+", transaction.UltimateDebtor)
+	add("ABWE+", transaction.UltimateCreditor)
+	return strings.Join(parts, " ")
+}
+
+func debitCredit(amount domain.Amount) string {
+	if amount.Sign() < 0 {
+		return "D"
+	}
+	return "C"
+}
+
+func truncate(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) > length {
+		return string(runes[:length])
+	}
+	return value
+}
+
+// Marshal marshals m into a S.W.I.F.T. message, terminated by the message
+// separator
+func (m *MT940) Marshal() ([]byte, error) {
+	if m.Account == nil || m.StartingBalance == nil || m.ClosingBalance == nil {
+		return nil, fmt.Errorf("%T: account and balances must be set", m)
+	}
+	type marshaler interface {
+		Marshal() ([]byte, error)
+	}
+	var tags []marshaler
+	if m.JobReference != nil {
+		tags = append(tags, m.JobReference)
+	} else {
+		tags = append(tags, NewAlphaNumericTag(":20:", defaultJobReference))
+	}
+	if m.Reference != nil {
+		tags = append(tags, m.Reference)
+	}
+	tags = append(tags, m.Account)
+	if m.StatementNumber != nil {
+		tags = append(tags, m.StatementNumber)
+	}
+	tags = append(tags, m.StartingBalance)
+	for _, sequence := range m.Transactions {
+		tags = append(tags, sequence.Transaction)
+		if sequence.Description != nil {
+			tags = append(tags, sequence.Description)
+		}
+	}
+	tags = append(tags, m.ClosingBalance)
+	if m.CurrentValutaBalance != nil {
+		tags = append(tags, m.CurrentValutaBalance)
+	}
+	if m.FutureValutaBalance != nil {
+		tags = append(tags, m.FutureValutaBalance)
+	}
+	if m.CustomField != nil {
+		tags = append(tags, m.CustomField)
+	}
+	var buf bytes.Buffer
+	for _, tag := range tags {
+		marshaled, err := tag.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString("\r\n")
+		buf.Write(marshaled)
+	}
+	buf.WriteString("\r\n-")
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,193 @@
+package swift
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kr/pretty"
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+func TestCustomFieldTagMarshal(t *testing.T) {
+	tag := &CustomFieldTag{
+		TransactionID: 166,
+		BookingText:   "GUTSCHR. UEBERWEISUNG",
+		Purpose: []string{
+			"EREF+RE-2021-0815",
+			"SVWZ+Rechnung? 2021-0815",
+		},
+		BankID:             "COBADEFFXXX",
+		AccountID:          "DE89370400440532013000",
+		Name:               "Stadtwerke Musterstadt Versorgungsbetriebe GmbH",
+		MessageKeyAddition: 997,
+	}
+
+	marshaled, err := tag.Marshal()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	expected := ":86:166?00GUTSCHR. UEBERWEISUNG?20EREF+RE-2021-0815\r\n" +
+		"?21SVWZ+Rechnung?? 2021-0815?30COBADEFFXXX\r\n" +
+		"?31DE89370400440532013000?32Stadtwerke Musterstadt\r\n" +
+		"?33Versorgungsbetriebe GmbH?34997"
+	if string(marshaled) != expected {
+		t.Logf("Expected marshaled tag to equal\n%q\n\tgot\n%q\n", expected, marshaled)
+		t.Fail()
+	}
+	for _, line := range strings.Split(string(marshaled), "\r\n") {
+		if len(line) > maxLineLength {
+			t.Logf("Expected lines to not exceed %d characters, got %q\n", maxLineLength, line)
+			t.Fail()
+		}
+	}
+
+	unmarshaled := &CustomFieldTag{}
+	err = unmarshaled.Unmarshal(marshaled)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	unmarshaled.Tag = ""
+	if !reflect.DeepEqual(tag, unmarshaled) {
+		t.Logf("Expected unmarshaled tag to equal\n%#v\n\tgot\n%#v\n", tag, unmarshaled)
+		t.Fail()
+	}
+
+	for _, purpose := range []string{strings.Repeat("X", 28), strings.Repeat("X", 26) + "?"} {
+		tag.Purpose = []string{purpose}
+
+		_, err = tag.Marshal()
+
+		if err == nil {
+			t.Logf("Expected error for purpose line %q exceeding 27 characters once escaped\n", purpose)
+			t.Fail()
+		}
+	}
+}
+
+func TestCustomFieldTagMarshalEncoding(t *testing.T) {
+	tag := &CustomFieldTag{TransactionID: 105, Name: "Müller €"}
+
+	marshaled, err := tag.Marshal()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	expected := []byte(":86:105?32M\xfcller .")
+	if !bytes.Equal(expected, marshaled) {
+		t.Logf("Expected marshaled tag to equal\n%q\n\tgot\n%q\n", expected, marshaled)
+		t.Fail()
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []string
+	}{
+		{"", nil},
+		{"Miete Maerz", []string{"Miete Maerz"}},
+		{"Beitrag Mitgliedschaft Sportverein 2021", []string{"Beitrag Mitgliedschaft", "Sportverein 2021"}},
+		{"EREF+1234567890123456789012345", []string{"EREF+1234567890123456789012", "345"}},
+		{"Rechnung 123456789 vom 1??", []string{"Rechnung 123456789 vom", "1??"}},
+		{"SVWZ+1234567890123456789012?X", []string{"SVWZ+1234567890123456789012", "?X"}},
+		{"SVWZ+123456789012345678901?X", []string{"SVWZ+123456789012345678901", "?X"}},
+		{"SVWZ+12345678901234567890?X", []string{"SVWZ+12345678901234567890?", "X"}},
+	}
+	for _, test := range tests {
+		actual := wrapText(test.value, maxPurposeLineLength)
+
+		if !reflect.DeepEqual(test.expected, actual) {
+			t.Logf("Expected %q to wrap into %q, got %q\n", test.value, test.expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+func TestMT940MessagesMarshalerRoundTrip(t *testing.T) {
+	account := domain.AccountConnection{BankID: "12345678", AccountID: "1234123456", CountryCode: 280}
+	before := domain.Balance{Amount: domain.NewAmount(123456, "EUR"), TransmissionDate: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)}
+	after := domain.Balance{Amount: domain.NewAmount(-20000, "EUR"), TransmissionDate: time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC)}
+	transactions := []domain.AccountTransaction{
+		{
+			Account:               account,
+			Amount:                domain.NewAmount(-143456, "EUR"),
+			ValutaDate:            time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+			BookingDate:           time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC),
+			BookingText:           "FOLGELASTSCHRIFT",
+			BankID:                "COBADEFFXXX",
+			AccountID:             "DE89370400440532013000",
+			Name:                  "Stadtwerke",
+			Purpose:               "EREF+RE-2021-0815 MREF+M-4711 CRED+DE98ZZZ09999999999 SVWZ+Rechnung 2021-0815",
+			TransactionID:         105,
+			Category:              domain.TransactionCategoryDirectDebit,
+			Reference:             "NONREF",
+			StatementNumber:       3,
+			AccountBalanceBefore:  before,
+			AccountBalanceAfter:   after,
+			EndToEndReference:     "RE-2021-0815",
+			MandateReference:      "M-4711",
+			CreditorID:            "DE98ZZZ09999999999",
+			RemittanceInformation: "Rechnung 2021-0815",
+			CounterpartyIBAN:      "DE89370400440532013000",
+			CounterpartyBIC:       "COBADEFFXXX",
+		},
+		{
+			Account:              account,
+			Amount:               domain.NewAmount(0, "EUR"),
+			ValutaDate:           time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC),
+			BookingDate:          time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC),
+			BookingText:          "ABSCHLUSS",
+			Purpose:              "Abrechnung vom 01.03.2021 bis 02.03.2021",
+			TransactionID:        805,
+			Category:             domain.TransactionCategoryFee,
+			Reference:            "NONREF",
+			StatementNumber:      3,
+			AccountBalanceBefore: before,
+			AccountBalanceAfter:  after,
+		},
+		{
+			Account:              account,
+			Amount:               domain.NewAmount(5000, "EUR"),
+			ValutaDate:           time.Date(2021, 3, 3, 0, 0, 0, 0, time.UTC),
+			BookingDate:          time.Date(2021, 3, 3, 0, 0, 0, 0, time.UTC),
+			Name:                 "Max Muster",
+			Purpose:              "Geschenk?20 Jahre?",
+			TransactionID:        51,
+			Category:             domain.TransactionCategoryTransfer,
+			Reference:            "NONREF",
+			StatementNumber:      4,
+			AccountBalanceBefore: after,
+			AccountBalanceAfter:  domain.Balance{Amount: domain.NewAmount(-15000, "EUR"), TransmissionDate: time.Date(2021, 3, 3, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	marshaled, err := NewMT940MessagesMarshaler().MarshalMT940(transactions)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	if count := bytes.Count(marshaled, []byte("\r\n-")); count != 2 {
+		t.Logf("Expected 2 statements, got %d\n", count)
+		t.Fail()
+	}
+
+	actual, err := NewMT940MessagesUnmarshaler().UnmarshalMT940(marshaled)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	if !reflect.DeepEqual(transactions, actual) {
+		pretty.Ldiff(t, transactions, actual)
+		t.Logf("Expected transactions to equal\n%#v\n\tgot\n%#v\n", transactions, actual)
+		t.Fail()
+	}
+}
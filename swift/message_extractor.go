@@ -1,6 +1,7 @@
 package swift
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/mitch000001/go-hbci/token"
@@ -21,36 +22,95 @@ type MessageExtractor struct {
 	lexer             *token.SwiftLexer
 	rawSwiftMessage   []byte
 	extractedMessages [][]byte
+	offsets           []int
 }
 
 // Extract extracts raw S.W.I.F.T. messages from the given input
 func (m *MessageExtractor) Extract() ([][]byte, error) {
+	if err := m.extract(); err != nil {
+		return nil, err
+	}
+	result := make([][]byte, len(m.extractedMessages))
+	copy(result, m.extractedMessages)
+	return result, nil
+}
+
+// extract extracts messages until the end of the input or the first syntax
+// error. The messages extracted before the error are kept in m.
+func (m *MessageExtractor) extract() *SyntaxError {
 	var current []byte
+	start := -1
 	for m.lexer.HasNext() {
 		tok := m.lexer.Next()
 		if tok.Type() == token.ERROR {
-			return nil, &SyntaxError{Token: tok, RawMessage: m.rawSwiftMessage}
+			return newSyntaxError(tok, m.rawSwiftMessage)
+		}
+		if start == -1 {
+			start = tok.Pos()
 		}
 		current = append(current, tok.Value()...)
 		if tok.Type() == token.SWIFT_MESSAGE_SEPARATOR {
 			m.extractedMessages = append(m.extractedMessages, current)
+			m.offsets = append(m.offsets, start)
 			current = []byte{}
+			start = -1
 		}
 	}
-	result := make([][]byte, len(m.extractedMessages))
-	copy(result, m.extractedMessages)
-	return result, nil
+	return nil
 }
 
+// SyntaxError represents an error within a S.W.I.F.T. message. Offset, Line
+// and Column define the position of the error within the parsed input.
 type SyntaxError struct {
+	// Token is the offending token. It is nil for errors not raised by the
+	// lexer, i.e. for malformed tag values.
 	Token      token.Token
 	RawMessage []byte
+	// Tag is the ID of the offending tag, if known
+	Tag    string
+	Offset int
+	Line   int
+	Column int
+	// Err is the underlying error, if any
+	Err error
+}
+
+func newSyntaxError(tok token.Token, rawMessage []byte) *SyntaxError {
+	s := &SyntaxError{Token: tok, RawMessage: rawMessage}
+	s.setOffset(tok.Pos())
+	return s
+}
+
+// setOffset sets the offset of s and the line and column derived from it
+func (s *SyntaxError) setOffset(offset int) {
+	if offset > len(s.RawMessage) {
+		offset = len(s.RawMessage)
+	}
+	s.Offset = offset
+	s.Line = bytes.Count(s.RawMessage[:offset], []byte("\n")) + 1
+	s.Column = offset - bytes.LastIndexByte(s.RawMessage[:offset], '\n')
 }
 
 func (s *SyntaxError) Error() string {
-	return fmt.Sprintf("syntax error at position %d: %q\n(%q)", s.Token.Pos(), s.Token.Value(), s.RawMessage)
+	var tag string
+	if s.Tag != "" {
+		tag = fmt.Sprintf(" in tag %s", s.Tag)
+	}
+	var cause string
+	switch {
+	case s.Err != nil:
+		cause = s.Err.Error()
+	case s.Token != nil:
+		cause = fmt.Sprintf("%q", s.Token.Value())
+	}
+	return fmt.Sprintf("syntax error at line %d, column %d (position %d)%s: %s", s.Line, s.Column, s.Offset, tag, cause)
+}
+
+// Unwrap returns the underlying error of s
+func (s *SyntaxError) Unwrap() error {
+	return s.Err
 }
 
 func (s SyntaxError) IsUnexpectedEndOfInput() bool {
-	return token.IsUnexpectedEndOfInput(s.Token)
+	return s.Token != nil && token.IsUnexpectedEndOfInput(s.Token)
 }
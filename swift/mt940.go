@@ -3,7 +3,6 @@ package swift
 import (
 	"bytes"
 	"fmt"
-	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -32,42 +31,11 @@ func (m *MT940) AccountTransactions() []domain.AccountTransaction {
 	accountConnection := domain.AccountConnection{BankID: m.Account.BankID, AccountID: m.Account.AccountID, CountryCode: 280}
 	var transactions []domain.AccountTransaction
 	for _, transactionSequence := range m.Transactions {
-		tr := transactionSequence.Transaction
-		descr := transactionSequence.Description
-		var amount float64
-		if tr.DebitCreditIndicator == "D" {
-			amount = -tr.Amount
-		} else {
-			amount = tr.Amount
-		}
-		transaction := domain.AccountTransaction{
-			Account:     accountConnection,
-			Amount:      domain.Amount{Amount: amount, Currency: m.StartingBalance.Currency},
-			ValutaDate:  tr.ValutaDate.Time,
-			BookingDate: tr.BookingDate.Time,
-			AccountBalanceBefore: domain.Balance{
-				Amount: domain.Amount{
-					Amount:   m.StartingBalance.Amount,
-					Currency: m.StartingBalance.Currency,
-				},
-				TransmissionDate: m.StartingBalance.BookingDate.Time,
-			},
-			AccountBalanceAfter: domain.Balance{
-				Amount: domain.Amount{
-					Amount:   m.ClosingBalance.Amount,
-					Currency: m.ClosingBalance.Currency,
-				},
-				TransmissionDate: m.ClosingBalance.BookingDate.Time,
-			},
-		}
-		if descr != nil {
-			transaction.BookingText = descr.BookingText
-			transaction.BankID = descr.BankID
-			transaction.AccountID = descr.AccountID
-			transaction.Name = descr.Name
-			transaction.Purpose = strings.Join(descr.Purpose, " ")
-			transaction.Purpose2 = strings.Join(descr.Purpose2, " ")
-			transaction.TransactionID = descr.TransactionID
+		transaction := transactionSequence.accountTransaction(accountConnection, m.StartingBalance.Currency)
+		transaction.AccountBalanceBefore = m.StartingBalance.Balance()
+		transaction.AccountBalanceAfter = m.ClosingBalance.Balance()
+		if m.StatementNumber != nil {
+			transaction.StatementNumber = m.StatementNumber.Number
 		}
 		transactions = append(transactions, transaction)
 	}
@@ -100,6 +68,11 @@ func (a *AccountTag) Unmarshal(value []byte) error {
 	return nil
 }
 
+// Marshal marshals a into its S.W.I.F.T. representation
+func (a *AccountTag) Marshal() ([]byte, error) {
+	return []byte(tagID(a.Tag, ":25:") + a.BankID + "/" + a.AccountID), nil
+}
+
 // StatementNumberTag represents a S.W.I.F.T. statement number
 type StatementNumberTag struct {
 	Tag         string
@@ -141,23 +114,32 @@ func (s *StatementNumberTag) Unmarshal(value []byte) error {
 	return nil
 }
 
+// Marshal marshals s into its S.W.I.F.T. representation
+func (s *StatementNumberTag) Marshal() ([]byte, error) {
+	value := tagID(s.Tag, ":28C:") + strconv.Itoa(s.Number)
+	if s.SheetNumber != 0 {
+		value += "/" + strconv.Itoa(s.SheetNumber)
+	}
+	return []byte(value), nil
+}
+
 // A BalanceTag represents a balance in S.W.I.F.T.
 type BalanceTag struct {
 	Tag                  string
 	DebitCreditIndicator string
 	BookingDate          domain.ShortDate
 	Currency             string
-	Amount               float64
+	Amount               domain.Amount
 }
 
 // Balance returns the balance embodied in b
 func (b *BalanceTag) Balance() domain.Balance {
 	amount := b.Amount
 	if b.DebitCreditIndicator == "D" {
-		amount = -amount
+		amount = amount.Neg()
 	}
 	return domain.Balance{
-		Amount:           domain.Amount{Amount: amount, Currency: b.Currency},
+		Amount:           amount,
 		TransmissionDate: b.BookingDate.Time,
 	}
 }
@@ -181,8 +163,7 @@ func (b *BalanceTag) Unmarshal(value []byte) error {
 	}
 	b.BookingDate = domain.NewShortDate(date)
 	b.Currency = string(buf.Next(3))
-	amountString := strings.Replace(buf.String(), ",", ".", 1)
-	amount, err := strconv.ParseFloat(amountString, 64)
+	amount, err := domain.ParseAmount(buf.String(), b.Currency)
 	if err != nil {
 		return errors.Wrap(err, "MT940 Balance tag: error unmarshaling amount")
 	}
@@ -190,6 +171,21 @@ func (b *BalanceTag) Unmarshal(value []byte) error {
 	return nil
 }
 
+// Marshal marshals b into its S.W.I.F.T. representation. As b is used for
+// different balances, the tag must be set.
+func (b *BalanceTag) Marshal() ([]byte, error) {
+	if b.Tag == "" {
+		return nil, fmt.Errorf("%T: missing tag", b)
+	}
+	if len(b.Currency) != 3 {
+		return nil, fmt.Errorf("%T: malformed currency %q", b, b.Currency)
+	}
+	return []byte(fmt.Sprintf(
+		"%s%s%s%s%s",
+		b.Tag, debitCreditIndicator(b.DebitCreditIndicator), b.BookingDate.Format("060102"), b.Currency, b.Amount.Abs().FormatHBCI(),
+	)), nil
+}
+
 // A TransactionSequence represents a transaction with an additional
 // description in S.W.I.F.T.
 type TransactionSequence struct {
@@ -197,6 +193,48 @@ type TransactionSequence struct {
 	Description *CustomFieldTag
 }
 
+// accountTransaction returns the account transaction embodied in t, with
+// its amount in currency
+func (t *TransactionSequence) accountTransaction(account domain.AccountConnection, currency string) domain.AccountTransaction {
+	tr := t.Transaction
+	descr := t.Description
+	amount := tr.Amount.WithCurrency(currency)
+	if tr.DebitCreditIndicator == "D" {
+		amount = amount.Neg()
+	}
+	transaction := domain.AccountTransaction{
+		Account:       account,
+		Amount:        amount,
+		ValutaDate:    tr.ValutaDate.Time,
+		BookingDate:   tr.BookingDate.Time,
+		Reference:     tr.Reference,
+		BankReference: tr.BankReference,
+	}
+	if descr != nil {
+		transaction.BookingText = descr.BookingText
+		transaction.BankID = descr.BankID
+		transaction.AccountID = descr.AccountID
+		transaction.Name = descr.Name
+		transaction.Purpose = strings.Join(descr.Purpose, " ")
+		transaction.Purpose2 = strings.Join(descr.Purpose2, " ")
+		transaction.TransactionID = descr.TransactionID
+		transaction.MessageKeyAddition = descr.MessageKeyAddition
+		transaction.Category = descr.Category()
+		if sepa, ok := descr.SepaPurpose(); ok {
+			transaction.EndToEndReference = sepa.EndToEndReference
+			transaction.CustomerReference = sepa.CustomerReference
+			transaction.MandateReference = sepa.MandateReference
+			transaction.CreditorID = sepa.CreditorID
+			transaction.RemittanceInformation = sepa.RemittanceInformation
+			transaction.UltimateDebtor = sepa.UltimateDebtor
+			transaction.UltimateCreditor = sepa.UltimateCreditor
+			transaction.CounterpartyIBAN = sepa.IBAN
+			transaction.CounterpartyBIC = sepa.BIC
+		}
+	}
+	return transaction
+}
+
 // A TransactionTag represents a transaction in S.W.I.F.T.
 type TransactionTag struct {
 	Tag                   string
@@ -204,15 +242,21 @@ type TransactionTag struct {
 	BookingDate           domain.ShortDate
 	DebitCreditIndicator  string
 	CurrencyKind          string
-	Amount                float64
+	Amount                domain.Amount
 	BookingKey            string
 	Reference             string
 	BankReference         string
 	AdditionalInformation string
 }
 
-// Unmarshal unmarshals value into t
+// Unmarshal unmarshals value into t. As the currency of the transaction is
+// defined by the surrounding statement, the amount is unmarshaled without
+// currency.
 func (t *TransactionTag) Unmarshal(value []byte) error {
+	return t.unmarshal(value, "")
+}
+
+func (t *TransactionTag) unmarshal(value []byte, currency string) error {
 	elements, err := extractTagElements(value)
 	if err != nil {
 		return err
@@ -235,15 +279,11 @@ func (t *TransactionTag) Unmarshal(value []byte) error {
 	if unicode.IsDigit(r) {
 		buf.UnreadRune()
 		dateBytes = buf.Next(4)
-		date, err = parseDate(dateBytes, t.ValutaDate.Year())
+		date, err = inferEntryDate(dateBytes, t.ValutaDate.Time, t.ValutaDate.Time)
 		if err != nil {
 			return errors.WithMessage(err, "unmarshal transaction tag: parsing booking date")
 		}
 		t.BookingDate = domain.NewShortDate(date)
-		monthDiff := int(math.Abs(float64(t.ValutaDate.Month() - t.BookingDate.Month())))
-		if monthDiff > 1 {
-			t.BookingDate = domain.NewShortDate(t.BookingDate.AddDate(1, 0, 0))
-		}
 	}
 	var runes []rune
 	for {
@@ -271,8 +311,7 @@ func (t *TransactionTag) Unmarshal(value []byte) error {
 	if err != nil {
 		return err
 	}
-	amountBytes = bytes.Replace(amountBytes[:len(amountBytes)-1], []byte(","), []byte("."), 1)
-	amount, err := strconv.ParseFloat(string(amountBytes), 64)
+	amount, err := domain.ParseAmount(string(amountBytes[:len(amountBytes)-1]), currency)
 	if err != nil {
 		return errors.Wrap(err, "MT940 Transaction tag: error unmarshaling amount")
 	}
@@ -308,6 +347,122 @@ func (t *TransactionTag) Unmarshal(value []byte) error {
 	return nil
 }
 
+// Marshal marshals t into its S.W.I.F.T. representation. The booking date is
+// omitted if it is not set, an empty reference is marshaled as NONREF.
+func (t *TransactionTag) Marshal() ([]byte, error) {
+	if t.ValutaDate.IsZero() {
+		return nil, fmt.Errorf("%T: missing valuta date", t)
+	}
+	var buf bytes.Buffer
+	buf.WriteString(tagID(t.Tag, ":61:"))
+	buf.WriteString(t.ValutaDate.Format("060102"))
+	if !t.BookingDate.IsZero() {
+		buf.WriteString(t.BookingDate.Format("0102"))
+	}
+	buf.WriteString(debitCreditIndicator(t.DebitCreditIndicator))
+	buf.WriteString(t.CurrencyKind)
+	buf.WriteString(t.Amount.Abs().FormatHBCI())
+	buf.WriteString("N")
+	bookingKey := t.BookingKey
+	if len(bookingKey) != 3 {
+		bookingKey = "MSC"
+	}
+	buf.WriteString(bookingKey)
+	reference := t.Reference
+	if reference == "" {
+		reference = "NONREF"
+	}
+	buf.Write(encodeSwiftText(reference))
+	if t.BankReference != "" {
+		buf.WriteString("//")
+		buf.Write(encodeSwiftText(t.BankReference))
+	}
+	if t.AdditionalInformation != "" {
+		buf.WriteString("\r\n/")
+		buf.Write(encodeSwiftText(t.AdditionalInformation))
+	}
+	return buf.Bytes(), nil
+}
+
+func debitCreditIndicator(indicator string) string {
+	if indicator == "" {
+		return "C"
+	}
+	return indicator
+}
+
+func tagID(id, defaultID string) string {
+	if id == "" {
+		return defaultID
+	}
+	return id
+}
+
+// entryDate returns the month and day of the entry date of the transaction
+// tag, or nil if tag has no entry date
+func entryDate(tag []byte) []byte {
+	elements, err := extractTagElements(tag)
+	if err != nil || len(elements) != 2 || len(elements[1]) < 10 {
+		return nil
+	}
+	for _, b := range elements[1][6:10] {
+		if b < '0' || b > '9' {
+			return nil
+		}
+	}
+	return elements[1][6:10]
+}
+
+// inferEntryDate parses the month and day of an entry date in the format MMDD
+// and infers its year: of the candidates within the year before, of and after
+// from, the date closest to the range between from and to is chosen. So
+// entries booked in early January belong to the next year of a December
+// reference and vice versa.
+func inferEntryDate(value []byte, from, to time.Time) (time.Time, error) {
+	if len(value) != 4 {
+		return time.Time{}, fmt.Errorf("malformed entry date %q", value)
+	}
+	month, err := strconv.Atoi(string(value[:2]))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed entry date %q: %w", value, err)
+	}
+	day, err := strconv.Atoi(string(value[2:]))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed entry date %q: %w", value, err)
+	}
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		return time.Time{}, fmt.Errorf("malformed entry date %q", value)
+	}
+	if to.Before(from) {
+		from, to = to, from
+	}
+	var date time.Time
+	var minDistance time.Duration
+	for year := from.Year() - 1; year <= from.Year()+1; year++ {
+		candidate := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+		if candidate.Day() != day {
+			// no such day in this year, i.e. the 29th of February
+			continue
+		}
+		var distance time.Duration
+		switch {
+		case candidate.Before(from):
+			distance = from.Sub(candidate)
+		case candidate.After(to):
+			distance = candidate.Sub(to)
+		}
+		if date.IsZero() || distance < minDistance {
+			date, minDistance = candidate, distance
+		}
+	}
+	if date.IsZero() {
+		// Some banks use the 30th of February for the end of the month,
+		// which is normalized into March
+		date = time.Date(from.Year(), time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	}
+	return date, nil
+}
+
 func parseDate(value []byte, referenceYear int) (time.Time, error) {
 	var offset int
 	if len(value) == 6 {
@@ -51,7 +51,7 @@ func TestTransactionTagUnmarshal(t *testing.T) {
 				BookingDate:           domain.ShortDate{Time: domain.Date(2015, time.December, 2, time.Local).Truncate(24 * time.Hour)},
 				DebitCreditIndicator:  "D",
 				CurrencyKind:          "R",
-				Amount:                4.52,
+				Amount:                domain.NewAmount(452, ""),
 				BookingKey:            "024",
 				Reference:             "NONREF",
 				BankReference:         "ABC",
@@ -67,7 +67,23 @@ func TestTransactionTagUnmarshal(t *testing.T) {
 				BookingDate:           domain.ShortDate{Time: domain.Date(2016, time.January, 2, time.Local).Truncate(24 * time.Hour)},
 				DebitCreditIndicator:  "D",
 				CurrencyKind:          "R",
-				Amount:                4.52,
+				Amount:                domain.NewAmount(452, ""),
+				BookingKey:            "024",
+				Reference:             "NONREF",
+				BankReference:         "ABC",
+				AdditionalInformation: "DEF",
+			},
+		},
+		{
+			"All attributes set, booking in old year",
+			":61:1601021230DR4,52N024NONREF//ABC\r\n/DEF",
+			&TransactionTag{
+				Tag:                   ":61:",
+				ValutaDate:            domain.ShortDate{Time: domain.Date(2016, time.January, 2, time.Local).Truncate(24 * time.Hour)},
+				BookingDate:           domain.ShortDate{Time: domain.Date(2015, time.December, 30, time.Local).Truncate(24 * time.Hour)},
+				DebitCreditIndicator:  "D",
+				CurrencyKind:          "R",
+				Amount:                domain.NewAmount(452, ""),
 				BookingKey:            "024",
 				Reference:             "NONREF",
 				BankReference:         "ABC",
@@ -83,7 +99,7 @@ func TestTransactionTagUnmarshal(t *testing.T) {
 				BookingDate:           domain.ShortDate{Time: domain.Date(2015, 8, 3, time.Local).Truncate(24 * time.Hour)},
 				DebitCreditIndicator:  "D",
 				CurrencyKind:          "R",
-				Amount:                4.52,
+				Amount:                domain.NewAmount(452, ""),
 				BookingKey:            "024",
 				Reference:             "NONREF",
 				BankReference:         "ABC",
@@ -99,7 +115,7 @@ func TestTransactionTagUnmarshal(t *testing.T) {
 				BookingDate:          domain.ShortDate{Time: domain.Date(2015, 8, 3, time.Local).Truncate(24 * time.Hour)},
 				DebitCreditIndicator: "D",
 				CurrencyKind:         "R",
-				Amount:               4.52,
+				Amount:               domain.NewAmount(452, ""),
 				BookingKey:           "024",
 				Reference:            "NONREF",
 				BankReference:        "ABC",
@@ -114,7 +130,7 @@ func TestTransactionTagUnmarshal(t *testing.T) {
 				BookingDate:           domain.ShortDate{Time: domain.Date(2015, 8, 3, time.Local).Truncate(24 * time.Hour)},
 				DebitCreditIndicator:  "D",
 				CurrencyKind:          "R",
-				Amount:                4.52,
+				Amount:                domain.NewAmount(452, ""),
 				BookingKey:            "024",
 				Reference:             "NONREF",
 				AdditionalInformation: "DEF",
@@ -129,7 +145,7 @@ func TestTransactionTagUnmarshal(t *testing.T) {
 				BookingDate:          domain.ShortDate{Time: domain.Date(2015, 8, 3, time.Local).Truncate(24 * time.Hour)},
 				DebitCreditIndicator: "D",
 				CurrencyKind:         "R",
-				Amount:               4.52,
+				Amount:               domain.NewAmount(452, ""),
 				BookingKey:           "024",
 				Reference:            "NONREF",
 			},
@@ -179,6 +195,78 @@ func TestTransactionTagOrder(t *testing.T) {
 
 }
 
+func TestMT940UnmarshalEntryDateYear(t *testing.T) {
+	testdata := "\r\n:20:STARTUMS" +
+		"\r\n:25:12345678/1234123456" +
+		"\r\n:28C:1" +
+		"\r\n:60F:C181231EUR1234,56" +
+		"\r\n:61:1806010102DR50,NMSCNONREF" +
+		"\r\n:61:1901021231CR50,NMSCNONREF" +
+		"\r\n:62F:C190102EUR1234,56" +
+		"\r\n-"
+	tests := []struct {
+		dateReference        DateReference
+		expectedBookingDates []time.Time
+	}{
+		{
+			DateReferenceBalances,
+			[]time.Time{
+				time.Date(2019, time.January, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2018, time.December, 31, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			DateReferenceValutaDate,
+			[]time.Time{
+				time.Date(2018, time.January, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2018, time.December, 31, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	for _, test := range tests {
+		mt := &MT940{}
+
+		err := mt.unmarshal([]byte(testdata), test.dateReference)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+		for i, tr := range mt.Transactions {
+			if !tr.Transaction.BookingDate.Equal(test.expectedBookingDates[i]) {
+				t.Logf("Reference %d: Expected booking date of transaction %d to equal %s, got %s\n", test.dateReference, i, test.expectedBookingDates[i], tr.Transaction.BookingDate)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestInferEntryDate(t *testing.T) {
+	tests := []struct {
+		value    string
+		from     time.Time
+		to       time.Time
+		expected time.Time
+	}{
+		{"0102", time.Date(2018, 12, 31, 0, 0, 0, 0, time.UTC), time.Date(2019, 1, 3, 0, 0, 0, 0, time.UTC), time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"1231", time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2018, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{"0615", time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2019, 6, 30, 0, 0, 0, 0, time.UTC), time.Date(2019, 6, 15, 0, 0, 0, 0, time.UTC)},
+		{"0229", time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC), time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC), time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0230", time.Date(2019, 2, 28, 0, 0, 0, 0, time.UTC), time.Date(2019, 2, 28, 0, 0, 0, 0, time.UTC), time.Date(2019, 3, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		date, err := inferEntryDate([]byte(test.value), test.from, test.to)
+
+		if err != nil {
+			t.Logf("%s: Expected no error, got %T:%v\n", test.value, err, err)
+			t.Fail()
+		}
+		if !date.Equal(test.expected) {
+			t.Logf("%s: Expected date to equal %s, got %s\n", test.value, test.expected, date)
+			t.Fail()
+		}
+	}
+}
+
 func TestTransactionListWithUnvalidData(t *testing.T) {
 	testdata := "\r\n:20:HBCIKTOLST"
 	testdata += "\r\n:25:12345678/1234123456" +
@@ -4,11 +4,31 @@ import (
 	"bytes"
 	"fmt"
 
+	"github.com/mitch000001/go-hbci/domain"
 	"github.com/pkg/errors"
 )
 
-// Unmarshal unmarshals value into m
+// DateReference defines how the year of entry dates is inferred, as the
+// :61: tag only carries their month and day
+type DateReference int
+
+const (
+	// DateReferenceBalances infers the year from the dates of the starting
+	// and closing balance of the statement. If the statement lacks a balance,
+	// the valuta date is used instead.
+	DateReferenceBalances DateReference = iota
+	// DateReferenceValutaDate infers the year from the valuta date of the
+	// transaction
+	DateReferenceValutaDate
+)
+
+// Unmarshal unmarshals value into m. The year of entry dates is inferred from
+// the balances of the statement.
 func (m *MT940) Unmarshal(value []byte) error {
+	return m.unmarshal(value, DateReferenceBalances)
+}
+
+func (m *MT940) unmarshal(value []byte, dateReference DateReference) error {
 	tagExtractor := newTagExtractor(value)
 	tags, err := tagExtractor.Extract()
 	if err != nil {
@@ -18,92 +38,162 @@ func (m *MT940) Unmarshal(value []byte) error {
 		return fmt.Errorf("malformed marshaled value")
 	}
 	balanceTagOpen := false
-	for _, tag := range tags {
-
-		switch {
-		case bytes.HasPrefix(tag, []byte(":20:")):
-			m.JobReference = &AlphaNumericTag{}
-			err = m.JobReference.Unmarshal(tag)
-			if err != nil {
-				return err
-			}
-		case bytes.HasPrefix(tag, []byte(":21:")):
-			m.Reference = &AlphaNumericTag{}
-			err = m.Reference.Unmarshal(tag)
-			if err != nil {
-				return err
-			}
-		case bytes.HasPrefix(tag, []byte(":25:")):
-			m.Account = &AccountTag{}
-			err = m.Account.Unmarshal(tag)
-			if err != nil {
-				return err
-			}
-		case bytes.HasPrefix(tag, []byte(":28C:")):
-			m.StatementNumber = &StatementNumberTag{}
-			err = m.StatementNumber.Unmarshal(tag)
-			if err != nil {
-				return err
-			}
-		case bytes.HasPrefix(tag, []byte(":60")):
-			m.StartingBalance = &BalanceTag{}
-			err = m.StartingBalance.Unmarshal(tag)
-			if err != nil {
-				return errors.WithMessage(err, "unmarshal starting balance tag")
-			}
-			balanceTagOpen = true
-		case bytes.HasPrefix(tag, []byte(":62")):
+	for i, tag := range tags {
+		err = m.unmarshalTag(tag, &balanceTagOpen)
+		if err != nil {
+			return &tagError{tag: tag, offset: tagExtractor.offsets[i], err: err}
+		}
+	}
+	if dateReference != DateReferenceBalances || m.StartingBalance == nil || m.ClosingBalance == nil {
+		return nil
+	}
+	var index int
+	for i, tag := range tags {
+		if !bytes.HasPrefix(tag, []byte(":61:")) {
+			continue
+		}
+		transaction := m.Transactions[index].Transaction
+		index++
+		monthDay := entryDate(tag)
+		if monthDay == nil {
+			continue
+		}
+		date, err := inferEntryDate(monthDay, m.StartingBalance.BookingDate.Time, m.ClosingBalance.BookingDate.Time)
+		if err != nil {
+			return &tagError{tag: tag, offset: tagExtractor.offsets[i], err: err}
+		}
+		transaction.BookingDate = domain.NewShortDate(date)
+	}
+	return nil
+}
 
-			m.ClosingBalance = &BalanceTag{}
-			err = m.ClosingBalance.Unmarshal(tag)
-			if err != nil {
-				return errors.WithMessage(err, "unmarshal closing balance tag")
-			}
+// unmarshalTag unmarshals tag into m. balanceTagOpen reports whether tag is
+// part of the transaction sequence between the starting and closing balance.
+func (m *MT940) unmarshalTag(tag []byte, balanceTagOpen *bool) error {
+	var err error
+	switch {
+	case bytes.HasPrefix(tag, []byte(":20:")):
+		m.JobReference = &AlphaNumericTag{}
+		err = m.JobReference.Unmarshal(tag)
+		if err != nil {
+			return err
+		}
+	case bytes.HasPrefix(tag, []byte(":21:")):
+		m.Reference = &AlphaNumericTag{}
+		err = m.Reference.Unmarshal(tag)
+		if err != nil {
+			return err
+		}
+	case bytes.HasPrefix(tag, []byte(":25:")):
+		m.Account = &AccountTag{}
+		err = m.Account.Unmarshal(tag)
+		if err != nil {
+			return err
+		}
+	case bytes.HasPrefix(tag, []byte(":28C:")):
+		m.StatementNumber = &StatementNumberTag{}
+		err = m.StatementNumber.Unmarshal(tag)
+		if err != nil {
+			return err
+		}
+	case bytes.HasPrefix(tag, []byte(":60")):
+		m.StartingBalance = &BalanceTag{}
+		err = m.StartingBalance.Unmarshal(tag)
+		if err != nil {
+			return errors.WithMessage(err, "unmarshal starting balance tag")
+		}
+		*balanceTagOpen = true
+	case bytes.HasPrefix(tag, []byte(":62")):
 
-			balanceTagOpen = false
-		case bytes.HasPrefix(tag, []byte(":64:")):
-			m.CurrentValutaBalance = &BalanceTag{}
-			err = m.CurrentValutaBalance.Unmarshal(tag)
-			if err != nil {
-				return errors.WithMessage(err, "unmarshal current valuta balance tag")
-			}
-		case bytes.HasPrefix(tag, []byte(":65:")):
-			m.FutureValutaBalance = &BalanceTag{}
-			err = m.FutureValutaBalance.Unmarshal(tag)
-			if err != nil {
-				return errors.WithMessage(err, "unmarshal future valuta balance tag")
-			}
-		case bytes.HasPrefix(tag, []byte(":61:")):
+		m.ClosingBalance = &BalanceTag{}
+		err = m.ClosingBalance.Unmarshal(tag)
+		if err != nil {
+			return errors.WithMessage(err, "unmarshal closing balance tag")
+		}
 
-			transaction := &TransactionTag{}
+		*balanceTagOpen = false
+	case bytes.HasPrefix(tag, []byte(":64:")):
+		m.CurrentValutaBalance = &BalanceTag{}
+		err = m.CurrentValutaBalance.Unmarshal(tag)
+		if err != nil {
+			return errors.WithMessage(err, "unmarshal current valuta balance tag")
+		}
+	case bytes.HasPrefix(tag, []byte(":65:")):
+		m.FutureValutaBalance = &BalanceTag{}
+		err = m.FutureValutaBalance.Unmarshal(tag)
+		if err != nil {
+			return errors.WithMessage(err, "unmarshal future valuta balance tag")
+		}
+	case bytes.HasPrefix(tag, []byte(":61:")):
 
-			err = transaction.Unmarshal(tag)
-			if err != nil {
-				return err
-			}
-			m.Transactions = append(m.Transactions, &TransactionSequence{Transaction: transaction})
-		case bytes.HasPrefix(tag, []byte(":86:")):
-			customField := &CustomFieldTag{}
-			err = customField.Unmarshal(tag)
-			if err != nil {
-				return err
+		transaction := &TransactionTag{}
+		var currency string
+		if m.StartingBalance != nil {
+			currency = m.StartingBalance.Currency
+		}
+		err = transaction.unmarshal(tag, currency)
+		if err != nil {
+			return err
+		}
+		m.Transactions = append(m.Transactions, &TransactionSequence{Transaction: transaction})
+	case bytes.HasPrefix(tag, []byte(":86:")):
+		customField := &CustomFieldTag{}
+		err = customField.Unmarshal(tag)
+		if err != nil {
+			return err
+		}
+		if *balanceTagOpen {
+			indexLastSliceitem := len(m.Transactions) - 1
+			if indexLastSliceitem < 0 {
+				return errors.New("Unexpected CustomTag before first TransactionTag")
 			}
-			if balanceTagOpen {
-				indexLastSliceitem := len(m.Transactions) - 1
-				if indexLastSliceitem < 0 {
-					return errors.New("Unexpected CustomTag before first TransactionTag")
-				}
-				if m.Transactions[indexLastSliceitem].Description != nil {
-					return errors.Errorf("Unexpected CustomTag: CustomTag would replace Description of %v", m.Transactions[indexLastSliceitem])
-				}
-				m.Transactions[indexLastSliceitem].Description = customField
-			} else {
-				m.CustomField = customField
+			if m.Transactions[indexLastSliceitem].Description != nil {
+				return errors.Errorf("Unexpected CustomTag: CustomTag would replace Description of %v", m.Transactions[indexLastSliceitem])
 			}
-		default:
-			return fmt.Errorf("malformed marshaled value")
+			m.Transactions[indexLastSliceitem].Description = customField
+		} else {
+			m.CustomField = customField
 		}
+	default:
+		return fmt.Errorf("malformed marshaled value")
 	}
+	return nil
+}
 
+// validate returns an error if m lacks tags required to build account
+// transactions
+func (m *MT940) validate() error {
+	switch {
+	case m.Account == nil:
+		return &missingTagError{tag: ":25:"}
+	case m.StartingBalance == nil:
+		return &missingTagError{tag: ":60a:"}
+	case m.ClosingBalance == nil:
+		return &missingTagError{tag: ":62a:"}
+	}
 	return nil
 }
+
+// tagError represents an error unmarshaling the tag at offset of a message
+type tagError struct {
+	tag    []byte
+	offset int
+	err    error
+}
+
+func (t *tagError) Error() string {
+	return t.err.Error()
+}
+
+func (t *tagError) Unwrap() error {
+	return t.err
+}
+
+// missingTagError represents the absence of a mandatory tag
+type missingTagError struct {
+	tag string
+}
+
+func (m *missingTagError) Error() string {
+	return fmt.Sprintf("missing mandatory tag %s", m.tag)
+}
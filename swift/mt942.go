@@ -0,0 +1,172 @@
+package swift
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+// MT942 represents a S.W.I.F.T. Interim Transaction Report. Within HBCI it
+// carries the transactions not booked yet.
+type MT942 struct {
+	JobReference    *AlphaNumericTag
+	Reference       *AlphaNumericTag
+	Account         *AccountTag
+	StatementNumber *StatementNumberTag
+	// FloorLimits holds either one floor limit for debits and credits, or a
+	// debit and a credit floor limit
+	FloorLimits   []*FloorLimitTag
+	DateTime      *DateTimeTag
+	Transactions  []*TransactionSequence
+	DebitSummary  *SummaryTag
+	CreditSummary *SummaryTag
+	CustomField   *CustomFieldTag
+}
+
+// AccountTransactions returns a slice of account transactions created from m.
+// As interim reports carry no balances, the balances of the transactions are
+// not set.
+func (m *MT942) AccountTransactions() []domain.AccountTransaction {
+	accountConnection := domain.AccountConnection{BankID: m.Account.BankID, AccountID: m.Account.AccountID, CountryCode: 280}
+	var transactions []domain.AccountTransaction
+	for _, transactionSequence := range m.Transactions {
+		transaction := transactionSequence.accountTransaction(accountConnection, m.FloorLimits[0].Currency)
+		if m.StatementNumber != nil {
+			transaction.StatementNumber = m.StatementNumber.Number
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions
+}
+
+// A FloorLimitTag represents the floor limit of an interim report in
+// S.W.I.F.T.. Transactions with smaller amounts are not reported.
+type FloorLimitTag struct {
+	Tag      string
+	Currency string
+	// DebitCreditIndicator is empty if the limit applies to debits and
+	// credits
+	DebitCreditIndicator string
+	Amount               domain.Amount
+}
+
+// Unmarshal unmarshals value into f
+func (f *FloorLimitTag) Unmarshal(value []byte) error {
+	elements, err := extractTagElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) != 2 || len(elements[1]) < 4 {
+		return fmt.Errorf("%T: Malformed marshaled value", f)
+	}
+	f.Tag = string(elements[0])
+	buf := bytes.NewBuffer(elements[1])
+	f.Currency = string(buf.Next(3))
+	if next := buf.Bytes()[0]; next == 'D' || next == 'C' {
+		f.DebitCreditIndicator = string(buf.Next(1))
+	}
+	amount, err := domain.ParseAmount(buf.String(), f.Currency)
+	if err != nil {
+		return fmt.Errorf("MT942 Floor limit tag: error unmarshaling amount: %w", err)
+	}
+	f.Amount = amount
+	return nil
+}
+
+// Marshal marshals f into its S.W.I.F.T. representation
+func (f *FloorLimitTag) Marshal() ([]byte, error) {
+	if len(f.Currency) != 3 {
+		return nil, fmt.Errorf("%T: malformed currency %q", f, f.Currency)
+	}
+	return []byte(tagID(f.Tag, ":34F:") + f.Currency + f.DebitCreditIndicator + f.Amount.Abs().FormatHBCI()), nil
+}
+
+// A DateTimeTag represents the creation time of an interim report in
+// S.W.I.F.T.
+type DateTimeTag struct {
+	Tag      string
+	DateTime time.Time
+}
+
+// Unmarshal unmarshals value into d. Values without offset to UTC, as sent by
+// some banks, are read as UTC.
+func (d *DateTimeTag) Unmarshal(value []byte) error {
+	elements, err := extractTagElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) != 2 {
+		return fmt.Errorf("%T: Malformed marshaled value", d)
+	}
+	d.Tag = string(elements[0])
+	layout := "0601021504-0700"
+	if len(elements[1]) == len("0601021504") {
+		layout = "0601021504"
+	}
+	dateTime, err := time.Parse(layout, string(elements[1]))
+	if err != nil {
+		return fmt.Errorf("%T: Malformed marshaled value: %w", d, err)
+	}
+	d.DateTime = dateTime
+	return nil
+}
+
+// Marshal marshals d into its S.W.I.F.T. representation
+func (d *DateTimeTag) Marshal() ([]byte, error) {
+	if d.DateTime.IsZero() {
+		return nil, fmt.Errorf("%T: missing date", d)
+	}
+	return []byte(tagID(d.Tag, ":13D:") + d.DateTime.Format("0601021504-0700")), nil
+}
+
+// A SummaryTag represents the number and the sum of the debit or credit
+// entries of an interim report in S.W.I.F.T.
+type SummaryTag struct {
+	Tag      string
+	Count    int
+	Currency string
+	Amount   domain.Amount
+}
+
+// Unmarshal unmarshals value into s
+func (s *SummaryTag) Unmarshal(value []byte) error {
+	elements, err := extractTagElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) != 2 {
+		return fmt.Errorf("%T: Malformed marshaled value", s)
+	}
+	s.Tag = string(elements[0])
+	countEnd := bytes.IndexFunc(elements[1], func(r rune) bool { return r < '0' || r > '9' })
+	if countEnd <= 0 || len(elements[1]) < countEnd+3 {
+		return fmt.Errorf("%T: Malformed marshaled value", s)
+	}
+	count, err := strconv.Atoi(string(elements[1][:countEnd]))
+	if err != nil {
+		return err
+	}
+	s.Count = count
+	s.Currency = string(elements[1][countEnd : countEnd+3])
+	amount, err := domain.ParseAmount(string(elements[1][countEnd+3:]), s.Currency)
+	if err != nil {
+		return fmt.Errorf("MT942 Summary tag: error unmarshaling amount: %w", err)
+	}
+	s.Amount = amount
+	return nil
+}
+
+// Marshal marshals s into its S.W.I.F.T. representation. As s is used for
+// debit and credit entries, the tag must be set.
+func (s *SummaryTag) Marshal() ([]byte, error) {
+	if s.Tag == "" {
+		return nil, fmt.Errorf("%T: missing tag", s)
+	}
+	if len(s.Currency) != 3 {
+		return nil, fmt.Errorf("%T: malformed currency %q", s, s.Currency)
+	}
+	return []byte(s.Tag + strconv.Itoa(s.Count) + s.Currency + s.Amount.Abs().FormatHBCI()), nil
+}
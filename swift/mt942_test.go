@@ -0,0 +1,169 @@
+package swift
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+const validTestInterimReport = "\r\n:20:STARTDISPE" +
+	"\r\n:25:12345678/1234123456" +
+	"\r\n:28C:00000/001" +
+	"\r\n:34F:EURD0," +
+	"\r\n:34F:EURC0," +
+	"\r\n:13D:1811071530+0100" +
+	"\r\n:61:1811071107DR50,NMSCNONREF" +
+	"\r\n:86:177?00SB-SEPA-Ueberweisung?20Miete?32Max Meier" +
+	"\r\n:61:1811071107CR20,NMSCNONREF" +
+	"\r\n:90D:1EUR50," +
+	"\r\n:90C:1EUR20," +
+	"\r\n:86:999?20Vorgemerkte Umsaetze" +
+	"\r\n-"
+
+func TestMT942Unmarshal(t *testing.T) {
+	report := &MT942{}
+
+	err := report.Unmarshal([]byte(validTestInterimReport))
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if err := report.validate(); err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	expectedFloorLimits := []*FloorLimitTag{
+		{Tag: ":34F:", Currency: "EUR", DebitCreditIndicator: "D", Amount: domain.NewAmount(0, "EUR")},
+		{Tag: ":34F:", Currency: "EUR", DebitCreditIndicator: "C", Amount: domain.NewAmount(0, "EUR")},
+	}
+	if !reflect.DeepEqual(expectedFloorLimits, report.FloorLimits) {
+		t.Logf("Expected floor limits to equal\n%#v\n\tgot\n%#v\n", expectedFloorLimits, report.FloorLimits)
+		t.Fail()
+	}
+	expectedDateTime := time.Date(2018, time.November, 7, 14, 30, 0, 0, time.UTC)
+	if report.DateTime == nil || !report.DateTime.DateTime.Equal(expectedDateTime) {
+		t.Logf("Expected date time %s, got %v\n", expectedDateTime, report.DateTime)
+		t.Fail()
+	}
+	expectedDebitSummary := &SummaryTag{Tag: ":90D:", Count: 1, Currency: "EUR", Amount: domain.NewAmount(5000, "EUR")}
+	if !reflect.DeepEqual(expectedDebitSummary, report.DebitSummary) {
+		t.Logf("Expected debit summary to equal\n%#v\n\tgot\n%#v\n", expectedDebitSummary, report.DebitSummary)
+		t.Fail()
+	}
+	expectedCreditSummary := &SummaryTag{Tag: ":90C:", Count: 1, Currency: "EUR", Amount: domain.NewAmount(2000, "EUR")}
+	if !reflect.DeepEqual(expectedCreditSummary, report.CreditSummary) {
+		t.Logf("Expected credit summary to equal\n%#v\n\tgot\n%#v\n", expectedCreditSummary, report.CreditSummary)
+		t.Fail()
+	}
+	if len(report.Transactions) != 2 {
+		t.Fatalf("Expected 2 transactions, got %d\n", len(report.Transactions))
+	}
+	if report.Transactions[0].Description == nil || report.Transactions[1].Description != nil {
+		t.Logf("Expected only the first transaction to have a description\n")
+		t.Fail()
+	}
+	if report.CustomField == nil || !reflect.DeepEqual(report.CustomField.Purpose, []string{"Vorgemerkte Umsaetze"}) {
+		t.Logf("Expected custom field of the report, got %#v\n", report.CustomField)
+		t.Fail()
+	}
+
+	transactions := report.AccountTransactions()
+
+	if len(transactions) != 2 {
+		t.Fatalf("Expected 2 account transactions, got %d\n", len(transactions))
+	}
+	if expected := domain.NewAmount(-5000, "EUR"); transactions[0].Amount != expected {
+		t.Logf("Expected amount %v, got %v\n", expected, transactions[0].Amount)
+		t.Fail()
+	}
+	if transactions[0].Name != "Max Meier" || transactions[0].Purpose != "Miete" {
+		t.Logf("Expected description to be taken over, got %q and %q\n", transactions[0].Name, transactions[0].Purpose)
+		t.Fail()
+	}
+	if expected := domain.NewAmount(2000, "EUR"); transactions[1].Amount != expected {
+		t.Logf("Expected amount %v, got %v\n", expected, transactions[1].Amount)
+		t.Fail()
+	}
+}
+
+func TestMT942Validate(t *testing.T) {
+	report := &MT942{}
+	err := report.Unmarshal([]byte("\r\n:20:STARTDISPE\r\n:25:12345678/1234123456\r\n:13D:1811071530+0100\r\n-"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	err = report.validate()
+
+	missingErr, ok := err.(*missingTagError)
+	if !ok || missingErr.tag != ":34F:" {
+		t.Logf("Expected missing floor limit, got %T:%v\n", err, err)
+		t.Fail()
+	}
+}
+
+func TestFloorLimitTagUnmarshal(t *testing.T) {
+	tests := []struct {
+		marshaledValue string
+		expectedTag    *FloorLimitTag
+	}{
+		{
+			":34F:EUR100,",
+			&FloorLimitTag{Tag: ":34F:", Currency: "EUR", Amount: domain.NewAmount(10000, "EUR")},
+		},
+		{
+			":34F:EURC12,5",
+			&FloorLimitTag{Tag: ":34F:", Currency: "EUR", DebitCreditIndicator: "C", Amount: domain.NewAmount(1250, "EUR")},
+		},
+	}
+	for _, test := range tests {
+		tag := &FloorLimitTag{}
+
+		err := tag.Unmarshal([]byte(test.marshaledValue))
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+		if !reflect.DeepEqual(test.expectedTag, tag) {
+			t.Logf("Expected tag to equal\n%#v\n\tgot\n%#v\n", test.expectedTag, tag)
+			t.Fail()
+		}
+		marshaled, err := tag.Marshal()
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+		if string(marshaled) != test.marshaledValue {
+			t.Logf("Expected tag to marshal to %q, got %q\n", test.marshaledValue, marshaled)
+			t.Fail()
+		}
+	}
+}
+
+func TestDateTimeTagUnmarshal(t *testing.T) {
+	tests := []struct {
+		marshaledValue string
+		expectedTime   time.Time
+	}{
+		{":13D:1811071530+0100", time.Date(2018, time.November, 7, 14, 30, 0, 0, time.UTC)},
+		{":13D:1811071530-0230", time.Date(2018, time.November, 7, 18, 0, 0, 0, time.UTC)},
+		{":13:1811071530", time.Date(2018, time.November, 7, 15, 30, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		tag := &DateTimeTag{}
+
+		err := tag.Unmarshal([]byte(test.marshaledValue))
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+		if !tag.DateTime.Equal(test.expectedTime) {
+			t.Logf("Expected %q to unmarshal to %s, got %s\n", test.marshaledValue, test.expectedTime, tag.DateTime)
+			t.Fail()
+		}
+	}
+
+	err := (&DateTimeTag{}).Unmarshal([]byte(":13D:18110715"))
+	if err == nil {
+		t.Logf("Expected error for malformed date time\n")
+		t.Fail()
+	}
+}
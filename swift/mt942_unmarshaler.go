@@ -0,0 +1,114 @@
+package swift
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Unmarshal unmarshals value into m. A :86: tag following a transaction
+// describes it, any other :86: tag is the custom field of the report.
+func (m *MT942) Unmarshal(value []byte) error {
+	tagExtractor := newTagExtractor(value)
+	tags, err := tagExtractor.Extract()
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return fmt.Errorf("malformed marshaled value")
+	}
+	transactionOpen := false
+	for i, tag := range tags {
+		err = m.unmarshalTag(tag, &transactionOpen)
+		if err != nil {
+			return &tagError{tag: tag, offset: tagExtractor.offsets[i], err: err}
+		}
+	}
+	return nil
+}
+
+// unmarshalTag unmarshals tag into m. transactionOpen reports whether the
+// previous tag was a transaction without description.
+func (m *MT942) unmarshalTag(tag []byte, transactionOpen *bool) error {
+	describesTransaction := *transactionOpen
+	*transactionOpen = false
+	var err error
+	switch {
+	case bytes.HasPrefix(tag, []byte(":20:")):
+		m.JobReference = &AlphaNumericTag{}
+		err = m.JobReference.Unmarshal(tag)
+	case bytes.HasPrefix(tag, []byte(":21:")):
+		m.Reference = &AlphaNumericTag{}
+		err = m.Reference.Unmarshal(tag)
+	case bytes.HasPrefix(tag, []byte(":25:")):
+		m.Account = &AccountTag{}
+		err = m.Account.Unmarshal(tag)
+	case bytes.HasPrefix(tag, []byte(":28C:")):
+		m.StatementNumber = &StatementNumberTag{}
+		err = m.StatementNumber.Unmarshal(tag)
+	case bytes.HasPrefix(tag, []byte(":34F:")):
+		floorLimit := &FloorLimitTag{}
+		err = floorLimit.Unmarshal(tag)
+		if err != nil {
+			return errors.WithMessage(err, "unmarshal floor limit tag")
+		}
+		if len(m.FloorLimits) == 2 {
+			return errors.New("Unexpected floor limit: at most two floor limits allowed")
+		}
+		m.FloorLimits = append(m.FloorLimits, floorLimit)
+	case bytes.HasPrefix(tag, []byte(":13")):
+		m.DateTime = &DateTimeTag{}
+		err = m.DateTime.Unmarshal(tag)
+	case bytes.HasPrefix(tag, []byte(":61:")):
+		transaction := &TransactionTag{}
+		var currency string
+		if len(m.FloorLimits) != 0 {
+			currency = m.FloorLimits[0].Currency
+		}
+		err = transaction.unmarshal(tag, currency)
+		if err != nil {
+			return err
+		}
+		m.Transactions = append(m.Transactions, &TransactionSequence{Transaction: transaction})
+		*transactionOpen = true
+	case bytes.HasPrefix(tag, []byte(":86:")):
+		customField := &CustomFieldTag{}
+		err = customField.Unmarshal(tag)
+		if err != nil {
+			return err
+		}
+		if describesTransaction {
+			m.Transactions[len(m.Transactions)-1].Description = customField
+		} else {
+			m.CustomField = customField
+		}
+	case bytes.HasPrefix(tag, []byte(":90D:")):
+		m.DebitSummary = &SummaryTag{}
+		err = m.DebitSummary.Unmarshal(tag)
+		if err != nil {
+			return errors.WithMessage(err, "unmarshal debit summary tag")
+		}
+	case bytes.HasPrefix(tag, []byte(":90C:")):
+		m.CreditSummary = &SummaryTag{}
+		err = m.CreditSummary.Unmarshal(tag)
+		if err != nil {
+			return errors.WithMessage(err, "unmarshal credit summary tag")
+		}
+	default:
+		return fmt.Errorf("malformed marshaled value")
+	}
+	return err
+}
+
+// validate returns an error if m lacks tags required to build account
+// transactions
+func (m *MT942) validate() error {
+	switch {
+	case m.Account == nil:
+		return &missingTagError{tag: ":25:"}
+	case len(m.FloorLimits) == 0:
+		return &missingTagError{tag: ":34F:"}
+	}
+	return nil
+}
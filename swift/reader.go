@@ -0,0 +1,264 @@
+package swift
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"unicode"
+)
+
+// messageSeparator terminates S.W.I.F.T. messages
+const messageSeparator = "\r\n-"
+
+// DefaultMaxMessageSize is the default maximum size of a single message read
+// by a MessageReader
+const DefaultMaxMessageSize = 1 << 20
+
+// NewMessageReader returns a MessageReader reading S.W.I.F.T. messages from r
+func NewMessageReader(r io.Reader) *MessageReader {
+	return &MessageReader{
+		MaxMessageSize: DefaultMaxMessageSize,
+		reader:         bufio.NewReader(r),
+		line:           1,
+	}
+}
+
+// MessageReader reads S.W.I.F.T. messages, like MT940 or MT942, one at a time
+// from an underlying reader. Only one message is held in memory, so arbitrary
+// large inputs like statement archives can be read.
+type MessageReader struct {
+	// MaxMessageSize limits the size of a single message. Larger messages
+	// are skipped and result in an error.
+	MaxMessageSize int
+	reader         *bufio.Reader
+	// offset, line and lineStart define the position of the next message
+	offset    int
+	line      int
+	lineStart int
+}
+
+// ReadMessage reads the next raw message. It returns io.EOF if no messages are
+// left. Malformed messages result in a *SyntaxError, with its position
+// relative to the whole input. Reading can be continued with the next
+// message after an error. Blank lines in front of a message and empty
+// messages, like doubled separators, are skipped.
+func (m *MessageReader) ReadMessage() ([]byte, error) {
+	message, _, err := m.next()
+	return message, err
+}
+
+// next reads the next raw message and returns it with its position
+func (m *MessageReader) next() ([]byte, position, error) {
+	for {
+		start := position{offset: m.offset, line: m.line, lineStart: m.lineStart}
+		message, size, err := m.readMessage()
+		if err != nil {
+			return nil, start, err
+		}
+		if size > m.MaxMessageSize {
+			return nil, start, fmt.Errorf("message at position %d exceeds the maximum size of %d bytes", start.offset, m.MaxMessageSize)
+		}
+		if len(bytes.TrimSpace(bytes.TrimSuffix(message, []byte("-")))) == 0 {
+			continue
+		}
+		message, start = skipBlankLines(message, start)
+		extractor := NewMessageExtractor(message)
+		if err := extractor.extract(); err != nil {
+			err.shift(start)
+			return nil, start, err
+		}
+		return message, start, nil
+	}
+}
+
+// readStatement reads the next message and passes it to unmarshal. Errors
+// returned by unmarshal result in a *SyntaxError, with its position relative
+// to the whole input.
+func (m *MessageReader) readStatement(unmarshal func(message []byte) error) error {
+	message, start, err := m.next()
+	if err != nil {
+		return err
+	}
+	if err := unmarshal(message); err != nil {
+		syntaxErr := statementError(message, 0, len(message), err)
+		syntaxErr.shift(start)
+		return syntaxErr
+	}
+	return nil
+}
+
+// readMessage reads the bytes up to and including the next message separator
+// and returns them with their size. Bytes exceeding the maximum message size
+// are discarded. It returns io.EOF if the input is exhausted.
+func (m *MessageReader) readMessage() ([]byte, int, error) {
+	var message, tail []byte
+	var size int
+	for {
+		chunk, err := m.reader.ReadSlice('-')
+		m.consume(chunk)
+		size += len(chunk)
+		if size <= m.MaxMessageSize {
+			message = append(message, chunk...)
+		}
+		tail = lastBytes(append(tail, lastBytes(chunk)...))
+		switch {
+		case err == io.EOF && size == 0:
+			return nil, 0, io.EOF
+		case err == io.EOF:
+			return message, size, nil
+		case err == bufio.ErrBufferFull:
+			continue
+		case err != nil:
+			return nil, size, err
+		}
+		if string(tail) == messageSeparator && m.atMessageBoundary() {
+			return message, size, nil
+		}
+	}
+}
+
+// atMessageBoundary reports whether the next line is the beginning of a new
+// message or the end of input, as the message separator can also occur
+// within the value of a tag. Trailing blanks after the separator are ignored.
+func (m *MessageReader) atMessageBoundary() bool {
+	for n := 1; ; n++ {
+		next, err := m.reader.Peek(n)
+		if err != nil {
+			return true
+		}
+		switch next[n-1] {
+		case ' ', '\t':
+			continue
+		case '\r', ':':
+			return true
+		}
+		return false
+	}
+}
+
+// consume advances the position of m by the bytes of chunk
+func (m *MessageReader) consume(chunk []byte) {
+	if i := bytes.LastIndexByte(chunk, '\n'); i != -1 {
+		m.line += bytes.Count(chunk, []byte("\n"))
+		m.lineStart = m.offset + i + 1
+	}
+	m.offset += len(chunk)
+}
+
+// lastBytes returns the last bytes of value, as long as a message separator
+func lastBytes(value []byte) []byte {
+	if len(value) > len(messageSeparator) {
+		return value[len(value)-len(messageSeparator):]
+	}
+	return value
+}
+
+// position defines the position of a message within the input of a
+// MessageReader. The message starts at offset in line, which begins at
+// lineStart.
+type position struct {
+	offset    int
+	line      int
+	lineStart int
+}
+
+// skipBlankLines strips the blank lines in front of the first tag of message,
+// keeping the line break the message starts with, and moves start
+// accordingly
+func skipBlankLines(message []byte, start position) ([]byte, position) {
+	content := bytes.IndexFunc(message, func(r rune) bool { return !unicode.IsSpace(r) })
+	if content == -1 {
+		return message, start
+	}
+	cut := bytes.LastIndex(message[:content], []byte("\r\n"))
+	if cut <= 0 {
+		return message, start
+	}
+	if i := bytes.LastIndexByte(message[:cut], '\n'); i != -1 {
+		start.line += bytes.Count(message[:cut], []byte("\n"))
+		start.lineStart = start.offset + i + 1
+	}
+	start.offset += cut
+	return message[cut:], start
+}
+
+// shift moves the position of s, which is relative to a message, to the
+// position within the whole input, with the message starting at start
+func (s *SyntaxError) shift(start position) {
+	if s.Line == 1 {
+		s.Column += start.offset - start.lineStart
+	}
+	s.Offset += start.offset
+	s.Line += start.line - 1
+}
+
+// NewMT940Reader returns a MT940Reader reading statements from r
+func NewMT940Reader(r io.Reader) *MT940Reader {
+	return &MT940Reader{messages: NewMessageReader(r)}
+}
+
+// MT940Reader reads MT940 statements one at a time from an underlying reader
+type MT940Reader struct {
+	// DateReference defines how the year of entry dates is inferred.
+	// Defaults to DateReferenceBalances.
+	DateReference DateReference
+	messages      *MessageReader
+}
+
+// SetMaxMessageSize limits the size of a single statement
+func (m *MT940Reader) SetMaxMessageSize(size int) {
+	m.messages.MaxMessageSize = size
+}
+
+// Read reads the next statement. It returns io.EOF if no statements are left.
+// Malformed statements result in a *SyntaxError, with its position relative
+// to the whole input. Reading can be continued with the next statement after
+// an error.
+func (m *MT940Reader) Read() (*MT940, error) {
+	statement := &MT940{}
+	err := m.messages.readStatement(func(message []byte) error {
+		if err := statement.unmarshal(message, m.DateReference); err != nil {
+			return err
+		}
+		return statement.validate()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+// NewMT942Reader returns a MT942Reader reading interim reports from r
+func NewMT942Reader(r io.Reader) *MT942Reader {
+	return &MT942Reader{messages: NewMessageReader(r)}
+}
+
+// MT942Reader reads MT942 interim reports one at a time from an underlying
+// reader
+type MT942Reader struct {
+	messages *MessageReader
+}
+
+// SetMaxMessageSize limits the size of a single report
+func (m *MT942Reader) SetMaxMessageSize(size int) {
+	m.messages.MaxMessageSize = size
+}
+
+// Read reads the next report. It returns io.EOF if no reports are left.
+// Malformed reports result in a *SyntaxError, with its position relative to
+// the whole input. Reading can be continued with the next report after an
+// error.
+func (m *MT942Reader) Read() (*MT942, error) {
+	report := &MT942{}
+	err := m.messages.readStatement(func(message []byte) error {
+		if err := report.Unmarshal(message); err != nil {
+			return err
+		}
+		return report.validate()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
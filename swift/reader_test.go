@@ -0,0 +1,207 @@
+package swift
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMessageReaderReadMessage(t *testing.T) {
+	test := "\r\n:20:abcde\r\n:86:foo\r\n-bar\r\n-" +
+		"\r\n:20:fghij\r\n-\r\n"
+
+	reader := NewMessageReader(strings.NewReader(test))
+
+	var messages []string
+	for {
+		message, err := reader.ReadMessage()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+		messages = append(messages, string(message))
+	}
+
+	expectedMessages := []string{
+		"\r\n:20:abcde\r\n:86:foo\r\n-bar\r\n-",
+		"\r\n:20:fghij\r\n-",
+	}
+	if len(messages) != len(expectedMessages) {
+		t.Fatalf("Expected %d messages, got %d: %q\n", len(expectedMessages), len(messages), messages)
+	}
+	for i, expected := range expectedMessages {
+		if messages[i] != expected {
+			t.Logf("Expected message %d to equal\n%q\n\tgot\n%q\n", i, expected, messages[i])
+			t.Fail()
+		}
+	}
+}
+
+func TestMessageReaderReadMessageSkipsBlankChunks(t *testing.T) {
+	tests := []struct {
+		description string
+		separator   string
+	}{
+		{"doubled separator", "\r\n-"},
+		{"blank line between separators", "\r\n \r\n-"},
+		{"blank line", "\r\n"},
+		{"trailing blanks", " \t"},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			input := validTestStatement + test.separator + validTestStatement + test.separator
+			reader := NewMessageReader(strings.NewReader(input))
+
+			for i := 0; i < 2; i++ {
+				message, err := reader.ReadMessage()
+				if err != nil {
+					t.Fatalf("Expected no error, got %T:%v\n", err, err)
+				}
+				if string(message) != validTestStatement {
+					t.Logf("Expected message %d to equal\n%q\n\tgot\n%q\n", i, validTestStatement, message)
+					t.Fail()
+				}
+			}
+			_, err := reader.ReadMessage()
+			if err != io.EOF {
+				t.Logf("Expected io.EOF, got %T:%v\n", err, err)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestMessageReaderMaxMessageSize(t *testing.T) {
+	large := "\r\n:20:" + strings.Repeat("A", 5000) + "\r\n-"
+	test := large + validTestStatement + large
+
+	reader := NewMessageReader(strings.NewReader(test))
+	reader.MaxMessageSize = 50
+
+	_, err := reader.ReadMessage()
+	if err == nil {
+		t.Logf("Expected error for message exceeding the maximum size\n")
+		t.Fail()
+	}
+
+	reader.MaxMessageSize = DefaultMaxMessageSize
+	message, err := reader.ReadMessage()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if string(message) != validTestStatement {
+		t.Logf("Expected message to equal\n%q\n\tgot\n%q\n", validTestStatement, message)
+		t.Fail()
+	}
+
+	message, err = reader.ReadMessage()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if string(message) != large {
+		t.Logf("Expected message exceeding the read buffer to be read completely\n")
+		t.Fail()
+	}
+}
+
+func TestMT940ReaderRead(t *testing.T) {
+	input := validTestStatement +
+		lexerErrorTestStatement +
+		malformedTagTestStatement +
+		validTestStatement
+
+	reader := NewMT940Reader(strings.NewReader(input))
+
+	var statements []*MT940
+	var errs []*SyntaxError
+	for {
+		statement, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			syntaxErr, ok := err.(*SyntaxError)
+			if !ok {
+				t.Fatalf("Expected error to be a *SyntaxError, got %T:%v\n", err, err)
+			}
+			errs = append(errs, syntaxErr)
+			continue
+		}
+		statements = append(statements, statement)
+	}
+
+	if len(statements) != 2 {
+		t.Logf("Expected 2 statements, got %d\n", len(statements))
+		t.Fail()
+	}
+	_, expectedWarnings, err := (&MT940MessagesUnmarshaler{Mode: ParseModeLenient}).UnmarshalMT940WithWarnings([]byte(input))
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if len(errs) != len(expectedWarnings) {
+		t.Fatalf("Expected %d errors, got %d: %v\n", len(expectedWarnings), len(errs), errs)
+	}
+	for i, expected := range expectedWarnings {
+		actual := errs[i]
+		if actual.Tag != expected.Tag || actual.Offset != expected.Offset || actual.Line != expected.Line || actual.Column != expected.Column {
+			t.Logf("Expected error %d at %s line %d, column %d (position %d), got %s line %d, column %d (position %d)\n", i, expected.Tag, expected.Line, expected.Column, expected.Offset, actual.Tag, actual.Line, actual.Column, actual.Offset)
+			t.Fail()
+		}
+	}
+}
+
+func TestMT940ReaderReadErrorPositionAfterBlankLines(t *testing.T) {
+	input := validTestStatement + "\r\n\r\n" + malformedTagTestStatement
+
+	reader := NewMT940Reader(strings.NewReader(input))
+
+	if _, err := reader.Read(); err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	_, err := reader.Read()
+
+	syntaxErr, ok := err.(*SyntaxError)
+	if !ok {
+		t.Fatalf("Expected error to be a *SyntaxError, got %T:%v\n", err, err)
+	}
+	expected := &SyntaxError{RawMessage: []byte(input)}
+	expected.setOffset(strings.Index(input, ":61:1811X7"))
+	if syntaxErr.Offset != expected.Offset || syntaxErr.Line != expected.Line || syntaxErr.Column != expected.Column {
+		t.Logf("Expected error at line %d, column %d (position %d), got line %d, column %d (position %d)\n", expected.Line, expected.Column, expected.Offset, syntaxErr.Line, syntaxErr.Column, syntaxErr.Offset)
+		t.Fail()
+	}
+}
+
+func TestMT942ReaderRead(t *testing.T) {
+	input := validTestInterimReport + lexerErrorTestStatement + validTestInterimReport
+
+	reader := NewMT942Reader(strings.NewReader(input))
+
+	var reports []*MT942
+	var errs []error
+	for {
+		report, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		reports = append(reports, report)
+	}
+
+	if len(reports) != 2 {
+		t.Logf("Expected 2 reports, got %d\n", len(reports))
+		t.Fail()
+	}
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %v\n", len(errs), errs)
+	}
+	if _, ok := errs[0].(*SyntaxError); !ok {
+		t.Logf("Expected error to be a *SyntaxError, got %T:%v\n", errs[0], errs[0])
+		t.Fail()
+	}
+}
@@ -0,0 +1,196 @@
+package swift
+
+import "strings"
+
+// maxPurposeLineLength is the length of a single purpose subfield. Longer
+// values are split by the bank institute into several subfields, often in the
+// middle of a word.
+const maxPurposeLineLength = 27
+
+// notProvided is used by the bank institute for empty references
+const notProvided = "NOTPROVIDED"
+
+// sepaQualifiers are the SEPA qualifiers within the purpose of field 86, as
+// defined by the DK in the specification of the SEPA data formats.
+var sepaQualifiers = []string{
+	"EREF+", // end to end reference
+	"KREF+", // customer reference
+	"MREF+", // mandate reference
+	"CRED+", // creditor ID
+	"DEBT+", // originator ID
+	"COAM+", // compensation amount
+	"OAMT+", // original amount
+	"SVWZ+", // remittance information
+	"ABWA+", // ultimate debtor
+	"ABWE+", // ultimate creditor
+	"IBAN+", // IBAN of the counterparty
+	"BIC+",  // BIC of the counterparty
+}
+
+// SepaPurpose represents the SEPA qualifiers within the purpose of field 86
+type SepaPurpose struct {
+	EndToEndReference     string // EREF+
+	CustomerReference     string // KREF+
+	MandateReference      string // MREF+
+	CreditorID            string // CRED+
+	OriginatorID          string // DEBT+
+	CompensationAmount    string // COAM+
+	OriginalAmount        string // OAMT+
+	RemittanceInformation string // SVWZ+
+	UltimateDebtor        string // ABWA+
+	UltimateCreditor      string // ABWE+
+	IBAN                  string // IBAN+
+	BIC                   string // BIC+
+}
+
+// ParseSepaPurpose reassembles the purpose subfields and extracts the SEPA
+// qualifiers. It returns false if the purpose does not contain any SEPA
+// qualifier.
+//
+// Subfields are joined without separator if they are completely filled, as
+// the bank institute splits long values at fixed length, often in the middle
+// of a word, and with a space otherwise. Qualifiers are only recognized at the
+// start of a subfield or after a space.
+func ParseSepaPurpose(subfields []string) (SepaPurpose, bool) {
+	purpose, subfieldStarts := joinPurpose(subfields)
+	var sepa SepaPurpose
+	fields := map[string]*string{
+		"EREF+": &sepa.EndToEndReference,
+		"KREF+": &sepa.CustomerReference,
+		"MREF+": &sepa.MandateReference,
+		"CRED+": &sepa.CreditorID,
+		"DEBT+": &sepa.OriginatorID,
+		"COAM+": &sepa.CompensationAmount,
+		"OAMT+": &sepa.OriginalAmount,
+		"SVWZ+": &sepa.RemittanceInformation,
+		"ABWA+": &sepa.UltimateDebtor,
+		"ABWE+": &sepa.UltimateCreditor,
+		"IBAN+": &sepa.IBAN,
+		"BIC+":  &sepa.BIC,
+	}
+	positions := qualifierPositions(purpose, subfieldStarts)
+	if len(positions) == 0 {
+		return sepa, false
+	}
+	for i, pos := range positions {
+		end := len(purpose)
+		if i+1 < len(positions) {
+			end = positions[i+1].index
+		}
+		value := strings.TrimSpace(purpose[pos.index+len(pos.qualifier) : end])
+		if value == notProvided {
+			value = ""
+		}
+		field := fields[pos.qualifier]
+		if *field != "" && value != "" {
+			// repeated qualifiers continue the value
+			value = *field + " " + value
+		}
+		if value != "" {
+			*field = value
+		}
+	}
+	return sepa, true
+}
+
+// joinPurpose joins the subfields and returns the purpose together with the
+// start index of every subfield within it. Trailing spaces of completely
+// filled subfields are kept, as they separate words split at the subfield
+// boundary.
+func joinPurpose(subfields []string) (string, []int) {
+	var buf strings.Builder
+	starts := make([]int, len(subfields))
+	for i, subfield := range subfields {
+		full := len([]rune(subfield)) >= maxPurposeLineLength
+		if i > 0 && len([]rune(subfields[i-1])) < maxPurposeLineLength {
+			buf.WriteString(" ")
+		}
+		if !full || i == len(subfields)-1 {
+			subfield = strings.TrimRight(subfield, " ")
+		}
+		starts[i] = buf.Len()
+		buf.WriteString(subfield)
+	}
+	return buf.String(), starts
+}
+
+type qualifierPosition struct {
+	qualifier string
+	index     int
+}
+
+// qualifierPositions returns the positions of all qualifiers in purpose, in
+// order. Qualifiers are recognized at the start of a subfield and after a
+// space, as some bank institutes do not start a new subfield for each
+// qualifier. Qualifiers within words are part of the value.
+func qualifierPositions(purpose string, subfieldStarts []int) []qualifierPosition {
+	anchored := func(i int) bool {
+		if i == 0 || purpose[i-1] == ' ' {
+			return true
+		}
+		for _, start := range subfieldStarts {
+			if start == i {
+				return true
+			}
+		}
+		return false
+	}
+	var positions []qualifierPosition
+	for i := 0; i < len(purpose); i++ {
+		if !anchored(i) {
+			continue
+		}
+		for _, qualifier := range sepaQualifiers {
+			if strings.HasPrefix(purpose[i:], qualifier) {
+				positions = append(positions, qualifierPosition{qualifier, i})
+				i += len(qualifier) - 1
+				break
+			}
+		}
+	}
+	return positions
+}
+
+// SepaPurpose returns the SEPA qualifiers within the purpose of c. IBAN and
+// BIC of the counterparty are taken from the account fields if the purpose
+// does not contain them. It returns false for transactions without SEPA
+// qualifiers.
+func (c *CustomFieldTag) SepaPurpose() (SepaPurpose, bool) {
+	sepa, ok := ParseSepaPurpose(append(append([]string{}, c.Purpose...), c.Purpose2...))
+	if !ok {
+		return sepa, false
+	}
+	if sepa.IBAN == "" && isIBAN(c.AccountID) {
+		sepa.IBAN = strings.TrimSpace(c.AccountID)
+	}
+	if sepa.BIC == "" && isBIC(c.BankID) {
+		sepa.BIC = strings.TrimSpace(c.BankID)
+	}
+	return sepa, true
+}
+
+// isIBAN reports whether value looks like an IBAN, in contrast to a national
+// account ID
+func isIBAN(value string) bool {
+	value = strings.TrimSpace(value)
+	return len(value) > 4 && isUpperLetter(value[0]) && isUpperLetter(value[1])
+}
+
+// isBIC reports whether value looks like a BIC, in contrast to a national
+// bank ID
+func isBIC(value string) bool {
+	value = strings.TrimSpace(value)
+	if len(value) != 8 && len(value) != 11 {
+		return false
+	}
+	for i := 0; i < 6; i++ {
+		if !isUpperLetter(value[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isUpperLetter(b byte) bool {
+	return b >= 'A' && b <= 'Z'
+}
@@ -0,0 +1,124 @@
+package swift
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSepaPurpose(t *testing.T) {
+	tests := []struct {
+		description string
+		subfields   []string
+		expected    SepaPurpose
+		expectedOk  bool
+	}{
+		{
+			"qualifiers per subfield",
+			[]string{
+				"EREF+RE-2021-0815",
+				"MREF+M-4711",
+				"CRED+DE98ZZZ09999999999",
+				"SVWZ+Rechnung 2021-0815",
+			},
+			SepaPurpose{
+				EndToEndReference:     "RE-2021-0815",
+				MandateReference:      "M-4711",
+				CreditorID:            "DE98ZZZ09999999999",
+				RemittanceInformation: "Rechnung 2021-0815",
+			},
+			true,
+		},
+		{
+			"values split mid-token",
+			[]string{
+				"EREF+1234567890123456789012",
+				"345 MREF+M-1 SVWZ+Beitrag M",
+				"itgliedschaft 2021 und das ",
+				"Jahr 2022 fuer Max Musterma",
+				"ABWA+Erika Muster  ",
+			},
+			SepaPurpose{
+				EndToEndReference:     "1234567890123456789012345",
+				MandateReference:      "M-1",
+				RemittanceInformation: "Beitrag Mitgliedschaft 2021 und das Jahr 2022 fuer Max Musterma",
+				UltimateDebtor:        "Erika Muster",
+			},
+			true,
+		},
+		{
+			"free text after short lines",
+			[]string{
+				"SVWZ+Miete",
+				"Maerz 2021",
+				"KREF+NOTPROVIDED",
+				"ABWE+Hausverwaltung GmbH",
+				"IBAN+DE89370400440532013000",
+				"BIC+COBADEFFXXX",
+			},
+			SepaPurpose{
+				RemittanceInformation: "Miete Maerz 2021",
+				UltimateCreditor:      "Hausverwaltung GmbH",
+				IBAN:                  "DE89370400440532013000",
+				BIC:                   "COBADEFFXXX",
+			},
+			true,
+		},
+		{
+			"qualifiers after spaces",
+			[]string{"EREF+NOTPROVIDED SVWZ+Spende"},
+			SepaPurpose{RemittanceInformation: "Spende"},
+			true,
+		},
+		{
+			"qualifiers within values",
+			[]string{"SVWZ+Bestellung SHOP-EREF+4711"},
+			SepaPurpose{RemittanceInformation: "Bestellung SHOP-EREF+4711"},
+			true,
+		},
+		{
+			"no SEPA purpose",
+			[]string{"Gehalt Maerz"},
+			SepaPurpose{},
+			false,
+		},
+	}
+	for _, test := range tests {
+		actual, ok := ParseSepaPurpose(test.subfields)
+
+		if ok != test.expectedOk {
+			t.Logf("%s: Expected ok to be %t, got %t\n", test.description, test.expectedOk, ok)
+			t.Fail()
+		}
+		if !reflect.DeepEqual(test.expected, actual) {
+			t.Logf("%s: Expected SEPA purpose to equal\n%+#v\n\tgot\n%+#v\n", test.description, test.expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+func TestCustomFieldTagSepaPurpose(t *testing.T) {
+	test := ":86:105?00FOLGELASTSCHRIFT?100005?20EREF+RE-2021-0815?21MREF+M-4711?22CRED+DE98ZZZ09999999999?23SVWZ+Rechnung 2021-0815?30COBADEFFXXX?31DE89370400440532013000?32Stadtwerke"
+
+	tag := &CustomFieldTag{}
+
+	err := tag.Unmarshal([]byte(test))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	sepa, ok := tag.SepaPurpose()
+
+	expected := SepaPurpose{
+		EndToEndReference:     "RE-2021-0815",
+		MandateReference:      "M-4711",
+		CreditorID:            "DE98ZZZ09999999999",
+		RemittanceInformation: "Rechnung 2021-0815",
+		IBAN:                  "DE89370400440532013000",
+		BIC:                   "COBADEFFXXX",
+	}
+	if !ok || !reflect.DeepEqual(expected, sepa) {
+		t.Logf("Expected SEPA purpose to equal\n%+#v\n\tgot\n%+#v\n", expected, sepa)
+		t.Fail()
+	}
+}
@@ -70,20 +70,26 @@ type tagExtractor struct {
 	lexer           *token.SwiftLexer
 	rawSwiftMessage []byte
 	extractedTags   [][]byte
+	offsets         []int
 }
 
 func (t *tagExtractor) Extract() ([][]byte, error) {
 	var current []byte
+	start := 0
 	for t.lexer.HasNext() {
 		tok := t.lexer.Next()
 		if tok.Type() == token.ERROR {
-			return nil, fmt.Errorf("%T: SyntaxError at position %d: %q\n(%q)", t, tok.Pos(), tok.Value(), t.rawSwiftMessage)
+			return nil, newSyntaxError(tok, t.rawSwiftMessage)
 		}
 		if tok.Type() == token.SWIFT_TAG_SEPARATOR || tok.Type() == token.SWIFT_MESSAGE_SEPARATOR {
 			t.extractedTags = append(t.extractedTags, current)
+			t.offsets = append(t.offsets, start)
 			current = []byte{}
 		} else {
 			if tok.Type() != token.SWIFT_DATASET_START {
+				if len(current) == 0 {
+					start = tok.Pos()
+				}
 				current = append(current, tok.Value()...)
 			}
 		}
@@ -1,12 +1,13 @@
 package swift
 
 import (
+	"bytes"
 	"fmt"
 	"sort"
-	"strings"
 	"time"
 
 	"github.com/mitch000001/go-hbci/domain"
+	"github.com/pkg/errors"
 )
 
 func NewMT940Messages(data []byte) *MT940Messages {
@@ -69,40 +70,146 @@ type MT940Unmarshaler interface {
 	UnmarshalMT940([]byte) ([]domain.AccountTransaction, error)
 }
 
+// ParseMode defines how malformed statements are handled when unmarshaling
+// MT940 messages
+type ParseMode int
+
+const (
+	// ParseModeStrict fails on the first malformed statement
+	ParseModeStrict ParseMode = iota
+	// ParseModeLenient skips malformed statements and reports them as
+	// ParseWarning
+	ParseModeLenient
+)
+
+// ParseWarning describes a statement skipped in lenient mode
+type ParseWarning struct {
+	// Tag is the ID of the offending tag, if known
+	Tag string
+	// Offset, Line and Column define the position of the error within the
+	// unmarshaled input
+	Offset int
+	Line   int
+	Column int
+	// Raw contains the raw bytes of the skipped statement
+	Raw []byte
+	// Err is the reason the statement was skipped
+	Err error
+}
+
+func (p ParseWarning) String() string {
+	return fmt.Sprintf("skipped statement at line %d: %v", p.Line, p.Err)
+}
+
+// NewMT940MessagesUnmarshaler returns an unmarshaler for MT940 messages in
+// strict mode
 func NewMT940MessagesUnmarshaler() MT940Unmarshaler {
-	return &mt940MessagesUnmarshaler{}
+	return &MT940MessagesUnmarshaler{}
 }
 
-type mt940MessagesUnmarshaler struct{}
+// MT940MessagesUnmarshaler unmarshals MT940 messages into account
+// transactions
+type MT940MessagesUnmarshaler struct {
+	// Mode defines how malformed statements are handled. Defaults to
+	// ParseModeStrict.
+	Mode ParseMode
+	// DateReference defines how the year of entry dates is inferred.
+	// Defaults to DateReferenceBalances.
+	DateReference DateReference
+}
 
-func (m *mt940MessagesUnmarshaler) UnmarshalMT940(value []byte) ([]domain.AccountTransaction, error) {
-	messageExtractor := NewMessageExtractor(value)
-	messages, err := messageExtractor.Extract()
-	if err != nil {
-		return nil, fmt.Errorf("error extracting messages: %w", err)
-	}
-	var errors errorList
+// UnmarshalMT940 unmarshals the MT940 messages within value into account
+// transactions. Warnings about statements skipped in lenient mode are
+// discarded.
+func (m *MT940MessagesUnmarshaler) UnmarshalMT940(value []byte) ([]domain.AccountTransaction, error) {
+	transactions, _, err := m.UnmarshalMT940WithWarnings(value)
+	return transactions, err
+}
+
+// UnmarshalMT940WithWarnings unmarshals the MT940 messages within value into
+// account transactions. In strict mode the first malformed statement results
+// in a *SyntaxError. In lenient mode malformed statements are skipped and
+// returned as warnings.
+func (m *MT940MessagesUnmarshaler) UnmarshalMT940WithWarnings(value []byte) ([]domain.AccountTransaction, []ParseWarning, error) {
 	var transactions []domain.AccountTransaction
-	for _, message := range messages {
-		tr := &MT940{}
-		err = tr.Unmarshal(message)
-		if err != nil {
-			errors = append(errors, fmt.Errorf("error unmarshaling MT940: %w", err))
+	var warnings []ParseWarning
+	var base int
+	for base < len(value) {
+		extractor := NewMessageExtractor(value[base:])
+		extractErr := extractor.extract()
+		for i, message := range extractor.extractedMessages {
+			offset := base + extractor.offsets[i]
+			tr := &MT940{}
+			err := tr.unmarshal(message, m.DateReference)
+			if err == nil {
+				err = tr.validate()
+			}
+			if err == nil {
+				transactions = append(transactions, tr.AccountTransactions()...)
+				continue
+			}
+			syntaxErr := statementError(value, offset, len(message), err)
+			if m.Mode != ParseModeLenient {
+				return nil, nil, syntaxErr
+			}
+			warnings = append(warnings, parseWarning(syntaxErr, message))
 		}
-		transactions = append(transactions, tr.AccountTransactions()...)
-	}
-	if len(errors) != 0 {
-		return nil, errors
+		if extractErr == nil {
+			break
+		}
+		syntaxErr := &SyntaxError{Token: extractErr.Token, RawMessage: value, Err: fmt.Errorf("%s", extractErr.Token.Value())}
+		syntaxErr.setOffset(base + extractErr.Offset)
+		if m.Mode != ParseModeLenient {
+			return nil, nil, syntaxErr
+		}
+		// skip the broken statement up to the next message separator
+		start := base
+		if n := len(extractor.offsets); n != 0 {
+			start = base + extractor.offsets[n-1] + len(extractor.extractedMessages[n-1])
+		}
+		end := len(value)
+		if i := bytes.Index(value[syntaxErr.Offset:], []byte(messageSeparator)); i != -1 {
+			end = syntaxErr.Offset + i + len(messageSeparator)
+		}
+		warnings = append(warnings, parseWarning(syntaxErr, value[start:end]))
+		base = end
 	}
-	return transactions, nil
+	return transactions, warnings, nil
 }
 
-type errorList []error
+// statementError returns a *SyntaxError for err, which occurred unmarshaling
+// the statement of length at offset within value
+func statementError(value []byte, offset, length int, err error) *SyntaxError {
+	syntaxErr := &SyntaxError{RawMessage: value, Err: err}
+	var tagErr *tagError
+	var missingErr *missingTagError
+	var lexerErr *SyntaxError
+	switch {
+	case errors.As(err, &lexerErr):
+		syntaxErr.Token = lexerErr.Token
+		syntaxErr.Err = fmt.Errorf("%s", lexerErr.Token.Value())
+		offset += lexerErr.Offset
+	case errors.As(err, &tagErr):
+		if id, err := extractTagID(tagErr.tag); err == nil {
+			syntaxErr.Tag = string(id)
+		}
+		syntaxErr.Err = tagErr.err
+		offset += tagErr.offset
+	case errors.As(err, &missingErr):
+		syntaxErr.Tag = missingErr.tag
+		offset += length
+	}
+	syntaxErr.setOffset(offset)
+	return syntaxErr
+}
 
-func (e errorList) Error() string {
-	errs := make([]string, len(e))
-	for i, err := range e {
-		errs[i] = err.Error()
+func parseWarning(err *SyntaxError, raw []byte) ParseWarning {
+	return ParseWarning{
+		Tag:    err.Tag,
+		Offset: err.Offset,
+		Line:   err.Line,
+		Column: err.Column,
+		Raw:    raw,
+		Err:    err.Err,
 	}
-	return strings.Join(errs, ",")
 }
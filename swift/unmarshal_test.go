@@ -0,0 +1,119 @@
+package swift
+
+import (
+	"testing"
+)
+
+const (
+	validTestStatement = "\r\n:20:STARTUMS" +
+		"\r\n:25:12345678/1234123456" +
+		"\r\n:28C:1" +
+		"\r\n:60F:C181105EUR1234,56" +
+		"\r\n:61:1811051105DR50,NMSCNONREF" +
+		"\r\n:86:177?00SB-SEPA-Ueberweisung?20Miete?32Max Meier" +
+		"\r\n:62F:C181105EUR1184,56" +
+		"\r\n-"
+	missingClosingBalanceTestStatement = "\r\n:20:STARTUMS" +
+		"\r\n:25:12345678/1234123456" +
+		"\r\n:28C:2" +
+		"\r\n:60F:C181106EUR1184,56" +
+		"\r\n:61:1811061106CR20,NMSCNONREF" +
+		"\r\n-"
+	malformedTagTestStatement = "\r\n:20:STARTUMS" +
+		"\r\n:25:12345678/1234123456" +
+		"\r\n:28C:3" +
+		"\r\n:60F:C181107EUR1204,56" +
+		"\r\n:61:1811X71107CR20,NMSCNONREF" +
+		"\r\n:62F:C181107EUR1224,56" +
+		"\r\n-"
+	lexerErrorTestStatement = "\r\n:20:STARTUMS" +
+		"\r\n:25:12345678/1234123456" +
+		"\r\n:2XY:broken" +
+		"\r\n:62F:C181107EUR1224,56" +
+		"\r\n-"
+	truncatedTestStatement = "\r\n:20:STARTUMS" +
+		"\r\n:25:12345678/1234123456\r\n"
+)
+
+func TestMT940MessagesUnmarshalerStrict(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expectedTag    string
+		expectedLine   int
+		expectedColumn int
+	}{
+		{"missing closing balance", validTestStatement + missingClosingBalanceTestStatement, ":62a:", 15, 2},
+		{"malformed tag", validTestStatement + malformedTagTestStatement, ":61:", 14, 1},
+		{"lexer error", validTestStatement + lexerErrorTestStatement, "", 12, 1},
+	}
+	for _, test := range tests {
+		unmarshaler := &MT940MessagesUnmarshaler{Mode: ParseModeStrict}
+
+		transactions, err := unmarshaler.UnmarshalMT940([]byte(test.input))
+
+		if transactions != nil {
+			t.Logf("%s: Expected no transactions, got %v\n", test.name, transactions)
+			t.Fail()
+		}
+		syntaxErr, ok := err.(*SyntaxError)
+		if !ok {
+			t.Fatalf("%s: Expected error to be a *SyntaxError, got %T:%v\n", test.name, err, err)
+		}
+		if syntaxErr.Tag != test.expectedTag {
+			t.Logf("%s: Expected tag to equal %q, got %q\n", test.name, test.expectedTag, syntaxErr.Tag)
+			t.Fail()
+		}
+		if syntaxErr.Line != test.expectedLine || syntaxErr.Column != test.expectedColumn {
+			t.Logf("%s: Expected error at line %d, column %d, got line %d, column %d\n", test.name, test.expectedLine, test.expectedColumn, syntaxErr.Line, syntaxErr.Column)
+			t.Fail()
+		}
+	}
+}
+
+func TestMT940MessagesUnmarshalerLenient(t *testing.T) {
+	input := missingClosingBalanceTestStatement +
+		validTestStatement +
+		lexerErrorTestStatement +
+		malformedTagTestStatement +
+		validTestStatement +
+		truncatedTestStatement
+	unmarshaler := &MT940MessagesUnmarshaler{Mode: ParseModeLenient}
+
+	transactions, warnings, err := unmarshaler.UnmarshalMT940WithWarnings([]byte(input))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if len(transactions) != 2 {
+		t.Logf("Expected 2 transactions, got %d\n", len(transactions))
+		t.Fail()
+	}
+	expectedWarnings := []struct {
+		tag string
+		raw string
+	}{
+		{":62a:", missingClosingBalanceTestStatement},
+		{"", lexerErrorTestStatement},
+		{":61:", malformedTagTestStatement},
+		{"", truncatedTestStatement},
+	}
+	if len(warnings) != len(expectedWarnings) {
+		t.Fatalf("Expected %d warnings, got %d: %v\n", len(expectedWarnings), len(warnings), warnings)
+	}
+	for i, expected := range expectedWarnings {
+		warning := warnings[i]
+		if warning.Tag != expected.tag {
+			t.Logf("Expected warning %d to have tag %q, got %q\n", i, expected.tag, warning.Tag)
+			t.Fail()
+		}
+		if string(warning.Raw) != expected.raw {
+			t.Logf("Expected warning %d to contain raw statement\n%q\n\tgot\n%q\n", i, expected.raw, warning.Raw)
+			t.Fail()
+		}
+		if warning.Err == nil {
+			t.Logf("Expected warning %d to have an error\n", i)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package sync
+
+import (
+	gosync "sync"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+// Store persists the sync state per account.
+type Store interface {
+	// Load returns the state for account. It returns an empty State if the
+	// account was never synced.
+	Load(account domain.InternationalAccountConnection) (State, error)
+	// Save persists state for account
+	Save(account domain.InternationalAccountConnection, state State) error
+}
+
+// State represents the sync state of one account
+type State struct {
+	// LastBookingDate is the latest booking date of all synced transactions
+	LastBookingDate time.Time `json:"last_booking_date"`
+	// Fingerprints contains the fingerprints of all synced transactions
+	// within the overlapping window
+	Fingerprints map[string]FingerprintEntry `json:"fingerprints"`
+}
+
+// FingerprintEntry records how often a fingerprint was seen within one run.
+type FingerprintEntry struct {
+	Count       int       `json:"count"`
+	BookingDate time.Time `json:"booking_date"`
+}
+
+// prune removes all fingerprints booked before date, as these will not be
+// requested again.
+func (s *State) prune(date time.Time) {
+	for fingerprint, entry := range s.Fingerprints {
+		if entry.BookingDate.Before(date) {
+			delete(s.Fingerprints, fingerprint)
+		}
+	}
+}
+
+// NewMemoryStore returns a Store which keeps all state in memory.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{states: make(map[string]State)}
+}
+
+// MemoryStore is a Store keeping the state in memory. It is safe for
+// concurrent use.
+type MemoryStore struct {
+	mu     gosync.Mutex
+	states map[string]State
+}
+
+// Load implements Store
+func (m *MemoryStore) Load(account domain.InternationalAccountConnection) (State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state := m.states[account.IBAN]
+	fingerprints := make(map[string]FingerprintEntry, len(state.Fingerprints))
+	for k, v := range state.Fingerprints {
+		fingerprints[k] = v
+	}
+	state.Fingerprints = fingerprints
+	return state, nil
+}
+
+// Save implements Store
+func (m *MemoryStore) Save(account domain.InternationalAccountConnection, state State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states[account.IBAN] = state
+	return nil
+}
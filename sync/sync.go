@@ -0,0 +1,143 @@
+// Package sync provides an incremental synchronization of account
+// transactions.
+//
+// MT940 statements carry no reliable transaction IDs, so the Syncer requests
+// windows overlapping the last synced booking date and deduplicates the
+// returned transactions with a stable Fingerprint. The state needed for that
+// is kept per account within a Store.
+package sync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+const (
+	// DefaultOverlapDays defines how many days before the last synced booking
+	// date are requested again if Syncer.OverlapDays is not set.
+	DefaultOverlapDays = 14
+	// DefaultInitialDays defines how many days are requested for accounts
+	// without state if Syncer.InitialDays is not set.
+	DefaultInitialDays = 90
+)
+
+// TransactionFetcher defines the source of account transactions. It is
+// implemented by client.Client.
+type TransactionFetcher interface {
+	SepaAccountTransactions(account domain.InternationalAccountConnection, timeframe domain.Timeframe, allAccounts bool, continuationReference string) ([]domain.AccountTransaction, error)
+}
+
+// NewSyncer returns a Syncer fetching transactions from fetcher and keeping
+// its state within store.
+func NewSyncer(fetcher TransactionFetcher, store Store) *Syncer {
+	return &Syncer{
+		fetcher: fetcher,
+		store:   store,
+		now:     time.Now,
+	}
+}
+
+// Syncer fetches the transactions booked since the last synchronization of an
+// account.
+type Syncer struct {
+	// OverlapDays defines how many days before the last synced booking date
+	// are requested again, to catch transactions booked late by the bank
+	// institute. Defaults to DefaultOverlapDays.
+	OverlapDays int
+	// InitialDays defines how many days are requested for accounts which were
+	// never synced. Defaults to DefaultInitialDays.
+	InitialDays int
+	fetcher     TransactionFetcher
+	store       Store
+	now         func() time.Time
+}
+
+// Sync fetches the transactions of account and returns all transactions not
+// returned by a previous call. The state is only saved when all transactions
+// could be fetched, so a failed Sync can safely be retried.
+func (s *Syncer) Sync(account domain.InternationalAccountConnection) ([]domain.AccountTransaction, error) {
+	state, err := s.store.Load(account)
+	if err != nil {
+		return nil, fmt.Errorf("error loading sync state: %w", err)
+	}
+	today := domain.NewShortDate(s.now())
+	startDate := domain.NewShortDate(today.AddDate(0, 0, -s.initialDays()))
+	if !state.LastBookingDate.IsZero() {
+		startDate = domain.NewShortDate(state.LastBookingDate.AddDate(0, 0, -s.overlapDays()))
+	}
+	timeframe := domain.Timeframe{StartDate: startDate, EndDate: today}
+	transactions, err := s.fetcher.SepaAccountTransactions(account, timeframe, false, "")
+	if err != nil {
+		return nil, fmt.Errorf("error fetching transactions: %w", err)
+	}
+
+	if state.Fingerprints == nil {
+		state.Fingerprints = make(map[string]FingerprintEntry)
+	}
+	var newTransactions []domain.AccountTransaction
+	occurrences := make(map[string]int)
+	for _, tx := range transactions {
+		fingerprint := Fingerprint(tx)
+		occurrences[fingerprint]++
+		entry := state.Fingerprints[fingerprint]
+		// Identical transactions can be booked multiple times a day, i.e. two
+		// purchases of the same value at the same shop. They are only new if
+		// they occur more often than within the previous runs.
+		if occurrences[fingerprint] > entry.Count {
+			newTransactions = append(newTransactions, tx)
+			state.Fingerprints[fingerprint] = FingerprintEntry{
+				Count:       occurrences[fingerprint],
+				BookingDate: tx.BookingDate,
+			}
+		}
+		if tx.BookingDate.After(state.LastBookingDate) {
+			state.LastBookingDate = tx.BookingDate
+		}
+	}
+	state.prune(state.LastBookingDate.AddDate(0, 0, -s.overlapDays()))
+
+	if err := s.store.Save(account, state); err != nil {
+		return nil, fmt.Errorf("error saving sync state: %w", err)
+	}
+	return newTransactions, nil
+}
+
+func (s *Syncer) overlapDays() int {
+	if s.OverlapDays <= 0 {
+		return DefaultOverlapDays
+	}
+	return s.OverlapDays
+}
+
+func (s *Syncer) initialDays() int {
+	if s.InitialDays <= 0 {
+		return DefaultInitialDays
+	}
+	return s.InitialDays
+}
+
+// Fingerprint returns a stable identifier for tx. It is built from the
+// booking date, the amount, the counterparty, the purpose, the bank reference
+// and the statement number of the transaction.
+func Fingerprint(tx domain.AccountTransaction) string {
+	fields := []string{
+		tx.BookingDate.Format("20060102"),
+		tx.Amount.Decimal(),
+		tx.Amount.Currency,
+		tx.Name,
+		tx.BankID,
+		tx.AccountID,
+		tx.Purpose,
+		tx.Purpose2,
+		tx.BankReference,
+		strconv.Itoa(tx.StatementNumber),
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x1f")))
+	return hex.EncodeToString(sum[:])
+}
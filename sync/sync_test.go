@@ -0,0 +1,135 @@
+package sync
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+type mockFetcher struct {
+	responses  [][]domain.AccountTransaction
+	timeframes []domain.Timeframe
+	err        error
+}
+
+func (m *mockFetcher) SepaAccountTransactions(account domain.InternationalAccountConnection, timeframe domain.Timeframe, allAccounts bool, continuationReference string) ([]domain.AccountTransaction, error) {
+	m.timeframes = append(m.timeframes, timeframe)
+	if m.err != nil {
+		return nil, m.err
+	}
+	res := m.responses[0]
+	m.responses = m.responses[1:]
+	return res, nil
+}
+
+func testTransaction(day int, minorUnits int64, purpose string) domain.AccountTransaction {
+	return domain.AccountTransaction{
+		Amount:          domain.NewAmount(minorUnits, "EUR"),
+		BookingDate:     time.Date(2020, 3, day, 0, 0, 0, 0, time.UTC),
+		Name:            "Shop",
+		Purpose:         purpose,
+		StatementNumber: 1,
+	}
+}
+
+func TestSyncerSync(t *testing.T) {
+	account := domain.InternationalAccountConnection{IBAN: "DE88100000000100000000"}
+	coffee := testTransaction(10, -250, "Coffee")
+	rent := testTransaction(10, -80000, "Rent")
+	salary := testTransaction(12, 300000, "Salary")
+
+	fetcher := &mockFetcher{
+		responses: [][]domain.AccountTransaction{
+			{coffee, rent},
+			{coffee, rent, coffee, salary},
+			{coffee, rent, coffee, salary},
+		},
+	}
+	syncer := NewSyncer(fetcher, NewMemoryStore())
+	syncer.OverlapDays = 5
+	syncer.now = func() time.Time { return time.Date(2020, 3, 15, 10, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		expected []domain.AccountTransaction
+	}{
+		{[]domain.AccountTransaction{coffee, rent}},
+		{[]domain.AccountTransaction{coffee, salary}},
+		{nil},
+	}
+	for i, test := range tests {
+		transactions, err := syncer.Sync(account)
+		if err != nil {
+			t.Logf("Run %d: Expected no error, got %T:%v\n", i, err, err)
+			t.Fail()
+		}
+		if !reflect.DeepEqual(transactions, test.expected) {
+			t.Logf("Run %d: Expected transactions\n%v\n\tgot\n%v\n", i, test.expected, transactions)
+			t.Fail()
+		}
+	}
+
+	expectedStart := domain.Date(2019, 12, 16, time.UTC)
+	if !fetcher.timeframes[0].StartDate.Equal(expectedStart.Time) {
+		t.Logf("Expected initial start date %s, got %s\n", expectedStart, fetcher.timeframes[0].StartDate)
+		t.Fail()
+	}
+	expectedStart = domain.Date(2020, 3, 5, time.UTC)
+	if !fetcher.timeframes[1].StartDate.Equal(expectedStart.Time) {
+		t.Logf("Expected overlapping start date %s, got %s\n", expectedStart, fetcher.timeframes[1].StartDate)
+		t.Fail()
+	}
+	expectedStart = domain.Date(2020, 3, 7, time.UTC)
+	if !fetcher.timeframes[2].StartDate.Equal(expectedStart.Time) {
+		t.Logf("Expected overlapping start date %s, got %s\n", expectedStart, fetcher.timeframes[2].StartDate)
+		t.Fail()
+	}
+}
+
+func TestSyncerSyncKeepsStateOnError(t *testing.T) {
+	account := domain.InternationalAccountConnection{IBAN: "DE88100000000100000000"}
+	store := NewMemoryStore()
+	fetchErr := errors.New("connection reset")
+	syncer := NewSyncer(&mockFetcher{err: fetchErr}, store)
+
+	_, err := syncer.Sync(account)
+
+	if !errors.Is(err, fetchErr) {
+		t.Logf("Expected error to wrap %v, got %T:%v\n", fetchErr, err, err)
+		t.Fail()
+	}
+	state, _ := store.Load(account)
+	if !state.LastBookingDate.IsZero() || len(state.Fingerprints) != 0 {
+		t.Logf("Expected state to be empty, got %+v\n", state)
+		t.Fail()
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	tx := testTransaction(10, -250, "Coffee")
+
+	if Fingerprint(tx) != Fingerprint(tx) {
+		t.Logf("Expected fingerprint to be stable\n")
+		t.Fail()
+	}
+
+	modifications := map[string]func(*domain.AccountTransaction){
+		"booking date":     func(tx *domain.AccountTransaction) { tx.BookingDate = tx.BookingDate.AddDate(0, 0, 1) },
+		"amount":           func(tx *domain.AccountTransaction) { tx.Amount = domain.NewAmount(-251, "EUR") },
+		"currency":         func(tx *domain.AccountTransaction) { tx.Amount = domain.NewAmount(-250, "USD") },
+		"counterparty":     func(tx *domain.AccountTransaction) { tx.Name = "Other Shop" },
+		"purpose":          func(tx *domain.AccountTransaction) { tx.Purpose = "Tea" },
+		"bank reference":   func(tx *domain.AccountTransaction) { tx.BankReference = "REF" },
+		"statement number": func(tx *domain.AccountTransaction) { tx.StatementNumber = 2 },
+	}
+	for name, modify := range modifications {
+		modified := tx
+		modify(&modified)
+		if Fingerprint(tx) == Fingerprint(modified) {
+			t.Logf("Expected fingerprint to change with %s\n", name)
+			t.Fail()
+		}
+	}
+}
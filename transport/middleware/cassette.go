@@ -0,0 +1,56 @@
+package transport
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A Cassette holds recorded request and response pairs of a session with a
+// bank institute. All secrets within are scrubbed.
+type Cassette struct {
+	Interactions []Interaction `yaml:"interactions"`
+}
+
+// An Interaction represents one request and the according response.
+type Interaction struct {
+	Request  RecordedMessage `yaml:"request"`
+	Response RecordedMessage `yaml:"response"`
+}
+
+// A RecordedMessage represents a decrypted message. Segments contains the
+// scrubbed segments of the message payload without message header, message
+// end and encryption segments.
+type RecordedMessage struct {
+	HBCIVersion   int      `yaml:"hbci_version"`
+	DialogID      string   `yaml:"dialog_id"`
+	MessageNumber int      `yaml:"message_number"`
+	Encrypted     bool     `yaml:"encrypted"`
+	Segments      []string `yaml:"segments"`
+}
+
+// ReadCassette reads the cassette stored at path.
+func ReadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cassette: %w", err)
+	}
+	var cassette Cassette
+	if err := yaml.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("error unmarshaling cassette %q: %w", path, err)
+	}
+	return &cassette, nil
+}
+
+// WriteFile writes c to path, replacing any existing file.
+func (c *Cassette) WriteFile(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("error marshaling cassette: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("error writing cassette: %w", err)
+	}
+	return nil
+}
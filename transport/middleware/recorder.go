@@ -0,0 +1,226 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/mitch000001/go-hbci/element"
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+	"github.com/mitch000001/go-hbci/transport"
+)
+
+// envelopeSegments are not recorded, as they get rebuilt on replay
+var envelopeSegments = map[string]bool{
+	segment.MessageHeaderID: true,
+	"HNHBS":                 true,
+	"HNVSK":                 true,
+	"HNVSD":                 true,
+}
+
+// Record creates a middleware that records every request and response sent
+// over the transport into a cassette at path. The cassette is rewritten after
+// every exchange, so it is complete even if the session aborts.
+//
+// PIN, TAN, dialog IDs, system IDs, user IDs and account numbers are scrubbed
+// from the recorded messages, as well as all additionally provided secrets.
+// If cryptoProvider is nil a PIN/TAN crypto provider is used.
+func Record(path string, cryptoProvider message.CryptoProvider, secrets ...string) transport.Middleware {
+	if cryptoProvider == nil {
+		cryptoProvider = message.NewPinTanCryptoProvider(nil, "")
+	}
+	recorder := &recorder{
+		path:           path,
+		cryptoProvider: cryptoProvider,
+		scrubber:       newScrubber(secrets...),
+		cassette:       &Cassette{},
+	}
+	return func(t transport.Transport) transport.Transport {
+		return transport.Func(func(req *transport.Request) (*transport.Response, error) {
+			marshaledRequest, err := io.ReadAll(req.Body)
+			if err != nil {
+				return nil, fmt.Errorf("error reading request body: %w", err)
+			}
+			req.Body = io.NopCloser(bytes.NewReader(marshaledRequest))
+			res, err := t.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			marshaledResponse, err := io.ReadAll(res.Body)
+			if err != nil {
+				return nil, fmt.Errorf("error reading response body: %w", err)
+			}
+			res.Body = io.NopCloser(bytes.NewReader(marshaledResponse))
+			if err := recorder.record(marshaledRequest, marshaledResponse); err != nil {
+				return nil, err
+			}
+			return res, nil
+		})
+	}
+}
+
+type recorder struct {
+	mu             sync.Mutex
+	path           string
+	cryptoProvider message.CryptoProvider
+	scrubber       *scrubber
+	cassette       *Cassette
+}
+
+func (r *recorder) record(marshaledRequest, marshaledResponse []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	request, err := decodeMessage(r.cryptoProvider, marshaledRequest)
+	if err != nil {
+		return fmt.Errorf("error recording request: %w", err)
+	}
+	response, err := decodeMessage(r.cryptoProvider, marshaledResponse)
+	if err != nil {
+		return fmt.Errorf("error recording response: %w", err)
+	}
+	interaction := Interaction{
+		Request:  request.record(r.scrubber),
+		Response: response.record(r.scrubber),
+	}
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	return r.cassette.WriteFile(r.path)
+}
+
+// decodedMessage represents a message split into its header information and
+// its payload segments.
+type decodedMessage struct {
+	hbciVersion   int
+	dialogID      string
+	messageNumber int
+	encrypted     bool
+	base64        bool
+	segments      [][]byte
+}
+
+func (d decodedMessage) record(s *scrubber) RecordedMessage {
+	segments := s.Scrub(d.segments)
+	return RecordedMessage{
+		HBCIVersion:   d.hbciVersion,
+		DialogID:      s.DialogID(d.dialogID),
+		MessageNumber: d.messageNumber,
+		Encrypted:     d.encrypted,
+		Segments:      segments,
+	}
+}
+
+// decodeMessage decodes a marshaled message, which may be base64 encoded. It
+// uses the same logic as the logging middleware and falls back to a plain
+// segment extraction for messages not known to the unmarshaler.
+func decodeMessage(cryptoProvider message.CryptoProvider, marshaledMessage []byte) (decodedMessage, error) {
+	var decoded decodedMessage
+	if !bytes.HasPrefix(marshaledMessage, []byte(segment.MessageHeaderID+":")) {
+		data, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(marshaledMessage)))
+		if err != nil {
+			return decoded, fmt.Errorf("malformed message: %w", err)
+		}
+		marshaledMessage = data
+		decoded.base64 = true
+	}
+	rawSegments, err := message.NewSegmentExtractor(marshaledMessage).Extract()
+	if err != nil {
+		return decoded, fmt.Errorf("error extracting segments from message: %w", err)
+	}
+	var encryptedData []byte
+	for _, seg := range rawSegments {
+		switch {
+		case bytes.HasPrefix(seg, []byte(segment.MessageHeaderID+":")):
+			header := &segment.MessageHeaderSegment{}
+			if err := header.UnmarshalHBCI(seg); err != nil {
+				return decoded, fmt.Errorf("error unmarshaling message header: %w", err)
+			}
+			decoded.hbciVersion = header.HBCIVersion.Val()
+			decoded.dialogID = header.DialogID.Val()
+			decoded.messageNumber = header.Number.Val()
+		case bytes.HasPrefix(seg, []byte("HNVSD:")):
+			decoded.encrypted = true
+			encryptedData = seg
+		}
+	}
+
+	var segments [][]byte
+	if bankMessage, err := readMessageData(cryptoProvider, marshaledMessage); err == nil {
+		if segmentProvider, ok := bankMessage.(marshaledSegmentsProvider); ok {
+			segments = segmentProvider.MarshaledSegments()
+		}
+	}
+	if segments == nil {
+		segments = rawSegments
+		if decoded.encrypted {
+			segments, err = decryptRawSegments(cryptoProvider, encryptedData)
+			if err != nil {
+				return decoded, err
+			}
+		}
+	}
+	for _, seg := range segments {
+		id := string(bytes.SplitN(seg, []byte(":"), 2)[0])
+		if !envelopeSegments[id] {
+			decoded.segments = append(decoded.segments, seg)
+		}
+	}
+	return decoded, nil
+}
+
+func decryptRawSegments(cryptoProvider message.CryptoProvider, encryptedData []byte) ([][]byte, error) {
+	elements, err := segment.ExtractElements(encryptedData)
+	if err != nil || len(elements) < 2 {
+		return nil, fmt.Errorf("malformed encrypted data segment")
+	}
+	data := &element.BinaryDataElement{}
+	if err := data.UnmarshalHBCI(elements[1]); err != nil {
+		return nil, fmt.Errorf("error unmarshaling encrypted data: %w", err)
+	}
+	decrypted, err := cryptoProvider.Decrypt(data.Val())
+	if err != nil {
+		return nil, fmt.Errorf("error while decrypting message: %w", err)
+	}
+	segments, err := message.NewSegmentExtractor(decrypted).Extract()
+	if err != nil {
+		return nil, fmt.Errorf("error extracting decrypted segments: %w", err)
+	}
+	return segments, nil
+}
+
+// marshal builds a complete message from d, wrapping the segments into an
+// encryption envelope if d is encrypted.
+func (d decodedMessage) marshal() []byte {
+	var payload bytes.Buffer
+	for _, seg := range d.segments {
+		payload.Write(seg)
+	}
+	nextSegmentNumber := len(d.segments) + 2
+	var body bytes.Buffer
+	if d.encrypted {
+		body.WriteString("HNVSK:998:3+PIN:1+998+1+2::0+1:20000101:000000+2:2:13:@8@\x00\x00\x00\x00\x00\x00\x00\x00:5:1+280:00000000:USER:V:0:0+0'")
+		fmt.Fprintf(&body, "HNVSD:999:1+@%d@", payload.Len())
+		body.Write(payload.Bytes())
+		body.WriteString("'")
+		nextSegmentNumber = 1000
+	} else {
+		body.Write(payload.Bytes())
+	}
+	dialogID := hbciEscaper.Replace(d.dialogID)
+	messageEnd := fmt.Sprintf("HNHBS:%d:1+%d'", nextSegmentNumber, d.messageNumber)
+	headerSuffix := fmt.Sprintf("+%d+%s+%d+%s:%d'", d.hbciVersion, dialogID, d.messageNumber, dialogID, d.messageNumber)
+	length := len("HNHBK:1:3+") + 12 + len(headerSuffix) + body.Len() + len(messageEnd)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "HNHBK:1:3+%012d%s", length, headerSuffix)
+	buf.Write(body.Bytes())
+	buf.WriteString(messageEnd)
+	if d.base64 {
+		return []byte(base64.StdEncoding.EncodeToString(buf.Bytes()))
+	}
+	return buf.Bytes()
+}
+
+var hbciEscaper = strings.NewReplacer("?", "??", "@", "?@", "'", "?'", ":", "?:", "+", "?+")
@@ -0,0 +1,236 @@
+package transport_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/client"
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/fintstest"
+	"github.com/mitch000001/go-hbci/transport"
+	https "github.com/mitch000001/go-hbci/transport/https"
+	middleware "github.com/mitch000001/go-hbci/transport/middleware"
+)
+
+func newClient(t *testing.T, url, userID, pin string, tr transport.Transport) *client.Client {
+	c, err := client.New(client.Config{
+		URL:         url,
+		BankID:      fintstest.DefaultBankID,
+		AccountID:   userID,
+		PIN:         pin,
+		HBCIVersion: 300,
+		Transport:   tr,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	return c
+}
+
+func balances(t *testing.T, c *client.Client) []domain.SepaAccountBalance {
+	balances, err := balancesForBIC(t, c, "TESTDEFFXXX")
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	return balances
+}
+
+func balancesForBIC(t *testing.T, c *client.Client, bic string) ([]domain.SepaAccountBalance, error) {
+	accounts, err := c.Accounts()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if len(accounts) != 1 {
+		t.Fatalf("Expected 1 account, got %d\n", len(accounts))
+	}
+	return c.SepaAccountBalances(domain.InternationalAccountConnection{
+		IBAN:      "DE89370400440532013000",
+		BIC:       bic,
+		AccountID: "1234567",
+		BankID:    domain.BankID{CountryCode: 280, ID: fintstest.DefaultBankID},
+	}, false, "")
+}
+
+func recordBalances(t *testing.T) string {
+	cassettePath := filepath.Join(t.TempDir(), "cassette.yaml")
+	server := fintstest.NewServer(fintstest.Config{
+		Accounts: []fintstest.Account{{
+			AccountID:   "1234567",
+			IBAN:        "DE89370400440532013000",
+			BIC:         "TESTDEFFXXX",
+			Name:        "Max Muster",
+			ProductName: "Girokonto",
+			Balance:     domain.NewAmount(123456, "EUR"),
+		}},
+	})
+	defer server.Close()
+	recorder := middleware.Record(cassettePath, nil)(https.New())
+	balances(t, newClient(t, server.URL, server.UserID(), server.PIN(), recorder))
+	return cassettePath
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	cassettePath := filepath.Join(t.TempDir(), "cassette.yaml")
+	server := fintstest.NewServer(fintstest.Config{
+		Accounts: []fintstest.Account{{
+			AccountID:   "1234567",
+			IBAN:        "DE89370400440532013000",
+			BIC:         "TESTDEFFXXX",
+			Name:        "Max Muster",
+			ProductName: "Girokonto",
+			Balance:     domain.NewAmount(123456, "EUR"),
+		}},
+	})
+
+	recorder := middleware.Record(cassettePath, nil, "Max Muster")(https.New())
+	recorded := balances(t, newClient(t, server.URL, server.UserID(), server.PIN(), recorder))
+	server.Close()
+
+	data, err := os.ReadFile(cassettePath)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	for _, secret := range []string{server.PIN(), server.UserID(), "1234567", "DE89370400440532013000", "Max Muster"} {
+		if strings.Contains(string(data), secret) {
+			t.Logf("Expected cassette not to contain %q\n", secret)
+			t.Fail()
+		}
+	}
+
+	player, err := middleware.Replay(cassettePath)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	replayed := balances(t, newClient(t, server.URL, "otheruser", "54321", player))
+
+	if len(replayed) != 1 || len(recorded) != 1 {
+		t.Fatalf("Expected one balance each, got %d recorded and %d replayed\n", len(recorded), len(replayed))
+	}
+	if replayed[0].BookedBalance.Amount != recorded[0].BookedBalance.Amount {
+		t.Logf("Expected replayed balance to equal %v, got %v\n", recorded[0].BookedBalance.Amount, replayed[0].BookedBalance.Amount)
+		t.Fail()
+	}
+	if replayed[0].Account.IBAN == recorded[0].Account.IBAN {
+		t.Logf("Expected replayed IBAN to be scrubbed, got %q\n", replayed[0].Account.IBAN)
+		t.Fail()
+	}
+}
+
+func TestRecordAndReplayTransactions(t *testing.T) {
+	cassettePath := filepath.Join(t.TempDir(), "cassette.yaml")
+	server := fintstest.NewServer(fintstest.Config{
+		Accounts: []fintstest.Account{{
+			AccountID:   "1234567",
+			IBAN:        "DE89370400440532013000",
+			BIC:         "TESTDEFFXXX",
+			Name:        "Max Muster",
+			ProductName: "Girokonto",
+			Balance:     domain.NewAmount(250000, "EUR"),
+			Transactions: []fintstest.Transaction{{
+				BookingDate: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC),
+				Amount:      domain.NewAmount(250000, "EUR"),
+				BookingText: "GUTSCHRIFT",
+				Name:        "Arbeitgeber",
+				IBAN:        "DE27100777770209299700",
+				BIC:         "TESTDEFFXXX",
+				Purpose:     "Gehalt Maerz",
+			}},
+		}},
+	})
+	account := domain.InternationalAccountConnection{
+		IBAN:      "DE89370400440532013000",
+		BIC:       "TESTDEFFXXX",
+		AccountID: "1234567",
+		BankID:    domain.BankID{CountryCode: 280, ID: fintstest.DefaultBankID},
+	}
+	timeframe := domain.Timeframe{
+		StartDate: domain.Date(2020, 3, 1, time.UTC),
+		EndDate:   domain.Date(2020, 3, 31, time.UTC),
+	}
+
+	recorder := middleware.Record(cassettePath, nil)(https.New())
+	recorded, err := newClient(t, server.URL, server.UserID(), server.PIN(), recorder).SepaAccountTransactions(account, timeframe, false, "")
+	server.Close()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	data, err := os.ReadFile(cassettePath)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	for _, secret := range []string{"1234567", "DE89370400440532013000", "DE27100777770209299700"} {
+		if strings.Contains(string(data), secret) {
+			t.Logf("Expected cassette not to contain %q\n", secret)
+			t.Fail()
+		}
+	}
+
+	player, err := middleware.Replay(cassettePath)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	replayed, err := newClient(t, server.URL, "otheruser", "54321", player).SepaAccountTransactions(account, timeframe, false, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	if len(replayed) != 1 || len(recorded) != 1 {
+		t.Fatalf("Expected one transaction each, got %d recorded and %d replayed\n", len(recorded), len(replayed))
+	}
+	if replayed[0].Amount != recorded[0].Amount || replayed[0].Purpose != recorded[0].Purpose {
+		t.Logf("Expected replayed transaction to equal %+v, got %+v\n", recorded[0], replayed[0])
+		t.Fail()
+	}
+	if replayed[0].Account.AccountID == recorded[0].Account.AccountID {
+		t.Logf("Expected replayed account ID to be scrubbed, got %q\n", replayed[0].Account.AccountID)
+		t.Fail()
+	}
+}
+
+func TestReplayUnknownRequest(t *testing.T) {
+	cassettePath := filepath.Join(t.TempDir(), "cassette.yaml")
+	if err := (&middleware.Cassette{}).WriteFile(cassettePath); err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	player, err := middleware.Replay(cassettePath)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	_, err = newClient(t, "http://localhost", "testuser", "12345", player).Accounts()
+	if err == nil {
+		t.Logf("Expected error for request without recorded interaction\n")
+		t.Fail()
+	}
+}
+
+func TestReplayDifferingRequest(t *testing.T) {
+	cassettePath := recordBalances(t)
+
+	player, err := middleware.Replay(cassettePath)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	_, err = balancesForBIC(t, newClient(t, "http://localhost", "otheruser", "54321", player), "OTHRDEFFXXX")
+	if err == nil {
+		t.Logf("Expected error for request differing from the recorded one\n")
+		t.Fail()
+	}
+
+	player, err = middleware.ReplayLenient(cassettePath)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	replayed, err := balancesForBIC(t, newClient(t, "http://localhost", "otheruser", "54321", player), "OTHRDEFFXXX")
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if len(replayed) != 1 {
+		t.Logf("Expected one replayed balance, got %d\n", len(replayed))
+		t.Fail()
+	}
+}
@@ -0,0 +1,146 @@
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/mitch000001/go-hbci/charset"
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/transport"
+)
+
+// Replay returns a transport serving the responses recorded in the cassette at
+// path. A request is matched against the first unused interaction whose
+// recorded request has the same segment content after scrubbing. Signature
+// segments are ignored, as they contain volatile data. Requests without a
+// matching interaction result in an error.
+func Replay(path string) (transport.Transport, error) {
+	return newPlayer(path, false)
+}
+
+// ReplayLenient works like Replay, but if no recorded request matches by
+// content, the first unused interaction with the same segment sequence is
+// taken. It allows to replay requests containing volatile data like the
+// current date, at the cost of serving responses to differing requests.
+func ReplayLenient(path string) (transport.Transport, error) {
+	return newPlayer(path, true)
+}
+
+func newPlayer(path string, matchSegmentIDs bool) (*player, error) {
+	cassette, err := ReadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	return &player{
+		cassette:        cassette,
+		used:            make([]bool, len(cassette.Interactions)),
+		scrubber:        newScrubber(),
+		cryptoProvider:  message.NewPinTanCryptoProvider(nil, ""),
+		matchSegmentIDs: matchSegmentIDs,
+	}, nil
+}
+
+type player struct {
+	mu              sync.Mutex
+	cassette        *Cassette
+	used            []bool
+	scrubber        *scrubber
+	cryptoProvider  message.CryptoProvider
+	matchSegmentIDs bool
+}
+
+// Do implements transport.Transport
+func (p *player) Do(req *transport.Request) (*transport.Response, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	marshaledRequest, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading request body: %w", err)
+	}
+	request, err := decodeMessage(p.cryptoProvider, marshaledRequest)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding request: %w", err)
+	}
+	segments := comparableSegments(p.scrubber.Scrub(request.segments))
+	index := p.match(func(recorded []string) bool {
+		return equalSegments(segments, recorded)
+	})
+	if index == -1 && p.matchSegmentIDs {
+		index = p.match(func(recorded []string) bool {
+			return equalSegments(segmentIDs(segments), segmentIDs(recorded))
+		})
+	}
+	if index == -1 {
+		return nil, fmt.Errorf("no recorded interaction matches request %q", segments)
+	}
+	p.used[index] = true
+
+	recorded := p.cassette.Interactions[index].Response
+	response := decodedMessage{
+		hbciVersion:   recorded.HBCIVersion,
+		dialogID:      recorded.DialogID,
+		messageNumber: recorded.MessageNumber,
+		encrypted:     recorded.Encrypted,
+		base64:        request.base64,
+	}
+	for _, seg := range recorded.Segments {
+		response.segments = append(response.segments, charset.ToISO8859_1(seg))
+	}
+	p.scrubber.Observe(response.dialogID, response.segments)
+	return &transport.Response{
+		Body:    io.NopCloser(bytes.NewReader(response.marshal())),
+		Request: req,
+	}, nil
+}
+
+func (p *player) match(matches func(recorded []string) bool) int {
+	for i, interaction := range p.cassette.Interactions {
+		if p.used[i] {
+			continue
+		}
+		if matches(comparableSegments(interaction.Request.Segments)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// comparableSegments returns all segments without signature header and
+// signature end.
+func comparableSegments(segments []string) []string {
+	var comparable []string
+	for _, seg := range segments {
+		if len(seg) > 6 && (seg[:6] == "HNSHK:" || seg[:6] == "HNSHA:") {
+			continue
+		}
+		comparable = append(comparable, seg)
+	}
+	return comparable
+}
+
+// segmentIDs returns the segment headers of segments.
+func segmentIDs(segments []string) []string {
+	ids := make([]string, len(segments))
+	for i, seg := range segments {
+		if idx := strings.IndexByte(seg, '+'); idx != -1 {
+			seg = seg[:idx]
+		}
+		ids[i] = seg
+	}
+	return ids
+}
+
+func equalSegments(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
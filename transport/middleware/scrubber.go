@@ -0,0 +1,417 @@
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/mitch000001/go-hbci/charset"
+	"github.com/mitch000001/go-hbci/element"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// minSecretLength defines the minimal length of values learned from messages.
+// Shorter values are too likely to be regular content like the system ID "0".
+const minSecretLength = 4
+
+// newScrubber returns a scrubber replacing the given secrets in addition to
+// all secrets found within the scrubbed messages.
+func newScrubber(secrets ...string) *scrubber {
+	s := &scrubber{replacements: make(map[string]string), counters: make(map[string]int)}
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		s.secrets = append(s.secrets, secret)
+		s.learn("SECRET", secret, 1)
+	}
+	// Replace longer secrets first, as they may contain shorter ones
+	sort.Slice(s.secrets, func(i, j int) bool {
+		if len(s.secrets[i]) != len(s.secrets[j]) {
+			return len(s.secrets[i]) > len(s.secrets[j])
+		}
+		return s.secrets[i] < s.secrets[j]
+	})
+	return s
+}
+
+// scrubber replaces PINs, TANs, dialog IDs, system IDs, user IDs and account
+// numbers within messages with stable placeholders. Placeholders are numbered
+// in order of appearance, so two sessions with identical flows get the same
+// placeholders even if the secrets differ.
+//
+// Values learned from messages are only replaced at the element positions they
+// are learned from, so equal content elsewhere, e.g. within amounts or binary
+// data, stays intact. Account IDs and IBANs within the statements of HIKAZ and
+// HICAZ are learned from and replaced at their fields within the MT940 and
+// camt data. Secrets provided explicitly are replaced everywhere.
+type scrubber struct {
+	replacements map[string]string
+	counters     map[string]int
+	secrets      []string
+	// observing defines whether learned values are kept as they are. It is
+	// used on replay for values which are already scrubbed.
+	observing bool
+}
+
+func (s *scrubber) learn(kind, value string, minLength int) {
+	if len(value) < minLength || value == "0" {
+		return
+	}
+	if _, ok := s.replacements[value]; ok {
+		return
+	}
+	if s.observing {
+		s.replacements[value] = value
+		return
+	}
+	s.counters[kind]++
+	var placeholder string
+	switch kind {
+	case "ACCOUNT":
+		placeholder = fmt.Sprintf("%010d", s.counters[kind])
+	case "IBAN":
+		placeholder = fmt.Sprintf("DE00%018d", s.counters[kind])
+	default:
+		placeholder = kind + strconv.Itoa(s.counters[kind])
+	}
+	s.replacements[value] = placeholder
+}
+
+// DialogID returns the placeholder for dialogID.
+func (s *scrubber) DialogID(dialogID string) string {
+	s.learn("DIALOG", dialogID, 1)
+	if placeholder, ok := s.replacements[dialogID]; ok {
+		return placeholder
+	}
+	return dialogID
+}
+
+// Observe learns all values within segments and dialogID as already scrubbed
+// placeholders.
+func (s *scrubber) Observe(dialogID string, segments [][]byte) {
+	s.observing = true
+	defer func() { s.observing = false }()
+	s.DialogID(dialogID)
+	for _, seg := range segments {
+		s.learnFromSegment(seg)
+	}
+}
+
+// Scrub learns all secrets within segments and returns the segments with all
+// known secrets replaced.
+func (s *scrubber) Scrub(segments [][]byte) []string {
+	for _, seg := range segments {
+		s.learnFromSegment(seg)
+	}
+	scrubbed := make([]string, len(segments))
+	for i, seg := range segments {
+		if bytes.HasPrefix(seg, []byte("HNSHA:")) {
+			seg = scrubSignatureEnd(seg)
+		} else {
+			seg = s.replacePositions(seg)
+		}
+		scrubbed[i] = charset.ToUTF8(s.replaceSecrets(seg, isStatementSegment(seg)))
+	}
+	return scrubbed
+}
+
+// secretPosition defines the position of a secret within a segment
+type secretPosition struct {
+	kind      string
+	element   int
+	group     int
+	minLength int
+}
+
+// secretPositions returns the positions of secrets within the segment with
+// the given ID and version. PINs are always replaced, all other values once
+// they are learned.
+func secretPositions(id string, version int) []secretPosition {
+	account := func(i int, international bool) []secretPosition {
+		if international {
+			return []secretPosition{
+				{kind: "IBAN", element: i, group: 0, minLength: minSecretLength},
+				{kind: "ACCOUNT", element: i, group: 2, minLength: minSecretLength},
+			}
+		}
+		return []secretPosition{{kind: "ACCOUNT", element: i, group: 0, minLength: minSecretLength}}
+	}
+	switch id {
+	case "HNSHK":
+		return []secretPosition{
+			{kind: "SYSTEM", element: 6, group: 2, minLength: minSecretLength},
+			{kind: "USER", element: 11, group: 2, minLength: minSecretLength},
+		}
+	case "HKIDN":
+		return []secretPosition{
+			{kind: "USER", element: 2, minLength: minSecretLength},
+			{kind: "SYSTEM", element: 3, minLength: minSecretLength},
+		}
+	case "HISYN":
+		return []secretPosition{{kind: "SYSTEM", element: 1, minLength: minSecretLength}}
+	case "HKEND":
+		return []secretPosition{{kind: "DIALOG", element: 1, minLength: 1}}
+	case "HIUPA":
+		return []secretPosition{{kind: "USER", element: 1, minLength: minSecretLength}}
+	case "HIUPD":
+		return append(account(1, false),
+			secretPosition{kind: "IBAN", element: 2, minLength: minSecretLength},
+			secretPosition{kind: "USER", element: 3, minLength: minSecretLength},
+		)
+	case "HKSAL", "HISAL", "HKKAZ":
+		return account(1, version >= 7)
+	case "HKCAZ", "HICAZ":
+		return account(1, true)
+	case "HKPAE":
+		return []secretPosition{{kind: "PIN", element: 1}}
+	}
+	return nil
+}
+
+// statementFields match account IDs and IBANs within the MT940 and camt
+// statements of HIKAZ and HICAZ. The first submatch is the value.
+var statementFields = []*regexp.Regexp{
+	// MT940 account identification, i.e. bank ID and account ID
+	regexp.MustCompile(`(?m)^:25:[^/\r\n]*/([0-9A-Za-z]+)`),
+	// MT940 account identification by IBAN
+	regexp.MustCompile(`(?m)^:25:([A-Z]{2}[0-9]{2}[0-9A-Z]{11,30}?)(?:[A-Z]{3})?\r?$`),
+	// account ID or IBAN of the counterparty within field 86
+	regexp.MustCompile(`(?:^|[^?])(?:\?\?)*\?31([0-9A-Z]{4,34})`),
+	regexp.MustCompile(`<IBAN>([^<]+)</IBAN>`),
+	regexp.MustCompile(`<Othr><Id>([^<]+)</Id>`),
+}
+
+// isStatementSegment reports whether seg carries statements, i.e. MT940 or
+// camt data.
+func isStatementSegment(seg []byte) bool {
+	return bytes.HasPrefix(seg, []byte("HIKAZ:")) || bytes.HasPrefix(seg, []byte("HICAZ:"))
+}
+
+// statementValueKind returns the kind of an account identification found
+// within a statement.
+func statementValueKind(value string) string {
+	if len(value) > 2 && value[0] >= 'A' && value[0] <= 'Z' && value[1] >= 'A' && value[1] <= 'Z' {
+		return "IBAN"
+	}
+	return "ACCOUNT"
+}
+
+// binaryElements returns the content of all binary data elements within seg.
+func binaryElements(seg []byte) [][]byte {
+	var binaries [][]byte
+	for i := 0; i < len(seg); i++ {
+		switch seg[i] {
+		case '?':
+			i++
+		case '@':
+			data, next, ok := binaryAt(seg, i)
+			if !ok {
+				continue
+			}
+			binaries = append(binaries, data)
+			i = next - 1
+		}
+	}
+	return binaries
+}
+
+// binaryAt returns the content of the binary data element starting at index i
+// of seg and the index following it.
+func binaryAt(seg []byte, i int) ([]byte, int, bool) {
+	end := bytes.IndexByte(seg[i+1:], '@')
+	if end <= 0 {
+		return nil, 0, false
+	}
+	length, err := strconv.Atoi(string(seg[i+1 : i+1+end]))
+	start := i + 1 + end + 1
+	if err != nil || length < 0 || start+length > len(seg) {
+		return nil, 0, false
+	}
+	return seg[start : start+length], start + length, true
+}
+
+func (s *scrubber) learnFromStatement(data []byte) {
+	for _, field := range statementFields {
+		for _, match := range field.FindAllSubmatch(data, -1) {
+			value := string(match[1])
+			s.learn(statementValueKind(value), value, minSecretLength)
+		}
+	}
+}
+
+// replaceStatementValues replaces the known account IDs and IBANs at their
+// fields within the statement data.
+func (s *scrubber) replaceStatementValues(data []byte) []byte {
+	for _, field := range statementFields {
+		var out []byte
+		last := 0
+		for _, match := range field.FindAllSubmatchIndex(data, -1) {
+			placeholder, ok := s.replacements[string(data[match[2]:match[3]])]
+			if !ok {
+				continue
+			}
+			out = append(out, data[last:match[2]]...)
+			out = append(out, placeholder...)
+			last = match[3]
+		}
+		if out != nil {
+			data = append(out, data[last:]...)
+		}
+	}
+	return data
+}
+
+// segmentHeader returns the ID and version of seg
+func segmentHeader(elements [][]byte) (string, int) {
+	header := bytes.Split(elements[0], []byte(":"))
+	version := 0
+	if len(header) > 2 {
+		version, _ = strconv.Atoi(string(header[2]))
+	}
+	return string(header[0]), version
+}
+
+// valueAt returns the value at position p within elements
+func valueAt(elements [][]byte, p secretPosition) (string, bool) {
+	if p.element >= len(elements) {
+		return "", false
+	}
+	groupElements, err := element.ExtractElements(elements[p.element])
+	if err != nil || p.group >= len(groupElements) {
+		return "", false
+	}
+	return string(groupElements[p.group]), true
+}
+
+func (s *scrubber) learnFromSegment(seg []byte) {
+	elements, err := segment.ExtractElements(seg)
+	if err != nil || len(elements) == 0 {
+		return
+	}
+	for _, p := range secretPositions(segmentHeader(elements)) {
+		if p.kind == "PIN" {
+			continue
+		}
+		if value, ok := valueAt(elements, p); ok {
+			s.learn(p.kind, value, p.minLength)
+		}
+	}
+	if isStatementSegment(seg) {
+		for _, data := range binaryElements(seg) {
+			s.learnFromStatement(data)
+		}
+	}
+}
+
+// replacePositions replaces the known secrets at their positions within seg.
+func (s *scrubber) replacePositions(seg []byte) []byte {
+	elements, err := segment.ExtractElements(seg)
+	if err != nil || len(elements) == 0 {
+		return seg
+	}
+	changed := false
+	for _, p := range secretPositions(segmentHeader(elements)) {
+		value, ok := valueAt(elements, p)
+		if !ok || value == "" {
+			continue
+		}
+		placeholder := p.kind
+		if p.kind != "PIN" {
+			placeholder, ok = s.replacements[value]
+			if !ok || placeholder == value {
+				continue
+			}
+		}
+		groupElements, _ := element.ExtractElements(elements[p.element])
+		groupElements[p.group] = []byte(placeholder)
+		elements[p.element] = bytes.Join(groupElements, []byte(":"))
+		changed = true
+	}
+	if !changed {
+		return seg
+	}
+	return append(bytes.Join(elements, []byte("+")), '\'')
+}
+
+// replaceSecrets replaces all explicitly provided secrets within seg. If
+// statement is true, the known account IDs and IBANs within the binary data are
+// replaced as well. Binary data elements are rewritten with their new length.
+func (s *scrubber) replaceSecrets(seg []byte, statement bool) []byte {
+	if len(s.secrets) == 0 && !statement {
+		return seg
+	}
+	var out bytes.Buffer
+	var text []byte
+	flush := func() {
+		out.Write(s.replaceValues(text))
+		text = text[:0]
+	}
+	for i := 0; i < len(seg); i++ {
+		switch seg[i] {
+		case '?':
+			text = append(text, seg[i])
+			if i+1 < len(seg) {
+				i++
+				text = append(text, seg[i])
+			}
+		case '@':
+			binary, next, ok := binaryAt(seg, i)
+			if !ok {
+				text = append(text, seg[i])
+				continue
+			}
+			flush()
+			data := s.replaceValues(binary)
+			if statement {
+				data = s.replaceStatementValues(data)
+			}
+			fmt.Fprintf(&out, "@%d@", len(data))
+			out.Write(data)
+			i = next - 1
+		default:
+			text = append(text, seg[i])
+		}
+	}
+	flush()
+	return out.Bytes()
+}
+
+func (s *scrubber) replaceValues(data []byte) []byte {
+	result := append([]byte(nil), data...)
+	for _, secret := range s.secrets {
+		result = bytes.ReplaceAll(result, charset.ToISO8859_1(secret), charset.ToISO8859_1(s.replacements[secret]))
+	}
+	return result
+}
+
+// scrubSignatureEnd replaces PIN and TAN within the signature end segment.
+func scrubSignatureEnd(seg []byte) []byte {
+	elements, err := segment.ExtractElements(seg)
+	if err != nil || len(elements) < 4 {
+		return seg
+	}
+	pinTan, err := element.ExtractElements(elements[3])
+	if err != nil {
+		return seg
+	}
+	scrubbed := []string{"PIN"}
+	if len(pinTan) > 1 {
+		scrubbed = append(scrubbed, "TAN")
+	}
+	elements[3] = []byte(joinStrings(scrubbed, ":"))
+	return append(bytes.Join(elements, []byte("+")), '\'')
+}
+
+func joinStrings(values []string, separator string) string {
+	var buf bytes.Buffer
+	for i, v := range values {
+		if i > 0 {
+			buf.WriteString(separator)
+		}
+		buf.WriteString(v)
+	}
+	return buf.String()
+}
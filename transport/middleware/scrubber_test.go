@@ -0,0 +1,83 @@
+package transport
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func binary(data string) string {
+	return fmt.Sprintf("@%d@%s", len(data), data)
+}
+
+func TestScrubberScrub(t *testing.T) {
+	tests := []struct {
+		name     string
+		secrets  []string
+		segments []string
+		expected []string
+	}{
+		{
+			name: "learned values are replaced at their positions only",
+			segments: []string{
+				"HIUPD:12:6:4+1234::280:10000000+DE89370400440000001234+testuser+1+EUR+Max Muster++Girokonto'",
+				"HISAL:5:5:4+1234::280:10000000+Girokonto+EUR+C:1234,56:EUR:20200101'",
+				"HIRMS:3:2:4+0020::Konto 1234 testuser'",
+			},
+			expected: []string{
+				"HIUPD:12:6:4+0000000001::280:10000000+DE00000000000000000001+USER1+1+EUR+Max Muster++Girokonto'",
+				"HISAL:5:5:4+0000000001::280:10000000+Girokonto+EUR+C:1234,56:EUR:20200101'",
+				"HIRMS:3:2:4+0020::Konto 1234 testuser'",
+			},
+		},
+		{
+			name: "account IDs and IBANs within statements are replaced",
+			segments: []string{
+				"HKKAZ:3:7+DE89370400440532013000:TESTDEFFXXX:1234567::280:10000000+N'",
+				"HIKAZ:4:7:3+" + binary("\r\n:20:STARTUMS\r\n:25:10000000/1234567\r\n:61:2003010301CR2500,00N166NONREF\r\n"+
+					":86:166?00GUTSCHRIFT?20Gehalt 1234567?30TESTDEFFXXX?31DE27100777770209299700?32Arbeitgeber\r\n-") + "'",
+				"HICAZ:4:1:3+DE89370400440532013000:TESTDEFFXXX:1234567::280:10000000+urn?:iso?:std?:iso?:20022?:tech?:xsd?:camt.052.001.02+" +
+					binary("<Acct><Id><IBAN>DE89370400440532013000</IBAN></Id></Acct><CdtrAcct><Id><Othr><Id>7654321</Id></Othr></Id></CdtrAcct>") + "'",
+			},
+			expected: []string{
+				"HKKAZ:3:7+DE00000000000000000001:TESTDEFFXXX:0000000001::280:10000000+N'",
+				"HIKAZ:4:7:3+" + binary("\r\n:20:STARTUMS\r\n:25:10000000/0000000001\r\n:61:2003010301CR2500,00N166NONREF\r\n"+
+					":86:166?00GUTSCHRIFT?20Gehalt 1234567?30TESTDEFFXXX?31DE00000000000000000002?32Arbeitgeber\r\n-") + "'",
+				"HICAZ:4:1:3+DE00000000000000000001:TESTDEFFXXX:0000000001::280:10000000+urn?:iso?:std?:iso?:20022?:tech?:xsd?:camt.052.001.02+" +
+					binary("<Acct><Id><IBAN>DE00000000000000000001</IBAN></Id></Acct><CdtrAcct><Id><Othr><Id>0000000002</Id></Othr></Id></CdtrAcct>") + "'",
+			},
+		},
+		{
+			name:     "PINs are replaced regardless of their length",
+			segments: []string{"HKPAE:3:1+12'"},
+			expected: []string{"HKPAE:3:1+PIN'"},
+		},
+		{
+			name:    "provided secrets are replaced everywhere",
+			secrets: []string{"Max Muster"},
+			segments: []string{
+				"HIUPD:12:6:4+1234::280:10000000+DE89370400440000001234+testuser+1+EUR+Max Muster++Girokonto'",
+				"HIRMS:3:2:4+0020::Konto von Max Muster'",
+			},
+			expected: []string{
+				"HIUPD:12:6:4+0000000001::280:10000000+DE00000000000000000001+USER1+1+EUR+SECRET1++Girokonto'",
+				"HIRMS:3:2:4+0020::Konto von SECRET1'",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var segments [][]byte
+			for _, seg := range test.segments {
+				segments = append(segments, []byte(seg))
+			}
+
+			actual := newScrubber(test.secrets...).Scrub(segments)
+
+			if !reflect.DeepEqual(test.expected, actual) {
+				t.Logf("Expected scrubbed segments to equal\n%q\n\tgot\n%q\n", test.expected, actual)
+				t.Fail()
+			}
+		})
+	}
+}
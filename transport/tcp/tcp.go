@@ -0,0 +1,230 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mitch000001/go-hbci/internal"
+	"github.com/mitch000001/go-hbci/segment"
+	"github.com/mitch000001/go-hbci/transport"
+)
+
+const (
+	// DefaultPort is the port HBCI servers listen on for plain TCP connections
+	DefaultPort = "3000"
+	// DefaultDialTimeout is the default timeout for establishing connections
+	DefaultDialTimeout = 30 * time.Second
+	// DefaultReadTimeout is the default timeout for receiving a response
+	DefaultReadTimeout = 60 * time.Second
+
+	// maxMessageSize limits the size of a single message to protect against
+	// malformed size fields
+	maxMessageSize = 16 << 20
+)
+
+// New returns a TCPTransport with default timeouts
+func New() *TCPTransport {
+	return &TCPTransport{
+		DialTimeout: DefaultDialTimeout,
+		ReadTimeout: DefaultReadTimeout,
+	}
+}
+
+// A TCPTransport implements transport.Transport and performs requests over
+// plain TCP connections, as used by HBCI 2.2 servers.
+//
+// Messages are sent as is, without any encoding. Responses are framed by the
+// size field of the message header. The connection is kept open and reused
+// for subsequent requests to the same address until Close is called or the
+// server closes it.
+type TCPTransport struct {
+	// DialTimeout limits the time to establish a connection. Zero means no
+	// timeout.
+	DialTimeout time.Duration
+	// ReadTimeout limits the time to receive a complete response, starting
+	// after the request was sent. Zero means no timeout.
+	ReadTimeout time.Duration
+
+	mu     sync.Mutex
+	addr   string
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// Do sends the request to the HBCI server at request.URL and reads the
+// response. The URL can either be a plain address like "hbci.example.com" or
+// "hbci.example.com:3000", or have a "tcp://" prefix. Without port the
+// DefaultPort is used.
+//
+// If successful, it returns a transport.Response with the complete message as
+// Body and the request as Request.
+func (t *TCPTransport) Do(request *transport.Request) (*transport.Response, error) {
+	addr, err := address(request.URL)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading request body: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	reused := t.conn != nil && t.addr == addr
+	response, err := t.roundTrip(addr, body)
+	if err != nil && reused && errors.Is(err, errWriteFailed) {
+		// the request was not sent over the stale connection, so it is safe
+		// to retry with a new one
+		response, err = t.roundTrip(addr, body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &transport.Response{
+		Request:           request,
+		MarshaledResponse: response,
+		Body:              io.NopCloser(bytes.NewReader(response)),
+	}, nil
+}
+
+// RawFraming implements transport.RawFramer. Messages are exchanged as is.
+func (t *TCPTransport) RawFraming() bool {
+	return true
+}
+
+// Close closes the open connection, if any
+func (t *TCPTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.closeConn()
+}
+
+var (
+	// errConnectionClosed marks errors of connections closed by the server
+	// before any response byte was received
+	errConnectionClosed = errors.New("connection closed by server")
+	// errWriteFailed marks errors of requests which could not be sent
+	errWriteFailed = errors.New("error writing request")
+)
+
+// idleTimeout is the time to wait for a server closing an idle connection
+// before reusing it
+const idleTimeout = time.Millisecond
+
+func (t *TCPTransport) roundTrip(addr string, body []byte) ([]byte, error) {
+	if err := t.connect(addr); err != nil {
+		return nil, err
+	}
+	if _, err := t.conn.Write(body); err != nil {
+		logErr(t.closeConn())
+		return nil, fmt.Errorf("%w: %v", errWriteFailed, err)
+	}
+	if t.ReadTimeout > 0 {
+		if err := t.conn.SetReadDeadline(time.Now().Add(t.ReadTimeout)); err != nil {
+			logErr(t.closeConn())
+			return nil, fmt.Errorf("error setting read deadline: %w", err)
+		}
+	}
+	response, err := ReadMessage(t.reader)
+	if err != nil {
+		logErr(t.closeConn())
+		return nil, err
+	}
+	return response, nil
+}
+
+func (t *TCPTransport) connect(addr string) error {
+	if t.conn != nil && t.addr == addr && t.idle() {
+		return nil
+	}
+	logErr(t.closeConn())
+	dialer := net.Dialer{Timeout: t.DialTimeout}
+	conn, err := dialer.Dial("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("error connecting to %s: %w", addr, err)
+	}
+	t.addr = addr
+	t.conn = conn
+	t.reader = bufio.NewReader(conn)
+	return nil
+}
+
+// idle returns whether the open connection can be reused, i.e. the server
+// neither closed it nor sent unrequested data.
+func (t *TCPTransport) idle() bool {
+	if t.reader.Buffered() > 0 {
+		return false
+	}
+	if err := t.conn.SetReadDeadline(time.Now().Add(idleTimeout)); err != nil {
+		return false
+	}
+	_, err := t.reader.Peek(1)
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		return false
+	}
+	return t.conn.SetReadDeadline(time.Time{}) == nil
+}
+
+func (t *TCPTransport) closeConn() error {
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.Close()
+	t.conn = nil
+	t.reader = nil
+	t.addr = ""
+	return err
+}
+
+// ReadMessage reads a single HBCI message from r. The length of the message
+// is taken from the size field of the message header.
+func ReadMessage(r *bufio.Reader) ([]byte, error) {
+	header, err := r.ReadBytes('\'')
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(header) == 0 {
+			return nil, errConnectionClosed
+		}
+		return nil, fmt.Errorf("error reading message header: %w", err)
+	}
+	messageHeader := &segment.MessageHeaderSegment{}
+	if err := messageHeader.UnmarshalHBCI(header); err != nil {
+		return nil, fmt.Errorf("malformed message header %q: %w", header, err)
+	}
+	size := messageHeader.Size.Val()
+	if size < len(header) || size > maxMessageSize {
+		return nil, fmt.Errorf("malformed message header: invalid message size %d", size)
+	}
+	message := make([]byte, size)
+	copy(message, header)
+	if _, err := io.ReadFull(r, message[len(header):]); err != nil {
+		return nil, fmt.Errorf("error reading message: %w", err)
+	}
+	return message, nil
+}
+
+func address(url string) (string, error) {
+	addr := strings.TrimPrefix(url, "tcp://")
+	addr = strings.TrimSuffix(addr, "/")
+	if addr == "" {
+		return "", fmt.Errorf("malformed address: %q", url)
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, DefaultPort)
+	}
+	return addr, nil
+}
+
+func logErr(err error) {
+	if err != nil {
+		internal.Debug.Println(err)
+	}
+}
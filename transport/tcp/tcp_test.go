@@ -0,0 +1,276 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/transport"
+)
+
+func TestTCPTransportDo(t *testing.T) {
+	server := newTestServer(t, func(conn net.Conn, request []byte) bool {
+		_, err := conn.Write(testMessage(strings.Replace(string(request[bytes.IndexByte(request, '\'')+1:]), "HKIDN", "HIRMG", 1)))
+		return err == nil
+	})
+	defer server.Close()
+
+	tcpTransport := New()
+	defer tcpTransport.Close()
+
+	for i := 0; i < 3; i++ {
+		request := testMessage(fmt.Sprintf("HKIDN:2:2+%d'", i))
+		response, err := tcpTransport.Do(&transport.Request{
+			URL:  "tcp://" + server.Addr(),
+			Body: io.NopCloser(bytes.NewReader(request)),
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+		expected := testMessage(fmt.Sprintf("HIRMG:2:2+%d'", i))
+		if !bytes.Equal(expected, body) {
+			t.Logf("Expected response to equal\n%q\n\tgot\n%q\n", expected, body)
+			t.Fail()
+		}
+	}
+
+	if server.Connections() != 1 {
+		t.Logf("Expected connection to be reused, got %d connections\n", server.Connections())
+		t.Fail()
+	}
+}
+
+func TestTCPTransportDoReconnects(t *testing.T) {
+	server := newTestServer(t, func(conn net.Conn, request []byte) bool {
+		conn.Write(testMessage("HIRMG:2:2+0100::Dialog beendet'"))
+		// close the connection after each message
+		return false
+	})
+	defer server.Close()
+
+	tcpTransport := New()
+	defer tcpTransport.Close()
+
+	for i := 0; i < 2; i++ {
+		_, err := tcpTransport.Do(&transport.Request{
+			URL:  server.Addr(),
+			Body: io.NopCloser(bytes.NewReader(testMessage("HKEND:2:1+abc'"))),
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+	}
+
+	if server.Connections() != 2 {
+		t.Logf("Expected new connection after server closed it, got %d connections\n", server.Connections())
+		t.Fail()
+	}
+}
+
+func TestTCPTransportDoDoesNotResendAfterWrite(t *testing.T) {
+	var mu sync.Mutex
+	var requests int
+	server := newTestServer(t, func(conn net.Conn, request []byte) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if requests > 1 {
+			// close the connection after receiving the request
+			return false
+		}
+		_, err := conn.Write(testMessage("HIRMG:2:2+0010::Nachricht entgegengenommen'"))
+		return err == nil
+	})
+	defer server.Close()
+
+	tcpTransport := New()
+	defer tcpTransport.Close()
+
+	for i := 0; i < 2; i++ {
+		_, err := tcpTransport.Do(&transport.Request{
+			URL:  server.Addr(),
+			Body: io.NopCloser(bytes.NewReader(testMessage(fmt.Sprintf("HKIDN:2:2+%d'", i)))),
+		})
+		if i == 0 && err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+		if i == 1 && !errors.Is(err, errConnectionClosed) {
+			t.Logf("Expected connection closed error, got %T:%v\n", err, err)
+			t.Fail()
+		}
+	}
+
+	server.Close()
+	if requests != 2 {
+		t.Logf("Expected request not to be resent after it was written, got %d requests\n", requests)
+		t.Fail()
+	}
+}
+
+func TestTCPTransportDoReadTimeout(t *testing.T) {
+	server := newTestServer(t, func(conn net.Conn, request []byte) bool {
+		// never answer
+		return true
+	})
+	defer server.Close()
+
+	tcpTransport := &TCPTransport{ReadTimeout: 50 * time.Millisecond}
+	defer tcpTransport.Close()
+
+	_, err := tcpTransport.Do(&transport.Request{
+		URL:  server.Addr(),
+		Body: io.NopCloser(bytes.NewReader(testMessage("HKIDN:2:2+0'"))),
+	})
+
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Logf("Expected timeout error, got %T:%v\n", err, err)
+		t.Fail()
+	}
+}
+
+func TestReadMessage(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    string
+		expectedErr bool
+	}{
+		{
+			string(testMessage("HIRMG:2:2+0010::ok'")) + "HNHBK:1:3+",
+			string(testMessage("HIRMG:2:2+0010::ok'")),
+			false,
+		},
+		{
+			string(testMessage("HNVSD:999:1+@5@ab'cd'")),
+			string(testMessage("HNVSD:999:1+@5@ab'cd'")),
+			false,
+		},
+		{
+			"HNHBK:1:3+000000000010+300+abc+1'",
+			"",
+			true,
+		},
+		{
+			"HNHBK:1:3+000000000100+300+abc+1'HIRMG:2:2'",
+			"",
+			true,
+		},
+	}
+	for _, test := range tests {
+		actual, err := ReadMessage(bufio.NewReader(strings.NewReader(test.input)))
+
+		if test.expectedErr && err == nil {
+			t.Logf("Expected error for input %q\n", test.input)
+			t.Fail()
+		}
+		if !test.expectedErr && err != nil {
+			t.Logf("Expected no error, got %T:%v\n", err, err)
+			t.Fail()
+		}
+		if test.expected != string(actual) {
+			t.Logf("Expected message to equal\n%q\n\tgot\n%q\n", test.expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+func TestAddress(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"hbci.example.com", "hbci.example.com:3000"},
+		{"hbci.example.com:3001", "hbci.example.com:3001"},
+		{"tcp://hbci.example.com", "hbci.example.com:3000"},
+		{"tcp://127.0.0.1:3000/", "127.0.0.1:3000"},
+	}
+	for _, test := range tests {
+		actual, err := address(test.url)
+		if err != nil {
+			t.Fatalf("Expected no error, got %T:%v\n", err, err)
+		}
+		if test.expected != actual {
+			t.Logf("Expected address for %q to equal %q, got %q\n", test.url, test.expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+// testMessage returns a message consisting of a message header with a
+// correct size field and body
+func testMessage(body string) []byte {
+	header := "HNHBK:1:3+%012d+300+abc+1'"
+	size := len(fmt.Sprintf(header, 0)) + len(body)
+	return []byte(fmt.Sprintf(header, size) + body)
+}
+
+// testServer accepts connections and calls handle for each received
+// message. The connection is closed when handle returns false.
+type testServer struct {
+	listener    net.Listener
+	mu          sync.Mutex
+	connections int
+	wg          sync.WaitGroup
+}
+
+func newTestServer(t *testing.T, handle func(conn net.Conn, request []byte) bool) *testServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	server := &testServer{listener: listener}
+	server.wg.Add(1)
+	go func() {
+		defer server.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			server.mu.Lock()
+			server.connections++
+			server.mu.Unlock()
+			server.wg.Add(1)
+			go func() {
+				defer server.wg.Done()
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					conn.SetReadDeadline(time.Now().Add(time.Second))
+					request, err := ReadMessage(reader)
+					if err != nil || !handle(conn, request) {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return server
+}
+
+func (s *testServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *testServer) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections
+}
+
+func (s *testServer) Close() {
+	s.listener.Close()
+	s.wg.Wait()
+}
@@ -16,6 +16,13 @@ type Transport interface {
 	Do(*Request) (*Response, error)
 }
 
+// RawFramer is implemented by transports which exchange messages unencoded,
+// framed only by the size field of the message header. Transports not
+// implementing it get the Base64 encoding used over HTTPS applied.
+type RawFramer interface {
+	RawFraming() bool
+}
+
 // The Func type is an adapter to allow the use of ordinary functions
 // as Transport handlers. If f is a function with the appropriate signature,
 // Func(f) is a Transport that calls f.